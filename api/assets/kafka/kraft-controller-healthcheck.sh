@@ -22,9 +22,9 @@
 #
 # Behaviour is selected by KRAFT_HEALTH_CHECK_MODE:
 #   readiness        -> fail-closed: succeeds only when the controller is a leader or follower (a
-#                       functioning quorum member). Koperator's rolling upgrade waits on this readiness
-#                       signal so it never restarts the next controller before the previously restarted
-#                       one has rejoined the metadata quorum.
+#                       functioning quorum member). Koperator's rolling upgrade falls back to this
+#                       readiness signal when it cannot describe the metadata quorum, so it never restarts
+#                       the next controller before the previously restarted one has rejoined the quorum.
 #   liveness (default) -> fails only when the controller is reachable and reporting a state that is not
 #                       leader/follower; a not-yet-emitted state (startup/catch-up) or an unreachable
 #                       metrics endpoint is treated as healthy (fail-open) so a slow-starting or briefly
@@ -66,6 +66,13 @@ if echo "${METRICS}" | grep -Eq "^${METRIC_PREFIX}(leader|follower) 1(\.[0-9]+)?
     exit 0
 fi
 
+# A controller of a dynamic quorum is an observer until the operator adds it to the voters: it is not a quorum
+# member yet (not ready), but restarting it would only delay the promotion.
+if [ "${mode}" != "readiness" ] && echo "${METRICS}" | grep -Eq "^${METRIC_PREFIX}observer 1(\.[0-9]+)?$"; then
+    echo "The controller is an observer of the quorum."
+    exit 0
+fi
+
 # Reachable and reporting some other raft state (e.g. candidate, unattached, observer).
 if echo "${METRICS}" | grep -q "^${METRIC_PREFIX}"; then
     STATE=$(echo "${METRICS}" | grep "^${METRIC_PREFIX}" | head -n 1 | sed -E "s/^${METRIC_PREFIX}([a-z]+).*/\1/")
@@ -49,6 +49,8 @@ const (
 	OperationRemoveDisks CruiseControlTaskOperation = "remove_disks"
 	// OperationRebalance means a Cruise Control rebalance operation
 	OperationRebalance CruiseControlTaskOperation = "rebalance"
+	// OperationFixOfflineReplicas means a Cruise Control fix_offline_replicas operation
+	OperationFixOfflineReplicas CruiseControlTaskOperation = "fix_offline_replicas"
 	// OperationStatus means a Cruise Control status operation
 	OperationStatus CruiseControlTaskOperation = "status"
 	// KafkaAccessTypeRead states that a user wants consume access to a topic
@@ -69,6 +71,22 @@ const (
 	TLSJKSKeyStore string = "keystore.jks"
 	// TLSJKSTrustStore is where a JKS truststore is stored in a user secret when requested
 	TLSJKSTrustStore string = "truststore.jks"
+	// TLSPKCS12KeyStore is where a PKCS#12 keystore is stored in a user secret when requested
+	TLSPKCS12KeyStore string = "keystore.p12"
+	// TLSPKCS12TrustStore is where a PKCS#12 truststore is stored in a user secret when requested
+	TLSPKCS12TrustStore string = "truststore.p12"
+	// PKCS12PasswordKey stores the password of the PKCS#12 keystore and truststore
+	PKCS12PasswordKey string = "pkcs12.password"
+	// PEMBundleKeyStore is where a PEM keystore with an encrypted private key is stored in a user secret when requested
+	PEMBundleKeyStore string = "keystore.pem"
+	// PEMBundleTrustStore is where the PEM encoded CA certificates are stored in a user secret when requested
+	PEMBundleTrustStore string = "truststore.pem"
+	// PEMBundlePasswordKey stores the password of the private key in the PEM keystore
+	PEMBundlePasswordKey string = "pem.password"
+	// ClientPropertiesKey is where the Java client configuration of a client bundle is stored
+	ClientPropertiesKey string = "client.properties"
+	// LibrdkafkaConfigKey is where the librdkafka client configuration of a client bundle is stored
+	LibrdkafkaConfigKey string = "librdkafka.config"
 	// CoreCACertKey is where ca certificates are stored in user certificates
 	CoreCACertKey string = "ca.crt"
 	// CaChainPem is where CA certificate(s) are stored as a chain for user secret
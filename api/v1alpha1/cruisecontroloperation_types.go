@@ -224,5 +224,6 @@ func (o *CruiseControlOperation) IsCurrentTaskOperationValid() bool {
 		o.CurrentTaskOperation() == OperationRebalance ||
 		o.CurrentTaskOperation() == OperationRemoveBroker ||
 		o.CurrentTaskOperation() == OperationStopExecution ||
-		o.CurrentTaskOperation() == OperationRemoveDisks
+		o.CurrentTaskOperation() == OperationRemoveDisks ||
+		o.CurrentTaskOperation() == OperationFixOfflineReplicas
 }
@@ -53,6 +53,8 @@ func addKnownTypes(s *runtime.Scheme) error {
 	s.AddKnownTypes(GroupVersion,
 		&CruiseControlOperation{},
 		&CruiseControlOperationList{},
+		&KafkaClusterBackup{},
+		&KafkaClusterBackupList{},
 		&KafkaTopic{},
 		&KafkaTopicList{},
 		&KafkaUser{},
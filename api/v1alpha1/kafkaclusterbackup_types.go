@@ -0,0 +1,122 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KafkaClusterBackupPhase is the phase of a KafkaClusterBackup
+type KafkaClusterBackupPhase string
+
+const (
+	// BackupFlushing means the brokers are being made to flush the logs of their partitions on every appended message
+	BackupFlushing KafkaClusterBackupPhase = "Flushing"
+	// BackupSnapshotting means the volume snapshots of the broker volumes are being taken
+	BackupSnapshotting KafkaClusterBackupPhase = "Snapshotting"
+	// BackupSucceeded means every volume snapshot of the backup is ready to be restored from
+	BackupSucceeded KafkaClusterBackupPhase = "Succeeded"
+	// BackupFailed means the backup could not be taken
+	BackupFailed KafkaClusterBackupPhase = "Failed"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterRef.name"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// KafkaClusterBackup is the Schema for the kafkaclusterbackups API. It takes a CSI volume snapshot of every broker
+// volume of a KafkaCluster, a KafkaCluster restores the volumes of its brokers from it with restoreFromBackup.
+type KafkaClusterBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KafkaClusterBackupSpec   `json:"spec,omitempty"`
+	Status KafkaClusterBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// KafkaClusterBackupList contains a list of KafkaClusterBackup.
+type KafkaClusterBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KafkaClusterBackup `json:"items"`
+}
+
+// KafkaClusterBackupSpec defines the desired state of KafkaClusterBackup.
+type KafkaClusterBackupSpec struct {
+	// ClusterRef is the KafkaCluster whose broker volumes are snapshotted, it has to be in the namespace of the backup
+	// as the volume snapshots are taken in the namespace of the persistent volume claims
+	ClusterRef ClusterReference `json:"clusterRef"`
+	// VolumeSnapshotClassName is the VolumeSnapshotClass of the volume snapshots, the default class of the CSI driver
+	// is used when it is not given
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+	// FlushBrokers makes the brokers flush the logs of their partitions on every appended message until the volume
+	// snapshots are taken, so the snapshots miss as few acknowledged messages as possible. It is done by a per-broker
+	// log.flush.interval.messages config, which is reverted afterwards. The flush is best effort: only the messages
+	// appended after the config is applied are flushed, the messages already written to idle partitions may still be
+	// unflushed when the snapshots are taken, so the snapshots are crash consistent only.
+	// +optional
+	FlushBrokers bool `json:"flushBrokers,omitempty"`
+}
+
+// KafkaClusterBackupStatus defines the observed state of KafkaClusterBackup.
+type KafkaClusterBackupStatus struct {
+	// Phase is the phase of the backup
+	// +optional
+	Phase KafkaClusterBackupPhase `json:"phase,omitempty"`
+	// Message tells why the backup failed
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the backup was started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time every volume snapshot became ready
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// ClusterID is the id of the KRaft cluster the volumes were snapshotted of, the clusters restored from the backup
+	// are formatted with it so the brokers accept the restored log dirs
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// VolumeSnapshots are the volume snapshots taken of the broker volumes
+	// +optional
+	VolumeSnapshots []BrokerVolumeSnapshot `json:"volumeSnapshots,omitempty"`
+}
+
+// BrokerVolumeSnapshot is the volume snapshot of a broker volume
+type BrokerVolumeSnapshot struct {
+	// BrokerID is the id of the broker the volume belongs to
+	BrokerID int32 `json:"brokerId"`
+	// MountPath is the mount path of the storage config of the volume
+	MountPath string `json:"mountPath"`
+	// PersistentVolumeClaimName is the name of the persistent volume claim of the volume
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+	// VolumeSnapshotName is the name of the VolumeSnapshot taken of the volume
+	VolumeSnapshotName string `json:"volumeSnapshotName"`
+	// ReadyToUse tells whether the volume snapshot can be restored from
+	// +optional
+	ReadyToUse bool `json:"readyToUse,omitempty"`
+	// RestoreSize is the minimum size of a volume restored from the volume snapshot
+	// +optional
+	RestoreSize *resource.Quantity `json:"restoreSize,omitempty"`
+}
+
+// IsFinished tells whether the backup succeeded or failed
+func (b *KafkaClusterBackup) IsFinished() bool {
+	return b.Status.Phase == BackupSucceeded || b.Status.Phase == BackupFailed
+}
@@ -23,6 +23,9 @@ const (
 	MinReplicationFactor = -1
 )
 
+// RemoteStorageEnableConfig is the topic config which enables tiered storage on the topic
+const RemoteStorageEnableConfig = "remote.storage.enable"
+
 // KafkaTopicSpec defines the desired state of KafkaTopic
 // +k8s:openapi-gen=true
 type KafkaTopicSpec struct {
@@ -35,6 +38,23 @@ type KafkaTopicSpec struct {
 	ReplicationFactor int32             `json:"replicationFactor"`
 	Config            map[string]string `json:"config,omitempty"`
 	ClusterRef        ClusterReference  `json:"clusterRef"`
+	// RemoteStorageEnable offloads the log segments of the topic to the remote storage, it requires tiered storage to
+	// be enabled on the cluster
+	// +optional
+	RemoteStorageEnable bool `json:"remoteStorageEnable,omitempty"`
+}
+
+// GetConfig returns the config of the topic including the one derived from the typed fields
+func (s KafkaTopicSpec) GetConfig() map[string]string {
+	if !s.RemoteStorageEnable {
+		return s.Config
+	}
+	config := make(map[string]string, len(s.Config)+1)
+	for key, value := range s.Config {
+		config[key] = value
+	}
+	config[RemoteStorageEnableConfig] = "true"
+	return config
 }
 
 // KafkaTopicStatus defines the observed state of KafkaTopic
@@ -0,0 +1,32 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestKafkaTopicSpecGetConfig(t *testing.T) {
+	t.Parallel()
+	spec := KafkaTopicSpec{Config: map[string]string{"retention.ms": "1000"}}
+	assert.DeepEqual(t, spec.GetConfig(), map[string]string{"retention.ms": "1000"})
+
+	spec.RemoteStorageEnable = true
+	assert.DeepEqual(t, spec.GetConfig(), map[string]string{"retention.ms": "1000", RemoteStorageEnableConfig: "true"})
+	// the config of the spec is left unchanged
+	assert.DeepEqual(t, spec.Config, map[string]string{"retention.ms": "1000"})
+}
@@ -15,6 +15,7 @@
 package v1alpha1
 
 import (
+	"slices"
 	"strings"
 	"time"
 
@@ -44,14 +45,71 @@ type KafkaUserSpec struct {
 	IncludeJKS     bool              `json:"includeJKS,omitempty"`
 	CreateCert     *bool             `json:"createCert,omitempty"`
 	PKIBackendSpec *PKIBackendSpec   `json:"pkiBackendSpec,omitempty"`
+	// keystoreFormats lists the keystore formats rendered into the user secret next to the PEM encoded
+	// certificate and key. Every format gets its own generated password. Listing jks is equivalent to includeJKS.
+	// +optional
+	KeystoreFormats []KeystoreFormat `json:"keystoreFormats,omitempty"`
 	// expirationSeconds is the requested duration of validity of the issued certificate.
 	// The minimum valid value for expirationSeconds is 3600 i.e. 1h.
 	// When it is not specified the default validation duration is 90 days
 	// +optional
 	// +kubebuilder:validation:Minimum=3600
 	ExpirationSeconds *int32 `json:"expirationSeconds,omitempty"`
+	// renewBeforeSeconds is how long before the expiry of the issued certificate the operator re-issues it.
+	// The minimum valid value for renewBeforeSeconds is 600 i.e. 10m.
+	// When it is not specified, or it is not shorter than expirationSeconds, the certificate is renewed when
+	// one third of its validity duration is left
+	// +optional
+	// +kubebuilder:validation:Minimum=600
+	RenewBeforeSeconds *int32 `json:"renewBeforeSeconds,omitempty"`
+	// clientBundle requests a ready-to-use client configuration, built from the listener statuses of the
+	// referenced cluster, to be written next to the user credentials
+	// +optional
+	ClientBundle *ClientBundleSpec `json:"clientBundle,omitempty"`
+}
+
+// ClientBundleSpec configures the client connection bundle written for a KafkaUser
+type ClientBundleSpec struct {
+	// listener is the name of the internal or external listener the clients connect to
+	Listener string `json:"listener"`
+	// target is where the bundle is written, either into the user secret or into a ConfigMap named
+	// <secretName>-client. Defaults to Secret
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +optional
+	Target ClientBundleTarget `json:"target,omitempty"`
+	// mountPath is the directory the user secret is mounted at in the client pods. Defaults to /etc/kafka/secrets
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+	// saslMechanism is set in the bundle when the listener uses SASL, e.g. SCRAM-SHA-512 or OAUTHBEARER
+	// +optional
+	SASLMechanism string `json:"saslMechanism,omitempty"`
 }
 
+// ClientBundleTarget is the kind of resource the client bundle of a KafkaUser is written into
+type ClientBundleTarget string
+
+const (
+	// ClientBundleTargetSecret writes the client bundle into the user secret
+	ClientBundleTargetSecret ClientBundleTarget = "Secret"
+	// ClientBundleTargetConfigMap writes the client bundle into a ConfigMap next to the user secret
+	ClientBundleTargetConfigMap ClientBundleTarget = "ConfigMap"
+
+	defaultClientBundleMountPath = "/etc/kafka/secrets"
+)
+
+// KeystoreFormat is a format in which the certificate of a KafkaUser is rendered into its secret
+// +kubebuilder:validation:Enum={"jks","pkcs12","pem-bundle"}
+type KeystoreFormat string
+
+const (
+	// KeystoreFormatJKS renders a JKS keystore and truststore
+	KeystoreFormatJKS KeystoreFormat = "jks"
+	// KeystoreFormatPKCS12 renders a PKCS#12 keystore and truststore
+	KeystoreFormatPKCS12 KeystoreFormat = "pkcs12"
+	// KeystoreFormatPEMBundle renders a PEM keystore with an encrypted private key followed by the certificate chain
+	KeystoreFormatPEMBundle KeystoreFormat = "pem-bundle"
+)
+
 type PKIBackendSpec struct {
 	IssuerRef *cmmeta.IssuerReference `json:"issuerRef,omitempty"`
 	// +kubebuilder:validation:Enum={"cert-manager","k8s-csr"}
@@ -74,6 +132,23 @@ type UserTopicGrant struct {
 type KafkaUserStatus struct {
 	State UserState `json:"state"`
 	ACLs  []string  `json:"acls,omitempty"`
+	// Certificate holds the details of the certificate issued for the KafkaUser
+	Certificate *UserCertificateStatus `json:"certificate,omitempty"`
+}
+
+// UserCertificateStatus describes the certificate issued for a KafkaUser
+type UserCertificateStatus struct {
+	// NotAfter is the expiry time of the certificate
+	NotAfter metav1.Time `json:"notAfter"`
+	// SerialNumber is the hex encoded serial number of the certificate
+	SerialNumber string `json:"serialNumber"`
+	// Issuer is the Distinguished Name of the certificate issuer
+	Issuer string `json:"issuer"`
+	// RenewalTime is the time after which the operator re-issues the certificate
+	RenewalTime metav1.Time `json:"renewalTime"`
+	// RenewalRequested tells whether the operator has requested the re-issuance of the certificate
+	// +optional
+	RenewalRequested bool `json:"renewalRequested,omitempty"`
 }
 
 // KafkaUser is the Schema for the kafka users API
@@ -129,3 +204,53 @@ func (spec *KafkaUserSpec) GetExpirationSeconds() int32 {
 	}
 	return *spec.ExpirationSeconds
 }
+
+// GetKeystoreFormats returns the deduplicated list of keystore formats requested for the user secret
+func (spec *KafkaUserSpec) GetKeystoreFormats() []KeystoreFormat {
+	formats := make([]KeystoreFormat, 0, len(spec.KeystoreFormats)+1)
+	if spec.IncludeJKS {
+		formats = append(formats, KeystoreFormatJKS)
+	}
+	for _, format := range spec.KeystoreFormats {
+		if !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// HasKeystoreFormat returns whether the given keystore format is requested for the user secret
+func (spec *KafkaUserSpec) HasKeystoreFormat(format KeystoreFormat) bool {
+	return slices.Contains(spec.GetKeystoreFormats(), format)
+}
+
+// GetTarget returns the kind of resource the client bundle is written into
+func (b *ClientBundleSpec) GetTarget() ClientBundleTarget {
+	if b.Target == "" {
+		return ClientBundleTargetSecret
+	}
+	return b.Target
+}
+
+// GetMountPath returns the directory the user secret is mounted at in the client pods
+func (b *ClientBundleSpec) GetMountPath() string {
+	if b.MountPath == "" {
+		return defaultClientBundleMountPath
+	}
+	return strings.TrimSuffix(b.MountPath, "/")
+}
+
+// GetConfigMapName returns the name of the ConfigMap holding the client bundle of the user secret
+func (b *ClientBundleSpec) GetConfigMapName(secretName string) string {
+	return secretName + "-client"
+}
+
+// GetRenewBefore returns how long before its expiry the user certificate should be re-issued. It is always shorter
+// than the validity of the certificate since cert-manager rejects certificates renewed before they are issued
+func (spec *KafkaUserSpec) GetRenewBefore() time.Duration {
+	expiration := time.Duration(spec.GetExpirationSeconds()) * time.Second
+	if spec.RenewBeforeSeconds == nil || time.Duration(*spec.RenewBeforeSeconds)*time.Second >= expiration {
+		return expiration / 3
+	}
+	return time.Duration(*spec.RenewBeforeSeconds) * time.Second
+}
@@ -17,6 +17,7 @@ package v1alpha1
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -101,3 +102,98 @@ func TestKafkaUserSpecValidateAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaUserSpecGetRenewBefore(t *testing.T) {
+	t.Parallel()
+	expirationSeconds := int32(7200)
+	renewBeforeSeconds := int32(600)
+	longRenewBeforeSeconds := int32(7200)
+
+	tests := []struct {
+		name   string
+		spec   KafkaUserSpec
+		wanted time.Duration
+	}{
+		{
+			name:   "default expiration and renew before",
+			spec:   KafkaUserSpec{},
+			wanted: defaultCertificateDuration / 3,
+		},
+		{
+			name:   "renew before derived from expiration",
+			spec:   KafkaUserSpec{ExpirationSeconds: &expirationSeconds},
+			wanted: 40 * time.Minute,
+		},
+		{
+			name:   "explicit renew before",
+			spec:   KafkaUserSpec{ExpirationSeconds: &expirationSeconds, RenewBeforeSeconds: &renewBeforeSeconds},
+			wanted: 10 * time.Minute,
+		},
+		{
+			name:   "renew before exceeding the expiration",
+			spec:   KafkaUserSpec{ExpirationSeconds: &expirationSeconds, RenewBeforeSeconds: &longRenewBeforeSeconds},
+			wanted: 40 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.wanted, tt.spec.GetRenewBefore())
+		})
+	}
+}
+
+func TestKafkaUserSpecGetKeystoreFormats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		spec   KafkaUserSpec
+		wanted []KeystoreFormat
+	}{
+		{
+			name:   "no keystore",
+			spec:   KafkaUserSpec{},
+			wanted: []KeystoreFormat{},
+		},
+		{
+			name:   "includeJKS",
+			spec:   KafkaUserSpec{IncludeJKS: true},
+			wanted: []KeystoreFormat{KeystoreFormatJKS},
+		},
+		{
+			name: "includeJKS merged with keystore formats",
+			spec: KafkaUserSpec{
+				IncludeJKS:      true,
+				KeystoreFormats: []KeystoreFormat{KeystoreFormatPKCS12, KeystoreFormatJKS, KeystoreFormatPKCS12},
+			},
+			wanted: []KeystoreFormat{KeystoreFormatJKS, KeystoreFormatPKCS12},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.DeepEqual(t, tt.wanted, tt.spec.GetKeystoreFormats())
+		})
+	}
+
+	spec := KafkaUserSpec{KeystoreFormats: []KeystoreFormat{KeystoreFormatPEMBundle}}
+	assert.Equal(t, true, spec.HasKeystoreFormat(KeystoreFormatPEMBundle))
+	assert.Equal(t, false, spec.HasKeystoreFormat(KeystoreFormatJKS))
+}
+
+func TestClientBundleSpecDefaults(t *testing.T) {
+	t.Parallel()
+	bundle := ClientBundleSpec{Listener: "internal"}
+	assert.Equal(t, ClientBundleTargetSecret, bundle.GetTarget())
+	assert.Equal(t, "/etc/kafka/secrets", bundle.GetMountPath())
+	assert.Equal(t, "test-secret-client", bundle.GetConfigMapName("test-secret"))
+
+	bundle = ClientBundleSpec{Listener: "internal", Target: ClientBundleTargetConfigMap, MountPath: "/var/run/kafka/"}
+	assert.Equal(t, ClientBundleTargetConfigMap, bundle.GetTarget())
+	assert.Equal(t, "/var/run/kafka", bundle.GetMountPath())
+}
@@ -19,6 +19,8 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // RackAwarenessState stores info about rack awareness status
@@ -47,6 +49,36 @@ type SecurityProtocol string
 // Valid values are: required, requested, none
 type SSLClientAuthentication string
 
+// TLSProtocol is a TLS protocol version enabled on a listener.
+// Valid values are: TLSv1.2, TLSv1.3
+// +kubebuilder:validation:Enum=TLSv1.2;TLSv1.3
+type TLSProtocol string
+
+// CipherSuite is the IANA name of a TLS cipher suite, e.g. TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384
+// +kubebuilder:validation:Pattern=`^TLS_[A-Z0-9_]+$`
+type CipherSuite string
+
+// MigrationPhase is a phase of the ZooKeeper to KRaft migration
+type MigrationPhase string
+
+// KRaftQuorumRole is the role of a node in the KRaft controller quorum
+type KRaftQuorumRole string
+
+// CanaryPhase is a phase of the canary of a rolling upgrade
+type CanaryPhase string
+
+// ConfigurationRolloutPhase is a phase of the rollout of a changed broker configuration
+type ConfigurationRolloutPhase string
+
+// BrokerRestartTrigger is what made the operator restart a broker
+type BrokerRestartTrigger string
+
+// VolumeResizeState is the state of the expansion of a broker volume
+type VolumeResizeState string
+
+// VolumeMigrationPhase is a phase of the migration of the data of a broker volume to a new volume
+type VolumeMigrationPhase string
+
 // PerBrokerConfigurationState holds info about the per-broker configuration state
 type PerBrokerConfigurationState string
 
@@ -85,10 +117,21 @@ func (s CruiseControlVolumeState) IsDiskRemovalRunning() bool {
 		s == GracefulDiskRemovalScheduled
 }
 
-// IsRequiredState returns true if CruiseControlVolumeState is in GracefulDiskRebalanceRequired state or GracefulDiskRemovalRequired state
+// IsOfflineReplicasFixRunning returns true if CruiseControlVolumeState indicates
+// that the CC fix offline replicas operation is scheduled and in-progress
+func (s CruiseControlVolumeState) IsOfflineReplicasFixRunning() bool {
+	return s == GracefulOfflineReplicasFixRunning ||
+		s == GracefulOfflineReplicasFixCompletedWithError ||
+		s == GracefulOfflineReplicasFixPaused ||
+		s == GracefulOfflineReplicasFixScheduled
+}
+
+// IsRequiredState returns true if CruiseControlVolumeState is in GracefulDiskRebalanceRequired state,
+// GracefulDiskRemovalRequired state or GracefulOfflineReplicasFixRequired state
 func (s CruiseControlVolumeState) IsRequiredState() bool {
 	return s == GracefulDiskRebalanceRequired ||
-		s == GracefulDiskRemovalRequired
+		s == GracefulDiskRemovalRequired ||
+		s == GracefulOfflineReplicasFixRequired
 }
 
 // IsDiskRebalance returns true if CruiseControlVolumeState is in disk rebalance state
@@ -102,6 +145,12 @@ func (s CruiseControlVolumeState) IsDiskRemoval() bool {
 	return s.IsDiskRemovalRunning() || s == GracefulDiskRemovalRequired
 }
 
+// IsOfflineReplicasFix returns true if CruiseControlVolumeState is in offline replicas fix state
+// the controller needs to take care of.
+func (s CruiseControlVolumeState) IsOfflineReplicasFix() bool {
+	return s.IsOfflineReplicasFixRunning() || s == GracefulOfflineReplicasFixRequired
+}
+
 // IsUpscale returns true if CruiseControlState in GracefulUpscale* state.
 func (r CruiseControlState) IsUpscale() bool {
 	return r == GracefulUpscaleRequired ||
@@ -171,6 +220,11 @@ func (s CruiseControlVolumeState) IsDiskRemovalSucceeded() bool {
 	return s == GracefulDiskRemovalSucceeded
 }
 
+// IsOfflineReplicasFixSucceeded returns true if CruiseControlVolumeState is offline replicas fix succeeded
+func (s CruiseControlVolumeState) IsOfflineReplicasFixSucceeded() bool {
+	return s == GracefulOfflineReplicasFixSucceeded
+}
+
 // IsSSL determines if the receiver is using SSL
 func (r SecurityProtocol) IsSSL() bool {
 	return r.Equal(SecurityProtocolSaslSSL) || r.Equal(SecurityProtocolSSL)
@@ -221,6 +275,29 @@ type VolumeState struct {
 	CruiseControlVolumeState CruiseControlVolumeState `json:"cruiseControlVolumeState"`
 	// CruiseControlOperationReference refers to the created CruiseControlOperation to execute a CC task
 	CruiseControlOperationReference *corev1.LocalObjectReference `json:"cruiseControlOperationReference,omitempty"`
+	// StorageMigration is the migration of the data of the volume of a replaced storage config to the volume
+	// +optional
+	StorageMigration *VolumeStorageMigration `json:"storageMigration,omitempty"`
+	// OfflineLogDir is the log directory of the volume Kafka marked offline after an I/O error
+	// +optional
+	OfflineLogDir *OfflineLogDir `json:"offlineLogDir,omitempty"`
+}
+
+// OfflineLogDir describes a log directory of a broker volume which is offline
+type OfflineLogDir struct {
+	// Path is the path of the offline log directory
+	Path string `json:"path"`
+	// Since is the time the log directory was first found offline
+	Since metav1.Time `json:"since"`
+}
+
+// VolumeStorageMigration describes the migration of the data of a broker volume to the volume of the storage config
+// replacing it
+type VolumeStorageMigration struct {
+	// MigrateFrom is the mount path of the volume the data is migrated from
+	MigrateFrom string `json:"migrateFrom"`
+	// Phase is the phase of the migration
+	Phase VolumeMigrationPhase `json:"phase"`
 }
 
 // BrokerState holds information about broker state
@@ -239,8 +316,110 @@ type BrokerState struct {
 	Version string `json:"version,omitempty"`
 	// Image specifies the current docker image of the broker
 	Image string `json:"image,omitempty"`
-	// Compressed data from broker configuration to restore broker pod in specific cases
+	// Compressed data from broker configuration to restore broker pod in specific cases. When the configuration
+	// rollback is enabled it is the last broker configuration the broker became ready with, without the cluster-level
+	// settings.
 	ConfigurationBackup string `json:"configurationBackup,omitempty"`
+	// ConfigurationRollout is the rollout of a changed configuration the broker was restarted with
+	// +optional
+	ConfigurationRollout *ConfigurationRolloutStatus `json:"configurationRollout,omitempty"`
+	// RestartHistory are the latest restarts of the broker by the operator, the oldest first
+	// +optional
+	RestartHistory []BrokerRestart `json:"restartHistory,omitempty"`
+	// VolumeResizes are the expansions of the broker volumes by their mount paths
+	// +optional
+	VolumeResizes map[string]VolumeResizeStatus `json:"volumeResizes,omitempty"`
+	// LogFlushForced tells whether the broker flushes the logs of its partitions on every appended message, which is
+	// forced while the volumes of the brokers are snapshotted by a KafkaClusterBackup. The messages appended before it
+	// was forced are not flushed by it
+	// +optional
+	LogFlushForced bool `json:"logFlushForced,omitempty"`
+	// Replacement is the replacement of the broker requested with the replace-broker annotation
+	// +optional
+	Replacement *BrokerReplacementStatus `json:"replacement,omitempty"`
+	// StorageAutoscaling are the last scalings of the broker storage by the mount paths of the autoscaled storage
+	// configs
+	// +optional
+	StorageAutoscaling map[string]StorageAutoscalingStatus `json:"storageAutoscaling,omitempty"`
+}
+
+// StorageAutoscalingStatus describes the last scaling of the storage of a broker by the operator
+type StorageAutoscalingStatus struct {
+	// LastScaleTime is the time the storage was last scaled
+	LastScaleTime metav1.Time `json:"lastScaleTime"`
+	// Mode is how the storage was scaled
+	Mode StorageAutoscalingMode `json:"mode"`
+	// DiskUsagePercentage is the disk usage of the storage reported by Cruise Control which triggered the scaling
+	DiskUsagePercentage int32 `json:"diskUsagePercentage"`
+	// Size is the size the volume was expanded to, or the size of the added disk
+	Size resource.Quantity `json:"size"`
+}
+
+// BrokerReplacementPhase is the phase of the replacement of a broker
+type BrokerReplacementPhase string
+
+const (
+	// BrokerReplacementDeletingVolumes means the pod and the persistent volume claims of the broker are being deleted
+	BrokerReplacementDeletingVolumes BrokerReplacementPhase = "DeletingVolumes"
+	// BrokerReplacementWaitingForBroker means the broker is recreated on new volumes and it has not registered yet
+	BrokerReplacementWaitingForBroker BrokerReplacementPhase = "WaitingForBroker"
+	// BrokerReplacementReplicating means Cruise Control re-replicates the partitions of the broker
+	BrokerReplacementReplicating BrokerReplacementPhase = "Replicating"
+	// BrokerReplacementSucceeded means the broker was replaced
+	BrokerReplacementSucceeded BrokerReplacementPhase = "Succeeded"
+)
+
+// BrokerReplacementStatus describes the replacement of a broker after the loss of its volumes
+type BrokerReplacementStatus struct {
+	// Phase is the phase of the replacement
+	Phase BrokerReplacementPhase `json:"phase"`
+	// StartTime is the time the replacement was requested
+	StartTime metav1.Time `json:"startTime"`
+	// LastTransitionTime is the time the phase of the replacement last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// VolumeResizeStatus describes the expansion of a broker volume to the storage request of its storage config
+type VolumeResizeStatus struct {
+	// State is the state of the expansion
+	State VolumeResizeState `json:"state"`
+	// RequestedSize is the storage request the persistent volume claim is expanded to
+	RequestedSize resource.Quantity `json:"requestedSize"`
+	// Capacity is the capacity of the volume reported by the persistent volume claim
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+	// StartTime is the time the persistent volume claim was patched with the storage request
+	StartTime metav1.Time `json:"startTime"`
+	// LastTransitionTime is the time the state of the expansion last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// Message is the message of the pending file system resize of the volume
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BrokerRestart records a restart of the broker by the operator
+type BrokerRestart struct {
+	// Time is the time the broker pod was deleted
+	Time metav1.Time `json:"time"`
+	// Trigger is what made the operator restart the broker
+	Trigger BrokerRestartTrigger `json:"trigger"`
+	// Diff is a short summary of the changed fields of the broker pod
+	// +optional
+	Diff string `json:"diff,omitempty"`
+}
+
+// ConfigurationRolloutStatus describes the rollout of a changed broker configuration
+type ConfigurationRolloutStatus struct {
+	// Revision is the revision of the configuration the broker was restarted with, the failed revision once the
+	// broker is rolled back
+	Revision string `json:"revision"`
+	// Phase is the phase of the rollout
+	Phase ConfigurationRolloutPhase `json:"phase"`
+	// StartTime is the time the broker was restarted with the configuration
+	StartTime metav1.Time `json:"startTime"`
+	// Message tells why the broker was rolled back
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 const (
@@ -359,6 +538,29 @@ const (
 	// intervention to resolve the issue before resuming.
 	GracefulDiskRebalancePaused CruiseControlVolumeState = "GracefulDiskRebalancePaused"
 
+	// Offline replicas fix cruise control states
+
+	// GracefulOfflineReplicasFixRequired indicates that the log directory of a broker volume is offline and the
+	// replicas it held need to be moved to the healthy log directories of the cluster.
+	// Transition: Required -> Scheduled -> Running -> Succeeded/CompletedWithError/Paused
+	GracefulOfflineReplicasFixRequired CruiseControlVolumeState = "GracefulOfflineReplicasFixRequired"
+	// GracefulOfflineReplicasFixRunning indicates that a Cruise Control fix_offline_replicas operation is actively
+	// executing, the offline replicas are being recreated on the healthy log directories.
+	GracefulOfflineReplicasFixRunning CruiseControlVolumeState = "GracefulOfflineReplicasFixRunning"
+	// GracefulOfflineReplicasFixSucceeded indicates that the offline replicas of the volume were moved to the healthy
+	// log directories. The volume is rebalanced once its log directory is back online.
+	GracefulOfflineReplicasFixSucceeded CruiseControlVolumeState = "GracefulOfflineReplicasFixSucceeded"
+	// GracefulOfflineReplicasFixScheduled indicates that a CruiseControlOperation resource has been created
+	// for the offline replicas fix and is waiting in the queue for execution.
+	GracefulOfflineReplicasFixScheduled CruiseControlVolumeState = "GracefulOfflineReplicasFixScheduled"
+	// GracefulOfflineReplicasFixCompletedWithError indicates that the offline replicas fix finished but
+	// encountered errors during execution. The operation may be retried automatically depending
+	// on the error type and retry policy configuration.
+	GracefulOfflineReplicasFixCompletedWithError CruiseControlVolumeState = "GracefulOfflineReplicasFixCompletedWithError"
+	// GracefulOfflineReplicasFixPaused indicates that the offline replicas fix encountered an error and has been
+	// paused. The operation will not be automatically retried and requires manual intervention.
+	GracefulOfflineReplicasFixPaused CruiseControlVolumeState = "GracefulOfflineReplicasFixPaused"
+
 	// CruiseControlTopicNotReady indicates that the Cruise Control metrics topic has not been created yet.
 	// This internal topic is required for CC to collect and store broker metrics. Operations cannot
 	// proceed until this topic is successfully created and ready.
@@ -433,4 +635,95 @@ const (
 
 	// SSLClientAuthRequired states that the client authentication is required when SSL is enabled
 	SSLClientAuthRequired SSLClientAuthentication = "required"
+
+	// MigrationPhaseControllersDeploying states that the KRaft controllers are deployed in migration mode
+	MigrationPhaseControllersDeploying MigrationPhase = "ControllersDeploying"
+	// MigrationPhaseBrokersDualWrite states that the brokers are rolled into dual-write mode
+	MigrationPhaseBrokersDualWrite MigrationPhase = "BrokersDualWrite"
+	// MigrationPhaseMetadataMigrating states that the metadata is being copied from ZooKeeper to the KRaft controllers
+	MigrationPhaseMetadataMigrating MigrationPhase = "MetadataMigrating"
+	// MigrationPhaseBrokersKRaft states that the brokers are rolled into KRaft mode
+	MigrationPhaseBrokersKRaft MigrationPhase = "BrokersKRaft"
+	// MigrationPhaseFinalizing states that the KRaft controllers are rolled without ZooKeeper
+	MigrationPhaseFinalizing MigrationPhase = "Finalizing"
+	// MigrationPhaseCompleted states that the cluster runs in KRaft mode without ZooKeeper
+	MigrationPhaseCompleted MigrationPhase = "Completed"
+	// MigrationPhaseRollbackBrokersDualWrite states that the brokers are rolled back from KRaft to dual-write mode
+	MigrationPhaseRollbackBrokersDualWrite MigrationPhase = "RollbackBrokersDualWrite"
+	// MigrationPhaseRollbackBrokersZooKeeper states that the brokers are rolled back to ZooKeeper mode
+	MigrationPhaseRollbackBrokersZooKeeper MigrationPhase = "RollbackBrokersZooKeeper"
+	// MigrationPhaseRolledBack states that the brokers run in ZooKeeper mode again
+	MigrationPhaseRolledBack MigrationPhase = "RolledBack"
+
+	// KRaftQuorumRoleVoter is the role of the controllers which vote for the quorum leader
+	KRaftQuorumRoleVoter KRaftQuorumRole = "Voter"
+	// KRaftQuorumRoleObserver is the role of the nodes which replicate the metadata log without voting
+	KRaftQuorumRoleObserver KRaftQuorumRole = "Observer"
+
+	// CanaryPhaseRestarting states that the canary brokers are being restarted
+	CanaryPhaseRestarting CanaryPhase = "Restarting"
+	// CanaryPhaseSoaking states that the canary brokers are watched before the rest of the brokers are restarted
+	CanaryPhaseSoaking CanaryPhase = "Soaking"
+	// CanaryPhasePassed states that the canary brokers stayed healthy, the rest of the brokers are restarted
+	CanaryPhasePassed CanaryPhase = "Passed"
+	// CanaryPhaseFailed states that the canary brokers broke the thresholds and the rolling upgrade is halted
+	CanaryPhaseFailed CanaryPhase = "Failed"
+
+	// ConfigurationRolloutVerifying states that the broker is watched until it becomes ready with the configuration
+	ConfigurationRolloutVerifying ConfigurationRolloutPhase = "Verifying"
+	// ConfigurationRolloutRolledBack states that the broker did not become ready with the configuration, it is
+	// rendered from its configuration backup and the rolling upgrade is halted
+	ConfigurationRolloutRolledBack ConfigurationRolloutPhase = "RolledBack"
+
+	// BrokerRestartPodSpecChanged states that the broker pod was restarted as its spec changed
+	BrokerRestartPodSpecChanged BrokerRestartTrigger = "PodSpecChanged"
+	// BrokerRestartConfigOutOfSync states that the broker pod was restarted to apply its read-only configuration
+	BrokerRestartConfigOutOfSync BrokerRestartTrigger = "ConfigOutOfSync"
+	// BrokerRestartTainted states that the broker pod was restarted as it matched the tainted brokers selector
+	BrokerRestartTainted BrokerRestartTrigger = "TaintedBrokersSelector"
+	// BrokerRestartContainerTerminated states that the broker pod was restarted as a container of it terminated
+	BrokerRestartContainerTerminated BrokerRestartTrigger = "ContainerTerminated"
+	// BrokerRestartPodEvicted states that the broker pod was restarted as it was evicted
+	BrokerRestartPodEvicted BrokerRestartTrigger = "PodEvicted"
+	// BrokerRestartNodeShutdown states that the broker pod was restarted as it was terminated by the shutdown of its node
+	BrokerRestartNodeShutdown BrokerRestartTrigger = "NodeShutdown"
+	// BrokerRestartRequested states that the broker pod was restarted as requested by the restart-brokers annotation
+	BrokerRestartRequested BrokerRestartTrigger = "RestartRequested"
+	// BrokerRestartFileSystemResize states that the broker pod was restarted to finish the offline file system resize
+	// of an expanded volume
+	BrokerRestartFileSystemResize BrokerRestartTrigger = "FileSystemResize"
+
+	// VolumeResizeInProgress states that the persistent volume claim is patched and its volume is being expanded
+	VolumeResizeInProgress VolumeResizeState = "InProgress"
+	// VolumeResizeFileSystemResizePending states that the volume is expanded and its file system waits to be resized
+	// on the node of the broker
+	VolumeResizeFileSystemResizePending VolumeResizeState = "FileSystemResizePending"
+	// VolumeResizeRestartRequired states that the file system of the volume can only be resized offline, the broker
+	// is restarted by the rolling upgrade
+	VolumeResizeRestartRequired VolumeResizeState = "RestartRequired"
+	// VolumeResizeSucceeded states that the capacity of the volume reached the requested size
+	VolumeResizeSucceeded VolumeResizeState = "Succeeded"
+
+	// VolumeMigrationWaiting states that the broker keeps the volume migrated from while another broker is migrated
+	VolumeMigrationWaiting VolumeMigrationPhase = "Waiting"
+	// VolumeMigrationAddingVolume states that the volume is added to the broker as an extra JBOD disk
+	VolumeMigrationAddingVolume VolumeMigrationPhase = "AddingVolume"
+	// VolumeMigrationDraining states that the volume migrated from is drained by Cruise Control and deleted afterwards
+	VolumeMigrationDraining VolumeMigrationPhase = "Draining"
+	// VolumeMigrationSucceeded states that the volume migrated from is deleted
+	VolumeMigrationSucceeded VolumeMigrationPhase = "Succeeded"
+
+	// RollingUpgradeHaltedCondition states that the rolling upgrade is halted by a failed canary or a broker rolled
+	// back to its previous configuration
+	RollingUpgradeHaltedCondition = "RollingUpgradeHalted"
+	// ForeignDataDirectoryCondition states that brokers refuse to start because the meta.properties of a log dir belongs
+	// to another broker or cluster. The cluster id is only checked in KRaft mode: the operator does not know the
+	// cluster id ZooKeeper generated, so the data directory of a broker with the same id of another ZooKeeper based
+	// cluster is not detected
+	ForeignDataDirectoryCondition = "ForeignDataDirectory"
+
+	// TLSProtocolV12 enables TLS 1.2
+	TLSProtocolV12 TLSProtocol = "TLSv1.2"
+	// TLSProtocolV13 enables TLS 1.3
+	TLSProtocolV13 TLSProtocol = "TLSv1.3"
 )
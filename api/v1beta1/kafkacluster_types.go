@@ -20,6 +20,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 
@@ -42,12 +43,41 @@ const (
 	KafkaCRLabelKey  = "kafka_cr"
 	BrokerIdLabelKey = "brokerId"
 
+	// RestartBrokersAnnotationKey requests a restart of brokers through the rolling upgrade, so its health gates apply.
+	// Its value is a comma separated list of broker ids or "all". The annotation is removed once the brokers are restarted.
+	RestartBrokersAnnotationKey = "kafka.banzaicloud.io/restart-brokers"
+	// RestartAllBrokers is the value of the restart-brokers annotation which restarts every broker
+	RestartAllBrokers = "all"
+
+	// FlushBrokersAnnotationKey makes the brokers flush the logs of their partitions on every appended message. Its value
+	// is the name of the KafkaClusterBackup which set it, the annotation is removed once the volumes are snapshotted.
+	// The flush is best effort, the messages appended before the annotation is applied are not flushed by it.
+	FlushBrokersAnnotationKey = "kafka.banzaicloud.io/flush-brokers"
+
+	// ReplaceBrokerAnnotationKey requests the replacement of a broker whose volumes are lost: its pod and persistent
+	// volume claims are deleted, the broker is recreated with the same id on new volumes and Cruise Control re-replicates
+	// its partitions. Its value is the id of a broker, KRaft controllers cannot be replaced. The annotation is removed
+	// once the broker is replaced.
+	ReplaceBrokerAnnotationKey = "kafka.banzaicloud.io/replace-broker"
+
+	// MountPathAnnotationKey is the annotation of the broker persistent volume claims with the mount path of their
+	// storage config
+	MountPathAnnotationKey = "mountPath"
+
+	// RetainedPvcAnnotationKey marks a broker persistent volume claim retained by the persistentVolumeClaimRetentionPolicy
+	// with the time it was retained. It is removed when a broker with the same id adopts the claim again.
+	RetainedPvcAnnotationKey = "kafka.banzaicloud.io/retained"
+
 	// ProcessRolesKey is used to identify which process roles the Kafka pod has
 	ProcessRolesKey = "processRoles"
 
 	// PvcRolesKey is used to identify which process roles a PVC serves (broker, controller, or broker_controller)
 	PvcRolesKey = "pvcRoles"
 
+	// BrokerTemplateLabelKey marks the brokers derived from the broker template, it is set in the broker labels of the
+	// brokers added from the template so their pods are selected by the scale subresource
+	BrokerTemplateLabelKey = "kafka.banzaicloud.io/broker-template"
+
 	// IsBrokerNodeKey is used to identify if the kafka pod is either a broker or a broker_controller
 	IsBrokerNodeKey = "isBrokerNode"
 
@@ -133,6 +163,19 @@ const (
 	// KafkaBroker.spec.container["kafka"].image
 	defaultKafkaImage = "ghcr.io/adobe/koperator/kafka:2.13-3.9.2-jdk21.0.11" // renovate: datasource=docker depName=ghcr.io/adobe/koperator/kafka
 
+	// KafkaCluster.spec.rollingUpgradeConfig.controllerQuorumMaxLag
+	defaultControllerQuorumMaxLag = 1000
+	// KafkaCluster.spec.rollingUpgradeConfig.canary.soakPeriod
+	defaultCanarySoakPeriod = 10 * time.Minute
+
+	defaultConfigRollbackReadyTimeout         = 10 * time.Minute
+	defaultConfigRollbackMaxContainerRestarts = 3
+
+	// KafkaCluster.spec.offlineLogDirs.checkInterval
+	defaultOfflineLogDirsCheckInterval = 5 * time.Minute
+	// StorageConfig.autoscaling.cooldown
+	defaultStorageAutoscalingCooldown = 30 * time.Minute
+
 	/* Monitor Config */
 
 	// KafkaBrokerPod.spec.initContainer["jmx-exporter"].command
@@ -146,7 +189,13 @@ type KafkaClusterSpec struct {
 	// This is default to be true; if set to false, the Kafka cluster is in ZooKeeper mode.
 	// +kubebuilder:default=false
 	// +optional
-	KRaftMode              bool `json:"kRaft"`
+	KRaftMode bool `json:"kRaft"`
+	// kRaftDynamicQuorum makes the controllers form a dynamic KRaft quorum (KIP-853, Kafka 3.9 or later).
+	// Controllers join the quorum as observers and are added to or removed from the voters by the operator,
+	// so changing the controllers does not roll the whole cluster. It can only be set when the cluster is created,
+	// and it is refused when the tag of the cluster image names an older Kafka release.
+	// +optional
+	KRaftDynamicQuorum     bool `json:"kRaftDynamicQuorum,omitempty"`
 	HeadlessServiceEnabled bool `json:"headlessServiceEnabled"`
 	// localDebugEnabled is used to decide whether to create a separate loadbalancer services for the
 	// Kafka and Cruise Control Pods. These services will expose the internal listener ports of the Kafka
@@ -209,6 +258,322 @@ type KafkaClusterSpec struct {
 	// The secret must contain the keystore, truststore jks files and the password for them in base64 encoded format
 	// under the keystore.jks, truststore.jks, password data fields.
 	ClientSSLCertSecret *corev1.LocalObjectReference `json:"clientSSLCertSecret,omitempty"`
+	// Migration drives the migration of a ZooKeeper based cluster to KRaft. The phases of the migration are
+	// tracked in status.migration and each of them waits for the previous one to become healthy.
+	// +optional
+	Migration *MigrationConfig `json:"migration,omitempty"`
+	// AutoFinalizeVersion finalizes Kafka version upgrades once every broker runs the same Kafka release: the
+	// metadata.version feature of a KRaft cluster is upgraded to the highest level the brokers support, the
+	// inter.broker.protocol.version of a ZooKeeper based cluster is set to the release and the brokers are rolled.
+	// Enabling it on a ZooKeeper based cluster rolls the brokers once to pin inter.broker.protocol.version to the
+	// lowest release they run, before any of them is rolled with a new image, and the pinned version is advanced only
+	// when the upgrade is finalized. A finalized upgrade cannot be rolled back to an older Kafka image.
+	// +optional
+	AutoFinalizeVersion bool `json:"autoFinalizeVersion,omitempty"`
+	// DryRun pauses the reconciliation of the cluster: no resource is changed, the operator only writes the plan of
+	// the spec into the <cluster name>-rolling-upgrade-plan ConfigMap. The plan lists the brokers which would be
+	// restarted and the ones whose configs would be updated dynamically, with the reasons.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+	// MaintenanceWindows restrict the disruptive operations to the given time windows: the broker pods are only
+	// deleted for a rolling upgrade and the queued Cruise Control rebalances are only started while a window is open.
+	// Pods with terminated or evicted containers are still restarted, and the Cruise Control operations which add or
+	// remove brokers or disks, including the disk rebalances the operator creates for the added disks, are still
+	// started. The operations are not restricted when no window is given.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// RestoreFromBackup is the name of a completed KafkaClusterBackup in the namespace of the cluster. The persistent
+	// volume claims of the brokers are created from the volume snapshots the backup took of the volumes with the same
+	// broker ids and mount paths. Existing persistent volume claims are left unchanged. A KRaft cluster is formatted
+	// with the cluster id recorded in the backup.
+	// +optional
+	RestoreFromBackup string `json:"restoreFromBackup,omitempty"`
+	// PersistentVolumeClaimRetentionPolicy tells what happens to the persistent volume claims of the brokers removed
+	// from the spec and of the brokers of the deleted cluster. They are deleted unless retained.
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *PersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+	// TieredStorage enables tiered storage (KIP-405, Kafka 3.6 or later) on the brokers: the log segments of the topics
+	// with remote storage enabled are offloaded to the remote storage once they are rolled.
+	// +optional
+	TieredStorage *TieredStorageConfig `json:"tieredStorage,omitempty"`
+	// OfflineLogDirs enables the periodic detection of the broker log directories Kafka marked offline after an I/O
+	// error. The offline log directories are recorded in the volume states of the brokers and handled according to
+	// the policy. They are only detected while Cruise Control is available.
+	// +optional
+	OfflineLogDirs *OfflineLogDirsConfig `json:"offlineLogDirs,omitempty"`
+	// BrokerTemplate derives brokers from a broker config group, their number is the replicas of the scale subresource
+	// of the cluster so it can be driven by a HorizontalPodAutoscaler or KEDA. The operator adds the brokers to and
+	// removes them from the brokers list, the removed brokers are downscaled gracefully by Cruise Control.
+	// +optional
+	BrokerTemplate *BrokerTemplate `json:"brokerTemplate,omitempty"`
+}
+
+// OfflineLogDirsPolicy tells how the operator handles an offline log directory of a broker
+// +kubebuilder:validation:Enum=Report;MoveReplicas;RecreateVolume
+type OfflineLogDirsPolicy string
+
+const (
+	// OfflineLogDirsPolicyReport only records the offline log directories in the volume states of the brokers
+	OfflineLogDirsPolicyReport OfflineLogDirsPolicy = "Report"
+	// OfflineLogDirsPolicyMoveReplicas moves the offline replicas to the healthy log directories with a Cruise
+	// Control fix_offline_replicas operation, the volume is rebalanced once its log directory is back online
+	OfflineLogDirsPolicyMoveReplicas OfflineLogDirsPolicy = "MoveReplicas"
+	// OfflineLogDirsPolicyRecreateVolume deletes the persistent volume claim of the offline log directory together
+	// with the broker pod, the broker is restarted on a new volume and its replicas are re-replicated by Kafka. The
+	// volume is only recreated when the rolling upgrade failure threshold is not reached and no partition of the
+	// broker would go offline or under min ISR without it
+	OfflineLogDirsPolicyRecreateVolume OfflineLogDirsPolicy = "RecreateVolume"
+)
+
+// OfflineLogDirsConfig defines how the offline log directories of the brokers are detected and handled
+type OfflineLogDirsConfig struct {
+	// Policy tells how an offline log directory is handled. Default value is Report.
+	// +kubebuilder:default=Report
+	// +optional
+	Policy OfflineLogDirsPolicy `json:"policy,omitempty"`
+	// CheckInterval is how often the log directories of the brokers are checked. Default value is 5m.
+	// +optional
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty"`
+}
+
+// GetPolicy returns how an offline log directory is handled
+func (c *OfflineLogDirsConfig) GetPolicy() OfflineLogDirsPolicy {
+	if c.Policy == "" {
+		return OfflineLogDirsPolicyReport
+	}
+	return c.Policy
+}
+
+// GetCheckInterval returns how often the log directories of the brokers are checked
+func (c *OfflineLogDirsConfig) GetCheckInterval() time.Duration {
+	if c.CheckInterval == nil {
+		return defaultOfflineLogDirsCheckInterval
+	}
+	return c.CheckInterval.Duration
+}
+
+// BrokerIDAllocationStrategy tells how the ids of the brokers added from the broker template are chosen
+// +kubebuilder:validation:Enum=LowestAvailable;Sequential
+type BrokerIDAllocationStrategy string
+
+const (
+	// BrokerIDAllocationLowestAvailable gives the added broker the lowest id from the first id which is not used
+	BrokerIDAllocationLowestAvailable BrokerIDAllocationStrategy = "LowestAvailable"
+	// BrokerIDAllocationSequential gives the added broker the id following the highest id used
+	BrokerIDAllocationSequential BrokerIDAllocationStrategy = "Sequential"
+)
+
+// BrokerTemplate defines the brokers derived from a broker config group. The brokers added from the template carry the
+// kafka.banzaicloud.io/broker-template broker label, only they are derived from the template: they are counted in its
+// replicas, selected by the scale subresource and removed when it is scaled down. The brokers of the broker config
+// group listed by hand are left alone.
+type BrokerTemplate struct {
+	// Replicas is the number of brokers derived from the template. It is set to the current number of the brokers
+	// derived from the template when not given.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// BrokerConfigGroup is the broker config group of the brokers derived from the template
+	// +kubebuilder:validation:MinLength=1
+	BrokerConfigGroup string `json:"brokerConfigGroup"`
+	// IDAllocation tells how the ids of the added brokers are chosen
+	// +optional
+	IDAllocation BrokerIDAllocation `json:"idAllocation,omitempty"`
+}
+
+// BrokerIDAllocation defines how the ids of the brokers added from the broker template are chosen
+type BrokerIDAllocation struct {
+	// Strategy tells how the id of an added broker is chosen. Default value is LowestAvailable.
+	// +kubebuilder:default=LowestAvailable
+	// +optional
+	Strategy BrokerIDAllocationStrategy `json:"strategy,omitempty"`
+	// FirstID is the lowest id of the brokers added from the template
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:ExclusiveMaximum=true
+	// +optional
+	FirstID int32 `json:"firstId,omitempty"`
+}
+
+// GetStrategy returns how the id of a broker added from the broker template is chosen
+func (a BrokerIDAllocation) GetStrategy() BrokerIDAllocationStrategy {
+	if a.Strategy == "" {
+		return BrokerIDAllocationLowestAvailable
+	}
+	return a.Strategy
+}
+
+// IsDerived returns true if the broker is derived from the broker template
+func (t *BrokerTemplate) IsDerived(broker Broker) bool {
+	return broker.BrokerConfigGroup == t.BrokerConfigGroup && broker.BrokerConfig != nil &&
+		broker.BrokerConfig.BrokerLabels[BrokerTemplateLabelKey] == "true"
+}
+
+// NewBroker returns a broker derived from the broker template
+func (t *BrokerTemplate) NewBroker(id int32) Broker {
+	return Broker{
+		Id:                id,
+		BrokerConfigGroup: t.BrokerConfigGroup,
+		BrokerConfig:      &BrokerConfig{BrokerLabels: map[string]string{BrokerTemplateLabelKey: "true"}},
+	}
+}
+
+// BrokerTemplateStatus describes the brokers derived from the broker template, it is the status of the scale subresource
+type BrokerTemplateStatus struct {
+	// Replicas is the number of brokers derived from the template in the brokers list
+	Replicas int32 `json:"replicas"`
+	// Selector is the label selector of the pods of the brokers derived from the template
+	Selector string `json:"selector,omitempty"`
+}
+
+// TieredStorageConfig defines the remote storage the brokers offload the log segments to
+type TieredStorageConfig struct {
+	// RemoteStorageManagerClassName is the class of the remote storage manager plugin,
+	// e.g. io.aiven.kafka.tieredstorage.RemoteStorageManager
+	// +kubebuilder:validation:MinLength=1
+	RemoteStorageManagerClassName string `json:"remoteStorageManagerClassName"`
+	// RemoteStorageManagerClassPath is the class path the remote storage manager plugin is loaded from. Default value is
+	// the directory the plugin image is copied to when pluginImage is given, otherwise the plugin is loaded from the
+	// class path of the broker.
+	// +optional
+	RemoteStorageManagerClassPath string `json:"remoteStorageManagerClassPath,omitempty"`
+	// PluginImage is the image holding the jars of the remote storage manager plugin, they are copied into the broker
+	// pods by an init container. The plugin can also be added by the initContainers of the broker config.
+	// +optional
+	PluginImage string `json:"pluginImage,omitempty"`
+	// PluginPath is the directory of the jars in the plugin image. Default value is /tiered-storage.
+	// +optional
+	PluginPath string `json:"pluginPath,omitempty"`
+	// RemoteStorageManagerConfigSecret is a reference to the secret holding the configuration of the remote storage
+	// manager plugin in the namespace of the cluster. Every key of the secret is passed to the plugin under the
+	// rsm.config. prefix, the values are read from the secret mounted into the broker pods so they are not written into
+	// the broker configuration. Changed values are picked up when the brokers are restarted.
+	// +optional
+	RemoteStorageManagerConfigSecret *corev1.LocalObjectReference `json:"remoteStorageManagerConfigSecret,omitempty"`
+	// RemoteLogMetadataManagerClassName is the class of the remote log metadata manager. Default value is the topic
+	// based remote log metadata manager of Kafka which uses the inter broker listener.
+	// +optional
+	RemoteLogMetadataManagerClassName string `json:"remoteLogMetadataManagerClassName,omitempty"`
+	// LocalRetentionMs is the default time the log segments offloaded to the remote storage are kept on the broker
+	// volumes, -2 keeps them for the whole retention of the topic. Topics can override it with local.retention.ms.
+	// +kubebuilder:validation:Minimum=-2
+	// +optional
+	LocalRetentionMs *int64 `json:"localRetentionMs,omitempty"`
+	// LocalRetentionBytes is the default size of the log segments offloaded to the remote storage kept on the broker
+	// volumes per partition, -2 keeps them for the whole retention of the topic. Topics can override it with
+	// local.retention.bytes.
+	// +kubebuilder:validation:Minimum=-2
+	// +optional
+	LocalRetentionBytes *int64 `json:"localRetentionBytes,omitempty"`
+}
+
+// GetPluginPath returns the directory of the jars in the plugin image
+func (t *TieredStorageConfig) GetPluginPath() string {
+	if t.PluginPath == "" {
+		return "/tiered-storage"
+	}
+	return t.PluginPath
+}
+
+// IsTieredStorageEnabled tells whether the brokers offload the log segments to remote storage
+func (s KafkaClusterSpec) IsTieredStorageEnabled() bool {
+	return s.TieredStorage != nil
+}
+
+// PersistentVolumeClaimRetentionPolicyType tells whether the persistent volume claims of the brokers are retained or
+// deleted
+// +kubebuilder:validation:Enum=Retain;Delete
+type PersistentVolumeClaimRetentionPolicyType string
+
+const (
+	// RetainPersistentVolumeClaimRetentionPolicyType keeps the persistent volume claims with the retained annotation,
+	// a broker with the same id adopts them again
+	RetainPersistentVolumeClaimRetentionPolicyType PersistentVolumeClaimRetentionPolicyType = "Retain"
+	// DeletePersistentVolumeClaimRetentionPolicyType deletes the persistent volume claims
+	DeletePersistentVolumeClaimRetentionPolicyType PersistentVolumeClaimRetentionPolicyType = "Delete"
+)
+
+// PersistentVolumeClaimRetentionPolicy defines the lifecycle of the persistent volume claims of the brokers
+type PersistentVolumeClaimRetentionPolicy struct {
+	// WhenScaled is what happens to the persistent volume claims of a broker removed from the spec. Retained ones are
+	// still owned by the cluster, so whenDeleted applies to them.
+	// +kubebuilder:default=Delete
+	// +optional
+	WhenScaled PersistentVolumeClaimRetentionPolicyType `json:"whenScaled,omitempty"`
+	// WhenDeleted is what happens to the persistent volume claims of the brokers when the cluster is deleted. Retained
+	// ones are released from the ownership of the cluster, which is not possible with foreground cascading deletion.
+	// +kubebuilder:default=Delete
+	// +optional
+	WhenDeleted PersistentVolumeClaimRetentionPolicyType `json:"whenDeleted,omitempty"`
+}
+
+// RetainWhenScaled tells whether the persistent volume claims of the brokers removed from the spec are retained
+func (p *PersistentVolumeClaimRetentionPolicy) RetainWhenScaled() bool {
+	return p != nil && p.WhenScaled == RetainPersistentVolumeClaimRetentionPolicyType
+}
+
+// RetainWhenDeleted tells whether the persistent volume claims of the brokers are retained when the cluster is deleted
+func (p *PersistentVolumeClaimRetentionPolicy) RetainWhenDeleted() bool {
+	return p != nil && p.WhenDeleted == RetainPersistentVolumeClaimRetentionPolicyType
+}
+
+// MaintenanceWindow defines a recurring time window for the disruptive operations
+type MaintenanceWindow struct {
+	// Schedule is a cron expression of when the window opens, with the minute, hour, day of month, month and day of
+	// week fields, e.g. "0 2 * * SAT" opens the window at 2 AM every Saturday
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// Duration is how long the window stays open
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone is the name of the IANA time zone the schedule is interpreted in, e.g. "Europe/Berlin". Default value is UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// MigrationConfig defines the desired state of the ZooKeeper to KRaft migration.
+// The migration requires kRaft to be enabled, the ZooKeeper connection to be kept configured and
+// controller-only nodes to be added to the brokers.
+type MigrationConfig struct {
+	// Enabled starts the migration and lets it advance through its phases
+	Enabled bool `json:"enabled"`
+	// Finalize lets the migration drop ZooKeeper once the brokers run in KRaft mode.
+	// Finalization is the point of no return: the migration cannot be rolled back afterwards
+	// +optional
+	Finalize bool `json:"finalize,omitempty"`
+	// Rollback rolls the brokers back to ZooKeeper mode. It is honored until the migration is finalized
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// MigrationStatus describes the progress of the ZooKeeper to KRaft migration
+type MigrationStatus struct {
+	Phase MigrationPhase `json:"phase"`
+	// Message tells what the current phase waits for
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the time the migration entered the current phase
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// IsInProgress returns whether the brokers are still being migrated to KRaft or rolled back to ZooKeeper
+func (s *MigrationStatus) IsInProgress() bool {
+	return s != nil && s.Phase != "" && s.Phase != MigrationPhaseCompleted && s.Phase != MigrationPhaseRolledBack
+}
+
+// IsRollback returns whether the phase belongs to the rollback of the migration
+func (p MigrationPhase) IsRollback() bool {
+	return p == MigrationPhaseRollbackBrokersDualWrite || p == MigrationPhaseRollbackBrokersZooKeeper || p == MigrationPhaseRolledBack
+}
+
+// IsRollbackAllowed returns whether the migration can still be rolled back from the phase
+func (p MigrationPhase) IsRollbackAllowed() bool {
+	switch p {
+	case MigrationPhaseControllersDeploying, MigrationPhaseBrokersDualWrite, MigrationPhaseMetadataMigrating, MigrationPhaseBrokersKRaft:
+		return true
+	default:
+		return false
+	}
 }
 
 // KafkaClusterStatus defines the observed state of KafkaCluster
@@ -221,6 +586,108 @@ type KafkaClusterStatus struct {
 	ListenerStatuses         ListenerStatuses         `json:"listenerStatuses,omitempty"`
 	// ClusterID is a base64-encoded random UUID generated by Koperator to run the Kafka cluster in KRaft mode
 	ClusterID string `json:"clusterID,omitempty"`
+	// Migration is the state of the ZooKeeper to KRaft migration
+	Migration *MigrationStatus `json:"migration,omitempty"`
+	// KRaftQuorum is the state of the KRaft controller quorum
+	KRaftQuorum *KRaftQuorumStatus `json:"kRaftQuorum,omitempty"`
+	// Features are the feature flags finalized in the KRaft cluster
+	Features *FeaturesStatus `json:"features,omitempty"`
+	// VersionUpgrade is the state of the Kafka version upgrade finalization
+	VersionUpgrade *VersionUpgradeStatus `json:"versionUpgrade,omitempty"`
+	// RestartRequest is the restart of brokers requested with the kafka.banzaicloud.io/restart-brokers annotation
+	// +optional
+	RestartRequest *RestartRequestStatus `json:"restartRequest,omitempty"`
+	// Maintenance describes the disruptive operations waiting for the next maintenance window
+	// +optional
+	Maintenance *MaintenanceStatus `json:"maintenance,omitempty"`
+	// BrokerTemplate describes the brokers derived from the broker template
+	// +optional
+	BrokerTemplate *BrokerTemplateStatus `json:"brokerTemplate,omitempty"`
+	// Conditions are the latest observations of the state of the cluster
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RestartRequestStatus describes a restart of brokers requested with the kafka.banzaicloud.io/restart-brokers annotation
+type RestartRequestStatus struct {
+	// Brokers is the value of the annotation, a comma separated list of broker ids or "all"
+	Brokers string `json:"brokers"`
+	// RequestTime is when the request was observed, the requested brokers running in pods created before it are restarted
+	RequestTime metav1.Time `json:"requestTime"`
+}
+
+// MaintenanceStatus describes the disruptive operations waiting for the next maintenance window
+type MaintenanceStatus struct {
+	// NextWindow is when the next maintenance window opens
+	// +optional
+	NextWindow *metav1.Time `json:"nextWindow,omitempty"`
+	// RollingUpgradeWaiting tells whether the rolling upgrade waits for the next maintenance window to delete broker pods
+	// +optional
+	RollingUpgradeWaiting bool `json:"rollingUpgradeWaiting,omitempty"`
+	// WaitingCruiseControlOperations are the names of the CruiseControlOperations waiting for the next maintenance window
+	// +optional
+	WaitingCruiseControlOperations []string `json:"waitingCruiseControlOperations,omitempty"`
+}
+
+// VersionUpgradeStatus describes the Kafka release the cluster runs and the one it is finalized at
+type VersionUpgradeStatus struct {
+	// Release is the Kafka release every broker runs, empty while the brokers run different releases
+	// +optional
+	Release string `json:"release,omitempty"`
+	// FinalizedRelease is the Kafka release the cluster is finalized at, the brokers cannot be downgraded below it. The
+	// inter.broker.protocol.version of a ZooKeeper based cluster is pinned to it
+	// +optional
+	FinalizedRelease string `json:"finalizedRelease,omitempty"`
+}
+
+// KRaftQuorumStatus describes the KRaft controller quorum
+type KRaftQuorumStatus struct {
+	// BootstrapControllers are the controllers listed in controller.quorum.bootstrap.servers of a dynamic quorum.
+	// Controllers added later are not listed so adding them does not change the configuration of the other nodes
+	BootstrapControllers []int32 `json:"bootstrapControllers,omitempty"`
+	// Voters are the controllers which are voters of the quorum
+	Voters []int32 `json:"voters,omitempty"`
+	// LeaderID is the id of the controller which leads the quorum, -1 when there is no leader
+	// +optional
+	LeaderID *int32 `json:"leaderId,omitempty"`
+	// LeaderEpoch is the epoch of the current leader
+	// +optional
+	LeaderEpoch int32 `json:"leaderEpoch,omitempty"`
+	// HighWatermark is the high watermark of the metadata log when the voters, the observers or the leader last changed
+	// +optional
+	HighWatermark int64 `json:"highWatermark,omitempty"`
+	// Replicas are the replication states of the voters and observers of the metadata log, they are not refreshed
+	// while the quorum is unchanged
+	// +optional
+	Replicas []KRaftQuorumReplicaStatus `json:"replicas,omitempty"`
+}
+
+// KRaftQuorumReplicaStatus describes how a voter or observer replicates the metadata log
+type KRaftQuorumReplicaStatus struct {
+	// ID is the node id of the replica
+	ID int32 `json:"id"`
+	// Role tells whether the replica is a voter or an observer of the quorum
+	Role KRaftQuorumRole `json:"role"`
+	// Lag is the number of metadata records the replica is behind the leader
+	Lag int64 `json:"lag"`
+	// LastFetchTime is the time the replica last fetched from the leader
+	// +optional
+	LastFetchTime *metav1.Time `json:"lastFetchTime,omitempty"`
+	// LastCaughtUpTime is the last time the replica was caught up with the leader
+	// +optional
+	LastCaughtUpTime *metav1.Time `json:"lastCaughtUpTime,omitempty"`
+}
+
+// FeaturesStatus describes the feature flags finalized in the cluster
+type FeaturesStatus struct {
+	// MetadataVersion is the finalized metadata.version of the cluster
+	// +optional
+	MetadataVersion int16 `json:"metadataVersion,omitempty"`
+	// Finalized are the finalized version levels of the features
+	// +optional
+	Finalized map[string]int16 `json:"finalized,omitempty"`
 }
 
 // RollingUpgradeStatus defines status of rolling upgrade
@@ -229,6 +696,27 @@ type RollingUpgradeStatus struct {
 	// ErrorCount keeps track the number of errors reported by alerts labeled with 'rollingupgrade'.
 	// It's reset once these alerts stop firing.
 	ErrorCount int `json:"errorCount"`
+	// Canary is the state of the canary phase of the current rolling upgrade
+	// +optional
+	Canary *CanaryStatus `json:"canary,omitempty"`
+}
+
+// CanaryStatus describes the canary phase of a rolling upgrade
+type CanaryStatus struct {
+	// Generation is the generation of the KafkaCluster the canary brokers are restarted with. A new canary is
+	// started, and a halted rolling upgrade is resumed, when the spec changes.
+	Generation int64 `json:"generation"`
+	// Phase is the phase of the canary
+	Phase CanaryPhase `json:"phase"`
+	// Brokers are the ids of the canary brokers
+	// +optional
+	Brokers []string `json:"brokers,omitempty"`
+	// SoakStartTime is the time the canary brokers became ready
+	// +optional
+	SoakStartTime *metav1.Time `json:"soakStartTime,omitempty"`
+	// Message tells why the canary failed
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // RollingUpgradeConfig defines the desired config of the RollingUpgrade
@@ -250,6 +738,100 @@ type RollingUpgradeConfig struct {
 	// +kubebuilder:default=1
 	// +optional
 	ConcurrentBrokerRestartCountPerRack int `json:"concurrentBrokerRestartCountPerRack,omitempty"`
+
+	// ControllerQuorumMaxLag is the number of metadata records a KRaft controller may lag behind the quorum leader
+	// while another controller is restarted during a rolling upgrade. Default value is 1000.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ControllerQuorumMaxLag *int64 `json:"controllerQuorumMaxLag,omitempty"`
+
+	// Canary restarts a few brokers first and watches them for a soak period before the rest of the brokers are
+	// restarted. The rolling upgrade is halted when the canary brokers break the thresholds.
+	// +optional
+	Canary *CanaryConfig `json:"canary,omitempty"`
+
+	// ConfigRollback rolls a broker back to its last healthy configuration when it crash-loops or does not become
+	// ready after it was restarted with a changed configuration of spec.brokers or spec.brokerConfigGroups. The
+	// rolling upgrade is halted until the configuration of the broker is changed again. Only the broker configuration
+	// is verified and rolled back: changes of the cluster-level settings, e.g. spec.readOnlyConfig, spec.clusterImage
+	// or spec.listenersConfig, are rolled out without being rolled back.
+	// +optional
+	ConfigRollback *ConfigRollbackConfig `json:"configRollback,omitempty"`
+}
+
+// ConfigRollbackConfig defines when the brokers are rolled back to their previous configuration
+type ConfigRollbackConfig struct {
+	// ReadyTimeout is how long a broker restarted with a changed configuration may take to become ready. Default value is 10m.
+	// +optional
+	ReadyTimeout *metav1.Duration `json:"readyTimeout,omitempty"`
+	// MaxContainerRestarts is the number of container terminations after which the broker is considered crash-looping.
+	// The broker pods never restart their containers, the operator recreates the pod of a terminated container.
+	// Default value is 3.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	MaxContainerRestarts int32 `json:"maxContainerRestarts,omitempty"`
+}
+
+// CanaryConfig defines the canary phase of the rolling upgrades
+type CanaryConfig struct {
+	// BrokerCount is the number of brokers restarted as canaries. Default value is 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	BrokerCount int `json:"brokerCount,omitempty"`
+	// SoakPeriod is how long the canary brokers are watched before the rest of the brokers are restarted. Default value is 10m.
+	// +optional
+	SoakPeriod *metav1.Duration `json:"soakPeriod,omitempty"`
+	// MaxUnhealthyBrokers is the number of brokers which may have offline or out of sync replicas during the soak period
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUnhealthyBrokers int `json:"maxUnhealthyBrokers,omitempty"`
+	// MaxPodRestarts is the number of container terminations the canary brokers may have during the soak period
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxPodRestarts int32 `json:"maxPodRestarts,omitempty"`
+}
+
+// GetBrokerCount returns the number of brokers restarted as canaries
+func (c *CanaryConfig) GetBrokerCount() int {
+	if c.BrokerCount < 1 {
+		return 1
+	}
+	return c.BrokerCount
+}
+
+// GetSoakPeriod returns how long the canary brokers are watched
+func (c *CanaryConfig) GetSoakPeriod() time.Duration {
+	if c.SoakPeriod == nil {
+		return defaultCanarySoakPeriod
+	}
+	return c.SoakPeriod.Duration
+}
+
+// GetReadyTimeout returns how long a broker restarted with a changed configuration may take to become ready
+func (c *ConfigRollbackConfig) GetReadyTimeout() time.Duration {
+	if c.ReadyTimeout == nil {
+		return defaultConfigRollbackReadyTimeout
+	}
+	return c.ReadyTimeout.Duration
+}
+
+// GetMaxContainerRestarts returns the number of container terminations after which the broker is considered crash-looping
+func (c *ConfigRollbackConfig) GetMaxContainerRestarts() int32 {
+	if c.MaxContainerRestarts < 1 {
+		return defaultConfigRollbackMaxContainerRestarts
+	}
+	return c.MaxContainerRestarts
+}
+
+// GetControllerQuorumMaxLag returns the number of metadata records a controller may lag behind the quorum leader
+// during a rolling upgrade
+func (c RollingUpgradeConfig) GetControllerQuorumMaxLag() int64 {
+	if c.ControllerQuorumMaxLag == nil {
+		return defaultControllerQuorumMaxLag
+	}
+	return *c.ControllerQuorumMaxLag
 }
 
 // DisruptionBudget defines the configuration for PodDisruptionBudget where the workload is managed by the kafka-operator
@@ -529,6 +1111,86 @@ type StorageConfig struct {
 	// the `pvcSpec` is used by default.
 	// +optional
 	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+
+	// MigrateFrom is the mount path of the storage config this one replaces, the data of its volume is migrated to the
+	// volume of this storage config, for example to move the broker logs to a new storage class without downtime.
+	// The brokers are migrated one by one: the volume is added to the broker as an extra JBOD disk, the disk of the
+	// replaced storage config is drained by Cruise Control and its persistent volume claim is deleted.
+	// It is not supported on KRaft controller-only nodes which can have only one volume.
+	// +optional
+	MigrateFrom string `json:"migrateFrom,omitempty"`
+
+	// Autoscaling scales the storage of the brokers when the disk usage reported by Cruise Control reaches the
+	// threshold, without Prometheus alerts. It is ignored on emptyDir storage.
+	// +optional
+	Autoscaling *StorageAutoscalingConfig `json:"autoscaling,omitempty"`
+}
+
+// StorageAutoscalingMode tells how the storage of a broker is scaled
+// +kubebuilder:validation:Enum=Resize;AddDisk
+type StorageAutoscalingMode string
+
+const (
+	// StorageAutoscalingModeResize expands the persistent volume claim of the storage config by the step size
+	StorageAutoscalingModeResize StorageAutoscalingMode = "Resize"
+	// StorageAutoscalingModeAddDisk adds a JBOD disk of the step size to the broker, its mount path is the mount
+	// path of the storage config with a random suffix
+	StorageAutoscalingModeAddDisk StorageAutoscalingMode = "AddDisk"
+)
+
+// StorageAutoscalingConfig defines when and how the storage of the brokers is scaled
+type StorageAutoscalingConfig struct {
+	// ThresholdPercentage is the disk usage percentage of the storage at which it is scaled. The disk usage of a storage
+	// config with added disks is the average usage of its disks.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	ThresholdPercentage int32 `json:"thresholdPercentage"`
+	// Mode tells whether the volume is expanded or a disk is added. Default value is Resize.
+	// +kubebuilder:default=Resize
+	// +optional
+	Mode StorageAutoscalingMode `json:"mode,omitempty"`
+	// StepSize is the size the volume is expanded by, or the size of the added disk
+	StepSize resource.Quantity `json:"stepSize"`
+	// MaxSize is the size the storage is not scaled beyond: the size of the volume, or the total size of the volume and
+	// the disks added to it. The storage is scaled without limit when it is not set.
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Cooldown is the minimum time between two scalings of the storage of a broker, so Cruise Control can report the
+	// disk usage of the scaled storage. Default value is 30m.
+	// +optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// GetMode returns how the storage of a broker is scaled
+func (c *StorageAutoscalingConfig) GetMode() StorageAutoscalingMode {
+	if c.Mode == "" {
+		return StorageAutoscalingModeResize
+	}
+	return c.Mode
+}
+
+// GetCooldown returns the minimum time between two scalings of the storage of a broker
+func (c *StorageAutoscalingConfig) GetCooldown() time.Duration {
+	if c.Cooldown == nil {
+		return defaultStorageAutoscalingCooldown
+	}
+	return c.Cooldown.Duration
+}
+
+// IsStorageAutoscalingEnabled tells whether the storage of any broker is scaled by the operator
+func (s KafkaClusterSpec) IsStorageAutoscalingEnabled() bool {
+	for _, broker := range s.Brokers {
+		brokerConfig, err := broker.GetBrokerConfig(s)
+		if err != nil {
+			continue
+		}
+		for _, storageConfig := range brokerConfig.StorageConfigs {
+			if storageConfig.Autoscaling != nil && storageConfig.PvcSpec != nil {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // ListenersConfig defines the Kafka listener types
@@ -537,6 +1199,14 @@ type ListenersConfig struct {
 	InternalListeners  []InternalListenerConfig `json:"internalListeners"`
 	SSLSecrets         *SSLSecrets              `json:"sslSecrets,omitempty"`
 	ServiceAnnotations map[string]string        `json:"serviceAnnotations,omitempty"`
+	// PrincipalMappingRules map the Distinguished Name of client certificates to Kafka principals. They are rendered
+	// into the ssl.principal.mapping.rules broker config, e.g. RULE:^CN=([^,]*),.*$/$1/ and DEFAULT, and the
+	// operator applies the same rules to the certificate principals of the ACLs and super users it manages. The
+	// operator evaluates the rules with Go regular expressions (RE2), which reject Java-only constructs such as
+	// lookarounds and backreferences that the brokers accept, so rules relying on them are refused and rules
+	// whose patterns behave differently in the two engines map the principals differently from the brokers.
+	// +optional
+	PrincipalMappingRules []string `json:"principalMappingRules,omitempty"`
 }
 
 // GetServiceAnnotations returns a copy of the ServiceAnnotations field.
@@ -612,6 +1282,14 @@ type SSLSecrets struct {
 	IssuerRef       *cmmeta.IssuerReference `json:"issuerRef,omitempty"`
 	// +kubebuilder:validation:Enum={"cert-manager"}
 	PKIBackend PKIBackend `json:"pkiBackend,omitempty"`
+	// TLSProtocols lists the TLS protocol versions enabled on the SSL listeners which don't set their own.
+	// The operator restricts its own connections to the brokers to these versions as well.
+	// +optional
+	TLSProtocols []TLSProtocol `json:"tlsProtocols,omitempty"`
+	// CipherSuites lists the cipher suites enabled on the SSL listeners which don't set their own.
+	// The operator restricts its own connections to the brokers to these cipher suites as well.
+	// +optional
+	CipherSuites []CipherSuite `json:"cipherSuites,omitempty"`
 }
 
 // TODO (tinyzimmer): The above are all optional now in one way or another.
@@ -746,6 +1424,30 @@ type CommonListenerSpec struct {
 	// UsedForKafkaAdminCommunication allows for a different port to be returned when the koperator is checking for the port to use to check if kafka is operating.
 	// +optional
 	UsedForKafkaAdminCommunication bool `json:"usedForKafkaAdminCommunication,omitempty"`
+	// TLSProtocols lists the TLS protocol versions enabled on the listener.
+	// When omitted the ones set in 'sslSecrets' are used, or the Kafka defaults if neither is set
+	// +optional
+	TLSProtocols []TLSProtocol `json:"tlsProtocols,omitempty"`
+	// CipherSuites lists the cipher suites enabled on the listener in IANA naming.
+	// When omitted the ones set in 'sslSecrets' are used, or the Kafka defaults if neither is set
+	// +optional
+	CipherSuites []CipherSuite `json:"cipherSuites,omitempty"`
+}
+
+// GetTLSProtocols returns the TLS protocol versions enabled on the listener, falling back to the cluster wide ones
+func (c *CommonListenerSpec) GetTLSProtocols(sslSecrets *SSLSecrets) []TLSProtocol {
+	if len(c.TLSProtocols) > 0 || sslSecrets == nil {
+		return c.TLSProtocols
+	}
+	return sslSecrets.TLSProtocols
+}
+
+// GetCipherSuites returns the cipher suites enabled on the listener, falling back to the cluster wide ones
+func (c *CommonListenerSpec) GetCipherSuites(sslSecrets *SSLSecrets) []CipherSuite {
+	if len(c.CipherSuites) > 0 || sslSecrets == nil {
+		return c.CipherSuites
+	}
+	return sslSecrets.CipherSuites
 }
 
 func (c *CommonListenerSpec) GetServerSSLCertSecretName() string {
@@ -785,6 +1487,7 @@ type ListenerStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.brokerTemplate.replicas,statuspath=.status.brokerTemplate.replicas,selectorpath=.status.brokerTemplate.selector
 // +kubebuilder:printcolumn:JSONPath=".status.state",name="Cluster state",type="string"
 // +kubebuilder:printcolumn:JSONPath=".status.alertCount",name="Cluster alert count",type="integer"
 // +kubebuilder:printcolumn:JSONPath=".status.rollingUpgradeStatus.lastSuccess",name="Last successful upgrade",type="string"
@@ -792,3 +792,37 @@ func TestIsCombinedNode(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTLSSettings(t *testing.T) {
+	sslSecrets := &SSLSecrets{
+		TLSProtocols: []TLSProtocol{TLSProtocolV13},
+		CipherSuites: []CipherSuite{"TLS_AES_256_GCM_SHA384"},
+	}
+	listener := CommonListenerSpec{}
+	assert.DeepEqual(t, listener.GetTLSProtocols(nil), []TLSProtocol(nil))
+	assert.DeepEqual(t, listener.GetTLSProtocols(sslSecrets), []TLSProtocol{TLSProtocolV13})
+	assert.DeepEqual(t, listener.GetCipherSuites(sslSecrets), []CipherSuite{"TLS_AES_256_GCM_SHA384"})
+
+	listener = CommonListenerSpec{
+		TLSProtocols: []TLSProtocol{TLSProtocolV12},
+		CipherSuites: []CipherSuite{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+	}
+	assert.DeepEqual(t, listener.GetTLSProtocols(sslSecrets), []TLSProtocol{TLSProtocolV12})
+	assert.DeepEqual(t, listener.GetCipherSuites(sslSecrets), []CipherSuite{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"})
+}
+
+func TestMigrationPhases(t *testing.T) {
+	var status *MigrationStatus
+	assert.Equal(t, status.IsInProgress(), false)
+	assert.Equal(t, (&MigrationStatus{Phase: MigrationPhaseBrokersDualWrite}).IsInProgress(), true)
+	assert.Equal(t, (&MigrationStatus{Phase: MigrationPhaseRollbackBrokersZooKeeper}).IsInProgress(), true)
+	assert.Equal(t, (&MigrationStatus{Phase: MigrationPhaseCompleted}).IsInProgress(), false)
+	assert.Equal(t, (&MigrationStatus{Phase: MigrationPhaseRolledBack}).IsInProgress(), false)
+
+	assert.Equal(t, MigrationPhaseBrokersKRaft.IsRollbackAllowed(), true)
+	assert.Equal(t, MigrationPhaseFinalizing.IsRollbackAllowed(), false)
+	assert.Equal(t, MigrationPhaseRollbackBrokersDualWrite.IsRollbackAllowed(), false)
+
+	assert.Equal(t, MigrationPhaseRollbackBrokersDualWrite.IsRollback(), true)
+	assert.Equal(t, MigrationPhaseMetadataMigrating.IsRollback(), false)
+}
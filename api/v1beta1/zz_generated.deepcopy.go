@@ -200,6 +200,54 @@ func (in *BrokerConfig) DeepCopy() *BrokerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerIDAllocation) DeepCopyInto(out *BrokerIDAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerIDAllocation.
+func (in *BrokerIDAllocation) DeepCopy() *BrokerIDAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerIDAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerReplacementStatus) DeepCopyInto(out *BrokerReplacementStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerReplacementStatus.
+func (in *BrokerReplacementStatus) DeepCopy() *BrokerReplacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerReplacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerRestart) DeepCopyInto(out *BrokerRestart) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerRestart.
+func (in *BrokerRestart) DeepCopy() *BrokerRestart {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerRestart)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BrokerState) DeepCopyInto(out *BrokerState) {
 	*out = *in
@@ -209,6 +257,37 @@ func (in *BrokerState) DeepCopyInto(out *BrokerState) {
 		*out = make(ExternalListenerConfigNames, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConfigurationRollout != nil {
+		in, out := &in.ConfigurationRollout, &out.ConfigurationRollout
+		*out = new(ConfigurationRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestartHistory != nil {
+		in, out := &in.RestartHistory, &out.RestartHistory
+		*out = make([]BrokerRestart, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeResizes != nil {
+		in, out := &in.VolumeResizes, &out.VolumeResizes
+		*out = make(map[string]VolumeResizeStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Replacement != nil {
+		in, out := &in.Replacement, &out.Replacement
+		*out = new(BrokerReplacementStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageAutoscaling != nil {
+		in, out := &in.StorageAutoscaling, &out.StorageAutoscaling
+		*out = make(map[string]StorageAutoscalingStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerState.
@@ -221,6 +300,86 @@ func (in *BrokerState) DeepCopy() *BrokerState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerTemplate) DeepCopyInto(out *BrokerTemplate) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	out.IDAllocation = in.IDAllocation
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerTemplate.
+func (in *BrokerTemplate) DeepCopy() *BrokerTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerTemplateStatus) DeepCopyInto(out *BrokerTemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerTemplateStatus.
+func (in *BrokerTemplateStatus) DeepCopy() *BrokerTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryConfig) DeepCopyInto(out *CanaryConfig) {
+	*out = *in
+	if in.SoakPeriod != nil {
+		in, out := &in.SoakPeriod, &out.SoakPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryConfig.
+func (in *CanaryConfig) DeepCopy() *CanaryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
+	*out = *in
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoakStartTime != nil {
+		in, out := &in.SoakStartTime, &out.SoakStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonListenerSpec) DeepCopyInto(out *CommonListenerSpec) {
 	*out = *in
@@ -229,6 +388,16 @@ func (in *CommonListenerSpec) DeepCopyInto(out *CommonListenerSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.TLSProtocols != nil {
+		in, out := &in.TLSProtocols, &out.TLSProtocols
+		*out = make([]TLSProtocol, len(*in))
+		copy(*out, *in)
+	}
+	if in.CipherSuites != nil {
+		in, out := &in.CipherSuites, &out.CipherSuites
+		*out = make([]CipherSuite, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonListenerSpec.
@@ -263,6 +432,42 @@ func (in *Config) DeepCopy() *Config {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigRollbackConfig) DeepCopyInto(out *ConfigRollbackConfig) {
+	*out = *in
+	if in.ReadyTimeout != nil {
+		in, out := &in.ReadyTimeout, &out.ReadyTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRollbackConfig.
+func (in *ConfigRollbackConfig) DeepCopy() *ConfigRollbackConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigRollbackConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationRolloutStatus) DeepCopyInto(out *ConfigurationRolloutStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationRolloutStatus.
+func (in *ConfigurationRolloutStatus) DeepCopy() *ConfigurationRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContourIngressConfig) DeepCopyInto(out *ContourIngressConfig) {
 	*out = *in
@@ -591,6 +796,28 @@ func (in ExternalListenerConfigNames) DeepCopy() ExternalListenerConfigNames {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeaturesStatus) DeepCopyInto(out *FeaturesStatus) {
+	*out = *in
+	if in.Finalized != nil {
+		in, out := &in.Finalized, &out.Finalized
+		*out = make(map[string]int16, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeaturesStatus.
+func (in *FeaturesStatus) DeepCopy() *FeaturesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FeaturesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GracefulActionState) DeepCopyInto(out *GracefulActionState) {
 	*out = *in
@@ -682,6 +909,66 @@ func (in *InternalListenerConfig) DeepCopy() *InternalListenerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KRaftQuorumReplicaStatus) DeepCopyInto(out *KRaftQuorumReplicaStatus) {
+	*out = *in
+	if in.LastFetchTime != nil {
+		in, out := &in.LastFetchTime, &out.LastFetchTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastCaughtUpTime != nil {
+		in, out := &in.LastCaughtUpTime, &out.LastCaughtUpTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KRaftQuorumReplicaStatus.
+func (in *KRaftQuorumReplicaStatus) DeepCopy() *KRaftQuorumReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KRaftQuorumReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KRaftQuorumStatus) DeepCopyInto(out *KRaftQuorumStatus) {
+	*out = *in
+	if in.BootstrapControllers != nil {
+		in, out := &in.BootstrapControllers, &out.BootstrapControllers
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Voters != nil {
+		in, out := &in.Voters, &out.Voters
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.LeaderID != nil {
+		in, out := &in.LeaderID, &out.LeaderID
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]KRaftQuorumReplicaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KRaftQuorumStatus.
+func (in *KRaftQuorumStatus) DeepCopy() *KRaftQuorumStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KRaftQuorumStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaCluster) DeepCopyInto(out *KafkaCluster) {
 	*out = *in
@@ -775,7 +1062,7 @@ func (in *KafkaClusterSpec) DeepCopyInto(out *KafkaClusterSpec) {
 		}
 	}
 	out.DisruptionBudget = in.DisruptionBudget
-	out.RollingUpgradeConfig = in.RollingUpgradeConfig
+	in.RollingUpgradeConfig.DeepCopyInto(&out.RollingUpgradeConfig)
 	if in.TaintedBrokersSelector != nil {
 		in, out := &in.TaintedBrokersSelector, &out.TaintedBrokersSelector
 		*out = new(metav1.LabelSelector)
@@ -802,6 +1089,36 @@ func (in *KafkaClusterSpec) DeepCopyInto(out *KafkaClusterSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(MigrationConfig)
+		**out = **in
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.PersistentVolumeClaimRetentionPolicy != nil {
+		in, out := &in.PersistentVolumeClaimRetentionPolicy, &out.PersistentVolumeClaimRetentionPolicy
+		*out = new(PersistentVolumeClaimRetentionPolicy)
+		**out = **in
+	}
+	if in.TieredStorage != nil {
+		in, out := &in.TieredStorage, &out.TieredStorage
+		*out = new(TieredStorageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OfflineLogDirs != nil {
+		in, out := &in.OfflineLogDirs, &out.OfflineLogDirs
+		*out = new(OfflineLogDirsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BrokerTemplate != nil {
+		in, out := &in.BrokerTemplate, &out.BrokerTemplate
+		*out = new(BrokerTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaClusterSpec.
@@ -824,8 +1141,50 @@ func (in *KafkaClusterStatus) DeepCopyInto(out *KafkaClusterStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
-	out.RollingUpgrade = in.RollingUpgrade
+	in.RollingUpgrade.DeepCopyInto(&out.RollingUpgrade)
 	in.ListenerStatuses.DeepCopyInto(&out.ListenerStatuses)
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(MigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KRaftQuorum != nil {
+		in, out := &in.KRaftQuorum, &out.KRaftQuorum
+		*out = new(KRaftQuorumStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = new(FeaturesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VersionUpgrade != nil {
+		in, out := &in.VersionUpgrade, &out.VersionUpgrade
+		*out = new(VersionUpgradeStatus)
+		**out = **in
+	}
+	if in.RestartRequest != nil {
+		in, out := &in.RestartRequest, &out.RestartRequest
+		*out = new(RestartRequestStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BrokerTemplate != nil {
+		in, out := &in.BrokerTemplate, &out.BrokerTemplate
+		*out = new(BrokerTemplateStatus)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaClusterStatus.
@@ -963,6 +1322,11 @@ func (in *ListenersConfig) DeepCopyInto(out *ListenersConfig) {
 			(*out)[key] = val
 		}
 	}
+	if in.PrincipalMappingRules != nil {
+		in, out := &in.PrincipalMappingRules, &out.PrincipalMappingRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenersConfig.
@@ -975,6 +1339,77 @@ func (in *ListenersConfig) DeepCopy() *ListenersConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceStatus) DeepCopyInto(out *MaintenanceStatus) {
+	*out = *in
+	if in.NextWindow != nil {
+		in, out := &in.NextWindow, &out.NextWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.WaitingCruiseControlOperations != nil {
+		in, out := &in.WaitingCruiseControlOperations, &out.WaitingCruiseControlOperations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceStatus.
+func (in *MaintenanceStatus) DeepCopy() *MaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationConfig) DeepCopyInto(out *MigrationConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationConfig.
+func (in *MigrationConfig) DeepCopy() *MigrationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
+func (in *MigrationStatus) DeepCopy() *MigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitoringConfig) DeepCopyInto(out *MonitoringConfig) {
 	*out = *in
@@ -1005,6 +1440,57 @@ func (in *NetworkConfig) DeepCopy() *NetworkConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflineLogDir) DeepCopyInto(out *OfflineLogDir) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflineLogDir.
+func (in *OfflineLogDir) DeepCopy() *OfflineLogDir {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflineLogDir)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflineLogDirsConfig) DeepCopyInto(out *OfflineLogDirsConfig) {
+	*out = *in
+	if in.CheckInterval != nil {
+		in, out := &in.CheckInterval, &out.CheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflineLogDirsConfig.
+func (in *OfflineLogDirsConfig) DeepCopy() *OfflineLogDirsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflineLogDirsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimRetentionPolicy) DeepCopyInto(out *PersistentVolumeClaimRetentionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimRetentionPolicy.
+func (in *PersistentVolumeClaimRetentionPolicy) DeepCopy() *PersistentVolumeClaimRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RackAwareness) DeepCopyInto(out *RackAwareness) {
 	*out = *in
@@ -1025,9 +1511,40 @@ func (in *RackAwareness) DeepCopy() *RackAwareness {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartRequestStatus) DeepCopyInto(out *RestartRequestStatus) {
+	*out = *in
+	in.RequestTime.DeepCopyInto(&out.RequestTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartRequestStatus.
+func (in *RestartRequestStatus) DeepCopy() *RestartRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpgradeConfig) DeepCopyInto(out *RollingUpgradeConfig) {
 	*out = *in
+	if in.ControllerQuorumMaxLag != nil {
+		in, out := &in.ControllerQuorumMaxLag, &out.ControllerQuorumMaxLag
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigRollback != nil {
+		in, out := &in.ConfigRollback, &out.ConfigRollback
+		*out = new(ConfigRollbackConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpgradeConfig.
@@ -1043,6 +1560,11 @@ func (in *RollingUpgradeConfig) DeepCopy() *RollingUpgradeConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpgradeStatus) DeepCopyInto(out *RollingUpgradeStatus) {
 	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpgradeStatus.
@@ -1063,6 +1585,16 @@ func (in *SSLSecrets) DeepCopyInto(out *SSLSecrets) {
 		*out = new(apismetav1.IssuerReference)
 		**out = **in
 	}
+	if in.TLSProtocols != nil {
+		in, out := &in.TLSProtocols, &out.TLSProtocols
+		*out = make([]TLSProtocol, len(*in))
+		copy(*out, *in)
+	}
+	if in.CipherSuites != nil {
+		in, out := &in.CipherSuites, &out.CipherSuites
+		*out = make([]CipherSuite, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSLSecrets.
@@ -1075,6 +1607,49 @@ func (in *SSLSecrets) DeepCopy() *SSLSecrets {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoscalingConfig) DeepCopyInto(out *StorageAutoscalingConfig) {
+	*out = *in
+	out.StepSize = in.StepSize.DeepCopy()
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoscalingConfig.
+func (in *StorageAutoscalingConfig) DeepCopy() *StorageAutoscalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutoscalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoscalingStatus) DeepCopyInto(out *StorageAutoscalingStatus) {
+	*out = *in
+	in.LastScaleTime.DeepCopyInto(&out.LastScaleTime)
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoscalingStatus.
+func (in *StorageAutoscalingStatus) DeepCopy() *StorageAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
 	*out = *in
@@ -1088,6 +1663,11 @@ func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
 		*out = new(v1.EmptyDirVolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(StorageAutoscalingConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
@@ -1100,6 +1680,36 @@ func (in *StorageConfig) DeepCopy() *StorageConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TieredStorageConfig) DeepCopyInto(out *TieredStorageConfig) {
+	*out = *in
+	if in.RemoteStorageManagerConfigSecret != nil {
+		in, out := &in.RemoteStorageManagerConfigSecret, &out.RemoteStorageManagerConfigSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.LocalRetentionMs != nil {
+		in, out := &in.LocalRetentionMs, &out.LocalRetentionMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LocalRetentionBytes != nil {
+		in, out := &in.LocalRetentionBytes, &out.LocalRetentionBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TieredStorageConfig.
+func (in *TieredStorageConfig) DeepCopy() *TieredStorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TieredStorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopicConfig) DeepCopyInto(out *TopicConfig) {
 	*out = *in
@@ -1115,6 +1725,44 @@ func (in *TopicConfig) DeepCopy() *TopicConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionUpgradeStatus) DeepCopyInto(out *VersionUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionUpgradeStatus.
+func (in *VersionUpgradeStatus) DeepCopy() *VersionUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeResizeStatus) DeepCopyInto(out *VolumeResizeStatus) {
+	*out = *in
+	out.RequestedSize = in.RequestedSize.DeepCopy()
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeResizeStatus.
+func (in *VolumeResizeStatus) DeepCopy() *VolumeResizeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeResizeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeState) DeepCopyInto(out *VolumeState) {
 	*out = *in
@@ -1123,6 +1771,16 @@ func (in *VolumeState) DeepCopyInto(out *VolumeState) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.StorageMigration != nil {
+		in, out := &in.StorageMigration, &out.StorageMigration
+		*out = new(VolumeStorageMigration)
+		**out = **in
+	}
+	if in.OfflineLogDir != nil {
+		in, out := &in.OfflineLogDir, &out.OfflineLogDir
+		*out = new(OfflineLogDir)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeState.
@@ -1134,3 +1792,18 @@ func (in *VolumeState) DeepCopy() *VolumeState {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeStorageMigration) DeepCopyInto(out *VolumeStorageMigration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeStorageMigration.
+func (in *VolumeStorageMigration) DeepCopy() *VolumeStorageMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeStorageMigration)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -39,8 +40,10 @@ import (
 	apiutil "github.com/banzaicloud/koperator/api/util"
 	banzaiv1alpha1 "github.com/banzaicloud/koperator/api/v1alpha1"
 	banzaiv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
 	"github.com/banzaicloud/koperator/pkg/scale"
 	"github.com/banzaicloud/koperator/pkg/util"
+	"github.com/banzaicloud/koperator/pkg/util/maintenance"
 )
 
 const (
@@ -56,10 +59,11 @@ const (
 var (
 	defaultRequeueIntervalInSeconds = 10
 	executionPriorityMap            = map[banzaiv1alpha1.CruiseControlTaskOperation]int{
-		banzaiv1alpha1.OperationAddBroker:    3,
-		banzaiv1alpha1.OperationRemoveBroker: 2,
-		banzaiv1alpha1.OperationRemoveDisks:  1,
-		banzaiv1alpha1.OperationRebalance:    0,
+		banzaiv1alpha1.OperationFixOfflineReplicas: 4,
+		banzaiv1alpha1.OperationAddBroker:          3,
+		banzaiv1alpha1.OperationRemoveBroker:       2,
+		banzaiv1alpha1.OperationRemoveDisks:        1,
+		banzaiv1alpha1.OperationRebalance:          0,
 	}
 	missingCCResErr = errors.New("missing Cruise Control user task result")
 )
@@ -205,9 +209,20 @@ func (r *CruiseControlOperationReconciler) Reconcile(ctx context.Context, reques
 	if len(ccOperationQueueMap[ccOperationForStopExecution]) == 0 && len(ccOperationQueueMap[ccOperationFirstExecution]) == 0 &&
 		len(ccOperationQueueMap[ccOperationRetryExecution]) == 0 && len(ccOperationQueueMap[ccOperationInProgress]) == 0 {
 		log.Info("there is no more operation for execution")
+		// the operations which waited for a maintenance window are done or deleted
+		if err := r.holdForMaintenanceWindow(ctx, log, kafkaCluster, ccOperationQueueMap); err != nil {
+			log.Error(err, "requeue event as checking the maintenance windows failed")
+			return requeueAfter(defaultRequeueIntervalInSeconds)
+		}
 		return reconciled()
 	}
 
+	// Outside of the maintenance windows only the operations which are not rebalances are started
+	if err := r.holdForMaintenanceWindow(ctx, log, kafkaCluster, ccOperationQueueMap); err != nil {
+		log.Error(err, "requeue event as checking the maintenance windows failed")
+		return requeueAfter(defaultRequeueIntervalInSeconds)
+	}
+
 	ccOperationExecution, err := r.selectOperationForExecution(ccOperationQueueMap)
 	if err != nil {
 		log.Error(err, "requeue event as selecting operation for execution failed")
@@ -282,6 +297,8 @@ func (r *CruiseControlOperationReconciler) executeOperation(ctx context.Context,
 		cruseControlTaskResult, err = r.scaler.RebalanceWithParams(ctx, ccOperationExecution.CurrentTaskParameters())
 	case banzaiv1alpha1.OperationRemoveDisks:
 		cruseControlTaskResult, err = r.scaler.RemoveDisksWithParams(ctx, ccOperationExecution.CurrentTaskParameters())
+	case banzaiv1alpha1.OperationFixOfflineReplicas:
+		cruseControlTaskResult, err = r.scaler.FixOfflineReplicasWithParams(ctx, ccOperationExecution.CurrentTaskParameters())
 	case banzaiv1alpha1.OperationStopExecution:
 		cruseControlTaskResult, err = r.scaler.StopExecution(ctx)
 	case banzaiv1alpha1.OperationStatus:
@@ -363,6 +380,54 @@ func (r *CruiseControlOperationReconciler) selectOperationForExecution(ccOperati
 	return getFirstOperation(ccOperationQueueMap, ccOperationFirstExecution), nil
 }
 
+// holdForMaintenanceWindow removes the rebalance operations from the execution queues while no maintenance window is
+// open and updates the maintenance status of the cluster with the operations waiting for the next window. The disk
+// rebalances the operator creates for the added disks are not held.
+func (r *CruiseControlOperationReconciler) holdForMaintenanceWindow(ctx context.Context, log logr.Logger, kafkaCluster *banzaiv1beta1.KafkaCluster,
+	ccOperationQueueMap map[string][]*banzaiv1alpha1.CruiseControlOperation) error {
+	open, next, err := maintenance.Open(kafkaCluster.Spec.MaintenanceWindows, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var waiting []string
+	if !open {
+		for _, key := range []string{ccOperationFirstExecution, ccOperationRetryExecution} {
+			var queue []*banzaiv1alpha1.CruiseControlOperation
+			for _, operation := range ccOperationQueueMap[key] {
+				if operation.CurrentTaskOperation() == banzaiv1alpha1.OperationRebalance && !isCreatedByOperator(operation) {
+					waiting = append(waiting, operation.GetName())
+					continue
+				}
+				queue = append(queue, operation)
+			}
+			ccOperationQueueMap[key] = queue
+		}
+		sort.Strings(waiting)
+	}
+
+	var waitingBefore []string
+	if kafkaCluster.Status.Maintenance != nil {
+		waitingBefore = kafkaCluster.Status.Maintenance.WaitingCruiseControlOperations
+	}
+	if slices.Equal(waiting, waitingBefore) {
+		return nil
+	}
+	state := k8sutil.CruiseControlOperationsMaintenance{Waiting: waiting}
+	if len(waiting) > 0 {
+		log.Info("Cruise Control operations are waiting for the next maintenance window", "operations", waiting, "nextWindow", next)
+		state.NextWindow = &v1.Time{Time: next}
+	}
+	return k8sutil.UpdateCRStatus(r.Client, kafkaCluster, state, log)
+}
+
+// isCreatedByOperator returns whether the operation was created by the operator for a KafkaCluster, e.g. the disk
+// rebalance of the disks added to the brokers
+func isCreatedByOperator(operation *banzaiv1alpha1.CruiseControlOperation) bool {
+	owner := v1.GetControllerOf(operation)
+	return owner != nil && owner.Kind == "KafkaCluster"
+}
+
 // getFirstOperation returns the first operation in the given queue
 func getFirstOperation(ccOperationQueueMap map[string][]*banzaiv1alpha1.CruiseControlOperation, key string) *banzaiv1alpha1.CruiseControlOperation {
 	if len(ccOperationQueueMap[key]) > 0 {
@@ -17,6 +17,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/banzaicloud/koperator/api/v1beta1"
 	mocks "github.com/banzaicloud/koperator/controllers/tests/mocks"
 	"github.com/banzaicloud/koperator/pkg/scale"
+	"github.com/banzaicloud/koperator/pkg/util"
 )
 
 func createCCRetryExecutionOperation(createTime time.Time, id string, operation v1alpha1.CruiseControlTaskOperation) *v1alpha1.CruiseControlOperation {
@@ -159,3 +161,116 @@ func TestGetStatusDoesNotPanicWhenStatusNil(t *testing.T) {
 		t.Fatal("expected an error, got nil")
 	}
 }
+
+func TestHoldForMaintenanceWindow(t *testing.T) {
+	createOperation := func(name string, operation v1alpha1.CruiseControlTaskOperation) *v1alpha1.CruiseControlOperation {
+		return &v1alpha1.CruiseControlOperation{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: v1alpha1.CruiseControlOperationStatus{
+				CurrentTask: &v1alpha1.CruiseControlTask{Operation: operation},
+			},
+		}
+	}
+	now := time.Now().UTC()
+	// the window opened an hour ago on every day
+	openWindow := v1beta1.MaintenanceWindow{
+		Schedule: fmt.Sprintf("%d %d * * *", now.Add(-time.Hour).Minute(), now.Add(-time.Hour).Hour()),
+		Duration: v1.Duration{Duration: 2 * time.Hour},
+	}
+	// the window opens in an hour on every day
+	closedWindow := v1beta1.MaintenanceWindow{
+		Schedule: fmt.Sprintf("%d %d * * *", now.Add(time.Hour).Minute(), now.Add(time.Hour).Hour()),
+		Duration: v1.Duration{Duration: 30 * time.Minute},
+	}
+
+	testCases := []struct {
+		testName                string
+		windows                 []v1beta1.MaintenanceWindow
+		waitingBefore           []string
+		expectedFirstExecution  []string
+		expectedRetryExecution  []string
+		expectedWaiting         []string
+		expectedNextWindowIsSet bool
+	}{
+		{
+			testName:               "no maintenance window",
+			expectedFirstExecution: []string{"add", "rebalance", "rebalance-disks"},
+			expectedRetryExecution: []string{"retry-rebalance"},
+		},
+		{
+			testName:               "maintenance window is open",
+			windows:                []v1beta1.MaintenanceWindow{openWindow},
+			waitingBefore:          []string{"rebalance", "retry-rebalance"},
+			expectedFirstExecution: []string{"add", "rebalance", "rebalance-disks"},
+			expectedRetryExecution: []string{"retry-rebalance"},
+		},
+		{
+			testName:                "maintenance window is closed",
+			windows:                 []v1beta1.MaintenanceWindow{closedWindow},
+			expectedFirstExecution:  []string{"add", "rebalance-disks"},
+			expectedWaiting:         []string{"rebalance", "retry-rebalance"},
+			expectedNextWindowIsSet: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := v1beta1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			kafkaCluster := &v1beta1.KafkaCluster{
+				ObjectMeta: v1.ObjectMeta{Name: "kafka", Namespace: "default"},
+				Spec:       v1beta1.KafkaClusterSpec{MaintenanceWindows: testCase.windows},
+			}
+			if testCase.waitingBefore != nil {
+				kafkaCluster.Status.Maintenance = &v1beta1.MaintenanceStatus{WaitingCruiseControlOperations: testCase.waitingBefore}
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithStatusSubresource(&v1beta1.KafkaCluster{}).
+				WithObjects(kafkaCluster).
+				Build()
+			r := &CruiseControlOperationReconciler{Client: fakeClient, Scheme: scheme}
+
+			// the disk rebalance created by the operator for the added disks
+			diskRebalance := createOperation("rebalance-disks", v1alpha1.OperationRebalance)
+			diskRebalance.SetOwnerReferences([]v1.OwnerReference{{
+				APIVersion: v1beta1.GroupVersion.String(),
+				Kind:       "KafkaCluster",
+				Name:       kafkaCluster.Name,
+				Controller: util.BoolPointer(true),
+			}})
+			ccOperationQueueMap := map[string][]*v1alpha1.CruiseControlOperation{
+				ccOperationFirstExecution: {
+					createOperation("add", v1alpha1.OperationAddBroker),
+					createOperation("rebalance", v1alpha1.OperationRebalance),
+					diskRebalance,
+				},
+				ccOperationRetryExecution: {
+					createOperation("retry-rebalance", v1alpha1.OperationRebalance),
+				},
+			}
+			err := r.holdForMaintenanceWindow(context.Background(), logr.Discard(), kafkaCluster, ccOperationQueueMap)
+			assert.NoError(t, err)
+
+			names := func(key string) []string {
+				var names []string
+				for _, operation := range ccOperationQueueMap[key] {
+					names = append(names, operation.GetName())
+				}
+				return names
+			}
+			assert.Equal(t, testCase.expectedFirstExecution, names(ccOperationFirstExecution))
+			assert.Equal(t, testCase.expectedRetryExecution, names(ccOperationRetryExecution))
+
+			updated := &v1beta1.KafkaCluster{}
+			assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(kafkaCluster), updated))
+			if testCase.expectedWaiting == nil {
+				assert.Nil(t, updated.Status.Maintenance)
+				return
+			}
+			assert.Equal(t, testCase.expectedWaiting, updated.Status.Maintenance.WaitingCruiseControlOperations)
+			assert.Equal(t, testCase.expectedNextWindowIsSet, updated.Status.Maintenance.NextWindow != nil)
+		})
+	}
+}
@@ -123,6 +123,23 @@ func (r *CruiseControlTaskReconciler) Reconcile(ctx context.Context, request ctr
 	operationTTLSecondsAfterFinished := instance.Spec.CruiseControlConfig.CruiseControlOperationSpec.GetTTLSecondsAfterFinished()
 
 	switch {
+	// the replicas of the offline log directories are under-replicated, so they are moved before any other operation
+	case tasksAndStates.NumActiveTasksByOp(banzaiv1alpha1.OperationFixOfflineReplicas) > 0:
+		cruiseControlOpRef, err := r.fixOfflineReplicas(ctx, instance, operationTTLSecondsAfterFinished)
+		if err != nil {
+			return requeueWithError(log, "creating CruiseControlOperation for fixing offline replicas has failed", err)
+		}
+
+		// a single operation moves the offline replicas of every volume
+		for _, task := range tasksAndStates.GetActiveTasksByOp(banzaiv1alpha1.OperationFixOfflineReplicas) {
+			if task == nil {
+				continue
+			}
+
+			task.SetCruiseControlOperationRef(cruiseControlOpRef)
+			task.SetStateScheduled()
+		}
+
 	case tasksAndStates.NumActiveTasksByOp(banzaiv1alpha1.OperationAddBroker) > 0:
 		brokerIDs := make([]string, 0)
 		for _, task := range tasksAndStates.GetActiveTasksByOp(banzaiv1alpha1.OperationAddBroker) {
@@ -378,6 +395,10 @@ func (r *CruiseControlTaskReconciler) removeDisks(ctx context.Context, kafkaClus
 	return r.createCCOperation(ctx, kafkaCluster, banzaiv1alpha1.ErrorPolicyRetry, ttlSecondsAfterFinished, banzaiv1alpha1.OperationRemoveDisks, nil, false, brokerIdsToRemovedLogDirs)
 }
 
+func (r *CruiseControlTaskReconciler) fixOfflineReplicas(ctx context.Context, kafkaCluster *banzaiv1beta1.KafkaCluster, ttlSecondsAfterFinished *int) (corev1.LocalObjectReference, error) {
+	return r.createCCOperation(ctx, kafkaCluster, banzaiv1alpha1.ErrorPolicyRetry, ttlSecondsAfterFinished, banzaiv1alpha1.OperationFixOfflineReplicas, nil, false, nil)
+}
+
 func (r *CruiseControlTaskReconciler) rebalanceDisks(ctx context.Context, kafkaCluster *banzaiv1beta1.KafkaCluster, ttlSecondsAfterFinished *int, bokerIDs []string, isJBOD bool) (corev1.LocalObjectReference, error) {
 	return r.createCCOperation(ctx, kafkaCluster, banzaiv1alpha1.ErrorPolicyRetry, ttlSecondsAfterFinished, banzaiv1alpha1.OperationRebalance, bokerIDs, isJBOD, nil)
 }
@@ -448,6 +469,8 @@ func (r *CruiseControlTaskReconciler) createCCOperation(
 		operation.Status.CurrentTask.Parameters[scale.ParamBrokerID] = strings.Join(brokerIDs, ",")
 	case banzaiv1alpha1.OperationStatus:
 		// No additional parameters needed for status operation
+	case banzaiv1alpha1.OperationFixOfflineReplicas:
+		// The offline replicas of the whole cluster are fixed
 	default:
 		operation.Status.CurrentTask.Parameters[scale.ParamBrokerID] = strings.Join(brokerIDs, ",")
 	}
@@ -650,6 +673,16 @@ func getActiveTasksFromCluster(instance *banzaiv1beta1.KafkaCluster) *CruiseCont
 					CruiseControlOperationReference: volumeState.CruiseControlOperationReference,
 				}
 				tasksAndStates.Add(t)
+
+			case volumeState.CruiseControlVolumeState.IsOfflineReplicasFix():
+				t := &CruiseControlTask{
+					BrokerID:                        brokerId,
+					Volume:                          mountPath,
+					VolumeState:                     volumeState.CruiseControlVolumeState,
+					Operation:                       banzaiv1alpha1.OperationFixOfflineReplicas,
+					CruiseControlOperationReference: volumeState.CruiseControlOperationReference,
+				}
+				tasksAndStates.Add(t)
 			}
 		}
 	}
@@ -381,6 +381,17 @@ func TestCreateCCOperation(t *testing.T) {
 				assert.Equal(t, "true", params[scale.ParamExcludeRemoved])
 			},
 		},
+		{
+			operationType:      banzaiv1alpha1.OperationFixOfflineReplicas,
+			brokerIDs:          nil,
+			isJBOD:             false,
+			brokerIdsToLogDirs: nil,
+			parameterCheck: func(t *testing.T, params map[string]string) {
+				assert.Len(t, params, 2)
+				assert.Equal(t, "true", params[scale.ParamExcludeDemoted])
+				assert.Equal(t, "true", params[scale.ParamExcludeRemoved])
+			},
+		},
 	}
 
 	mockCtrl := gomock.NewController(t)
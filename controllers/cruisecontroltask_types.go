@@ -42,7 +42,7 @@ func (t *CruiseControlTask) IsRequired() bool {
 	switch t.Operation {
 	case koperatorv1alpha1.OperationAddBroker, koperatorv1alpha1.OperationRemoveBroker:
 		return t.BrokerState.IsRequiredState()
-	case koperatorv1alpha1.OperationRebalance, koperatorv1alpha1.OperationRemoveDisks:
+	case koperatorv1alpha1.OperationRebalance, koperatorv1alpha1.OperationRemoveDisks, koperatorv1alpha1.OperationFixOfflineReplicas:
 		return t.VolumeState.IsRequiredState()
 	}
 	return false
@@ -62,7 +62,7 @@ func (t *CruiseControlTask) Apply(instance *koperatorv1beta1.KafkaCluster) {
 			state.GracefulActionState.CruiseControlOperationReference = t.CruiseControlOperationReference
 			instance.Status.BrokersState[t.BrokerID] = state
 		}
-	case koperatorv1alpha1.OperationRebalance, koperatorv1alpha1.OperationRemoveDisks:
+	case koperatorv1alpha1.OperationRebalance, koperatorv1alpha1.OperationRemoveDisks, koperatorv1alpha1.OperationFixOfflineReplicas:
 		if state, ok := instance.Status.BrokersState[t.BrokerID]; ok {
 			if volState, ok := state.GracefulActionState.VolumeStates[t.Volume]; ok {
 				volState.CruiseControlVolumeState = t.VolumeState
@@ -91,6 +91,8 @@ func (t *CruiseControlTask) SetStateScheduled() {
 		t.VolumeState = koperatorv1beta1.GracefulDiskRebalanceScheduled
 	case koperatorv1alpha1.OperationRemoveDisks:
 		t.VolumeState = koperatorv1beta1.GracefulDiskRemovalScheduled
+	case koperatorv1alpha1.OperationFixOfflineReplicas:
+		t.VolumeState = koperatorv1beta1.GracefulOfflineReplicasFixScheduled
 	}
 }
 
@@ -175,6 +177,24 @@ func (t *CruiseControlTask) FromResult(operation *koperatorv1alpha1.CruiseContro
 		case operation.CurrentTaskState() == "":
 			t.VolumeState = koperatorv1beta1.GracefulDiskRebalanceScheduled
 		}
+
+	case koperatorv1alpha1.OperationFixOfflineReplicas:
+		switch {
+		case operation == nil:
+			t.VolumeState = koperatorv1beta1.GracefulOfflineReplicasFixSucceeded
+		case operation.IsErrorPolicyIgnore() && operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
+			t.VolumeState = koperatorv1beta1.GracefulOfflineReplicasFixSucceeded
+		case operation.IsPaused() && operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
+			t.VolumeState = koperatorv1beta1.GracefulOfflineReplicasFixPaused
+		case operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskActive, operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskInExecution:
+			t.VolumeState = koperatorv1beta1.GracefulOfflineReplicasFixRunning
+		case operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompleted:
+			t.VolumeState = koperatorv1beta1.GracefulOfflineReplicasFixSucceeded
+		case operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
+			t.VolumeState = koperatorv1beta1.GracefulOfflineReplicasFixCompletedWithError
+		case operation.CurrentTaskState() == "":
+			t.VolumeState = koperatorv1beta1.GracefulOfflineReplicasFixScheduled
+		}
 	}
 }
 
@@ -29,6 +29,7 @@ import (
 	policyv1 "k8s.io/api/policy/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -61,12 +62,20 @@ var clusterFinalizer = "finalizer.kafkaclusters.kafka.banzaicloud.io"
 var clusterTopicsFinalizer = "topics.kafkaclusters.kafka.banzaicloud.io"
 var clusterUsersFinalizer = "users.kafkaclusters.kafka.banzaicloud.io"
 
+// storageAutoscalingCheckInterval is how often the disk usage of the brokers is checked when storage autoscaling is enabled
+const storageAutoscalingCheckInterval = time.Minute
+
+// brokerTemplateCheckInterval is how often the removal of the brokers derived from the broker template is retried
+// while it is deferred by active Cruise Control operations
+const brokerTemplateCheckInterval = 20 * time.Second
+
 // KafkaClusterReconciler reconciles a KafkaCluster object
 type KafkaClusterReconciler struct {
 	client.Client
 	DirectClient        client.Reader
 	Namespaces          []string
 	KafkaClientProvider kafkaclient.Provider
+	Recorder            events.EventRecorder
 }
 
 // Reconcile reads that state of the cluster for a KafkaCluster object and makes changes based on the state read
@@ -75,6 +84,7 @@ type KafkaClusterReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete;patch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
@@ -82,11 +92,13 @@ type KafkaClusterReconciler struct {
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusters/finalizers,verbs=create;update;patch;delete
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusterbackups,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=projectcontour.io,resources=httpproxies,verbs=get;list;watch;create;update;patch;delete
 
@@ -113,19 +125,30 @@ func (r *KafkaClusterReconciler) Reconcile(ctx context.Context, request ctrl.Req
 		return r.checkFinalizers(ctx, instance)
 	}
 
+	// In dry run no resource is changed, only the rolling upgrade plan of the spec is written
+	if instance.Spec.DryRun {
+		if err := kafka.New(r.Client, r.DirectClient, instance, r.KafkaClientProvider).Plan(log); err != nil {
+			return requeueWithError(log, err.Error(), err)
+		}
+		return reconciled()
+	}
+
 	if instance.Status.State != v1beta1.KafkaClusterRollingUpgrading {
 		if err := k8sutil.UpdateCRStatus(r.Client, instance, v1beta1.KafkaClusterReconciling, log); err != nil {
 			return requeueWithError(log, err.Error(), err)
 		}
 	}
 
+	kafkaReconciler := kafka.New(r.Client, r.DirectClient, instance, r.KafkaClientProvider)
+	kafkaReconciler.Recorder = r.Recorder
+
 	reconcilers := []resources.ComponentReconciler{
 		envoy.New(r.Client, instance),
 		nodeportexternalaccess.New(r.Client, instance),
 		contouringress.New(r.Client, instance),
 		kafkamonitoring.New(r.Client, instance),
 		cruisecontrolmonitoring.New(r.Client, instance),
-		kafka.New(r.Client, r.DirectClient, instance, r.KafkaClientProvider),
+		kafkaReconciler,
 		cruisecontrol.New(r.Client, instance, r.KafkaClientProvider),
 	}
 
@@ -200,6 +223,24 @@ func (r *KafkaClusterReconciler) Reconcile(ctx context.Context, request ctrl.Req
 		return requeueWithError(log, err.Error(), err)
 	}
 
+	// the offline log directories, the disk usage of the brokers and the deferred removal of the brokers derived from
+	// the broker template are checked periodically
+	var requeueAfter time.Duration
+	if instance.Spec.OfflineLogDirs != nil {
+		requeueAfter = instance.Spec.OfflineLogDirs.GetCheckInterval()
+	}
+	if instance.Spec.IsStorageAutoscalingEnabled() && (requeueAfter == 0 || requeueAfter > storageAutoscalingCheckInterval) {
+		requeueAfter = storageAutoscalingCheckInterval
+	}
+	if isBrokerTemplateScaling(instance) && (requeueAfter == 0 || requeueAfter > brokerTemplateCheckInterval) {
+		requeueAfter = brokerTemplateCheckInterval
+	}
+	if requeueAfter > 0 {
+		return ctrl.Result{
+			RequeueAfter: requeueAfter,
+		}, nil
+	}
+
 	return reconciled()
 }
 
@@ -312,6 +353,12 @@ func (r *KafkaClusterReconciler) checkFinalizers(ctx context.Context, cluster *v
 		}
 	}
 
+	if cluster.Spec.PersistentVolumeClaimRetentionPolicy.RetainWhenDeleted() {
+		if err = kafka.RetainBrokerPvcs(ctx, r.Client, cluster, log); err != nil {
+			return requeueWithError(log, "failed to retain broker pvcs", err)
+		}
+	}
+
 	log.Info("Finalizing deletion of kafkacluster instance")
 	if _, err = r.removeFinalizer(ctx, cluster, clusterFinalizer); err != nil {
 		if client.IgnoreNotFound(err) == nil {
@@ -325,6 +372,13 @@ func (r *KafkaClusterReconciler) checkFinalizers(ctx context.Context, cluster *v
 	return ctrl.Result{}, nil
 }
 
+// isBrokerTemplateScaling returns true if the number of brokers derived from the broker template does not match its replicas
+func isBrokerTemplateScaling(cluster *v1beta1.KafkaCluster) bool {
+	template := cluster.Spec.BrokerTemplate
+	return template != nil && template.Replicas != nil && cluster.Status.BrokerTemplate != nil &&
+		*template.Replicas != cluster.Status.BrokerTemplate.Replicas
+}
+
 func topicListToStrSlice(list v1alpha1.KafkaTopicList) []string {
 	names := make([]string, 0)
 	for _, topic := range list.Items {
@@ -425,7 +479,20 @@ func SetupKafkaClusterWithManager(mgr ctrl.Manager, contourEnabled bool) *ctrl.B
 			},
 			UpdateFunc: func(e event.UpdateEvent) bool {
 				switch newObj := e.ObjectNew.(type) {
-				case *corev1.Pod, *corev1.ConfigMap, *corev1.PersistentVolumeClaim:
+				case *corev1.PersistentVolumeClaim:
+					// the expansion of the volume is followed by the capacity and the conditions of the claim
+					oldObj := e.ObjectOld.(*corev1.PersistentVolumeClaim)
+					if !reflect.DeepEqual(oldObj.Status.Capacity, newObj.Status.Capacity) ||
+						!reflect.DeepEqual(oldObj.Status.Conditions, newObj.Status.Conditions) {
+						return true
+					}
+					patchResult, err := patch.DefaultPatchMaker.Calculate(e.ObjectOld, e.ObjectNew, patch.IgnoreStatusFields(), ignoreMetadataFields("managedFields", "resourceVersion"))
+					if err != nil {
+						log.Error(err, "could not match objects", "kind", e.ObjectOld.GetObjectKind())
+					} else if patchResult.IsEmpty() {
+						return false
+					}
+				case *corev1.Pod, *corev1.ConfigMap:
 					patchResult, err := patch.DefaultPatchMaker.Calculate(e.ObjectOld, e.ObjectNew, patch.IgnoreStatusFields(), ignoreMetadataFields("managedFields", "resourceVersion"))
 					if err != nil {
 						log.Error(err, "could not match objects", "kind", e.ObjectOld.GetObjectKind())
@@ -437,6 +504,9 @@ func SetupKafkaClusterWithManager(mgr ctrl.Manager, contourEnabled bool) *ctrl.B
 					if !reflect.DeepEqual(oldObj.Spec, newObj.Spec) ||
 						oldObj.GetDeletionTimestamp() != newObj.GetDeletionTimestamp() ||
 						oldObj.GetGeneration() != newObj.GetGeneration() ||
+						oldObj.GetAnnotations()[v1beta1.RestartBrokersAnnotationKey] != newObj.GetAnnotations()[v1beta1.RestartBrokersAnnotationKey] ||
+						oldObj.GetAnnotations()[v1beta1.FlushBrokersAnnotationKey] != newObj.GetAnnotations()[v1beta1.FlushBrokersAnnotationKey] ||
+						oldObj.GetAnnotations()[v1beta1.ReplaceBrokerAnnotationKey] != newObj.GetAnnotations()[v1beta1.ReplaceBrokerAnnotationKey] ||
 						!reflect.DeepEqual(oldObj.Status.BrokersState, newObj.Status.BrokersState) {
 						return true
 					}
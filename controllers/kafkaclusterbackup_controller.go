@@ -0,0 +1,324 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+)
+
+// backupRequeueSec is how often the progress of a backup is checked, the volume snapshots are not watched so the
+// operator runs without the CSI snapshot CRDs
+const backupRequeueSec = 10
+
+// backupFinalizer releases the forced flush of the brokers when a backup flushing them is deleted
+var backupFinalizer = "finalizer.kafkaclusterbackups.kafka.banzaicloud.io"
+
+// volumeSnapshotGVK is the kind of the CSI volume snapshots taken of the broker volumes
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+// KafkaClusterBackupReconciler reconciles KafkaClusterBackup custom resources
+type KafkaClusterBackupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusterbackups,verbs=get;list;watch;create;update;patch;delete;deletecollection
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusterbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusterbackups/finalizers,verbs=create;update;patch;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+
+// Reconcile takes a volume snapshot of every broker volume of the cluster of the backup. When the brokers are flushed
+// first, the backup annotates the cluster so the kafka reconciler forces the flush through the per-broker configs, and
+// the volumes are snapshotted once every broker reports the forced flush.
+func (r *KafkaClusterBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	backup := &v1alpha1.KafkaClusterBackup{}
+	if err := r.Get(ctx, req.NamespacedName, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			return reconciled()
+		}
+		// Error reading the object - requeue the request.
+		return requeueWithError(log, err.Error(), err)
+	}
+	if k8sutil.IsMarkedForDeletion(backup.ObjectMeta) {
+		return r.finalize(ctx, log, backup)
+	}
+	if backup.IsFinished() {
+		return reconciled()
+	}
+
+	// the volume snapshots are taken in the namespace of the persistent volume claims
+	if getClusterRefNamespace(backup.Namespace, backup.Spec.ClusterRef) != backup.Namespace {
+		return r.fail(ctx, log, backup, nil, "the cluster has to be in the namespace of the backup")
+	}
+	cluster := &v1beta1.KafkaCluster{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.ClusterRef.Name}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.fail(ctx, log, backup, nil, "the cluster is not found")
+		}
+		return requeueWithError(log, "failed to get cluster of backup", err)
+	}
+
+	switch backup.Status.Phase {
+	case "":
+		if backup.Spec.FlushBrokers {
+			if holder, ok := cluster.GetAnnotations()[v1beta1.FlushBrokersAnnotationKey]; ok && holder != backup.Name {
+				log.Info("waiting for the brokers to be flushed for another backup", "backup", holder)
+				return requeueAfter(backupRequeueSec)
+			}
+			// the finalizer is added before the cluster is annotated so the flush is released when the backup is deleted
+			if controllerutil.AddFinalizer(backup, backupFinalizer) {
+				if err := r.Update(ctx, backup); err != nil {
+					return requeueWithError(log, "failed to add finalizer to backup", err)
+				}
+			}
+		}
+		now := metav1.Now()
+		backup.Status.StartTime = &now
+		backup.Status.Phase = v1alpha1.BackupSnapshotting
+		if backup.Spec.FlushBrokers {
+			patch := client.MergeFrom(cluster.DeepCopy())
+			cluster.SetAnnotations(apiutil.MergeLabels(cluster.GetAnnotations(), map[string]string{v1beta1.FlushBrokersAnnotationKey: backup.Name}))
+			if err := r.Patch(ctx, cluster, patch); err != nil {
+				return requeueWithError(log, "failed to request the flush of the brokers", err)
+			}
+			backup.Status.Phase = v1alpha1.BackupFlushing
+		}
+		if err := r.Status().Update(ctx, backup); err != nil {
+			return requeueWithError(log, "failed to update backup status", err)
+		}
+		log.Info("backup started", "phase", backup.Status.Phase)
+		return requeueAfter(backupRequeueSec)
+	case v1alpha1.BackupFlushing:
+		for _, broker := range cluster.Spec.Brokers {
+			if !cluster.Status.BrokersState[strconv.Itoa(int(broker.Id))].LogFlushForced {
+				log.Info("waiting for the broker to flush its logs on every message", v1beta1.BrokerIdLabelKey, broker.Id)
+				return requeueAfter(backupRequeueSec)
+			}
+		}
+		backup.Status.Phase = v1alpha1.BackupSnapshotting
+		if err := r.Status().Update(ctx, backup); err != nil {
+			return requeueWithError(log, "failed to update backup status", err)
+		}
+		return reconciled()
+	case v1alpha1.BackupSnapshotting:
+		if len(backup.Status.VolumeSnapshots) == 0 {
+			return r.takeVolumeSnapshots(ctx, log, backup, cluster)
+		}
+		return r.checkVolumeSnapshots(ctx, log, backup, cluster)
+	}
+	return reconciled()
+}
+
+// takeVolumeSnapshots creates a volume snapshot of every broker volume and records them in the backup status, the
+// forced flush of the brokers is released once the snapshots are taken
+func (r *KafkaClusterBackupReconciler) takeVolumeSnapshots(ctx context.Context, log logr.Logger, backup *v1alpha1.KafkaClusterBackup, cluster *v1beta1.KafkaCluster) (ctrl.Result, error) {
+	var pvcList corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcList, client.InNamespace(cluster.Namespace), client.MatchingLabels(apiutil.LabelsForKafka(cluster.Name))); err != nil {
+		return requeueWithError(log, "failed to list broker pvcs", err)
+	}
+	brokerIDs := make(map[string]bool, len(cluster.Spec.Brokers))
+	for _, broker := range cluster.Spec.Brokers {
+		brokerIDs[strconv.Itoa(int(broker.Id))] = true
+	}
+
+	var volumeSnapshots []v1alpha1.BrokerVolumeSnapshot
+	for _, pvc := range pvcList.Items {
+		brokerID := pvc.Labels[v1beta1.BrokerIdLabelKey]
+		if !brokerIDs[brokerID] || pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+		id, err := strconv.ParseInt(brokerID, 10, 32)
+		if err != nil {
+			return r.fail(ctx, log, backup, cluster, fmt.Sprintf("invalid broker id of pvc %s: %s", pvc.Name, brokerID))
+		}
+
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+		snapshot.SetNamespace(pvc.Namespace)
+		snapshot.SetName(fmt.Sprintf("%s-%s", backup.Name, pvc.Name))
+		snapshot.SetLabels(apiutil.MergeLabels(apiutil.LabelsForKafka(cluster.Name), map[string]string{v1beta1.BrokerIdLabelKey: brokerID}))
+		if err := unstructured.SetNestedField(snapshot.Object, pvc.Name, "spec", "source", "persistentVolumeClaimName"); err != nil {
+			return requeueWithError(log, "failed to generate volume snapshot", err)
+		}
+		if backup.Spec.VolumeSnapshotClassName != "" {
+			if err := unstructured.SetNestedField(snapshot.Object, backup.Spec.VolumeSnapshotClassName, "spec", "volumeSnapshotClassName"); err != nil {
+				return requeueWithError(log, "failed to generate volume snapshot", err)
+			}
+		}
+		if err := controllerutil.SetControllerReference(backup, snapshot, r.Scheme); err != nil {
+			return requeueWithError(log, "failed to set owner of volume snapshot", err)
+		}
+		if err := r.Create(ctx, snapshot); err != nil && !apierrors.IsAlreadyExists(err) {
+			if meta.IsNoMatchError(err) {
+				return r.fail(ctx, log, backup, cluster, "the VolumeSnapshot CRD of the CSI external snapshotter is not installed")
+			}
+			return requeueWithError(log, "failed to create volume snapshot", err)
+		}
+		log.Info("volume snapshot created", v1beta1.BrokerIdLabelKey, brokerID, "persistentVolumeClaim", pvc.Name, "volumeSnapshot", snapshot.GetName())
+
+		volumeSnapshots = append(volumeSnapshots, v1alpha1.BrokerVolumeSnapshot{
+			BrokerID:                  int32(id),
+			MountPath:                 pvc.Annotations[v1beta1.MountPathAnnotationKey],
+			PersistentVolumeClaimName: pvc.Name,
+			VolumeSnapshotName:        snapshot.GetName(),
+		})
+	}
+	if len(volumeSnapshots) == 0 {
+		return r.fail(ctx, log, backup, cluster, "the cluster has no bound broker volume")
+	}
+	sort.Slice(volumeSnapshots, func(i, j int) bool {
+		if volumeSnapshots[i].BrokerID != volumeSnapshots[j].BrokerID {
+			return volumeSnapshots[i].BrokerID < volumeSnapshots[j].BrokerID
+		}
+		return volumeSnapshots[i].MountPath < volumeSnapshots[j].MountPath
+	})
+
+	backup.Status.VolumeSnapshots = volumeSnapshots
+	backup.Status.ClusterID = cluster.Status.ClusterID
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return requeueWithError(log, "failed to update backup status", err)
+	}
+	if err := r.releaseBrokerFlush(ctx, backup, cluster); err != nil {
+		return requeueWithError(log, "failed to release the flush of the brokers", err)
+	}
+	return requeueAfter(backupRequeueSec)
+}
+
+// checkVolumeSnapshots follows the volume snapshots of the backup until every one of them is ready to be restored from
+func (r *KafkaClusterBackupReconciler) checkVolumeSnapshots(ctx context.Context, log logr.Logger, backup *v1alpha1.KafkaClusterBackup, cluster *v1beta1.KafkaCluster) (ctrl.Result, error) {
+	ready := true
+	for i := range backup.Status.VolumeSnapshots {
+		volumeSnapshot := &backup.Status.VolumeSnapshots[i]
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+		if err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: volumeSnapshot.VolumeSnapshotName}, snapshot); err != nil {
+			if apierrors.IsNotFound(err) {
+				return r.fail(ctx, log, backup, cluster, fmt.Sprintf("volume snapshot %s is deleted", volumeSnapshot.VolumeSnapshotName))
+			}
+			return requeueWithError(log, "failed to get volume snapshot", err)
+		}
+
+		if message, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found && message != "" {
+			return r.fail(ctx, log, backup, cluster, fmt.Sprintf("volume snapshot %s failed: %s", volumeSnapshot.VolumeSnapshotName, message))
+		}
+		volumeSnapshot.ReadyToUse, _, _ = unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+		if restoreSize, found, _ := unstructured.NestedString(snapshot.Object, "status", "restoreSize"); found {
+			if size, err := resource.ParseQuantity(restoreSize); err == nil {
+				volumeSnapshot.RestoreSize = &size
+			}
+		}
+		ready = ready && volumeSnapshot.ReadyToUse
+	}
+
+	if ready {
+		now := metav1.Now()
+		backup.Status.Phase = v1alpha1.BackupSucceeded
+		backup.Status.CompletionTime = &now
+		log.Info("backup succeeded", "volumeSnapshots", len(backup.Status.VolumeSnapshots))
+	}
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return requeueWithError(log, "failed to update backup status", err)
+	}
+	if ready {
+		return reconciled()
+	}
+	return requeueAfter(backupRequeueSec)
+}
+
+// fail marks the backup failed and releases the forced flush of the brokers of the cluster
+func (r *KafkaClusterBackupReconciler) fail(ctx context.Context, log logr.Logger, backup *v1alpha1.KafkaClusterBackup, cluster *v1beta1.KafkaCluster, message string) (ctrl.Result, error) {
+	log.Info("backup failed", "reason", message)
+	if cluster != nil {
+		if err := r.releaseBrokerFlush(ctx, backup, cluster); err != nil {
+			return requeueWithError(log, "failed to release the flush of the brokers", err)
+		}
+	}
+	now := metav1.Now()
+	backup.Status.Phase = v1alpha1.BackupFailed
+	backup.Status.Message = message
+	backup.Status.CompletionTime = &now
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return requeueWithError(log, "failed to update backup status", err)
+	}
+	return reconciled()
+}
+
+// releaseBrokerFlush removes the flush-brokers annotation of the cluster when it was set by the backup
+func (r *KafkaClusterBackupReconciler) releaseBrokerFlush(ctx context.Context, backup *v1alpha1.KafkaClusterBackup, cluster *v1beta1.KafkaCluster) error {
+	if cluster.GetAnnotations()[v1beta1.FlushBrokersAnnotationKey] != backup.Name {
+		return nil
+	}
+	patch := client.MergeFrom(cluster.DeepCopy())
+	annotations := cluster.GetAnnotations()
+	delete(annotations, v1beta1.FlushBrokersAnnotationKey)
+	cluster.SetAnnotations(annotations)
+	return errors.WrapIf(r.Patch(ctx, cluster, patch), "could not remove flush-brokers annotation")
+}
+
+// finalize releases the forced flush of the brokers held by the deleted backup and removes its finalizer
+func (r *KafkaClusterBackupReconciler) finalize(ctx context.Context, log logr.Logger, backup *v1alpha1.KafkaClusterBackup) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(backup, backupFinalizer) {
+		return reconciled()
+	}
+	cluster := &v1beta1.KafkaCluster{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.ClusterRef.Name}, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return requeueWithError(log, "failed to get cluster of backup", err)
+	default:
+		if err := r.releaseBrokerFlush(ctx, backup, cluster); err != nil {
+			return requeueWithError(log, "failed to release the flush of the brokers", err)
+		}
+	}
+	controllerutil.RemoveFinalizer(backup, backupFinalizer)
+	if err := r.Update(ctx, backup); err != nil {
+		return requeueWithError(log, "failed to remove finalizer from backup", err)
+	}
+	return reconciled()
+}
+
+// SetupKafkaClusterBackupWithManager registers the kafka cluster backup controller to the manager
+func SetupKafkaClusterBackupWithManager(mgr ctrl.Manager) *ctrl.Builder {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.KafkaClusterBackup{}).
+		WithEventFilter(SkipClusterRegistryOwnedResourcePredicate{}).
+		Named("KafkaClusterBackup")
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func newBackupBrokerPvc(name, brokerID, mountPath string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        name,
+			Namespace:   "kafka",
+			Labels:      apiutil.MergeLabels(apiutil.LabelsForKafka("kafka"), map[string]string{v1beta1.BrokerIdLabelKey: brokerID}),
+			Annotations: map[string]string{v1beta1.MountPathAnnotationKey: mountPath},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+}
+
+func TestKafkaClusterBackupReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	scheme.AddKnownTypeWithName(volumeSnapshotGVK, &unstructured.Unstructured{})
+
+	kafkaCluster := &v1beta1.KafkaCluster{
+		ObjectMeta: v1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec:       v1beta1.KafkaClusterSpec{Brokers: []v1beta1.Broker{{Id: 0}, {Id: 1}}},
+		Status:     v1beta1.KafkaClusterStatus{ClusterID: "Rb3Vd1LbRk2uSSOLRkvxBw"},
+	}
+	backup := &v1alpha1.KafkaClusterBackup{
+		ObjectMeta: v1.ObjectMeta{Name: "backup", Namespace: "kafka"},
+		Spec: v1alpha1.KafkaClusterBackupSpec{
+			ClusterRef:              v1alpha1.ClusterReference{Name: "kafka"},
+			VolumeSnapshotClassName: "csi-snapclass",
+			FlushBrokers:            true,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.KafkaClusterBackup{}, &v1beta1.KafkaCluster{}).
+		WithObjects(kafkaCluster, backup,
+			newBackupBrokerPvc("kafka-1-storage-0-abcde", "1", "/kafka-logs"),
+			newBackupBrokerPvc("kafka-0-storage-0-fghij", "0", "/kafka-logs"),
+			// the volume of a removed broker is not snapshotted
+			newBackupBrokerPvc("kafka-2-storage-0-klmno", "2", "/kafka-logs")).
+		Build()
+	r := &KafkaClusterBackupReconciler{Client: fakeClient, Scheme: scheme}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(backup)}
+	reconcileBackup := func() (*v1alpha1.KafkaClusterBackup, *v1beta1.KafkaCluster) {
+		_, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+		require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, backup))
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(kafkaCluster), kafkaCluster))
+		return backup, kafkaCluster
+	}
+
+	// the brokers are asked to flush their logs
+	backup, kafkaCluster = reconcileBackup()
+	require.Equal(t, v1alpha1.BackupFlushing, backup.Status.Phase)
+	require.NotNil(t, backup.Status.StartTime)
+	require.Equal(t, "backup", kafkaCluster.GetAnnotations()[v1beta1.FlushBrokersAnnotationKey])
+
+	// the volumes are not snapshotted until every broker flushes its logs
+	kafkaCluster.Status.BrokersState = map[string]v1beta1.BrokerState{"0": {LogFlushForced: true}}
+	require.NoError(t, fakeClient.Status().Update(ctx, kafkaCluster))
+	backup, _ = reconcileBackup()
+	require.Equal(t, v1alpha1.BackupFlushing, backup.Status.Phase)
+
+	kafkaCluster.Status.BrokersState["1"] = v1beta1.BrokerState{LogFlushForced: true}
+	require.NoError(t, fakeClient.Status().Update(ctx, kafkaCluster))
+	backup, _ = reconcileBackup()
+	require.Equal(t, v1alpha1.BackupSnapshotting, backup.Status.Phase)
+
+	// the volumes are snapshotted and the flush of the brokers is released
+	backup, kafkaCluster = reconcileBackup()
+	require.Equal(t, []v1alpha1.BrokerVolumeSnapshot{
+		{BrokerID: 0, MountPath: "/kafka-logs", PersistentVolumeClaimName: "kafka-0-storage-0-fghij", VolumeSnapshotName: "backup-kafka-0-storage-0-fghij"},
+		{BrokerID: 1, MountPath: "/kafka-logs", PersistentVolumeClaimName: "kafka-1-storage-0-abcde", VolumeSnapshotName: "backup-kafka-1-storage-0-abcde"},
+	}, backup.Status.VolumeSnapshots)
+	require.Equal(t, "Rb3Vd1LbRk2uSSOLRkvxBw", backup.Status.ClusterID)
+	require.NotContains(t, kafkaCluster.GetAnnotations(), v1beta1.FlushBrokersAnnotationKey)
+
+	snapshots := make([]*unstructured.Unstructured, 0, len(backup.Status.VolumeSnapshots))
+	for _, volumeSnapshot := range backup.Status.VolumeSnapshots {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: "kafka", Name: volumeSnapshot.VolumeSnapshotName}, snapshot))
+		pvcName, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		require.Equal(t, volumeSnapshot.PersistentVolumeClaimName, pvcName)
+		className, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+		require.Equal(t, "csi-snapclass", className)
+		require.Equal(t, "backup", snapshot.GetOwnerReferences()[0].Name)
+		snapshots = append(snapshots, snapshot)
+	}
+
+	// the backup succeeds once every volume snapshot is ready
+	require.NoError(t, unstructured.SetNestedField(snapshots[0].Object, map[string]interface{}{"readyToUse": true, "restoreSize": "10Gi"}, "status"))
+	require.NoError(t, fakeClient.Update(ctx, snapshots[0]))
+	backup, _ = reconcileBackup()
+	require.Equal(t, v1alpha1.BackupSnapshotting, backup.Status.Phase)
+	require.True(t, backup.Status.VolumeSnapshots[0].ReadyToUse)
+	require.Equal(t, "10Gi", backup.Status.VolumeSnapshots[0].RestoreSize.String())
+
+	require.NoError(t, unstructured.SetNestedField(snapshots[1].Object, map[string]interface{}{"readyToUse": true}, "status"))
+	require.NoError(t, fakeClient.Update(ctx, snapshots[1]))
+	backup, _ = reconcileBackup()
+	require.Equal(t, v1alpha1.BackupSucceeded, backup.Status.Phase)
+	require.NotNil(t, backup.Status.CompletionTime)
+}
+
+func TestKafkaClusterBackupDeletionReleasesBrokerFlush(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	kafkaCluster := &v1beta1.KafkaCluster{
+		ObjectMeta: v1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec:       v1beta1.KafkaClusterSpec{Brokers: []v1beta1.Broker{{Id: 0}}},
+	}
+	backup := &v1alpha1.KafkaClusterBackup{
+		ObjectMeta: v1.ObjectMeta{Name: "backup", Namespace: "kafka"},
+		Spec: v1alpha1.KafkaClusterBackupSpec{
+			ClusterRef:   v1alpha1.ClusterReference{Name: "kafka"},
+			FlushBrokers: true,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.KafkaClusterBackup{}, &v1beta1.KafkaCluster{}).
+		WithObjects(kafkaCluster, backup).
+		Build()
+	r := &KafkaClusterBackupReconciler{Client: fakeClient, Scheme: scheme}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(backup)}
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, backup))
+	require.Equal(t, v1alpha1.BackupFlushing, backup.Status.Phase)
+	require.Contains(t, backup.GetFinalizers(), backupFinalizer)
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(kafkaCluster), kafkaCluster))
+	require.Equal(t, "backup", kafkaCluster.GetAnnotations()[v1beta1.FlushBrokersAnnotationKey])
+
+	// the flush of the brokers is released when the backup is deleted while flushing
+	require.NoError(t, fakeClient.Delete(ctx, backup))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(kafkaCluster), kafkaCluster))
+	require.NotContains(t, kafkaCluster.GetAnnotations(), v1beta1.FlushBrokersAnnotationKey)
+	require.True(t, apierrors.IsNotFound(fakeClient.Get(ctx, req.NamespacedName, backup)))
+}
+
+func TestKafkaClusterBackupReconcileClusterInAnotherNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	backup := &v1alpha1.KafkaClusterBackup{
+		ObjectMeta: v1.ObjectMeta{Name: "backup", Namespace: "backups"},
+		Spec:       v1alpha1.KafkaClusterBackupSpec{ClusterRef: v1alpha1.ClusterReference{Name: "kafka", Namespace: "kafka"}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.KafkaClusterBackup{}).
+		WithObjects(backup).
+		Build()
+	r := &KafkaClusterBackupReconciler{Client: fakeClient, Scheme: scheme}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(backup)})
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(backup), backup))
+	require.Equal(t, v1alpha1.BackupFailed, backup.Status.Phase)
+	require.Equal(t, "the cluster has to be in the namespace of the backup", backup.Status.Message)
+}
@@ -163,7 +163,7 @@ func (r *KafkaTopicReconciler) Reconcile(ctx context.Context, request reconcile.
 			reqLogger.Info("Increased partition count for topic")
 		}
 		// Ensure topic configurations
-		if err = broker.EnsureTopicConfig(instance.Spec.Name, util.MapStringStringPointer(instance.Spec.Config)); err != nil {
+		if err = broker.EnsureTopicConfig(instance.Spec.Name, util.MapStringStringPointer(instance.Spec.GetConfig())); err != nil {
 			return requeueWithError(reqLogger, "failure to ensure topic config", err)
 		}
 		reqLogger.Info("Verified partitions and configuration for topic")
@@ -172,7 +172,7 @@ func (r *KafkaTopicReconciler) Reconcile(ctx context.Context, request reconcile.
 		Name:              instance.Spec.Name,
 		Partitions:        instance.Spec.Partitions,
 		ReplicationFactor: int16(instance.Spec.ReplicationFactor),
-		Config:            util.MapStringStringPointer(instance.Spec.Config),
+		Config:            util.MapStringStringPointer(instance.Spec.GetConfig()),
 	}); err != nil {
 		return requeueWithError(reqLogger, "failed to create kafka topic", err)
 	}
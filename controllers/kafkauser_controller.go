@@ -31,9 +31,12 @@ import (
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/go-logr/logr"
 	certsigningreqv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlBuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,12 +52,16 @@ import (
 	"github.com/banzaicloud/koperator/pkg/k8sutil"
 	"github.com/banzaicloud/koperator/pkg/pki"
 	"github.com/banzaicloud/koperator/pkg/util"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
 	kafkautil "github.com/banzaicloud/koperator/pkg/util/kafka"
 	pkicommon "github.com/banzaicloud/koperator/pkg/util/pki"
 )
 
 var userFinalizer = "finalizer.kafkausers.kafka.banzaicloud.io"
 
+// userCertificateRenewalCheckInterval is how often a user waiting for its re-issued certificate is reconciled
+const userCertificateRenewalCheckInterval = time.Minute
+
 // SetupKafkaUserWithManager registers KafkaUser controller to the manager
 func SetupKafkaUserWithManager(mgr ctrl.Manager, certSigningEnabled bool, certManagerEnabled bool) *ctrl.Builder {
 	log := mgr.GetLogger()
@@ -148,8 +155,9 @@ var _ reconcile.Reconciler = &KafkaUserReconciler{}
 type KafkaUserReconciler struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	Client client.Client
-	Scheme *runtime.Scheme
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkausers,verbs=get;list;watch;create;update;patch;delete;deletecollection
@@ -161,6 +169,8 @@ type KafkaUserReconciler struct {
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/approval,verbs=update
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=signers,verbs=approve
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 
 // Reconcile reads that state of the cluster for a KafkaUser object and makes changes based on the state read
 // and what is in the KafkaUser.Spec
@@ -196,6 +206,7 @@ func (r *KafkaUserReconciler) Reconcile(ctx context.Context, request reconcile.R
 	}
 
 	var kafkaUser string
+	var certStatus *v1alpha1.UserCertificateStatus
 
 	if instance.Spec.GetIfCertShouldBeCreated() {
 		// Validate the KafkaUser instance annotations before creating a certificate request
@@ -254,12 +265,29 @@ func (r *KafkaUserReconciler) Reconcile(ctx context.Context, request reconcile.R
 				Requeue: false,
 			}, err
 		}
+		// the ACLs have to reference the principal the brokers map the certificate name to
+		if kafkaUser, err = kafkautil.MapPrincipal(cluster.Spec.ListenersConfig.PrincipalMappingRules, kafkaUser); err != nil {
+			return requeueWithError(reqLogger, "failed to map kafkauser to principal", err)
+		}
 		// check if marked for deletion and remove created certs
 		if k8sutil.IsMarkedForDeletion(instance.ObjectMeta) {
 			reqLogger.Info("Kafka user is marked for deletion, revoking certificates")
 			if err = pkiManager.FinalizeUserCertificate(ctx, instance); err != nil {
 				return requeueWithError(reqLogger, "failed to finalize user certificate", err)
 			}
+			kafkaUserCertificateExpiry.DeleteLabelValues(instance.Namespace, instance.Name)
+			kafkaUserCertificateRenewals.DeleteLabelValues(instance.Namespace, instance.Name)
+		} else {
+			if certStatus, err = userCertificateStatus(user.Certificate, instance.Spec.GetRenewBefore()); err != nil {
+				return requeueWithError(reqLogger, "could not get certificate details from the generated TLS certificate", err)
+			}
+			kafkaUserCertificateExpiry.WithLabelValues(instance.Namespace, instance.Name).Set(float64(certStatus.NotAfter.Unix()))
+			// the ACLs and the status are still reconciled while the certificate is re-issued
+			if !time.Now().Before(certStatus.RenewalTime.Time) {
+				if err = r.renewUserCertificate(ctx, pkiManager, instance, certStatus); err != nil {
+					return requeueWithError(reqLogger, "failed to renew user certificate", err)
+				}
+			}
 		}
 	} else {
 		kafkaUser = fmt.Sprintf("CN=%s", instance.Name)
@@ -301,9 +329,24 @@ func (r *KafkaUserReconciler) Reconcile(ctx context.Context, request reconcile.R
 		}
 	}
 
+	// write the client connection bundle when requested
+	if instance.Spec.ClientBundle != nil {
+		if err = r.reconcileClientBundle(ctx, cluster, instance); err != nil {
+			if errors.As(err, &errorfactory.ResourceNotReady{}) {
+				reqLogger.Info("client bundle cannot be created yet", "reason", err.Error())
+				return ctrl.Result{
+					Requeue:      true,
+					RequeueAfter: time.Duration(15) * time.Second,
+				}, nil
+			}
+			return requeueWithError(reqLogger, "failed to reconcile client bundle", err)
+		}
+	}
+
 	// set user status
 	instance.Status = v1alpha1.KafkaUserStatus{
-		State: v1alpha1.UserStateCreated,
+		State:       v1alpha1.UserStateCreated,
+		Certificate: certStatus,
 	}
 	if len(instance.Spec.TopicGrants) > 0 {
 		instance.Status.ACLs = kafkautil.GrantsToACLStrings(kafkaUser, instance.Spec.TopicGrants)
@@ -312,9 +355,125 @@ func (r *KafkaUserReconciler) Reconcile(ctx context.Context, request reconcile.R
 		return requeueWithError(reqLogger, "failed to update kafkauser status", err)
 	}
 
+	if certStatus != nil {
+		if certStatus.RenewalRequested {
+			// come back to pick up the re-issued certificate
+			return ctrl.Result{RequeueAfter: userCertificateRenewalCheckInterval}, nil
+		}
+		// come back when the certificate is due for renewal
+		return ctrl.Result{RequeueAfter: time.Until(certStatus.RenewalTime.Time)}, nil
+	}
 	return reconciled()
 }
 
+// userCertificateStatus extracts the status details of a PEM encoded user certificate. The renewal time
+// falls back to two thirds of the certificate validity when renewBefore exceeds it.
+func userCertificateStatus(certPEM []byte, renewBefore time.Duration) (*v1alpha1.UserCertificateStatus, error) {
+	cert, err := certutil.DecodeCertificate(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	renewalTime := cert.NotAfter.Add(-renewBefore)
+	if !renewalTime.After(cert.NotBefore) {
+		renewalTime = cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) * 2 / 3)
+	}
+	return &v1alpha1.UserCertificateStatus{
+		NotAfter:     metav1.NewTime(cert.NotAfter),
+		SerialNumber: fmt.Sprintf("%X", cert.SerialNumber),
+		Issuer:       cert.Issuer.String(),
+		RenewalTime:  metav1.NewTime(renewalTime),
+	}, nil
+}
+
+// renewUserCertificate triggers the re-issuance of a user certificate which is about to expire. The renewal is
+// requested once per certificate, it is recorded in the certificate status until the certificate is replaced.
+func (r *KafkaUserReconciler) renewUserCertificate(ctx context.Context, pkiManager pkicommon.Manager,
+	user *v1alpha1.KafkaUser, certStatus *v1alpha1.UserCertificateStatus) error {
+	if current := user.Status.Certificate; current != nil && current.SerialNumber == certStatus.SerialNumber && current.RenewalRequested {
+		certStatus.RenewalRequested = true
+		return nil
+	}
+	reqLogger := logr.FromContextOrDiscard(ctx)
+	reqLogger.Info("user certificate is about to expire, renewing it",
+		"serialNumber", certStatus.SerialNumber, "notAfter", certStatus.NotAfter)
+	r.Recorder.Eventf(user, nil, corev1.EventTypeWarning, "CertificateExpiring", "RenewCertificate",
+		"certificate %s expires at %s, re-issuing it", certStatus.SerialNumber, certStatus.NotAfter.UTC().Format(time.RFC3339))
+	if err := pkiManager.RenewUserCertificate(ctx, user); err != nil {
+		return err
+	}
+	kafkaUserCertificateRenewals.WithLabelValues(user.Namespace, user.Name).Inc()
+	certStatus.RenewalRequested = true
+	return nil
+}
+
+// reconcileClientBundle writes the client configurations of the user into its secret or into a sibling ConfigMap
+func (r *KafkaUserReconciler) reconcileClientBundle(ctx context.Context, cluster *v1beta1.KafkaCluster, user *v1alpha1.KafkaUser) error {
+	bundle := user.Spec.ClientBundle
+	bootstrapServers, protocol, err := kafkautil.ClientBundleListener(cluster, bundle.Listener)
+	if err != nil {
+		return errorfactory.New(errorfactory.ResourceNotReady{}, err, "could not get client bundle listener")
+	}
+
+	secret := &corev1.Secret{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret)
+	switch {
+	case apierrors.IsNotFound(err) && bundle.GetTarget() == v1alpha1.ClientBundleTargetSecret:
+		// users without a certificate get a secret holding only the bundle
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: user.Spec.SecretName, Namespace: user.Namespace},
+		}
+		if err = ctrl.SetControllerReference(user, secret, r.Scheme); err != nil {
+			return errors.WrapIf(err, "could not set controller reference on user secret")
+		}
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not get user secret")
+	}
+
+	bundleData, err := kafkautil.RenderClientBundle(bundle, bootstrapServers, protocol, secret.Data)
+	if err != nil {
+		return err
+	}
+
+	if bundle.GetTarget() == v1alpha1.ClientBundleTargetConfigMap {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bundle.GetConfigMapName(user.Spec.SecretName),
+				Namespace: user.Namespace,
+			},
+		}
+		_, err = ctrl.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+			configMap.Data = bundleData
+			return ctrl.SetControllerReference(user, configMap, r.Scheme)
+		})
+		if err != nil {
+			return errorfactory.New(errorfactory.APIFailure{}, err, "could not reconcile client bundle configmap")
+		}
+		return nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte, len(bundleData))
+	}
+	changed := false
+	for key, value := range bundleData {
+		if string(secret.Data[key]) != value {
+			secret.Data[key] = []byte(value)
+			changed = true
+		}
+	}
+	switch {
+	case secret.CreationTimestamp.IsZero():
+		err = r.Client.Create(ctx, secret)
+	case changed:
+		err = r.Client.Update(ctx, secret)
+	}
+	if err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not write client bundle into user secret")
+	}
+	return nil
+}
+
 func (r *KafkaUserReconciler) ensureClusterLabel(ctx context.Context, cluster *v1beta1.KafkaCluster, user *v1alpha1.KafkaUser) (*v1alpha1.KafkaUser, error) {
 	labels := applyClusterRefLabel(cluster, user.GetLabels())
 	if !reflect.DeepEqual(labels, user.GetLabels()) {
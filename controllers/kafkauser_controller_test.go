@@ -0,0 +1,148 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	pkicommon "github.com/banzaicloud/koperator/pkg/util/pki"
+)
+
+func generateTestUserCert(t *testing.T, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(0xABCDEF),
+		Subject:      pkix.Name{CommonName: "test-user"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestUserCertificateStatus(t *testing.T) {
+	notBefore := time.Now().Truncate(time.Second)
+	notAfter := notBefore.Add(90 * time.Hour)
+	certPEM := generateTestUserCert(t, notBefore, notAfter)
+
+	testCases := []struct {
+		testName            string
+		renewBefore         time.Duration
+		expectedRenewalTime time.Time
+	}{
+		{
+			testName:            "renewal time honours renewBefore",
+			renewBefore:         30 * time.Hour,
+			expectedRenewalTime: notAfter.Add(-30 * time.Hour),
+		},
+		{
+			testName:            "renewBefore exceeding the validity falls back to two thirds of it",
+			renewBefore:         100 * time.Hour,
+			expectedRenewalTime: notBefore.Add(60 * time.Hour),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			status, err := userCertificateStatus(certPEM, test.renewBefore)
+			if err != nil {
+				t.Fatal("Expected no error, got:", err)
+			}
+			if !status.NotAfter.Time.Equal(notAfter) {
+				t.Errorf("Expected notAfter %s, got %s", notAfter, status.NotAfter.Time)
+			}
+			if status.SerialNumber != "ABCDEF" {
+				t.Error("Expected serial number ABCDEF, got:", status.SerialNumber)
+			}
+			if status.Issuer != "CN=test-user" {
+				t.Error("Expected issuer CN=test-user, got:", status.Issuer)
+			}
+			if !status.RenewalTime.Time.Equal(test.expectedRenewalTime) {
+				t.Errorf("Expected renewal time %s, got %s", test.expectedRenewalTime, status.RenewalTime.Time)
+			}
+		})
+	}
+
+	if _, err := userCertificateStatus([]byte("invalid"), time.Hour); err == nil {
+		t.Error("Expected error for invalid certificate, got nil")
+	}
+}
+
+// renewalCountingPKIManager counts the renewals of the user certificates
+type renewalCountingPKIManager struct {
+	pkicommon.Manager
+	renewals int
+}
+
+func (m *renewalCountingPKIManager) RenewUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser) error {
+	m.renewals++
+	return nil
+}
+
+func TestRenewUserCertificate(t *testing.T) {
+	recorder := events.NewFakeRecorder(10)
+	r := &KafkaUserReconciler{Recorder: recorder}
+	pkiManager := &renewalCountingPKIManager{}
+	user := &v1alpha1.KafkaUser{ObjectMeta: metav1.ObjectMeta{Name: "test-user", Namespace: "kafka"}}
+	certStatus := func(serial string) *v1alpha1.UserCertificateStatus {
+		return &v1alpha1.UserCertificateStatus{SerialNumber: serial, NotAfter: metav1.NewTime(time.Now().Add(time.Hour))}
+	}
+
+	status := certStatus("AB")
+	if err := r.renewUserCertificate(context.Background(), pkiManager, user, status); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if !status.RenewalRequested || pkiManager.renewals != 1 || len(recorder.Events) != 1 {
+		t.Errorf("Expected a single renewal and event, got renewals: %d, events: %d", pkiManager.renewals, len(recorder.Events))
+	}
+
+	// the renewal of the same certificate is not requested again
+	user.Status.Certificate = status
+	status = certStatus("AB")
+	if err := r.renewUserCertificate(context.Background(), pkiManager, user, status); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if !status.RenewalRequested || pkiManager.renewals != 1 || len(recorder.Events) != 1 {
+		t.Errorf("Expected no further renewal or event, got renewals: %d, events: %d", pkiManager.renewals, len(recorder.Events))
+	}
+
+	// the re-issued certificate is renewed again once it is due
+	status = certStatus("CD")
+	if err := r.renewUserCertificate(context.Background(), pkiManager, user, status); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if !status.RenewalRequested || pkiManager.renewals != 2 {
+		t.Errorf("Expected the re-issued certificate to be renewed, got renewals: %d", pkiManager.renewals)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "koperator"
+
+var (
+	// kafkaUserCertificateExpiry exposes the expiry time of the certificates issued for KafkaUsers
+	kafkaUserCertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "kafkauser",
+		Name:      "certificate_expiration_timestamp_seconds",
+		Help:      "The date after which the certificate of the KafkaUser expires, expressed as a Unix Epoch Time.",
+	}, []string{"namespace", "name"})
+
+	// kafkaUserCertificateRenewals counts the certificate renewals triggered by the operator
+	kafkaUserCertificateRenewals = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "kafkauser",
+		Name:      "certificate_renewals_total",
+		Help:      "The number of certificate renewals triggered for the KafkaUser.",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		kafkaUserCertificateExpiry,
+		kafkaUserCertificateRenewals,
+	)
+}
@@ -129,6 +129,21 @@ func (mr *MockCruiseControlScalerMockRecorder) BrokersWithState(ctx any, states
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BrokersWithState", reflect.TypeOf((*MockCruiseControlScaler)(nil).BrokersWithState), varargs...)
 }
 
+// FixOfflineReplicasWithParams mocks base method.
+func (m *MockCruiseControlScaler) FixOfflineReplicasWithParams(ctx context.Context, params map[string]string) (*scale.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FixOfflineReplicasWithParams", ctx, params)
+	ret0, _ := ret[0].(*scale.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FixOfflineReplicasWithParams indicates an expected call of FixOfflineReplicasWithParams.
+func (mr *MockCruiseControlScalerMockRecorder) FixOfflineReplicasWithParams(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FixOfflineReplicasWithParams", reflect.TypeOf((*MockCruiseControlScaler)(nil).FixOfflineReplicasWithParams), ctx, params)
+}
+
 // IsReady mocks base method.
 func (m *MockCruiseControlScaler) IsReady(ctx context.Context) bool {
 	m.ctrl.T.Helper()
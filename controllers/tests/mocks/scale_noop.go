@@ -80,6 +80,10 @@ func (n *noopCruiseControlScaler) RemoveDisksWithParams(ctx context.Context, par
 	return &scale.Result{State: v1beta1.CruiseControlTaskActive}, nil
 }
 
+func (n *noopCruiseControlScaler) FixOfflineReplicasWithParams(ctx context.Context, params map[string]string) (*scale.Result, error) {
+	return &scale.Result{State: v1beta1.CruiseControlTaskActive}, nil
+}
+
 func (n *noopCruiseControlScaler) RebalanceDisks(ctx context.Context, brokerIDs ...string) (*scale.Result, error) {
 	return &scale.Result{State: v1beta1.CruiseControlTaskActive}, nil
 }
@@ -176,8 +176,9 @@ var _ = BeforeSuite(func(ctx SpecContext) {
 
 	// Create a new  kafka user reconciler
 	kafkaUserReconciler := controllers.KafkaUserReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorder("kafkauser-controller"),
 	}
 
 	err = controllers.SetupKafkaUserWithManager(mgr, true, true).Complete(&kafkaUserReconciler)
@@ -24,7 +24,6 @@ import (
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
 	"github.com/prometheus/common/model"
-	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
@@ -251,51 +250,12 @@ func resizePvc(log logr.Logger, labels model.LabelSet, annotiations model.LabelS
 		return err
 	}
 
-	cr, err := k8sutil.GetCr(pvc.Labels[v1beta1.KafkaCRLabelKey], string(labels["namespace"]), client)
-	if err != nil {
-		return err
-	}
 	incrementBy, err := resource.ParseQuantity(string(annotiations["incrementBy"]))
 	if err != nil {
 		return err
 	}
 
-	for i, broker := range cr.Spec.Brokers {
-		if strconv.Itoa(int(broker.Id)) == pvc.Labels[v1beta1.BrokerIdLabelKey] {
-			brokerConfig, err := broker.GetBrokerConfig(cr.Spec)
-			if err != nil {
-				return errors.WrapIf(err, "failed to determine broker config")
-			}
-
-			storageConfigs := brokerConfig.StorageConfigs
-
-			for _, c := range storageConfigs {
-				modifiableConfig := c.DeepCopy()
-				if modifiableConfig.MountPath == pvc.Annotations["mountPath"] {
-					size := *modifiableConfig.PvcSpec.Resources.Requests.Storage()
-					size.Add(incrementBy)
-
-					modifiableConfig.PvcSpec.Resources.Requests[corev1.ResourceStorage] = size
-
-					// When the storage is in a brokerConfigGroup we don't resize the storage there because in that case
-					// all of the brokers that are using this brokerConfigGroup would have their storages resized.
-					// We add the storage into the broker.BrokerConfig.StorageConfigs in this way only the PVC belonging to that specific broker will be resized.
-					if broker.BrokerConfig == nil {
-						broker.BrokerConfig = &v1beta1.BrokerConfig{}
-					}
-					idx := slices.IndexFunc(broker.BrokerConfig.StorageConfigs, func(c v1beta1.StorageConfig) bool { return c.MountPath == pvc.Annotations["mountPath"] })
-					if idx == -1 {
-						broker.BrokerConfig.StorageConfigs = append(broker.BrokerConfig.StorageConfigs, *modifiableConfig)
-					} else {
-						broker.BrokerConfig.StorageConfigs[idx] = *modifiableConfig
-					}
-				}
-			}
-			cr.Spec.Brokers[i].BrokerConfig = broker.BrokerConfig
-		}
-	}
-
-	err = k8sutil.UpdateCr(cr, client)
+	err = k8sutil.ResizePvOfSpecificBroker(pvc.Labels[v1beta1.BrokerIdLabelKey], pvc.Labels[v1beta1.KafkaCRLabelKey], string(labels["namespace"]), pvc.Annotations["mountPath"], incrementBy, client)
 	if err != nil {
 		return err
 	}
@@ -180,6 +180,7 @@ func main() {
 		DirectClient:        mgr.GetAPIReader(),
 		Namespaces:          namespaceList,
 		KafkaClientProvider: kafkaclient.NewDefaultProvider(),
+		Recorder:            mgr.GetEventRecorder("kafkacluster-controller"),
 	}
 
 	if err = controllers.SetupKafkaClusterWithManager(mgr, contourEnabled).Complete(kafkaClusterReconciler); err != nil {
@@ -199,8 +200,9 @@ func main() {
 
 	// Create a new  kafka user reconciler
 	kafkaUserReconciler := &controllers.KafkaUserReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorder("kafkauser-controller"),
 	}
 
 	if err = controllers.SetupKafkaUserWithManager(mgr, !certSigningDisabled, certManagerEnabled).Complete(kafkaUserReconciler); err != nil {
@@ -232,6 +234,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	kafkaClusterBackupReconciler := controllers.KafkaClusterBackupReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+
+	if err = controllers.SetupKafkaClusterBackupWithManager(mgr).Complete(&kafkaClusterBackupReconciler); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KafkaClusterBackup")
+		os.Exit(1)
+	}
+
 	cruiseControlOperationTTLReconciler := controllers.CruiseControlOperationTTLReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -20,6 +20,7 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/banzaicloud/koperator/api/v1beta1"
@@ -40,10 +41,27 @@ const (
 var newJMXExtractor = createNewJMXExtractor
 var jmxMetricRegex = regexp.MustCompile(
 	fmt.Sprintf(`kafka_server_app_info_version{broker_id=\"[0-9]+\",version=\"(?P<%s>[0-9]+.[0-9]+.[0-9]+)\"`, versionRegexGroup))
+var zkMigrationStateMetricRegex = regexp.MustCompile(`(?m)^kafka_controller_kafkacontroller_zkmigrationstate(?:{[^}]*})? ([0-9.eE+-]+)$`)
+
+// ZkMigrationState is the state of the ZooKeeper to KRaft migration the KRaft controllers report in the
+// kafka.controller:type=KafkaController,name=ZkMigrationState metric
+type ZkMigrationState int
+
+const (
+	ZkMigrationStateNone ZkMigrationState = iota
+	// ZkMigrationStateMigration is reported once the metadata is copied from ZooKeeper and the controllers dual-write
+	ZkMigrationStateMigration
+	ZkMigrationStatePostMigration
+	ZkMigrationStatePreMigration
+	ZkMigrationStateZk
+)
 
 type JMXExtractor interface {
 	ExtractDockerImageAndVersion(brokerId int32, brokerConfig *v1beta1.BrokerConfig,
 		clusterImage string, headlessServiceEnabled bool) (*v1beta1.KafkaVersion, error)
+	// ExtractZkMigrationState returns the ZooKeeper migration state the KRaft controller reports, nodes which do not
+	// report it are in ZkMigrationStateNone
+	ExtractZkMigrationState(brokerId int32, brokerConfig *v1beta1.BrokerConfig, headlessServiceEnabled bool) (ZkMigrationState, error)
 }
 
 type jmxExtractor struct {
@@ -74,8 +92,57 @@ func NewMockJMXExtractor() {
 	newJMXExtractor = createMockJMXExtractor
 }
 
+// NewMockJMXExtractorWithZkMigrationState replaces the extractor with a mock reporting the given ZooKeeper migration
+// state, the returned func restores the previous extractor
+func NewMockJMXExtractorWithZkMigrationState(state ZkMigrationState) func() {
+	previous := newJMXExtractor
+	newJMXExtractor = func(namespace, kubernetesClusterDomain, clusterName string, log logr.Logger) JMXExtractor {
+		return &mockJmxExtractor{zkMigrationState: state}
+	}
+	return func() { newJMXExtractor = previous }
+}
+
 func (exp *jmxExtractor) ExtractDockerImageAndVersion(brokerId int32, brokerConfig *v1beta1.BrokerConfig,
 	clusterImage string, headlessServiceEnabled bool) (*v1beta1.KafkaVersion, error) {
+	body, err := exp.fetchMetrics(brokerId, brokerConfig, headlessServiceEnabled)
+	if err != nil {
+		return nil, err
+	}
+	index := jmxMetricRegex.SubexpIndex(versionRegexGroup)
+	var version string
+	if index > -1 {
+		if metrics := jmxMetricRegex.FindStringSubmatch(body); len(metrics) > index {
+			version = metrics[index]
+		}
+	}
+
+	brokerImage := util.GetBrokerImage(brokerConfig, clusterImage)
+	return &v1beta1.KafkaVersion{Version: version, Image: brokerImage}, nil
+}
+
+func (exp *jmxExtractor) ExtractZkMigrationState(brokerId int32, brokerConfig *v1beta1.BrokerConfig,
+	headlessServiceEnabled bool) (ZkMigrationState, error) {
+	body, err := exp.fetchMetrics(brokerId, brokerConfig, headlessServiceEnabled)
+	if err != nil {
+		return ZkMigrationStateNone, err
+	}
+	return parseZkMigrationState(body)
+}
+
+func parseZkMigrationState(body string) (ZkMigrationState, error) {
+	metric := zkMigrationStateMetricRegex.FindStringSubmatch(body)
+	if len(metric) < 2 {
+		return ZkMigrationStateNone, nil
+	}
+	state, err := strconv.ParseFloat(metric[1], 64)
+	if err != nil {
+		return ZkMigrationStateNone, errorfactory.New(errorfactory.InternalError{}, err, "could not parse ZkMigrationState metric")
+	}
+	return ZkMigrationState(state), nil
+}
+
+// fetchMetrics returns the metrics the JMX exporter of the broker exposes
+func (exp *jmxExtractor) fetchMetrics(brokerId int32, brokerConfig *v1beta1.BrokerConfig, headlessServiceEnabled bool) (string, error) {
 	var requestURL string
 
 	if headlessServiceEnabled {
@@ -95,7 +162,7 @@ func (exp *jmxExtractor) ExtractDockerImageAndVersion(brokerId int32, brokerConf
 	rsp, err := client.Get(requestURL)
 	if err != nil {
 		exp.log.Error(err, fmt.Sprintf("error during talking to broker-%d", brokerId))
-		return nil, errorfactory.New(errorfactory.BrokersNotReady{}, err, "unable to talk to ...")
+		return "", errorfactory.New(errorfactory.BrokersNotReady{}, err, "unable to talk to ...")
 	}
 	defer func() {
 		closeErr := rsp.Body.Close()
@@ -106,16 +173,7 @@ func (exp *jmxExtractor) ExtractDockerImageAndVersion(brokerId int32, brokerConf
 
 	body, err := io.ReadAll(rsp.Body)
 	if err != nil {
-		return nil, err
-	}
-	index := jmxMetricRegex.SubexpIndex(versionRegexGroup)
-	var version string
-	if index > -1 {
-		if metrics := jmxMetricRegex.FindStringSubmatch(string(body)); len(metrics) > index {
-			version = metrics[index]
-		}
+		return "", err
 	}
-
-	brokerImage := util.GetBrokerImage(brokerConfig, clusterImage)
-	return &v1beta1.KafkaVersion{Version: version, Image: brokerImage}, nil
+	return string(body), nil
 }
@@ -0,0 +1,55 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jmxextractor
+
+import "testing"
+
+func TestParseZkMigrationState(t *testing.T) {
+	tests := []struct {
+		testName string
+		body     string
+		expected ZkMigrationState
+	}{
+		{
+			testName: "metric is not exported",
+			body:     "kafka_controller_kafkacontroller_activebrokercount 3.0\n",
+			expected: ZkMigrationStateNone,
+		},
+		{
+			testName: "metadata is being copied",
+			body: "# HELP kafka_controller_kafkacontroller_zkmigrationstate Attribute exposed for management\n" +
+				"# TYPE kafka_controller_kafkacontroller_zkmigrationstate untyped\n" +
+				"kafka_controller_kafkacontroller_zkmigrationstate 3.0\n",
+			expected: ZkMigrationStatePreMigration,
+		},
+		{
+			testName: "metadata is copied",
+			body:     "kafka_controller_kafkacontroller_zkmigrationstate 1.0\n",
+			expected: ZkMigrationStateMigration,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			state, err := parseZkMigrationState(test.body)
+			if err != nil {
+				t.Fatalf("expected nil error, got: %v", err)
+			}
+			if state != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, state)
+			}
+		})
+	}
+}
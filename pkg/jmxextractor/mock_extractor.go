@@ -17,9 +17,16 @@ package jmxextractor
 
 import "github.com/banzaicloud/koperator/api/v1beta1"
 
-type mockJmxExtractor struct{}
+type mockJmxExtractor struct {
+	zkMigrationState ZkMigrationState
+}
 
 func (exp *mockJmxExtractor) ExtractDockerImageAndVersion(brokerId int32, brokerConfig *v1beta1.BrokerConfig,
 	clusterImage string, headlessServiceEnabled bool) (*v1beta1.KafkaVersion, error) {
 	return &v1beta1.KafkaVersion{Image: clusterImage, Version: "3.4.1"}, nil
 }
+
+func (exp *mockJmxExtractor) ExtractZkMigrationState(brokerId int32, brokerConfig *v1beta1.BrokerConfig,
+	headlessServiceEnabled bool) (ZkMigrationState, error) {
+	return exp.zkMigrationState, nil
+}
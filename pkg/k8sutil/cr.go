@@ -17,6 +17,7 @@ package k8sutil
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -29,6 +30,7 @@ import (
 	"github.com/banzaicloud/koperator/pkg/errorfactory"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -125,6 +127,52 @@ func AddPvToSpecificBroker(brokerID, crName, namespace string, storageConfig *v1
 	return UpdateCr(cr, client)
 }
 
+// ResizePvOfSpecificBroker increases the storage request of the storage config of the broker mounted at the mount path
+// by the given quantity
+func ResizePvOfSpecificBroker(brokerID, crName, namespace, mountPath string, incrementBy resource.Quantity, client runtimeClient.Client) error {
+	cr, err := GetCr(crName, namespace, client)
+	if err != nil {
+		return err
+	}
+
+	for i, broker := range cr.Spec.Brokers {
+		if strconv.Itoa(int(broker.Id)) == brokerID {
+			brokerConfig, err := broker.GetBrokerConfig(cr.Spec)
+			if err != nil {
+				return errors.WrapIf(err, "failed to determine broker config")
+			}
+
+			storageConfigs := brokerConfig.StorageConfigs
+
+			for _, c := range storageConfigs {
+				modifiableConfig := c.DeepCopy()
+				if modifiableConfig.MountPath == mountPath {
+					size := *modifiableConfig.PvcSpec.Resources.Requests.Storage()
+					size.Add(incrementBy)
+
+					modifiableConfig.PvcSpec.Resources.Requests[corev1.ResourceStorage] = size
+
+					// When the storage is in a brokerConfigGroup we don't resize the storage there because in that case
+					// all of the brokers that are using this brokerConfigGroup would have their storages resized.
+					// We add the storage into the broker.BrokerConfig.StorageConfigs in this way only the PVC belonging to that specific broker will be resized.
+					if broker.BrokerConfig == nil {
+						broker.BrokerConfig = &v1beta1.BrokerConfig{}
+					}
+					idx := slices.IndexFunc(broker.BrokerConfig.StorageConfigs, func(c v1beta1.StorageConfig) bool { return c.MountPath == mountPath })
+					if idx == -1 {
+						broker.BrokerConfig.StorageConfigs = append(broker.BrokerConfig.StorageConfigs, *modifiableConfig)
+					} else {
+						broker.BrokerConfig.StorageConfigs[idx] = *modifiableConfig
+					}
+				}
+			}
+			cr.Spec.Brokers[i].BrokerConfig = broker.BrokerConfig
+		}
+	}
+
+	return UpdateCr(cr, client)
+}
+
 // GetCr returns the given cr object
 func GetCr(name, namespace string, client runtimeClient.Client) (*v1beta1.KafkaCluster, error) {
 	cr := &v1beta1.KafkaCluster{}
@@ -25,6 +25,7 @@ import (
 	"github.com/go-logr/logr"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -78,9 +79,23 @@ func generateBrokerConfigurationBackups(cluster *banzaicloudv1beta1.KafkaCluster
 		cluster.Status.BrokersState = make(map[string]banzaicloudv1beta1.BrokerState)
 	}
 
+	configRollback := cluster.Spec.RollingUpgradeConfig.ConfigRollback != nil
 	for _, broker := range cluster.Spec.Brokers {
 		brokerState := cluster.Status.BrokersState[fmt.Sprint(broker.Id)]
-		configurationBackup, err := util.GzipAndBase64BrokerConfiguration(&broker)
+		var configurationBackup string
+		var err error
+		if configRollback {
+			// the backup is the last configuration the broker became ready with, it is only replaced once the broker
+			// restarted with a changed configuration is ready
+			if brokerState.ConfigurationBackup != "" {
+				configurationBackup = resolvedConfigurationBackup(brokerState.ConfigurationBackup, cluster.Spec)
+			}
+			if configurationBackup == "" {
+				configurationBackup, err = util.GzipAndBase64ResolvedBrokerConfiguration(&broker, cluster.Spec)
+			}
+		} else {
+			configurationBackup, err = util.GzipAndBase64BrokerConfiguration(&broker)
+		}
 		if err != nil {
 			return false, errors.WrapIfWithDetails(err, "could not generate broker configuration backup", banzaicloudv1beta1.BrokerIdLabelKey, broker.Id)
 		}
@@ -93,6 +108,24 @@ func generateBrokerConfigurationBackups(cluster *banzaicloudv1beta1.KafkaCluster
 	return needsUpdate, nil
 }
 
+// resolvedConfigurationBackup converts a backup taken before the configuration rollback was enabled, which refers to
+// the broker config group of the broker, to the resolved format with the current configuration of the group merged in.
+// An empty string is returned when the backup cannot be converted, the broker is then backed up again.
+func resolvedConfigurationBackup(configurationBackup string, kafkaClusterSpec banzaicloudv1beta1.KafkaClusterSpec) string {
+	broker, err := util.GetBrokerFromBrokerConfigurationBackup(configurationBackup)
+	if err != nil {
+		return ""
+	}
+	if broker.BrokerConfigGroup == "" {
+		return configurationBackup
+	}
+	resolved, err := util.GzipAndBase64ResolvedBrokerConfiguration(&broker, kafkaClusterSpec)
+	if err != nil {
+		return ""
+	}
+	return resolved
+}
+
 // UpdateBrokerStatus updates the broker status with rack and configuration infos
 func UpdateBrokerStatus(c client.Client, brokerIDs []string, cluster *banzaicloudv1beta1.KafkaCluster, state interface{}, logger logr.Logger) error {
 	typeMeta := cluster.TypeMeta
@@ -131,6 +164,16 @@ func UpdateBrokerStatus(c client.Client, brokerIDs []string, cluster *banzaiclou
 	return nil
 }
 
+// maxBrokerRestartHistory is the number of restarts kept in the restart history of a broker
+const maxBrokerRestartHistory = 10
+
+// BrokerConfigurationBackup is the configuration backup of a broker which became ready with a changed configuration,
+// updating the broker status with it completes the rollout of the configuration
+type BrokerConfigurationBackup string
+
+// BrokerLogFlushForced tells whether the per-broker config of a broker forces the flush of every appended message
+type BrokerLogFlushForced bool
+
 func generateBrokerState(brokerIDs []string, cluster *banzaicloudv1beta1.KafkaCluster, state interface{}) {
 	brokersState := cluster.Status.BrokersState
 	if brokersState == nil {
@@ -171,9 +214,37 @@ func generateBrokerState(brokerIDs []string, cluster *banzaicloudv1beta1.KafkaCl
 			for mountPath, volumeState := range state {
 				brokerState.GracefulActionState.VolumeStates[mountPath] = volumeState
 			}
+		case map[string]banzaicloudv1beta1.VolumeResizeStatus:
+			if brokerState.VolumeResizes == nil {
+				brokerState.VolumeResizes = make(map[string]banzaicloudv1beta1.VolumeResizeStatus, len(s))
+			}
+			for mountPath, resize := range s {
+				brokerState.VolumeResizes[mountPath] = resize
+			}
 		case banzaicloudv1beta1.KafkaVersion:
 			brokerState.Image = s.Image
 			brokerState.Version = s.Version
+		case *banzaicloudv1beta1.ConfigurationRolloutStatus:
+			brokerState.ConfigurationRollout = s
+		case BrokerConfigurationBackup:
+			brokerState.ConfigurationBackup = string(s)
+			brokerState.ConfigurationRollout = nil
+		case BrokerLogFlushForced:
+			brokerState.LogFlushForced = bool(s)
+		case *banzaicloudv1beta1.BrokerReplacementStatus:
+			brokerState.Replacement = s
+		case map[string]banzaicloudv1beta1.StorageAutoscalingStatus:
+			if brokerState.StorageAutoscaling == nil {
+				brokerState.StorageAutoscaling = make(map[string]banzaicloudv1beta1.StorageAutoscalingStatus, len(s))
+			}
+			for mountPath, scaling := range s {
+				brokerState.StorageAutoscaling[mountPath] = scaling
+			}
+		case banzaicloudv1beta1.BrokerRestart:
+			brokerState.RestartHistory = append(brokerState.RestartHistory, s)
+			if len(brokerState.RestartHistory) > maxBrokerRestartHistory {
+				brokerState.RestartHistory = brokerState.RestartHistory[len(brokerState.RestartHistory)-maxBrokerRestartHistory:]
+			}
 		}
 		brokersState[brokerID] = brokerState
 	}
@@ -274,6 +345,37 @@ func DeleteVolumeStatus(c client.Client, brokerID string, mountPath string, clus
 	return nil
 }
 
+// RollingUpgradeMaintenance tells whether the rolling upgrade waits for the next maintenance window, updating the
+// cluster status with it keeps the Cruise Control operations waiting for the window
+type RollingUpgradeMaintenance struct {
+	Waiting    bool
+	NextWindow *metav1.Time
+}
+
+// CruiseControlOperationsMaintenance are the CruiseControlOperations waiting for the next maintenance window, updating
+// the cluster status with it keeps the rolling upgrade waiting for the window
+type CruiseControlOperationsMaintenance struct {
+	Waiting    []string
+	NextWindow *metav1.Time
+}
+
+// setMaintenanceStatus updates the maintenance status of the cluster, it is dropped once nothing waits for a window
+func setMaintenanceStatus(cluster *banzaicloudv1beta1.KafkaCluster, nextWindow *metav1.Time, update func(status *banzaicloudv1beta1.MaintenanceStatus)) {
+	status := &banzaicloudv1beta1.MaintenanceStatus{}
+	if cluster.Status.Maintenance != nil {
+		status = cluster.Status.Maintenance.DeepCopy()
+	}
+	update(status)
+	if nextWindow != nil {
+		status.NextWindow = nextWindow
+	}
+	if !status.RollingUpgradeWaiting && len(status.WaitingCruiseControlOperations) == 0 {
+		cluster.Status.Maintenance = nil
+		return
+	}
+	cluster.Status.Maintenance = status
+}
+
 // UpdateCRStatus updates the cluster state
 func UpdateCRStatus(c client.Client, cluster *banzaicloudv1beta1.KafkaCluster, state interface{}, logger logr.Logger) error {
 	typeMeta := cluster.TypeMeta
@@ -283,6 +385,30 @@ func UpdateCRStatus(c client.Client, cluster *banzaicloudv1beta1.KafkaCluster, s
 		cluster.Status.State = s
 	case banzaicloudv1beta1.CruiseControlTopicStatus:
 		cluster.Status.CruiseControlTopicStatus = s
+	case banzaicloudv1beta1.MigrationStatus:
+		cluster.Status.Migration = &s
+	case banzaicloudv1beta1.KRaftQuorumStatus:
+		cluster.Status.KRaftQuorum = &s
+	case banzaicloudv1beta1.FeaturesStatus:
+		cluster.Status.Features = &s
+	case banzaicloudv1beta1.VersionUpgradeStatus:
+		cluster.Status.VersionUpgrade = &s
+	case banzaicloudv1beta1.CanaryStatus:
+		cluster.Status.RollingUpgrade.Canary = &s
+	case *banzaicloudv1beta1.RestartRequestStatus:
+		cluster.Status.RestartRequest = s
+	case banzaicloudv1beta1.BrokerTemplateStatus:
+		cluster.Status.BrokerTemplate = &s
+	case RollingUpgradeMaintenance:
+		setMaintenanceStatus(cluster, s.NextWindow, func(status *banzaicloudv1beta1.MaintenanceStatus) {
+			status.RollingUpgradeWaiting = s.Waiting
+		})
+	case CruiseControlOperationsMaintenance:
+		setMaintenanceStatus(cluster, s.NextWindow, func(status *banzaicloudv1beta1.MaintenanceStatus) {
+			status.WaitingCruiseControlOperations = s.Waiting
+		})
+	case metav1.Condition:
+		meta.SetStatusCondition(&cluster.Status.Conditions, s)
 	}
 
 	err := c.Status().Update(context.Background(), cluster)
@@ -305,6 +431,30 @@ func UpdateCRStatus(c client.Client, cluster *banzaicloudv1beta1.KafkaCluster, s
 			cluster.Status.State = s
 		case banzaicloudv1beta1.CruiseControlTopicStatus:
 			cluster.Status.CruiseControlTopicStatus = s
+		case banzaicloudv1beta1.MigrationStatus:
+			cluster.Status.Migration = &s
+		case banzaicloudv1beta1.KRaftQuorumStatus:
+			cluster.Status.KRaftQuorum = &s
+		case banzaicloudv1beta1.FeaturesStatus:
+			cluster.Status.Features = &s
+		case banzaicloudv1beta1.VersionUpgradeStatus:
+			cluster.Status.VersionUpgrade = &s
+		case banzaicloudv1beta1.CanaryStatus:
+			cluster.Status.RollingUpgrade.Canary = &s
+		case *banzaicloudv1beta1.RestartRequestStatus:
+			cluster.Status.RestartRequest = s
+		case banzaicloudv1beta1.BrokerTemplateStatus:
+			cluster.Status.BrokerTemplate = &s
+		case RollingUpgradeMaintenance:
+			setMaintenanceStatus(cluster, s.NextWindow, func(status *banzaicloudv1beta1.MaintenanceStatus) {
+				status.RollingUpgradeWaiting = s.Waiting
+			})
+		case CruiseControlOperationsMaintenance:
+			setMaintenanceStatus(cluster, s.NextWindow, func(status *banzaicloudv1beta1.MaintenanceStatus) {
+				status.WaitingCruiseControlOperations = s.Waiting
+			})
+		case metav1.Condition:
+			meta.SetStatusCondition(&cluster.Status.Conditions, s)
 		}
 
 		err = c.Status().Update(context.Background(), cluster)
@@ -321,12 +471,23 @@ func UpdateCRStatus(c client.Client, cluster *banzaicloudv1beta1.KafkaCluster, s
 	return nil
 }
 
+// clearCompletedCanary drops the canary of the completed rolling upgrade so the next one starts with a new canary
+func clearCompletedCanary(cluster *banzaicloudv1beta1.KafkaCluster) {
+	if canary := cluster.Status.RollingUpgrade.Canary; canary != nil && canary.Phase != banzaicloudv1beta1.CanaryPhaseFailed {
+		cluster.Status.RollingUpgrade.Canary = nil
+	}
+}
+
 // UpdateRollingUpgradeState updates the state of the cluster with rolling upgrade info
 func UpdateRollingUpgradeState(c client.Client, cluster *banzaicloudv1beta1.KafkaCluster, time time.Time, logger logr.Logger) error {
 	typeMeta := cluster.TypeMeta
 
 	timeStamp := time.Format("2006-01-02 15:04:05")
 	cluster.Status.RollingUpgrade.LastSuccess = timeStamp
+	clearCompletedCanary(cluster)
+	setMaintenanceStatus(cluster, nil, func(status *banzaicloudv1beta1.MaintenanceStatus) {
+		status.RollingUpgradeWaiting = false
+	})
 
 	err := c.Status().Update(context.Background(), cluster)
 	if apierrors.IsNotFound(err) {
@@ -345,6 +506,10 @@ func UpdateRollingUpgradeState(c client.Client, cluster *banzaicloudv1beta1.Kafk
 		}
 
 		cluster.Status.RollingUpgrade.LastSuccess = timeStamp
+		clearCompletedCanary(cluster)
+		setMaintenanceStatus(cluster, nil, func(status *banzaicloudv1beta1.MaintenanceStatus) {
+			status.RollingUpgradeWaiting = false
+		})
 
 		err = c.Status().Update(context.Background(), cluster)
 		if apierrors.IsNotFound(err) {
@@ -0,0 +1,109 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+func TestGenerateBrokerConfigurationBackups(t *testing.T) {
+	broker := v1beta1.Broker{Id: 0, BrokerConfigGroup: "default"}
+	previousSpec := v1beta1.KafkaClusterSpec{
+		Brokers:            []v1beta1.Broker{broker},
+		BrokerConfigGroups: map[string]v1beta1.BrokerConfig{"default": {Config: "num.io.threads=8"}},
+	}
+	unresolvedBackup, err := util.GzipAndBase64BrokerConfiguration(&broker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolvedBackup, err := util.GzipAndBase64ResolvedBrokerConfiguration(&broker, previousSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testName            string
+		configurationBackup string
+		groups              map[string]v1beta1.BrokerConfig
+		expectedConfig      string
+		expectedUpdate      bool
+	}{
+		{
+			testName:       "missing backup is taken in the resolved format",
+			groups:         previousSpec.BrokerConfigGroups,
+			expectedConfig: "num.io.threads=8",
+			expectedUpdate: true,
+		},
+		{
+			testName:            "resolved backup is kept",
+			configurationBackup: resolvedBackup,
+			groups:              map[string]v1beta1.BrokerConfig{"default": {Config: "num.io.threads=16"}},
+			expectedConfig:      "num.io.threads=8",
+		},
+		{
+			testName:            "backup referring to the broker config group is resolved",
+			configurationBackup: unresolvedBackup,
+			groups:              previousSpec.BrokerConfigGroups,
+			expectedConfig:      "num.io.threads=8",
+			expectedUpdate:      true,
+		},
+		{
+			testName:            "backup referring to a removed broker config group is taken again",
+			configurationBackup: unresolvedBackup,
+			groups:              map[string]v1beta1.BrokerConfig{},
+			expectedUpdate:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				Spec: v1beta1.KafkaClusterSpec{
+					Brokers:              []v1beta1.Broker{{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{}}},
+					BrokerConfigGroups:   test.groups,
+					RollingUpgradeConfig: v1beta1.RollingUpgradeConfig{ConfigRollback: &v1beta1.ConfigRollbackConfig{}},
+				},
+				Status: v1beta1.KafkaClusterStatus{
+					BrokersState: map[string]v1beta1.BrokerState{"0": {ConfigurationBackup: test.configurationBackup}},
+				},
+			}
+			// the broker is moved out of the removed broker config group
+			if len(test.groups) > 0 {
+				cluster.Spec.Brokers = []v1beta1.Broker{broker}
+			}
+
+			needsUpdate, err := generateBrokerConfigurationBackups(cluster)
+			if err != nil {
+				t.Fatal("Expected no error, got:", err)
+			}
+			if needsUpdate != test.expectedUpdate {
+				t.Errorf("Expected update %t, got %t", test.expectedUpdate, needsUpdate)
+			}
+			backupBroker, err := util.GetBrokerFromBrokerConfigurationBackup(cluster.Status.BrokersState["0"].ConfigurationBackup)
+			if err != nil {
+				t.Fatal("Expected a valid backup, got:", err)
+			}
+			if backupBroker.BrokerConfigGroup != "" {
+				t.Error("Expected the backup in the resolved format, got broker config group:", backupBroker.BrokerConfigGroup)
+			}
+			if backupBroker.BrokerConfig == nil || backupBroker.BrokerConfig.Config != test.expectedConfig {
+				t.Errorf("Expected the backup with config %q, got: %+v", test.expectedConfig, backupBroker.BrokerConfig)
+			}
+		})
+	}
+}
@@ -17,8 +17,10 @@ package kafkaclient
 
 import (
 	"fmt"
+	"net"
 	"time"
 
+	"emperror.dev/errors"
 	"github.com/IBM/sarama"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -48,6 +50,19 @@ type KafkaClient interface {
 
 	Brokers() map[int32]string
 	DescribeCluster() ([]*sarama.Broker, int32, error)
+	// ClusterID returns the id of the Kafka cluster the brokers belong to
+	ClusterID() (string, error)
+
+	// DescribeQuorum describes the KRaft metadata quorum
+	DescribeQuorum() (*QuorumInfo, error)
+	// AddRaftVoter promotes an observer of the KRaft metadata quorum to a voter
+	AddRaftVoter(id int32, directoryID Uuid, endpoints []RaftVoterEndpoint) error
+	// RemoveRaftVoter removes a voter from the KRaft metadata quorum
+	RemoveRaftVoter(id int32, directoryID Uuid) error
+	// DescribeFeatures describes the supported and finalized feature flags of the cluster
+	DescribeFeatures() (*FeaturesInfo, error)
+	// UpdateFeatures upgrades the finalized version levels of the features
+	UpdateFeatures(levels map[string]int16) error
 
 	// AllOfflineReplicas returns the list of unique offline replica (broker) ids
 	AllOfflineReplicas() ([]int32, error)
@@ -55,6 +70,10 @@ type KafkaClient interface {
 	// OutOfSyncReplicas returns the list of unique out of sync replica (broker) ids
 	OutOfSyncReplicas() ([]int32, error)
 
+	// UnsafeToLoseBrokerPartitions returns the partitions which would go offline or under min ISR
+	// if the replicas of the given broker were lost
+	UnsafeToLoseBrokerPartitions(brokerID int32) ([]string, error)
+
 	AlterPerBrokerConfig(int32, map[string]*string, bool) error
 	DescribePerBrokerConfig(int32, []string) ([]*sarama.ConfigEntry, error)
 
@@ -78,6 +97,8 @@ type kafkaClient struct {
 	// client funcs for mocking
 	newClusterAdmin func([]string, *sarama.Config) (sarama.ClusterAdmin, error)
 	newClient       func([]string, *sarama.Config) (sarama.Client, error)
+	// dialer opens the connections for the requests sarama does not implement
+	dialer func(address string) (net.Conn, error)
 }
 
 func New(opts *KafkaConfig) KafkaClient {
@@ -162,6 +183,22 @@ func (k *kafkaClient) DescribeCluster() (brokers []*sarama.Broker, controllerID
 	return
 }
 
+func (k *kafkaClient) ClusterID() (string, error) {
+	controller, err := k.client.Controller()
+	if err != nil {
+		return "", errors.WrapIf(err, "could not get controller broker")
+	}
+	// an empty topic list requests the cluster metadata only
+	metadata, err := controller.GetMetadata(sarama.NewMetadataRequest(apiVersion, []string{}))
+	if err != nil {
+		return "", errors.WrapIf(err, "could not get cluster metadata")
+	}
+	if metadata.ClusterID == nil || *metadata.ClusterID == "" {
+		return "", errors.New("cluster id is not reported by the brokers")
+	}
+	return *metadata.ClusterID, nil
+}
+
 func (k *kafkaClient) getSaramaConfig() (config *sarama.Config) {
 	config = sarama.NewConfig()
 	if k.opts.UseSSL {
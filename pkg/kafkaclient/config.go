@@ -58,6 +58,12 @@ func ClusterConfig(client client.Client, cluster *v1beta1.KafkaCluster) (*KafkaC
 		if err != nil {
 			return conf, err
 		}
+		if listener := clientutil.GetInnerBrokerListener(cluster); tlsConfig != nil && listener != nil {
+			sslSecrets := cluster.Spec.ListenersConfig.SSLSecrets
+			if err = util.ApplyTLSSettings(tlsConfig, listener.GetTLSProtocols(sslSecrets), listener.GetCipherSuites(sslSecrets)); err != nil {
+				return conf, err
+			}
+		}
 		conf.UseSSL = true
 		conf.TLSConfig = tlsConfig
 	}
@@ -0,0 +1,154 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaclient
+
+import (
+	"maps"
+	"slices"
+
+	"emperror.dev/errors"
+	"github.com/IBM/sarama"
+)
+
+const (
+	// MetadataVersionFeature is the feature which versions the KRaft metadata
+	MetadataVersionFeature = "metadata.version"
+
+	// apiVersionsVersion is the first version which reports the features of the cluster (KIP-584)
+	apiVersionsVersion int16 = 3
+
+	apiKeyUpdateFeatures int16 = 57
+	// updateFeaturesVersion is the first version with the upgrade type of the feature updates (KIP-778)
+	updateFeaturesVersion int16 = 1
+
+	featureUpgradeTypeUpgrade int8 = 1
+)
+
+// FeatureVersionRange is the range of the versions of a feature a broker supports
+type FeatureVersionRange struct {
+	MinVersion int16
+	MaxVersion int16
+}
+
+// FeaturesInfo describes the feature flags of the cluster
+type FeaturesInfo struct {
+	// FinalizedEpoch is the epoch of the finalized features, -1 when they are unknown
+	FinalizedEpoch int64
+	// Finalized are the cluster-wide finalized version levels of the features
+	Finalized map[string]int16
+	// Supported are the versions of the features the broker supports
+	Supported map[string]FeatureVersionRange
+	// apiVersions are the highest versions of the APIs the broker supports
+	apiVersions map[int16]int16
+}
+
+// MetadataVersion returns the finalized metadata.version of the cluster, 0 when it is not finalized
+func (f *FeaturesInfo) MetadataVersion() int16 {
+	return f.Finalized[MetadataVersionFeature]
+}
+
+// DescribeFeatures describes the feature flags of the cluster through the ApiVersions API of the controller broker
+func (k *kafkaClient) DescribeFeatures() (*FeaturesInfo, error) {
+	controller, err := k.client.Controller()
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not get controller broker")
+	}
+	response, err := controller.ApiVersions(&sarama.ApiVersionsRequest{
+		Version:               apiVersionsVersion,
+		ClientSoftwareName:    clientId,
+		ClientSoftwareVersion: "1.0.0",
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not describe the features")
+	}
+	return featuresFromApiVersions(response)
+}
+
+func featuresFromApiVersions(response *sarama.ApiVersionsResponse) (*FeaturesInfo, error) {
+	if err := protocolError(response.ErrorCode, nil); err != nil {
+		return nil, errors.WrapIf(err, "could not describe the features")
+	}
+	features := &FeaturesInfo{
+		FinalizedEpoch: response.FinalizedFeaturesEpoch,
+		Finalized:      make(map[string]int16, len(response.FinalizedFeatures)),
+		Supported:      make(map[string]FeatureVersionRange, len(response.SupportedFeatures)),
+		apiVersions:    make(map[int16]int16, len(response.ApiKeys)),
+	}
+	// the finalized features are only valid with a known epoch
+	if response.FinalizedFeaturesEpoch >= 0 {
+		for _, feature := range response.FinalizedFeatures {
+			features.Finalized[feature.Name] = feature.MaxVersionLevel
+		}
+	}
+	for _, feature := range response.SupportedFeatures {
+		features.Supported[feature.Name] = FeatureVersionRange{MinVersion: feature.MinVersion, MaxVersion: feature.MaxVersion}
+	}
+	for _, key := range response.ApiKeys {
+		features.apiVersions[key.ApiKey] = key.MaxVersion
+	}
+	return features, nil
+}
+
+// supportedVersion returns the highest version of the API up to the preferred one which the brokers support
+func (k *kafkaClient) supportedVersion(apiKey, preferred int16) (int16, error) {
+	features, err := k.DescribeFeatures()
+	if err != nil {
+		return 0, err
+	}
+	version, ok := features.apiVersions[apiKey]
+	if !ok {
+		return 0, errors.NewWithDetails("the API is not supported by the brokers", "apiKey", apiKey)
+	}
+	return min(version, preferred), nil
+}
+
+// UpdateFeatures upgrades the finalized version levels of the features through the UpdateFeatures API
+func (k *kafkaClient) UpdateFeatures(levels map[string]int16) error {
+	request := &protocolEncoder{}
+	request.putInt32(int32(k.timeout.Milliseconds()))
+	request.putCompactArrayLength(len(levels))
+	for _, feature := range slices.Sorted(maps.Keys(levels)) {
+		request.putCompactString(feature)
+		request.putInt16(levels[feature])
+		request.putInt8(featureUpgradeTypeUpgrade)
+		request.putEmptyTaggedFields()
+	}
+	request.putBool(false) // validate only
+	request.putEmptyTaggedFields()
+
+	response, err := k.sendFlexibleRequest(apiKeyUpdateFeatures, updateFeaturesVersion, request.buf.Bytes())
+	if err != nil {
+		return errors.WrapIf(err, "could not update the features")
+	}
+	return errors.WrapIf(decodeUpdateFeaturesResponse(response), "could not update the features")
+}
+
+func decodeUpdateFeaturesResponse(response *protocolDecoder) error {
+	_ = response.getInt32() // throttle time
+	if err := protocolError(response.getInt16(), response.getCompactNullableString()); err != nil {
+		return err
+	}
+	for results := response.getCompactArrayLength(); results > 0; results-- {
+		feature := response.getCompactString()
+		if err := protocolError(response.getInt16(), response.getCompactNullableString()); err != nil {
+			return errors.WrapIfWithDetails(err, "feature could not be updated", "feature", feature)
+		}
+		response.skipTaggedFields()
+	}
+	if response.err != nil {
+		return errors.WrapIf(response.err, "could not decode the UpdateFeatures response")
+	}
+	return nil
+}
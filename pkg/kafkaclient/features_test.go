@@ -0,0 +1,94 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaclient
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeaturesFromApiVersions(t *testing.T) {
+	features, err := featuresFromApiVersions(&sarama.ApiVersionsResponse{
+		ApiKeys: []sarama.ApiVersionsResponseKey{{ApiKey: apiKeyDescribeQuorum, MaxVersion: 1}},
+		SupportedFeatures: []sarama.SupportedFeatureKey{
+			{Name: MetadataVersionFeature, MinVersion: 7, MaxVersion: 25},
+		},
+		FinalizedFeaturesEpoch: 12,
+		FinalizedFeatures: []sarama.FinalizedFeatureKey{
+			{Name: MetadataVersionFeature, MinVersionLevel: 1, MaxVersionLevel: 21},
+			{Name: "kraft.version", MinVersionLevel: 1, MaxVersionLevel: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(12), features.FinalizedEpoch)
+	require.Equal(t, int16(21), features.MetadataVersion())
+	require.Equal(t, map[string]int16{MetadataVersionFeature: 21, "kraft.version": 1}, features.Finalized)
+	require.Equal(t, FeatureVersionRange{MinVersion: 7, MaxVersion: 25}, features.Supported[MetadataVersionFeature])
+	require.Equal(t, int16(1), features.apiVersions[apiKeyDescribeQuorum])
+
+	// the finalized features are unknown without an epoch
+	features, err = featuresFromApiVersions(&sarama.ApiVersionsResponse{
+		FinalizedFeaturesEpoch: -1,
+		FinalizedFeatures:      []sarama.FinalizedFeatureKey{{Name: MetadataVersionFeature, MaxVersionLevel: 21}},
+	})
+	require.NoError(t, err)
+	require.Empty(t, features.Finalized)
+	require.Equal(t, int16(0), features.MetadataVersion())
+
+	_, err = featuresFromApiVersions(&sarama.ApiVersionsResponse{ErrorCode: int16(sarama.ErrUnsupportedVersion)})
+	require.Error(t, err)
+}
+
+func TestUpdateFeatures(t *testing.T) {
+	levels := make(map[string]int16)
+	var upgradeType int8
+	broker := &fakeBroker{}
+	respondWith := func(errorCode int16) func(request *protocolDecoder) []byte {
+		return func(request *protocolDecoder) []byte {
+			request.getInt32()
+			for n := request.getCompactArrayLength(); n > 0; n-- {
+				feature := request.getCompactString()
+				levels[feature] = request.getInt16()
+				upgradeType = request.getInt8()
+				request.skipTaggedFields()
+			}
+
+			response := &protocolEncoder{}
+			response.putInt32(0)
+			response.putInt16(0)
+			response.putCompactNullableString(nil)
+			response.putCompactArrayLength(1)
+			response.putCompactString(MetadataVersionFeature)
+			response.putInt16(errorCode)
+			response.putCompactNullableString(nil)
+			response.putEmptyTaggedFields()
+			response.putEmptyTaggedFields()
+			return response.buf.Bytes()
+		}
+	}
+
+	err := broker.client(t, respondWith(0)).UpdateFeatures(map[string]int16{MetadataVersionFeature: 21})
+	require.NoError(t, err)
+	require.Equal(t, apiKeyUpdateFeatures, broker.apiKey)
+	require.Equal(t, updateFeaturesVersion, broker.apiVersion)
+	require.Equal(t, map[string]int16{MetadataVersionFeature: 21}, levels)
+	require.Equal(t, featureUpgradeTypeUpgrade, upgradeType)
+
+	// INVALID_UPDATE_VERSION is returned when a broker does not support the level
+	err = broker.client(t, respondWith(95)).UpdateFeatures(map[string]int16{MetadataVersionFeature: 21})
+	require.Error(t, err)
+}
@@ -15,9 +15,16 @@
 package kafkaclient
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+
 	"emperror.dev/errors"
+	"github.com/IBM/sarama"
 )
 
+const minInSyncReplicasConfig = "min.insync.replicas"
+
 func (k *kafkaClient) AllOfflineReplicas() ([]int32, error) {
 	availableTopics, err := k.client.Topics()
 	if err != nil {
@@ -91,3 +98,80 @@ func (k *kafkaClient) OutOfSyncReplicas() ([]int32, error) {
 	}
 	return brokerIDs, nil
 }
+
+func (k *kafkaClient) UnsafeToLoseBrokerPartitions(brokerID int32) ([]string, error) {
+	defaultMinISR, err := k.defaultMinInSyncReplicas()
+	if err != nil {
+		return nil, err
+	}
+	topicDetails, err := k.admin.ListTopics()
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not list topics")
+	}
+
+	unsafePartitions := make([]string, 0)
+	for topic, detail := range topicDetails {
+		minISR := defaultMinISR
+		if value, ok := detail.ConfigEntries[minInSyncReplicasConfig]; ok && value != nil {
+			minISR, err = strconv.Atoi(*value)
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "could not parse min.insync.replicas", "topic", topic)
+			}
+		}
+		// a partition must keep at least one in-sync replica to stay online
+		minISR = max(minISR, 1)
+
+		partitions, err := k.client.Partitions(topic)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "could not fetch partition", "topic", topic)
+		}
+		for _, partition := range partitions {
+			replicas, err := k.client.Replicas(topic, partition)
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "could not fetch replicas", "topic", topic, "partition", partition)
+			}
+			if !slices.Contains(replicas, brokerID) {
+				continue
+			}
+			isrReplicas, err := k.client.InSyncReplicas(topic, partition)
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "could not fetch isr replicas", "topic", topic, "partition", partition)
+			}
+			remainingISR := len(isrReplicas)
+			if slices.Contains(isrReplicas, brokerID) {
+				remainingISR--
+			}
+			if remainingISR < minISR {
+				unsafePartitions = append(unsafePartitions, fmt.Sprintf("%s-%d", topic, partition))
+			}
+		}
+	}
+	return unsafePartitions, nil
+}
+
+// defaultMinInSyncReplicas returns the min.insync.replicas the controller broker applies to
+// topics which do not override it
+func (k *kafkaClient) defaultMinInSyncReplicas() (int, error) {
+	controller, err := k.admin.Controller()
+	if err != nil {
+		return 0, errors.WrapIf(err, "could not find controller broker")
+	}
+	entries, err := k.admin.DescribeConfig(sarama.ConfigResource{
+		Type:        sarama.BrokerResource,
+		Name:        strconv.Itoa(int(controller.ID())),
+		ConfigNames: []string{minInSyncReplicasConfig},
+	})
+	if err != nil {
+		return 0, errors.WrapIf(err, "could not describe min.insync.replicas")
+	}
+	for _, entry := range entries {
+		if entry.Name == minInSyncReplicasConfig {
+			minISR, err := strconv.Atoi(entry.Value)
+			if err != nil {
+				return 0, errors.WrapIf(err, "could not parse min.insync.replicas")
+			}
+			return minISR, nil
+		}
+	}
+	return 1, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaclient
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsafeToLoseBrokerPartitions(t *testing.T) {
+	minISR := "2"
+	mock := newEmptyMockClusterAdmin(false)
+	mock.mockTopics = map[string]sarama.TopicDetail{
+		"replicated": {
+			NumPartitions:     3,
+			ReplicaAssignment: map[int32][]int32{0: {0, 1, 2}, 1: {1, 2, 3}, 2: {0, 3}},
+		},
+		"min-isr": {
+			NumPartitions:     2,
+			ReplicaAssignment: map[int32][]int32{0: {0, 1, 2}, 1: {0, 1, 2}},
+			ConfigEntries:     map[string]*string{minInSyncReplicasConfig: &minISR},
+		},
+	}
+	mock.mockInSyncReplicas = map[string]map[int32][]int32{
+		// the replica on broker 0 is already offline, broker 3 is the last in-sync replica
+		"replicated": {2: {3}},
+		// only broker 0 and 1 are in-sync, losing broker 0 leaves the partition under min ISR
+		"min-isr": {1: {0, 1}},
+	}
+
+	client := newOpenedMockClient()
+	client.admin = mock
+	client.client = mock
+
+	partitions, err := client.UnsafeToLoseBrokerPartitions(0)
+	require.NoError(t, err)
+	sort.Strings(partitions)
+	require.Equal(t, []string{"min-isr-1"}, partitions)
+
+	partitions, err = client.UnsafeToLoseBrokerPartitions(3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"replicated-2"}, partitions)
+
+	partitions, err = client.UnsafeToLoseBrokerPartitions(4)
+	require.NoError(t, err)
+	require.Empty(t, partitions)
+
+	client.admin, _ = newMockClusterAdminFailOps([]string{}, sarama.NewConfig())
+	_, err = client.UnsafeToLoseBrokerPartitions(0)
+	require.Error(t, err)
+}
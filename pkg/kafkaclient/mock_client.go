@@ -39,6 +39,8 @@ type mockClusterAdmin struct {
 	failOps    bool
 	mockTopics map[string]sarama.TopicDetail
 	mockACLs   map[sarama.Resource]*sarama.ResourceAcls
+	// mockInSyncReplicas overrides the in-sync replicas of a partition, by default all the replicas are in-sync
+	mockInSyncReplicas map[string]map[int32][]int32
 }
 
 // Coordinator resolves the ambiguity between sarama.ClusterAdmin.Coordinator and sarama.Client.Coordinator
@@ -130,6 +132,42 @@ func (m *mockClusterAdmin) Topics() ([]string, error) {
 	return topics, nil
 }
 
+func (m *mockClusterAdmin) Partitions(topic string) ([]int32, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	detail, ok := m.mockTopics[topic]
+	if !ok {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	partitions := make([]int32, 0, detail.NumPartitions)
+	for partition := int32(0); partition < detail.NumPartitions; partition++ {
+		partitions = append(partitions, partition)
+	}
+	return partitions, nil
+}
+
+func (m *mockClusterAdmin) Replicas(topic string, partitionID int32) ([]int32, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	detail, ok := m.mockTopics[topic]
+	if !ok {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	return detail.ReplicaAssignment[partitionID], nil
+}
+
+func (m *mockClusterAdmin) InSyncReplicas(topic string, partitionID int32) ([]int32, error) {
+	m.Lock()
+	isr, ok := m.mockInSyncReplicas[topic][partitionID]
+	m.Unlock()
+	if ok {
+		return isr, nil
+	}
+	return m.Replicas(topic, partitionID)
+}
+
 func (m *mockClusterAdmin) DescribeTopics(topics []string) ([]*sarama.TopicMetadata, error) {
 	if m.failOps {
 		return []*sarama.TopicMetadata{}, errors.New("bad describe topics")
@@ -0,0 +1,261 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/IBM/sarama"
+)
+
+// The KRaft admin APIs are newer than the protocol versions sarama implements, so their requests are encoded here.
+// Only the flexible (KIP-482) request and response layouts are supported, which is what these APIs use.
+
+var correlationID int32
+
+// sendFlexibleRequest sends a request with a v2 request header to the bootstrap brokers and
+// returns the response body following the v1 response header
+func (k *kafkaClient) sendFlexibleRequest(apiKey, apiVersion int16, body []byte) (*protocolDecoder, error) {
+	conn, err := k.dial()
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not connect to kafka brokers", "brokerURI", k.opts.BrokerURI)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(k.timeout)); err != nil {
+		return nil, err
+	}
+
+	id := atomic.AddInt32(&correlationID, 1)
+	header := &protocolEncoder{}
+	header.putInt16(apiKey)
+	header.putInt16(apiVersion)
+	header.putInt32(id)
+	// the client id is a non-compact nullable string even in the flexible header
+	header.putInt16(int16(len(clientId)))
+	header.buf.WriteString(clientId)
+	header.putEmptyTaggedFields()
+
+	request := make([]byte, 4, 4+header.buf.Len()+len(body))
+	binary.BigEndian.PutUint32(request, uint32(header.buf.Len()+len(body)))
+	request = append(request, header.buf.Bytes()...)
+	request = append(request, body...)
+	if _, err := conn.Write(request); err != nil {
+		return nil, errors.WrapIf(err, "could not send request")
+	}
+
+	var size int32
+	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+		return nil, errors.WrapIf(err, "could not read response")
+	}
+	response := make([]byte, size)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return nil, errors.WrapIf(err, "could not read response")
+	}
+
+	decoder := &protocolDecoder{buf: bytes.NewReader(response)}
+	if responseID := decoder.getInt32(); responseID != id {
+		return nil, errors.Errorf("correlation id mismatch: expected %d, got %d", id, responseID)
+	}
+	decoder.skipTaggedFields()
+	return decoder, decoder.err
+}
+
+func (k *kafkaClient) dial() (net.Conn, error) {
+	if k.dialer != nil {
+		return k.dialer(k.opts.BrokerURI)
+	}
+	dialer := &net.Dialer{Timeout: k.timeout}
+	if k.opts.UseSSL {
+		return tls.DialWithDialer(dialer, "tcp", k.opts.BrokerURI, k.opts.TLSConfig)
+	}
+	return dialer.Dial("tcp", k.opts.BrokerURI)
+}
+
+// protocolError returns the error reported by the broker in a response
+func protocolError(code int16, message *string) error {
+	if code == 0 {
+		return nil
+	}
+	err := errors.Errorf("%s (error code %d)", sarama.KError(code).Error(), code)
+	if message != nil && *message != "" {
+		err = errors.Errorf("%s (error code %d): %s", sarama.KError(code).Error(), code, *message)
+	}
+	return err
+}
+
+type protocolEncoder struct {
+	buf bytes.Buffer
+}
+
+func (e *protocolEncoder) putInt8(v int8) {
+	e.buf.WriteByte(byte(v))
+}
+
+func (e *protocolEncoder) putBool(v bool) {
+	if v {
+		e.putInt8(1)
+		return
+	}
+	e.putInt8(0)
+}
+
+func (e *protocolEncoder) putInt16(v int16) {
+	_ = binary.Write(&e.buf, binary.BigEndian, v)
+}
+
+func (e *protocolEncoder) putUint16(v uint16) {
+	_ = binary.Write(&e.buf, binary.BigEndian, v)
+}
+
+func (e *protocolEncoder) putInt32(v int32) {
+	_ = binary.Write(&e.buf, binary.BigEndian, v)
+}
+
+func (e *protocolEncoder) putUvarint(v uint64) {
+	e.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (e *protocolEncoder) putCompactString(v string) {
+	e.putUvarint(uint64(len(v) + 1))
+	e.buf.WriteString(v)
+}
+
+func (e *protocolEncoder) putCompactNullableString(v *string) {
+	if v == nil {
+		e.putUvarint(0)
+		return
+	}
+	e.putCompactString(*v)
+}
+
+func (e *protocolEncoder) putCompactArrayLength(n int) {
+	e.putUvarint(uint64(n + 1))
+}
+
+func (e *protocolEncoder) putUUID(v Uuid) {
+	e.buf.Write(v[:])
+}
+
+func (e *protocolEncoder) putEmptyTaggedFields() {
+	e.putUvarint(0)
+}
+
+// protocolDecoder reads a response and keeps the first error, so the fields can be read without checking each of them
+type protocolDecoder struct {
+	buf *bytes.Reader
+	err error
+}
+
+func (d *protocolDecoder) read(v interface{}) {
+	if d.err != nil {
+		return
+	}
+	d.err = binary.Read(d.buf, binary.BigEndian, v)
+}
+
+func (d *protocolDecoder) getInt8() (v int8) {
+	d.read(&v)
+	return
+}
+
+func (d *protocolDecoder) getInt16() (v int16) {
+	d.read(&v)
+	return
+}
+
+func (d *protocolDecoder) getUint16() (v uint16) {
+	d.read(&v)
+	return
+}
+
+func (d *protocolDecoder) getInt32() (v int32) {
+	d.read(&v)
+	return
+}
+
+func (d *protocolDecoder) getInt64() (v int64) {
+	d.read(&v)
+	return
+}
+
+func (d *protocolDecoder) getUvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.buf)
+	d.err = err
+	return v
+}
+
+func (d *protocolDecoder) getBytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > d.buf.Len() {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := make([]byte, n)
+	_, d.err = io.ReadFull(d.buf, b)
+	return b
+}
+
+func (d *protocolDecoder) getCompactNullableString() *string {
+	n := d.getUvarint()
+	if n == 0 || d.err != nil {
+		return nil
+	}
+	s := string(d.getBytes(int(n - 1)))
+	return &s
+}
+
+func (d *protocolDecoder) getCompactString() string {
+	if s := d.getCompactNullableString(); s != nil {
+		return *s
+	}
+	return ""
+}
+
+// getCompactArrayLength returns the number of elements in a compact array, null arrays are read as empty
+func (d *protocolDecoder) getCompactArrayLength() int {
+	n := d.getUvarint()
+	if n == 0 || d.err != nil {
+		return 0
+	}
+	if n-1 > uint64(d.buf.Len()) {
+		d.err = errors.New("invalid array length")
+		return 0
+	}
+	return int(n - 1)
+}
+
+func (d *protocolDecoder) getUUID() (v Uuid) {
+	copy(v[:], d.getBytes(len(v)))
+	return
+}
+
+func (d *protocolDecoder) skipTaggedFields() {
+	for i := d.getUvarint(); i > 0 && d.err == nil; i-- {
+		d.getUvarint()
+		d.getBytes(int(d.getUvarint()))
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaclient
+
+import (
+	"encoding/base64"
+
+	"emperror.dev/errors"
+)
+
+const (
+	apiKeyDescribeQuorum  int16 = 55
+	apiKeyAddRaftVoter    int16 = 80
+	apiKeyRemoveRaftVoter int16 = 81
+
+	// describeQuorumVersion is the first version which reports the directory ids of the replicas (KIP-853),
+	// older versions are used when the brokers do not support it yet
+	describeQuorumVersion  int16 = 2
+	addRaftVoterVersion    int16 = 0
+	removeRaftVoterVersion int16 = 0
+
+	clusterMetadataTopic = "__cluster_metadata"
+)
+
+// Uuid is a Kafka UUID, e.g. the id of the metadata log directory of a KRaft controller
+type Uuid [16]byte
+
+// String returns the base64 representation Kafka uses for UUIDs
+func (u Uuid) String() string {
+	return base64.RawURLEncoding.EncodeToString(u[:])
+}
+
+// QuorumReplica is the replication state of a KRaft voter or observer.
+// The timestamps are the milliseconds since the epoch on the leader, or -1 when they are unknown.
+type QuorumReplica struct {
+	ID                    int32
+	DirectoryID           Uuid
+	LogEndOffset          int64
+	LastFetchTimestamp    int64
+	LastCaughtUpTimestamp int64
+}
+
+// QuorumInfo describes the KRaft metadata quorum
+type QuorumInfo struct {
+	LeaderID      int32
+	LeaderEpoch   int32
+	HighWatermark int64
+	Voters        []QuorumReplica
+	Observers     []QuorumReplica
+}
+
+// Voter returns the voter with the given node id
+func (q *QuorumInfo) Voter(id int32) (QuorumReplica, bool) {
+	return findQuorumReplica(q.Voters, id)
+}
+
+// Observer returns the observer with the given node id
+func (q *QuorumInfo) Observer(id int32) (QuorumReplica, bool) {
+	return findQuorumReplica(q.Observers, id)
+}
+
+func findQuorumReplica(replicas []QuorumReplica, id int32) (QuorumReplica, bool) {
+	for _, replica := range replicas {
+		if replica.ID == id {
+			return replica, true
+		}
+	}
+	return QuorumReplica{}, false
+}
+
+// RaftVoterEndpoint is a listener a KRaft voter is reachable on
+type RaftVoterEndpoint struct {
+	Name string
+	Host string
+	Port uint16
+}
+
+// Leader returns the voter which is the leader of the quorum
+func (q *QuorumInfo) Leader() (QuorumReplica, bool) {
+	return q.Voter(q.LeaderID)
+}
+
+// Lag returns how many records the replica is behind the leader
+func (q *QuorumInfo) Lag(replica QuorumReplica) int64 {
+	leaderEndOffset := q.HighWatermark
+	if leader, ok := q.Leader(); ok {
+		leaderEndOffset = leader.LogEndOffset
+	}
+	return max(leaderEndOffset-replica.LogEndOffset, 0)
+}
+
+// DescribeQuorum describes the KRaft metadata quorum through the DescribeQuorum API
+func (k *kafkaClient) DescribeQuorum() (*QuorumInfo, error) {
+	version, err := k.supportedVersion(apiKeyDescribeQuorum, describeQuorumVersion)
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not describe the metadata quorum")
+	}
+	return k.describeQuorum(version)
+}
+
+func (k *kafkaClient) describeQuorum(version int16) (*QuorumInfo, error) {
+	request := &protocolEncoder{}
+	request.putCompactArrayLength(1)
+	request.putCompactString(clusterMetadataTopic)
+	request.putCompactArrayLength(1)
+	request.putInt32(0)
+	request.putEmptyTaggedFields()
+	request.putEmptyTaggedFields()
+	request.putEmptyTaggedFields()
+
+	response, err := k.sendFlexibleRequest(apiKeyDescribeQuorum, version, request.buf.Bytes())
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not describe the metadata quorum")
+	}
+	return decodeDescribeQuorumResponse(response, version)
+}
+
+// decodeDescribeQuorumResponse decodes the DescribeQuorum response, version 1 added the fetch timestamps of the
+// replicas, version 2 the error messages and the directory ids
+func decodeDescribeQuorumResponse(response *protocolDecoder, version int16) (*QuorumInfo, error) {
+	getErrorMessage := func() *string {
+		if version < 2 {
+			return nil
+		}
+		return response.getCompactNullableString()
+	}
+
+	if err := protocolError(response.getInt16(), getErrorMessage()); err != nil {
+		return nil, errors.WrapIf(err, "could not describe the metadata quorum")
+	}
+
+	var info *QuorumInfo
+	for topics := response.getCompactArrayLength(); topics > 0; topics-- {
+		topic := response.getCompactString()
+		for partitions := response.getCompactArrayLength(); partitions > 0; partitions-- {
+			partition := response.getInt32()
+			errorCode := response.getInt16()
+			errorMessage := getErrorMessage()
+			partitionInfo := &QuorumInfo{
+				LeaderID:      response.getInt32(),
+				LeaderEpoch:   response.getInt32(),
+				HighWatermark: response.getInt64(),
+				Voters:        decodeQuorumReplicas(response, version),
+				Observers:     decodeQuorumReplicas(response, version),
+			}
+			response.skipTaggedFields()
+			if topic != clusterMetadataTopic || partition != 0 {
+				continue
+			}
+			if err := protocolError(errorCode, errorMessage); err != nil {
+				return nil, errors.WrapIf(err, "could not describe the metadata quorum")
+			}
+			info = partitionInfo
+		}
+		response.skipTaggedFields()
+	}
+	// the nodes and their endpoints are not needed, they can be derived from the cluster spec
+	if response.err != nil {
+		return nil, errors.WrapIf(response.err, "could not decode the DescribeQuorum response")
+	}
+	if info == nil {
+		return nil, errors.New("metadata partition is missing from the DescribeQuorum response")
+	}
+	return info, nil
+}
+
+func decodeQuorumReplicas(response *protocolDecoder, version int16) []QuorumReplica {
+	n := response.getCompactArrayLength()
+	replicas := make([]QuorumReplica, 0, n)
+	for ; n > 0; n-- {
+		replica := QuorumReplica{ID: response.getInt32(), LastFetchTimestamp: -1, LastCaughtUpTimestamp: -1}
+		if version >= 2 {
+			replica.DirectoryID = response.getUUID()
+		}
+		replica.LogEndOffset = response.getInt64()
+		if version >= 1 {
+			replica.LastFetchTimestamp = response.getInt64()
+			replica.LastCaughtUpTimestamp = response.getInt64()
+		}
+		replicas = append(replicas, replica)
+		response.skipTaggedFields()
+	}
+	return replicas
+}
+
+// AddRaftVoter promotes a KRaft controller, which is an observer of the quorum, to a voter through the AddRaftVoter API
+func (k *kafkaClient) AddRaftVoter(id int32, directoryID Uuid, endpoints []RaftVoterEndpoint) error {
+	request := &protocolEncoder{}
+	request.putCompactNullableString(nil)
+	request.putInt32(int32(k.timeout.Milliseconds()))
+	request.putInt32(id)
+	request.putUUID(directoryID)
+	request.putCompactArrayLength(len(endpoints))
+	for _, endpoint := range endpoints {
+		request.putCompactString(endpoint.Name)
+		request.putCompactString(endpoint.Host)
+		request.putUint16(endpoint.Port)
+		request.putEmptyTaggedFields()
+	}
+	request.putEmptyTaggedFields()
+
+	response, err := k.sendFlexibleRequest(apiKeyAddRaftVoter, addRaftVoterVersion, request.buf.Bytes())
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not add voter to the metadata quorum", "id", id)
+	}
+	return errors.WrapIfWithDetails(decodeRaftVoterResponse(response), "could not add voter to the metadata quorum", "id", id)
+}
+
+// RemoveRaftVoter removes a KRaft controller from the voters of the quorum through the RemoveRaftVoter API
+func (k *kafkaClient) RemoveRaftVoter(id int32, directoryID Uuid) error {
+	request := &protocolEncoder{}
+	request.putCompactNullableString(nil)
+	request.putInt32(id)
+	request.putUUID(directoryID)
+	request.putEmptyTaggedFields()
+
+	response, err := k.sendFlexibleRequest(apiKeyRemoveRaftVoter, removeRaftVoterVersion, request.buf.Bytes())
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not remove voter from the metadata quorum", "id", id)
+	}
+	return errors.WrapIfWithDetails(decodeRaftVoterResponse(response), "could not remove voter from the metadata quorum", "id", id)
+}
+
+// decodeRaftVoterResponse decodes the AddRaftVoter and RemoveRaftVoter responses, they share their layout
+func decodeRaftVoterResponse(response *protocolDecoder) error {
+	_ = response.getInt32() // throttle time
+	errorCode := response.getInt16()
+	errorMessage := response.getCompactNullableString()
+	if response.err != nil {
+		return errors.WrapIf(response.err, "could not decode the response")
+	}
+	return protocolError(errorCode, errorMessage)
+}
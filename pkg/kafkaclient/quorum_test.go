@@ -0,0 +1,239 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaclient
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker answers a single request on a pipe with the response body returned by respond
+type fakeBroker struct {
+	apiKey     int16
+	apiVersion int16
+	body       []byte
+}
+
+func (f *fakeBroker) client(t *testing.T, respond func(request *protocolDecoder) []byte) *kafkaClient {
+	return &kafkaClient{
+		opts:    &KafkaConfig{BrokerURI: "kafka:29092"},
+		timeout: 5 * time.Second,
+		dialer: func(string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go f.serve(t, server, respond)
+			return client, nil
+		},
+	}
+}
+
+func (f *fakeBroker) serve(t *testing.T, conn net.Conn, respond func(request *protocolDecoder) []byte) {
+	defer conn.Close()
+	var size int32
+	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+		t.Errorf("could not read request size: %v", err)
+		return
+	}
+	request := make([]byte, size)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		t.Errorf("could not read request: %v", err)
+		return
+	}
+
+	decoder := &protocolDecoder{buf: bytes.NewReader(request)}
+	f.apiKey = decoder.getInt16()
+	f.apiVersion = decoder.getInt16()
+	correlation := decoder.getInt32()
+	decoder.getBytes(int(decoder.getInt16()))
+	decoder.skipTaggedFields()
+
+	response := &protocolEncoder{}
+	response.putInt32(correlation)
+	response.putEmptyTaggedFields()
+	response.buf.Write(respond(decoder))
+
+	message := make([]byte, 4, 4+response.buf.Len())
+	binary.BigEndian.PutUint32(message, uint32(response.buf.Len()))
+	if _, err := conn.Write(append(message, response.buf.Bytes()...)); err != nil {
+		t.Errorf("could not write response: %v", err)
+	}
+}
+
+func putQuorumReplica(e *protocolEncoder, id int32, directoryID Uuid, logEndOffset int64) {
+	e.putInt32(id)
+	e.putUUID(directoryID)
+	_ = binary.Write(&e.buf, binary.BigEndian, logEndOffset)
+	_ = binary.Write(&e.buf, binary.BigEndian, int64(1000))
+	_ = binary.Write(&e.buf, binary.BigEndian, int64(900))
+	e.putEmptyTaggedFields()
+}
+
+func TestDescribeQuorum(t *testing.T) {
+	voterDirectory := Uuid{1}
+	observerDirectory := Uuid{2}
+
+	broker := &fakeBroker{}
+	client := broker.client(t, func(request *protocolDecoder) []byte {
+		response := &protocolEncoder{}
+		response.putInt16(0)
+		response.putCompactNullableString(nil)
+		response.putCompactArrayLength(1)
+		response.putCompactString(clusterMetadataTopic)
+		response.putCompactArrayLength(1)
+		response.putInt32(0)
+		response.putInt16(0)
+		response.putCompactNullableString(nil)
+		response.putInt32(100)
+		response.putInt32(7)
+		_ = binary.Write(&response.buf, binary.BigEndian, int64(42))
+		response.putCompactArrayLength(1)
+		putQuorumReplica(response, 100, voterDirectory, 42)
+		response.putCompactArrayLength(2)
+		putQuorumReplica(response, 101, observerDirectory, 40)
+		putQuorumReplica(response, 0, Uuid{}, 42)
+		response.putEmptyTaggedFields()
+		response.putEmptyTaggedFields()
+		// nodes
+		response.putCompactArrayLength(0)
+		response.putEmptyTaggedFields()
+		return response.buf.Bytes()
+	})
+
+	quorum, err := client.describeQuorum(describeQuorumVersion)
+	require.NoError(t, err)
+	require.Equal(t, apiKeyDescribeQuorum, broker.apiKey)
+	require.Equal(t, describeQuorumVersion, broker.apiVersion)
+	require.Equal(t, int32(100), quorum.LeaderID)
+	require.Equal(t, int32(7), quorum.LeaderEpoch)
+	require.Equal(t, int64(42), quorum.HighWatermark)
+	require.Len(t, quorum.Voters, 1)
+	require.Len(t, quorum.Observers, 2)
+
+	observer, ok := quorum.Observer(101)
+	require.True(t, ok)
+	require.Equal(t, observerDirectory, observer.DirectoryID)
+	require.Equal(t, int64(40), observer.LogEndOffset)
+	_, ok = quorum.Voter(101)
+	require.False(t, ok)
+	require.Equal(t, int64(2), quorum.Lag(observer))
+}
+
+func TestDescribeQuorumVersion1(t *testing.T) {
+	broker := &fakeBroker{}
+	client := broker.client(t, func(request *protocolDecoder) []byte {
+		response := &protocolEncoder{}
+		response.putInt16(0)
+		response.putCompactArrayLength(1)
+		response.putCompactString(clusterMetadataTopic)
+		response.putCompactArrayLength(1)
+		response.putInt32(0)
+		response.putInt16(0)
+		response.putInt32(100)
+		response.putInt32(3)
+		_ = binary.Write(&response.buf, binary.BigEndian, int64(50))
+		response.putCompactArrayLength(2)
+		for _, voter := range []struct {
+			id           int32
+			logEndOffset int64
+		}{{100, 50}, {101, 20}} {
+			response.putInt32(voter.id)
+			_ = binary.Write(&response.buf, binary.BigEndian, voter.logEndOffset)
+			_ = binary.Write(&response.buf, binary.BigEndian, int64(1000))
+			_ = binary.Write(&response.buf, binary.BigEndian, int64(900))
+			response.putEmptyTaggedFields()
+		}
+		response.putCompactArrayLength(0)
+		response.putEmptyTaggedFields()
+		response.putEmptyTaggedFields()
+		response.putEmptyTaggedFields()
+		return response.buf.Bytes()
+	})
+
+	quorum, err := client.describeQuorum(1)
+	require.NoError(t, err)
+	require.Equal(t, int16(1), broker.apiVersion)
+	require.Equal(t, int32(100), quorum.LeaderID)
+	require.Len(t, quorum.Voters, 2)
+	require.Empty(t, quorum.Observers)
+
+	voter, ok := quorum.Voter(101)
+	require.True(t, ok)
+	require.Equal(t, Uuid{}, voter.DirectoryID)
+	require.Equal(t, int64(1000), voter.LastFetchTimestamp)
+	require.Equal(t, int64(30), quorum.Lag(voter))
+}
+
+func TestAddRaftVoter(t *testing.T) {
+	var (
+		voterID     int32
+		directoryID Uuid
+		endpoint    RaftVoterEndpoint
+	)
+	broker := &fakeBroker{}
+	respondWith := func(errorCode int16) func(request *protocolDecoder) []byte {
+		return func(request *protocolDecoder) []byte {
+			request.getCompactNullableString()
+			request.getInt32()
+			voterID = request.getInt32()
+			directoryID = request.getUUID()
+			request.getCompactArrayLength()
+			endpoint = RaftVoterEndpoint{Name: request.getCompactString(), Host: request.getCompactString(), Port: request.getUint16()}
+
+			response := &protocolEncoder{}
+			response.putInt32(0)
+			response.putInt16(errorCode)
+			response.putCompactNullableString(nil)
+			response.putEmptyTaggedFields()
+			return response.buf.Bytes()
+		}
+	}
+
+	expectedEndpoint := RaftVoterEndpoint{Name: "CONTROLLER", Host: "kafka-101.kafka.svc.cluster.local", Port: 29093}
+	err := broker.client(t, respondWith(0)).AddRaftVoter(101, Uuid{3}, []RaftVoterEndpoint{expectedEndpoint})
+	require.NoError(t, err)
+	require.Equal(t, apiKeyAddRaftVoter, broker.apiKey)
+	require.Equal(t, int32(101), voterID)
+	require.Equal(t, Uuid{3}, directoryID)
+	require.Equal(t, expectedEndpoint, endpoint)
+
+	// REQUEST_TIMED_OUT is returned while the new voter is catching up with the leader
+	err = broker.client(t, respondWith(7)).AddRaftVoter(101, Uuid{3}, []RaftVoterEndpoint{expectedEndpoint})
+	require.Error(t, err)
+}
+
+func TestRemoveRaftVoter(t *testing.T) {
+	var voterID int32
+	broker := &fakeBroker{}
+	client := broker.client(t, func(request *protocolDecoder) []byte {
+		request.getCompactNullableString()
+		voterID = request.getInt32()
+
+		response := &protocolEncoder{}
+		response.putInt32(0)
+		response.putInt16(0)
+		response.putCompactNullableString(nil)
+		response.putEmptyTaggedFields()
+		return response.buf.Bytes()
+	})
+
+	require.NoError(t, client.RemoveRaftVoter(102, Uuid{4}))
+	require.Equal(t, apiKeyRemoveRaftVoter, broker.apiKey)
+	require.Equal(t, int32(102), voterID)
+}
@@ -21,7 +21,11 @@ import (
 	"github.com/banzaicloud/koperator/pkg/util/pki"
 )
 
-const spiffeIdTemplate = "spiffe://%s/ns/%s/kafkauser/%s"
+const (
+	spiffeIdTemplate = "spiffe://%s/ns/%s/kafkauser/%s"
+	// keystoreSerialAnnotation records the serial number of the certificate the operator rendered keystores from
+	keystoreSerialAnnotation = "banzaicloud.io/keystore-serial"
+)
 
 type CertManager interface {
 	pki.Manager
@@ -68,7 +68,7 @@ func newMock(cluster *v1beta1.KafkaCluster) (*certManager, error) {
 	}
 	return &certManager{
 		cluster: cluster,
-		client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(&certv1.Certificate{}).Build(),
 	}, nil
 }
 
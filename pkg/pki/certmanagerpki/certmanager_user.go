@@ -16,6 +16,7 @@
 package certmanagerpki
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"time"
@@ -43,6 +44,15 @@ func (c *certManager) FinalizeUserCertificate(_ context.Context, _ *v1alpha1.Kaf
 	return
 }
 
+// RenewUserCertificate leaves the re-issuance to cert-manager which renews the certificate once its renewBefore
+// is reached, only the existence of the certificate is checked
+func (c *certManager) RenewUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser) error {
+	if _, err := c.getUserCertificate(ctx, user); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "failed looking up user certificate")
+	}
+	return nil
+}
+
 // ReconcileUserCertificate ensures a certificate/secret combination using cert-manager
 func (c *certManager) ReconcileUserCertificate(
 	ctx context.Context, user *v1alpha1.KafkaUser, scheme *runtime.Scheme, clusterDomain string) (*pkicommon.UserCertificate, error) {
@@ -54,7 +64,7 @@ func (c *certManager) ReconcileUserCertificate(
 	if err != nil && apierrors.IsNotFound(err) {
 		// the certificate does not exist, let's make one
 		// check if jks is required and create password for it
-		if user.Spec.IncludeJKS {
+		if user.Spec.HasKeystoreFormat(v1alpha1.KeystoreFormatJKS) {
 			if err := c.injectJKSPassword(ctx, user); err != nil {
 				return nil, err
 			}
@@ -79,6 +89,11 @@ func (c *certManager) ReconcileUserCertificate(
 		return nil, err
 	}
 
+	// Render the keystores which are not natively supported by cert-manager
+	if err = c.ensureUserKeystores(ctx, user, secret); err != nil {
+		return nil, err
+	}
+
 	return &pkicommon.UserCertificate{
 		CA:          secret.Data[v1alpha1.CoreCACertKey],
 		Certificate: secret.Data[corev1.TLSCertKey],
@@ -86,6 +101,56 @@ func (c *certManager) ReconcileUserCertificate(
 	}, nil
 }
 
+// ensureUserKeystores renders the requested keystore formats, except JKS which is created by cert-manager,
+// into the user secret and re-renders them whenever cert-manager re-issues the certificate
+func (c *certManager) ensureUserKeystores(ctx context.Context, user *v1alpha1.KafkaUser, secret *corev1.Secret) error {
+	formats := make([]v1alpha1.KeystoreFormat, 0)
+	for _, format := range user.Spec.GetKeystoreFormats() {
+		if format != v1alpha1.KeystoreFormatJKS {
+			formats = append(formats, format)
+		}
+	}
+	if len(formats) == 0 {
+		return nil
+	}
+
+	certs, err := certutil.ParseCertificates(bytes.TrimSpace(secret.Data[corev1.TLSCertKey]))
+	if err != nil {
+		return errorfactory.New(errorfactory.InternalError{}, err, "could not parse user certificate")
+	}
+	caCerts, err := certutil.ParseCertificates(bytes.TrimSpace(secret.Data[v1alpha1.CoreCACertKey]))
+	if err != nil {
+		return errorfactory.New(errorfactory.InternalError{}, err, "could not parse user CA certificate")
+	}
+
+	serial := fmt.Sprintf("%X", certs[0].Certificate.SerialNumber)
+	if secret.GetAnnotations()[keystoreSerialAnnotation] != serial {
+		// the certificate has been re-issued since the keystores were rendered
+		for _, format := range formats {
+			for _, field := range certutil.KeystoreEntries(format) {
+				delete(secret.Data, field)
+			}
+		}
+	}
+	changed, err := certutil.RenderKeystores(secret.Data, formats, certutil.GetCertBundle(certs),
+		certutil.GetCertBundle(caCerts), secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return errorfactory.New(errorfactory.InternalError{}, err, "could not render user keystores")
+	}
+	if !changed {
+		return nil
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[keystoreSerialAnnotation] = serial
+	if err = c.client.Update(ctx, secret); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not update user secret with keystores")
+	}
+	return nil
+}
+
 // injectJKSPassword ensures that a secret contains JKS password when requested
 func (c *certManager) injectJKSPassword(ctx context.Context, user *v1alpha1.KafkaUser) error {
 	var err error
@@ -124,13 +189,14 @@ func (c *certManager) getUserSecret(ctx context.Context, user *v1alpha1.KafkaUse
 		}
 		return secret, errorfactory.New(errorfactory.APIFailure{}, err, "failed to get user secret")
 	}
-	if user.Spec.IncludeJKS {
-		if len(secret.Data) != 6 {
-			return secret, errorfactory.New(errorfactory.ResourceNotReady{}, err, "user secret not populated yet")
-		}
-	} else {
-		if len(secret.Data) != 3 {
-			return secret, errorfactory.New(errorfactory.ResourceNotReady{}, err, "user secret not populated yet")
+	requiredFields := []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, v1alpha1.CoreCACertKey}
+	if user.Spec.HasKeystoreFormat(v1alpha1.KeystoreFormatJKS) {
+		requiredFields = append(requiredFields, certutil.KeystoreEntries(v1alpha1.KeystoreFormatJKS)...)
+	}
+	for _, field := range requiredFields {
+		if _, ok := secret.Data[field]; !ok {
+			return secret, errorfactory.New(errorfactory.ResourceNotReady{},
+				errors.NewWithDetails("missing secret field", "field", field), "user secret not populated yet")
 		}
 	}
 
@@ -163,10 +229,11 @@ func (c *certManager) clusterCertificateForUser(
 				Kind:  caKind,
 				Group: caGroup,
 			},
-			Duration: &metav1.Duration{Duration: time.Duration(user.Spec.GetExpirationSeconds()) * time.Second},
+			Duration:    &metav1.Duration{Duration: time.Duration(user.Spec.GetExpirationSeconds()) * time.Second},
+			RenewBefore: &metav1.Duration{Duration: user.Spec.GetRenewBefore()},
 		},
 	}
-	if user.Spec.IncludeJKS {
+	if user.Spec.HasKeystoreFormat(v1alpha1.KeystoreFormatJKS) {
 		cert.Spec.Keystores = &certv1.CertificateKeystores{
 			JKS: &certv1.JKSKeystore{
 				Create: true,
@@ -21,6 +21,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 
@@ -128,3 +129,98 @@ func TestReconcileUserCertificate(t *testing.T) {
 		t.Error("Expected  error, got nil")
 	}
 }
+
+func TestReconcileUserCertificateKeystores(t *testing.T) {
+	clusterDomain := "cluster.local"
+	manager, err := newMock(newMockCluster())
+	if err != nil {
+		t.Error("Expected no error during initialization, got:", err)
+	}
+	ctx := context.Background()
+
+	user := newMockUser()
+	user.Spec.KeystoreFormats = []v1alpha1.KeystoreFormat{v1alpha1.KeystoreFormatPKCS12, v1alpha1.KeystoreFormatPEMBundle}
+	if err := manager.client.Create(ctx, user); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+	if err := manager.client.Create(ctx, manager.clusterCertificateForUser(user, clusterDomain)); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+	if err := manager.client.Create(ctx, newMockUserSecret()); err != nil {
+		t.Error("could not create test secret")
+	}
+	if _, err := manager.ReconcileUserCertificate(ctx, user, scheme.Scheme, clusterDomain); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+
+	secret := newMockUserSecret()
+	if err := manager.client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if missing := certutil.MissingKeystores(secret.Data, user.Spec.GetKeystoreFormats()); len(missing) != 0 {
+		t.Error("Expected every keystore to be rendered, missing:", missing)
+	}
+	// the JKS keystore is created by cert-manager
+	if string(secret.Data[v1alpha1.TLSJKSKeyStore]) != "testkeystore" {
+		t.Error("Expected JKS keystore to be left untouched")
+	}
+	pkcs12Password := secret.Data[v1alpha1.PKCS12PasswordKey]
+
+	// a re-issued certificate invalidates the rendered keystores
+	cert, key, _, _ := certutil.GenerateTestCert()
+	secret.Data[corev1.TLSCertKey] = cert
+	secret.Data[corev1.TLSPrivateKeyKey] = key
+	secret.Data[v1alpha1.CoreCACertKey] = cert
+	if err := manager.client.Update(ctx, secret); err != nil {
+		t.Error("could not update test secret")
+	}
+	if _, err := manager.ReconcileUserCertificate(ctx, user, scheme.Scheme, clusterDomain); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+	if err := manager.client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if reflect.DeepEqual(pkcs12Password, secret.Data[v1alpha1.PKCS12PasswordKey]) {
+		t.Error("Expected PKCS#12 keystore to be rendered again for the re-issued certificate")
+	}
+}
+
+func TestRenewUserCertificate(t *testing.T) {
+	clusterDomain := "cluster.local"
+	manager, err := newMock(newMockCluster())
+	if err != nil {
+		t.Error("Expected no error during initialization, got:", err)
+	}
+	ctx := context.Background()
+
+	if err := manager.RenewUserCertificate(ctx, newMockUser()); err == nil {
+		t.Error("Expected error for missing certificate, got nil")
+	}
+
+	cert := manager.clusterCertificateForUser(newMockUser(), clusterDomain)
+	if err := manager.client.Create(ctx, cert); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+	if err := manager.RenewUserCertificate(ctx, newMockUser()); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+
+	// cert-manager re-issues the certificate by itself once renewBefore is reached
+	if err := manager.client.Get(ctx, client.ObjectKeyFromObject(cert), cert); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+	if len(cert.Status.Conditions) != 0 {
+		t.Error("Expected no condition to be set, got:", cert.Status.Conditions)
+	}
+	if cert.Spec.RenewBefore == nil || cert.Spec.RenewBefore.Duration != newMockUser().Spec.GetRenewBefore() {
+		t.Error("Expected renewBefore of the user, got:", cert.Spec.RenewBefore)
+	}
+
+	// cert-manager rejects certificates which are renewed before they are issued
+	user := newMockUser()
+	expirationSeconds, renewBeforeSeconds := int32(3600), int32(7200)
+	user.Spec.ExpirationSeconds, user.Spec.RenewBeforeSeconds = &expirationSeconds, &renewBeforeSeconds
+	if cert := manager.clusterCertificateForUser(user, clusterDomain); cert.Spec.RenewBefore.Duration >= cert.Spec.Duration.Duration {
+		t.Error("Expected renewBefore shorter than the duration, got:", cert.Spec.RenewBefore)
+	}
+}
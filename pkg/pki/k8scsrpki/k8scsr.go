@@ -24,6 +24,8 @@ import (
 const (
 	DependingCsrAnnotation     string = "banzaicloud.io/csr"
 	IncludeFullChainAnnotation string = "csr.banzaicloud.io/fullchain"
+	// RenewalCsrAnnotation refers to the signing request of the certificate which replaces the issued one
+	RenewalCsrAnnotation string = "banzaicloud.io/renewal-csr"
 )
 
 type K8sCSR interface {
@@ -15,6 +15,7 @@
 package k8scsrpki
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"encoding/pem"
@@ -108,8 +109,26 @@ func (c *k8sCSR) ReconcileUserCertificate(
 	}
 
 	// skip handling CSR if the secret already includes all the required fields
-	kafkaUserSecretReady := isKafkaUserCertificateReady(secret, user.Spec.IncludeJKS)
+	kafkaUserSecretReady := isKafkaUserCertificateReady(secret)
 	if kafkaUserSecretReady {
+		// the issued certificate is served until the certificate renewing it is issued
+		if renewalCsrName, ok := secret.Annotations[RenewalCsrAnnotation]; ok {
+			if err := c.reconcileRenewal(ctx, secret, user, renewalCsrName); err != nil {
+				return nil, err
+			}
+		}
+		// render the keystore formats which have been requested since the certificate was issued
+		changed, err := renderUserKeystores(secret, user, nil)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			typeMeta := secret.TypeMeta
+			if err = c.client.Update(ctx, secret); err != nil {
+				return nil, err
+			}
+			secret.TypeMeta = typeMeta
+		}
 		return &pkicommon.UserCertificate{
 			CA:          secret.Data[v1alpha1.CaChainPem],
 			Certificate: secret.Data[corev1.TLSCertKey],
@@ -182,37 +201,10 @@ func (c *k8sCSR) ReconcileUserCertificate(
 			"csrName", signingReq.GetName())
 	}
 
-	certs, err := certutil.ParseCertificates(signingReq.Status.Certificate)
-	if err != nil {
+	if err = c.setIssuedCertificate(ctx, secret, user, signingReq); err != nil {
 		return nil, err
 	}
 
-	//Leaf cert
-	secret.Data[corev1.TLSCertKey] = certs[0].ToPEM()
-	//CA chain certs
-	caChain, err := c.getCAChain(ctx, signingReq, certs)
-	if err != nil {
-		return nil, err
-	}
-
-	secret.Data[v1alpha1.CaChainPem] = caChain
-	certBundleX509 := certutil.GetCertBundle(certs)
-
-	// Ensure a JKS if requested
-	if user.Spec.IncludeJKS {
-		// we don't have an existing one - make a new one
-		if value, ok := secret.Data[v1alpha1.TLSJKSKeyStore]; !ok || len(value) == 0 {
-			jks, jksPasswd, err := certutil.GenerateJKS(certBundleX509, secret.Data[corev1.TLSPrivateKeyKey])
-			if err != nil {
-				return nil, err
-			}
-			secret.Data[v1alpha1.TLSJKSKeyStore] = jks
-			// Adding Truststore to the secret to align with the Cert Manager generated secret
-			secret.Data[v1alpha1.TLSJKSTrustStore] = jks
-			secret.Data[v1alpha1.PasswordKey] = jksPasswd
-		}
-	}
-
 	typeMeta := secret.TypeMeta
 	err = c.client.Update(ctx, secret)
 	if err != nil {
@@ -234,6 +226,101 @@ func (c *k8sCSR) FinalizeUserCertificate(_ context.Context, _ *v1alpha1.KafkaUse
 	return nil
 }
 
+// setIssuedCertificate stores the certificate issued for the signing request and its CA chain in the user secret
+func (c *k8sCSR) setIssuedCertificate(ctx context.Context, secret *corev1.Secret, user *v1alpha1.KafkaUser,
+	signingReq *certsigningreqv1.CertificateSigningRequest) error {
+	certs, err := certutil.ParseCertificates(signingReq.Status.Certificate)
+	if err != nil {
+		return err
+	}
+
+	//Leaf cert
+	secret.Data[corev1.TLSCertKey] = certs[0].ToPEM()
+	//CA chain certs
+	caChain, err := c.getCAChain(ctx, signingReq, certs)
+	if err != nil {
+		return err
+	}
+
+	secret.Data[v1alpha1.CaChainPem] = caChain
+
+	// Ensure the requested keystores, a freshly issued certificate invalidates the previous ones
+	certutil.DeleteKeystores(secret.Data)
+	_, err = renderUserKeystores(secret, user, certs)
+	return err
+}
+
+// RenewUserCertificate requests a new certificate for the private key of the user with a fresh
+// CertificateSigningRequest. The issued certificate stays in the user secret until the new one is issued.
+func (c *k8sCSR) RenewUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser) error {
+	secret := &corev1.Secret{}
+	err := c.client.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret)
+	if err != nil {
+		return errors.WrapIfWithDetails(err,
+			"failed to get user's secret from K8s", "secretName", user.Spec.SecretName,
+			"namespace", user.GetNamespace())
+	}
+	if _, ok := secret.Annotations[RenewalCsrAnnotation]; ok {
+		// the renewal is already requested
+		return nil
+	}
+	return c.requestRenewal(ctx, secret, user)
+}
+
+// requestRenewal creates the signing request renewing the certificate and records it in the user secret
+func (c *k8sCSR) requestRenewal(ctx context.Context, secret *corev1.Secret, user *v1alpha1.KafkaUser) error {
+	signingReq, err := c.generateAndCreateCSR(ctx, secret.Data[corev1.TLSPrivateKeyKey], user)
+	if err != nil {
+		return err
+	}
+	secret.Annotations = util.MergeAnnotations(secret.Annotations, map[string]string{RenewalCsrAnnotation: signingReq.GetName()})
+	return c.client.Update(ctx, secret)
+}
+
+// reconcileRenewal replaces the certificate in the user secret once the signing request renewing it is approved
+// and issued. The issued certificate is kept while the new one is waiting for an approval.
+func (c *k8sCSR) reconcileRenewal(ctx context.Context, secret *corev1.Secret, user *v1alpha1.KafkaUser, renewalCsrName string) error {
+	log := logr.FromContextOrDiscard(ctx)
+	// the signing requests are cluster scoped
+	signingReq, err := c.getUserSigningRequest(ctx, renewalCsrName, "")
+	if apierrors.IsNotFound(err) {
+		// kubernetes removed the signing request before it was issued
+		log.Info("renewal signing request not found, requesting a new one", "csrName", renewalCsrName)
+		return c.requestRenewal(ctx, secret, user)
+	} else if err != nil {
+		return errors.WrapIfWithDetails(err,
+			"failed to get signing request from K8s", "signingRequestName", renewalCsrName,
+			"namespace", secret.GetNamespace())
+	}
+
+	approved := false
+	for _, cond := range signingReq.Status.Conditions {
+		if cond.Type == certsigningreqv1.CertificateApproved {
+			approved = true
+			break
+		}
+	}
+	if !approved {
+		if strings.Split(signingReq.Spec.SignerName, "/")[0] != v1alpha1.CertManagerSignerNamePrefix {
+			log.Info("renewal signing request is waiting for an approval", "csrName", renewalCsrName)
+			return nil
+		}
+		if err = c.Approve(ctx, signingReq); err != nil {
+			return err
+		}
+	}
+	if len(signingReq.Status.Certificate) == 0 {
+		return nil
+	}
+
+	if err = c.setIssuedCertificate(ctx, secret, user, signingReq); err != nil {
+		return err
+	}
+	secret.Annotations[DependingCsrAnnotation] = renewalCsrName
+	delete(secret.Annotations, RenewalCsrAnnotation)
+	return c.client.Update(ctx, secret)
+}
+
 // getUserSigningRequest fetches the k8s signing request for a user
 func (c *k8sCSR) getUserSigningRequest(ctx context.Context, name, namespace string) (*certsigningreqv1.CertificateSigningRequest, error) {
 	signingRequest := &certsigningreqv1.CertificateSigningRequest{}
@@ -309,12 +396,8 @@ func (c *k8sCSR) secretUpdateAnnotation(ctx context.Context, secret *corev1.Secr
 	return nil
 }
 
-func isKafkaUserCertificateReady(secret *corev1.Secret, includeJKS bool) bool {
-	requiredFields := []string{corev1.TLSCertKey, v1alpha1.CaChainPem}
-	if includeJKS {
-		requiredFields = append(requiredFields, v1alpha1.TLSJKSKeyStore, v1alpha1.PasswordKey)
-	}
-	for _, field := range requiredFields {
+func isKafkaUserCertificateReady(secret *corev1.Secret) bool {
+	for _, field := range []string{corev1.TLSCertKey, v1alpha1.CaChainPem} {
 		if _, ok := secret.Data[field]; !ok {
 			return false
 		}
@@ -323,6 +406,38 @@ func isKafkaUserCertificateReady(secret *corev1.Secret, includeJKS bool) bool {
 	return true
 }
 
+// renderUserKeystores renders the keystore formats requested by the KafkaUser which are missing from its secret.
+// The certificate chain is read from the secret when not provided.
+func renderUserKeystores(secret *corev1.Secret, user *v1alpha1.KafkaUser, certs []*certutil.CertificateContainer) (bool, error) {
+	formats := certutil.MissingKeystores(secret.Data, user.Spec.GetKeystoreFormats())
+	if len(formats) == 0 {
+		return false, nil
+	}
+	if certs == nil {
+		chain := bytes.Join([][]byte{secret.Data[corev1.TLSCertKey], secret.Data[v1alpha1.CaChainPem]}, []byte("\n"))
+		var err error
+		if certs, err = certutil.ParseCertificates(bytes.TrimSpace(chain)); err != nil {
+			return false, errors.WrapIf(err, "could not parse user certificate")
+		}
+	}
+	caCerts := make([]*certutil.CertificateContainer, 0)
+	for _, cert := range certs {
+		if cert.Certificate.IsCA {
+			caCerts = append(caCerts, cert)
+		}
+	}
+	if len(caCerts) == 0 {
+		// the CA chain of cert-manager signers is not part of the signing request
+		chain, err := certutil.ParseCertificates(bytes.TrimSpace(secret.Data[v1alpha1.CaChainPem]))
+		if err != nil {
+			return false, errors.WrapIf(err, "could not parse user CA chain")
+		}
+		caCerts = chain
+	}
+	return certutil.RenderKeystores(secret.Data, formats, certutil.GetCertBundle(certs),
+		certutil.GetCertBundle(caCerts), secret.Data[corev1.TLSPrivateKeyKey])
+}
+
 // Approve approves certificate signing requests
 func (c *k8sCSR) Approve(ctx context.Context, signingReq *certsigningreqv1.CertificateSigningRequest) error {
 	cond := certsigningreqv1.CertificateSigningRequestCondition{
@@ -25,10 +25,13 @@ import (
 	. "github.com/onsi/gomega"
 
 	certsigningreqv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/banzaicloud/koperator/api/v1alpha1"
 	"github.com/banzaicloud/koperator/api/v1beta1"
@@ -109,3 +112,89 @@ func TestReconcileUserCertificate(t *testing.T) {
 	Expect(certReq.Subject.CommonName).To(Equal(user.GetName()))
 	Expect(certReq.DNSNames).To(ConsistOf(testDns))
 }
+
+func TestRenewUserCertificate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	sch, err := setupSchemeForTests()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	user := createKafkaUser()
+	userCert, key, _, err := cert.GenerateTestCert()
+	g.Expect(err).NotTo(HaveOccurred())
+	secret := generateUserSecret(key, user.Spec.SecretName, user.Namespace)
+	g.Expect(controllerutil.SetControllerReference(user, secret, sch)).To(Succeed())
+	secret.Annotations = map[string]string{DependingCsrAnnotation: "test-user-csr"}
+	secret.Data[corev1.TLSCertKey] = userCert
+	secret.Data[v1alpha1.CaChainPem] = userCert
+
+	fakeClient := fake.NewClientBuilder().WithScheme(sch).WithObjects(secret).Build()
+	pkiManager := New(fakeClient, newMockCluster())
+	ctx := context.Background()
+	g.Expect(pkiManager.RenewUserCertificate(ctx, user)).To(Succeed())
+	// the renewal is requested once
+	g.Expect(pkiManager.RenewUserCertificate(ctx, user)).To(Succeed())
+
+	var requestList certsigningreqv1.CertificateSigningRequestList
+	g.Expect(fakeClient.List(ctx, &requestList)).To(Succeed())
+	g.Expect(requestList.Items).To(HaveLen(1))
+	signingReq := requestList.Items[0]
+	renewed := &corev1.Secret{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(secret), renewed)).To(Succeed())
+	g.Expect(renewed.Annotations).To(HaveKeyWithValue(RenewalCsrAnnotation, signingReq.Name))
+
+	// the issued certificate is served while the renewal waits for an approval
+	userCertificate, err := pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(userCertificate.Certificate).To(Equal(userCert))
+
+	renewedCert, _, _, err := cert.GenerateTestCert()
+	g.Expect(err).NotTo(HaveOccurred())
+	signingReq.Status.Conditions = []certsigningreqv1.CertificateSigningRequestCondition{
+		{Type: certsigningreqv1.CertificateApproved, Status: corev1.ConditionTrue},
+	}
+	signingReq.Status.Certificate = renewedCert
+	g.Expect(fakeClient.Status().Update(ctx, &signingReq)).To(Succeed())
+
+	userCertificate, err = pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(userCertificate.Certificate).To(Equal(renewedCert))
+	g.Expect(userCertificate.Key).To(Equal(key))
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(secret), renewed)).To(Succeed())
+	g.Expect(renewed.Annotations).To(HaveKeyWithValue(DependingCsrAnnotation, signingReq.Name))
+	g.Expect(renewed.Annotations).NotTo(HaveKey(RenewalCsrAnnotation))
+}
+
+func TestReconcileUserCertificateKeystores(t *testing.T) {
+	g := NewGomegaWithT(t)
+	sch, err := setupSchemeForTests()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	user := createKafkaUser()
+	user.Spec.KeystoreFormats = []v1alpha1.KeystoreFormat{v1alpha1.KeystoreFormatPKCS12, v1alpha1.KeystoreFormatPEMBundle}
+	userCert, key, _, err := cert.GenerateTestCert()
+	g.Expect(err).NotTo(HaveOccurred())
+	secret := generateUserSecret(key, user.Spec.SecretName, user.Namespace)
+	g.Expect(controllerutil.SetControllerReference(user, secret, sch)).To(Succeed())
+	secret.Data[corev1.TLSCertKey] = userCert
+	// the CA chain is stored with a trailing new line
+	secret.Data[v1alpha1.CaChainPem] = append(userCert, '\n')
+
+	fakeClient := fake.NewClientBuilder().WithScheme(sch).WithObjects(secret).Build()
+	pkiManager := New(fakeClient, newMockCluster())
+	ctx := context.Background()
+	_, err = pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	rendered := &corev1.Secret{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(secret), rendered)).To(Succeed())
+	g.Expect(cert.MissingKeystores(rendered.Data, user.Spec.GetKeystoreFormats())).To(BeEmpty())
+	g.Expect(rendered.Data).NotTo(HaveKey(v1alpha1.TLSJKSKeyStore))
+
+	// keystores are rendered only once so their passwords stay stable
+	_, err = pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	reconciled := &corev1.Secret{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(secret), reconciled)).To(Succeed())
+	g.Expect(reconciled.Data[v1alpha1.PKCS12PasswordKey]).To(Equal(rendered.Data[v1alpha1.PKCS12PasswordKey]))
+	g.Expect(reconciled.Data[v1alpha1.PEMBundlePasswordKey]).To(Equal(rendered.Data[v1alpha1.PEMBundlePasswordKey]))
+}
@@ -85,6 +85,10 @@ func (m *mockPKIManager) FinalizeUserCertificate(ctx context.Context, user *v1al
 	return nil
 }
 
+func (m *mockPKIManager) RenewUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser) error {
+	return nil
+}
+
 func (m *mockPKIManager) GetControllerTLSConfig() (*tls.Config, error) {
 	return &tls.Config{}, nil
 }
@@ -73,6 +73,10 @@ func TestGetPKIManager(t *testing.T) {
 		t.Error("Expected nil error got:", err)
 	}
 
+	if err = mock.RenewUserCertificate(ctx, &v1alpha1.KafkaUser{}); err != nil {
+		t.Error("Expected nil error got:", err)
+	}
+
 	if _, err = mock.GetControllerTLSConfig(); err != nil {
 		t.Error("Expected nil error got:", err)
 	}
@@ -239,7 +239,7 @@ func appendGeneratedBrokerCapacities(kafkaCluster *v1beta1.KafkaCluster, log log
 		for _, broker := range kafkaCluster.Spec.Brokers {
 			if brokerId == strconv.Itoa(int(broker.Id)) {
 				brokerFoundInSpec = true
-				brokerDisks, err := generateBrokerDisks(broker, kafkaCluster.Spec, log)
+				brokerDisks, err := generateBrokerDisks(broker, kafkaCluster.Spec, kafkaCluster.Status.BrokersState[brokerId], log)
 				if err != nil {
 					return nil, errors.WrapIfWithDetails(err, "could not generate broker disks config for broker", v1beta1.BrokerIdLabelKey, broker.Id)
 				}
@@ -324,7 +324,10 @@ func generateBrokerCPU(broker v1beta1.Broker, kafkaClusterSpec v1beta1.KafkaClus
 	return strconv.Itoa(int(brokerConfig.GetResources().Limits.Cpu().ScaledValue(-2)))
 }
 
-func generateBrokerDisks(brokerState v1beta1.Broker, kafkaClusterSpec v1beta1.KafkaClusterSpec, log logr.Logger) (map[string]string, error) {
+// generateBrokerDisks generates the disk capacities of the broker, a volume being expanded keeps its capacity until the
+// expansion succeeds and the disks of a broker being migrated to new storage configs follow the phases of the migration
+func generateBrokerDisks(brokerState v1beta1.Broker, kafkaClusterSpec v1beta1.KafkaClusterSpec,
+	brokerStatus v1beta1.BrokerState, log logr.Logger) (map[string]string, error) {
 	storageConfigs := make(map[string]v1beta1.StorageConfig)
 
 	// Get disks from the BrokerConfigGroup if it's in use
@@ -343,9 +346,28 @@ func generateBrokerDisks(brokerState v1beta1.Broker, kafkaClusterSpec v1beta1.Ka
 		}
 	}
 
+	// The broker keeps the disk migrated from until it is drained
+	for path, conf := range storageConfigs {
+		migration := brokerStatus.GracefulActionState.VolumeStates[path].StorageMigration
+		if conf.MigrateFrom == "" || migration == nil {
+			continue
+		}
+		switch migration.Phase {
+		case v1beta1.VolumeMigrationWaiting:
+			delete(storageConfigs, path)
+			storageConfigs[conf.MigrateFrom] = conf
+		case v1beta1.VolumeMigrationAddingVolume:
+			storageConfigs[conf.MigrateFrom] = conf
+		}
+	}
+
 	// Generate log dir configuration
 	logDirs := make(map[string]string, len(storageConfigs))
 	for path, conf := range storageConfigs {
+		if resize, ok := brokerStatus.VolumeResizes[path]; ok && resize.State != v1beta1.VolumeResizeSucceeded && resize.Capacity != nil && conf.PvcSpec != nil {
+			conf.PvcSpec = conf.PvcSpec.DeepCopy()
+			conf.PvcSpec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: *resize.Capacity}
+		}
 		size := parseMountPathWithSize(conf)
 		log.V(1).Info(fmt.Sprintf("broker log.dir %s size in MB: %d", path, size), v1beta1.BrokerIdLabelKey, brokerState.Id)
 
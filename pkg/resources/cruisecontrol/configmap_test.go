@@ -624,6 +624,67 @@ func TestReturnErrorStorageConfigLessThan1MB(t *testing.T) {
 	}
 }
 
+func TestGenerateBrokerDisksWithVolumeResize(t *testing.T) {
+	capacity := resource.MustParse("10Gi")
+	broker := v1beta1.Broker{
+		Id: 0,
+		BrokerConfig: &v1beta1.BrokerConfig{
+			StorageConfigs: []v1beta1.StorageConfig{
+				{
+					MountPath: "/kafka-logs",
+					PvcSpec: &v1.PersistentVolumeClaimSpec{
+						Resources: v1.VolumeResourceRequirements{
+							Requests: v1.ResourceList{
+								v1.ResourceStorage: resource.MustParse("20Gi"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		testName      string
+		volumeResizes map[string]v1beta1.VolumeResizeStatus
+		expectedDisks map[string]string
+	}{
+		{
+			testName:      "volume is not expanded",
+			expectedDisks: map[string]string{"/kafka-logs/kafka": "21474"},
+		},
+		{
+			testName: "volume is being expanded",
+			volumeResizes: map[string]v1beta1.VolumeResizeStatus{
+				"/kafka-logs": {State: v1beta1.VolumeResizeFileSystemResizePending, Capacity: &capacity},
+			},
+			expectedDisks: map[string]string{"/kafka-logs/kafka": "10737"},
+		},
+		{
+			testName: "volume expansion succeeded",
+			volumeResizes: map[string]v1beta1.VolumeResizeStatus{
+				"/kafka-logs": {State: v1beta1.VolumeResizeSucceeded, Capacity: &capacity},
+			},
+			expectedDisks: map[string]string{"/kafka-logs/kafka": "21474"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			disks, err := generateBrokerDisks(broker, v1beta1.KafkaClusterSpec{}, v1beta1.BrokerState{VolumeResizes: test.volumeResizes}, logr.Discard())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(disks, test.expectedDisks) {
+				t.Errorf("expected disks: %v, got: %v", test.expectedDisks, disks)
+			}
+			if broker.BrokerConfig.StorageConfigs[0].PvcSpec.Resources.Requests.Storage().String() != "20Gi" {
+				t.Error("the storage config of the broker must not be changed")
+			}
+		})
+	}
+}
+
 //nolint:funlen
 func TestGenerateCapacityConfigWithUserProvidedInput(t *testing.T) {
 	cpuQuantity, _ := resource.ParseQuantity("2000m")
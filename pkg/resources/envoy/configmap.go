@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 
+	"emperror.dev/errors"
 	envoyaccesslog "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	envoybootstrap "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
 	envoycluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
@@ -221,13 +223,66 @@ func generateEnvoyHealthCheckListener(ingressConfig v1beta1.IngressConfig, log l
 	}
 }
 
-func GenerateEnvoyTLSFilterChain(tcpProxy *envoytcpproxy.TcpProxy, brokerFqdn string, log logr.Logger) (*envoylistener.FilterChain, error) {
+// envoyCipherSuites maps the IANA names of the TLS 1.2 cipher suites to the BoringSSL names envoy accepts
+var envoyCipherSuites = map[v1beta1.CipherSuite]string{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256":       "ECDHE-ECDSA-AES128-GCM-SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":         "ECDHE-RSA-AES128-GCM-SHA256",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384":       "ECDHE-ECDSA-AES256-GCM-SHA384",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":         "ECDHE-RSA-AES256-GCM-SHA384",
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256": "ECDHE-ECDSA-CHACHA20-POLY1305",
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256":   "ECDHE-RSA-CHACHA20-POLY1305",
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":          "ECDHE-ECDSA-AES128-SHA",
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":            "ECDHE-RSA-AES128-SHA",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":          "ECDHE-ECDSA-AES256-SHA",
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":            "ECDHE-RSA-AES256-SHA",
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":               "AES128-GCM-SHA256",
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":               "AES256-GCM-SHA384",
+	"TLS_RSA_WITH_AES_128_CBC_SHA":                  "AES128-SHA",
+	"TLS_RSA_WITH_AES_256_CBC_SHA":                  "AES256-SHA",
+}
+
+// GenerateEnvoyTLSParameters translates the TLS protocol versions and cipher suites of an external listener
+// to envoy TLS parameters. TLS 1.3 cipher suites are not configurable in envoy so they are skipped.
+func GenerateEnvoyTLSParameters(protocols []v1beta1.TLSProtocol, cipherSuites []v1beta1.CipherSuite) (*tlsv3.TlsParameters, error) {
+	tlsParams := &tlsv3.TlsParameters{
+		TlsMinimumProtocolVersion: tlsv3.TlsParameters_TLSv1_2,
+		TlsMaximumProtocolVersion: tlsv3.TlsParameters_TLSv1_3,
+	}
+	if len(protocols) > 0 {
+		tlsParams.TlsMinimumProtocolVersion = tlsv3.TlsParameters_TLSv1_3
+		tlsParams.TlsMaximumProtocolVersion = tlsv3.TlsParameters_TLSv1_2
+		for _, protocol := range protocols {
+			var version tlsv3.TlsParameters_TlsProtocol
+			switch protocol {
+			case v1beta1.TLSProtocolV12:
+				version = tlsv3.TlsParameters_TLSv1_2
+			case v1beta1.TLSProtocolV13:
+				version = tlsv3.TlsParameters_TLSv1_3
+			default:
+				return nil, errors.NewWithDetails("unsupported TLS protocol", "protocol", protocol)
+			}
+			tlsParams.TlsMinimumProtocolVersion = min(tlsParams.TlsMinimumProtocolVersion, version)
+			tlsParams.TlsMaximumProtocolVersion = max(tlsParams.TlsMaximumProtocolVersion, version)
+		}
+	}
+	for _, cipherSuite := range cipherSuites {
+		if strings.HasPrefix(string(cipherSuite), "TLS_AES_") || strings.HasPrefix(string(cipherSuite), "TLS_CHACHA20_") {
+			continue
+		}
+		name, ok := envoyCipherSuites[cipherSuite]
+		if !ok {
+			return nil, errors.NewWithDetails("unsupported cipher suite", "cipherSuite", cipherSuite)
+		}
+		tlsParams.CipherSuites = append(tlsParams.CipherSuites, name)
+	}
+	return tlsParams, nil
+}
+
+func GenerateEnvoyTLSFilterChain(tcpProxy *envoytcpproxy.TcpProxy, brokerFqdn string, tlsParams *tlsv3.TlsParameters,
+	log logr.Logger) (*envoylistener.FilterChain, error) {
 	tlsContext := &tlsv3.DownstreamTlsContext{
 		CommonTlsContext: &tlsv3.CommonTlsContext{
-			TlsParams: &tlsv3.TlsParameters{
-				TlsMinimumProtocolVersion: tlsv3.TlsParameters_TLSv1_2,
-				TlsMaximumProtocolVersion: tlsv3.TlsParameters_TLSv1_3,
-			},
+			TlsParams: tlsParams,
 			TlsCertificates: []*tlsv3.TlsCertificate{
 				{
 					CertificateChain: &envoycore.DataSource{
@@ -324,6 +379,13 @@ func GenerateEnvoyConfig(kc *v1beta1.KafkaCluster, elistener v1beta1.ExternalLis
 	var filterChain *envoylistener.FilterChain
 	var err error
 
+	sslSecrets := kc.Spec.ListenersConfig.SSLSecrets
+	tlsParams, err := GenerateEnvoyTLSParameters(elistener.GetTLSProtocols(sslSecrets), elistener.GetCipherSuites(sslSecrets))
+	if err != nil {
+		log.Error(err, "Unable to generate envoy tls parameters")
+		return ""
+	}
+
 	tempListeners := make(map[int32][]*envoylistener.FilterChain)
 
 	for _, brokerId := range util.GetBrokerIdsFromStatusAndSpec(kc.Status.BrokersState, kc.Spec.Brokers, log) {
@@ -352,7 +414,7 @@ func GenerateEnvoyConfig(kc *v1beta1.KafkaCluster, elistener v1beta1.ExternalLis
 						return ""
 					}
 					return ingressConfig.EnvoyConfig.GetBrokerHostname(int32(brokerId))
-				}(), log)
+				}(), tlsParams, log)
 				if err != nil {
 					log.Error(err, "Unable to generate broker envoy tls filter chain")
 					return ""
@@ -442,7 +504,7 @@ func GenerateEnvoyConfig(kc *v1beta1.KafkaCluster, elistener v1beta1.ExternalLis
 
 	// Create TLS anycast broker listener
 	if elistener.TLSEnabled() {
-		filterChain, err = GenerateEnvoyTLSFilterChain(tcpProxy, ingressConfig.HostnameOverride, log)
+		filterChain, err = GenerateEnvoyTLSFilterChain(tcpProxy, ingressConfig.HostnameOverride, tlsParams, log)
 		if err != nil {
 			log.Error(err, "Unable to generate anycast envoy tls filter chain")
 			return ""
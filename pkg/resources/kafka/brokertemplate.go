@@ -0,0 +1,163 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"sort"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+)
+
+// maxBrokerID is the exclusive upper bound of the broker ids
+const maxBrokerID int32 = 65535
+
+// reconcileBrokerTemplate adds brokers to or removes brokers from the brokers list until the number of brokers
+// derived from the broker template matches its replicas. The brokers with the highest ids are removed first, the
+// brokers with an active Cruise Control operation are kept until it finishes. The removed brokers are downscaled
+// gracefully the same way as the brokers removed from the brokers list by hand.
+func (r *Reconciler) reconcileBrokerTemplate(log logr.Logger) error {
+	template := r.KafkaCluster.Spec.BrokerTemplate
+	if template == nil {
+		return nil
+	}
+
+	var derived []v1beta1.Broker
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		if template.IsDerived(broker) {
+			derived = append(derived, broker)
+		}
+	}
+
+	status := v1beta1.BrokerTemplateStatus{
+		Replicas: int32(len(derived)),
+		Selector: brokerTemplateSelector(r.KafkaCluster),
+	}
+	if current := r.KafkaCluster.Status.BrokerTemplate; current == nil || *current != status {
+		if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, status, log); err != nil {
+			return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update broker template status")
+		}
+	}
+
+	var brokers []v1beta1.Broker
+	switch {
+	case template.Replicas == nil:
+		// the brokers derived from the template are kept, the scale subresource starts from their number
+		replicas := int32(len(derived))
+		r.KafkaCluster.Spec.BrokerTemplate.Replicas = &replicas
+		brokers = r.KafkaCluster.Spec.Brokers
+	case *template.Replicas > int32(len(derived)):
+		ids, err := allocateBrokerIDs(r.KafkaCluster, int(*template.Replicas)-len(derived))
+		if err != nil {
+			return err
+		}
+		brokers = r.KafkaCluster.Spec.Brokers
+		for _, id := range ids {
+			brokers = append(brokers, template.NewBroker(id))
+		}
+		log.Info("adding brokers derived from the broker template", "brokerIds", ids)
+	case *template.Replicas < int32(len(derived)):
+		removed := brokersToRemove(r.KafkaCluster, derived, len(derived)-int(*template.Replicas))
+		if len(removed) == 0 {
+			log.Info("removal of brokers derived from the broker template is deferred as their Cruise Control operations are active")
+			return nil
+		}
+		for _, broker := range r.KafkaCluster.Spec.Brokers {
+			if !removed[broker.Id] {
+				brokers = append(brokers, broker)
+			}
+		}
+		log.Info("removing brokers derived from the broker template", "count", len(removed))
+	default:
+		return nil
+	}
+
+	r.KafkaCluster.Spec.Brokers = brokers
+	if err := k8sutil.UpdateCr(r.KafkaCluster, r.Client); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not update brokers of the broker template")
+	}
+	return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("brokers changed"), "brokers of the broker template changed")
+}
+
+// brokerTemplateSelector returns the label selector of the pods of the brokers derived from the broker template
+func brokerTemplateSelector(cluster *v1beta1.KafkaCluster) string {
+	return labels.SelectorFromSet(apiutil.MergeLabels(
+		apiutil.LabelsForKafka(cluster.Name),
+		map[string]string{v1beta1.IsBrokerNodeKey: "true", v1beta1.BrokerTemplateLabelKey: "true"},
+	)).String()
+}
+
+// allocateBrokerIDs returns the ids of the brokers added from the broker template. The ids of the brokers in the
+// brokers list and in the status are used, the latter belong to brokers which may still be downscaled.
+func allocateBrokerIDs(cluster *v1beta1.KafkaCluster, count int) ([]int32, error) {
+	allocation := cluster.Spec.BrokerTemplate.IDAllocation
+	used := make(map[int32]bool, len(cluster.Spec.Brokers)+len(cluster.Status.BrokersState))
+	for _, broker := range cluster.Spec.Brokers {
+		used[broker.Id] = true
+	}
+	for brokerID := range cluster.Status.BrokersState {
+		if id, err := strconv.Atoi(brokerID); err == nil {
+			used[int32(id)] = true
+		}
+	}
+
+	next := allocation.FirstID
+	if allocation.GetStrategy() == v1beta1.BrokerIDAllocationSequential {
+		for id := range used {
+			if id >= next {
+				next = id + 1
+			}
+		}
+	}
+	ids := make([]int32, 0, count)
+	for id := next; id < maxBrokerID && len(ids) < count; id++ {
+		if !used[id] {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < count {
+		return nil, errorfactory.New(errorfactory.InternalError{}, errors.New("no free broker id"),
+			"could not allocate ids for the brokers of the broker template", "firstId", allocation.FirstID)
+	}
+	return ids, nil
+}
+
+// brokersToRemove returns the ids of the brokers derived from the broker template to remove, the brokers with the
+// highest ids are removed first and the brokers with an active Cruise Control operation are kept
+func brokersToRemove(cluster *v1beta1.KafkaCluster, derived []v1beta1.Broker, count int) map[int32]bool {
+	sorted := make([]v1beta1.Broker, len(derived))
+	copy(sorted, derived)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Id > sorted[j].Id })
+
+	removed := make(map[int32]bool, count)
+	for _, broker := range sorted {
+		if len(removed) == count {
+			break
+		}
+		if state, ok := cluster.Status.BrokersState[strconv.Itoa(int(broker.Id))]; ok &&
+			state.GracefulActionState.CruiseControlState.IsActive() {
+			continue
+		}
+		removed[broker.Id] = true
+	}
+	return removed
+}
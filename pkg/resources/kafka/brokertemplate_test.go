@@ -0,0 +1,189 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+func TestReconcileBrokerTemplate(t *testing.T) {
+	tests := []struct {
+		testName          string
+		replicas          *int32
+		strategy          v1beta1.BrokerIDAllocationStrategy
+		brokerIDs         []int32
+		handListedIDs     []int32
+		brokerStates      map[string]v1beta1.CruiseControlState
+		expectedBrokerIDs []int32
+		expectedReplicas  int32
+		expectedUpdate    bool
+	}{
+		{
+			testName:          "replicas are set to the number of derived brokers",
+			brokerIDs:         []int32{0, 100, 101},
+			expectedBrokerIDs: []int32{0, 100, 101},
+			expectedReplicas:  2,
+			expectedUpdate:    true,
+		},
+		{
+			testName:          "brokers are left unchanged when they match the replicas",
+			replicas:          util.Int32Pointer(2),
+			brokerIDs:         []int32{0, 100, 101},
+			expectedBrokerIDs: []int32{0, 100, 101},
+			expectedReplicas:  2,
+		},
+		{
+			testName:          "brokers of the group listed by hand are not derived",
+			replicas:          util.Int32Pointer(0),
+			brokerIDs:         []int32{0, 100},
+			handListedIDs:     []int32{101},
+			expectedBrokerIDs: []int32{0, 101},
+			expectedReplicas:  1,
+			expectedUpdate:    true,
+		},
+		{
+			testName:          "brokers are added with the lowest available ids",
+			replicas:          util.Int32Pointer(4),
+			brokerIDs:         []int32{0, 100, 102},
+			brokerStates:      map[string]v1beta1.CruiseControlState{"103": v1beta1.GracefulDownscaleRunning},
+			expectedBrokerIDs: []int32{0, 100, 102, 101, 104},
+			expectedReplicas:  2,
+			expectedUpdate:    true,
+		},
+		{
+			testName:          "brokers are added with sequential ids",
+			replicas:          util.Int32Pointer(4),
+			strategy:          v1beta1.BrokerIDAllocationSequential,
+			brokerIDs:         []int32{0, 100, 102},
+			brokerStates:      map[string]v1beta1.CruiseControlState{"103": v1beta1.GracefulDownscaleRunning},
+			expectedBrokerIDs: []int32{0, 100, 102, 104, 105},
+			expectedReplicas:  2,
+			expectedUpdate:    true,
+		},
+		{
+			testName:          "brokers with the highest ids are removed",
+			replicas:          util.Int32Pointer(1),
+			brokerIDs:         []int32{0, 100, 101, 102},
+			expectedBrokerIDs: []int32{0, 100},
+			expectedReplicas:  3,
+			expectedUpdate:    true,
+		},
+		{
+			testName:  "brokers with active Cruise Control operations are not removed",
+			replicas:  util.Int32Pointer(1),
+			brokerIDs: []int32{0, 100, 101, 102},
+			brokerStates: map[string]v1beta1.CruiseControlState{
+				"100": v1beta1.GracefulUpscaleSucceeded,
+				"102": v1beta1.GracefulUpscaleRunning,
+			},
+			expectedBrokerIDs: []int32{0, 102},
+			expectedReplicas:  3,
+			expectedUpdate:    true,
+		},
+		{
+			testName:  "removal is deferred while all brokers have active Cruise Control operations",
+			replicas:  util.Int32Pointer(0),
+			brokerIDs: []int32{0, 100},
+			brokerStates: map[string]v1beta1.CruiseControlState{
+				"100": v1beta1.GracefulUpscaleRequired,
+			},
+			expectedBrokerIDs: []int32{0, 100},
+			expectedReplicas:  1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			brokers := make([]v1beta1.Broker, 0, len(test.brokerIDs))
+			template := &v1beta1.BrokerTemplate{
+				Replicas:          test.replicas,
+				BrokerConfigGroup: "template",
+				IDAllocation:      v1beta1.BrokerIDAllocation{Strategy: test.strategy, FirstID: 100},
+			}
+			for _, id := range test.brokerIDs {
+				if id < 100 {
+					brokers = append(brokers, v1beta1.Broker{Id: id, BrokerConfigGroup: "default"})
+				} else {
+					brokers = append(brokers, template.NewBroker(id))
+				}
+			}
+			for _, id := range test.handListedIDs {
+				brokers = append(brokers, v1beta1.Broker{Id: id, BrokerConfigGroup: "template"})
+			}
+			brokerStates := make(map[string]v1beta1.BrokerState, len(test.brokerStates))
+			for id, state := range test.brokerStates {
+				brokerStates[id] = v1beta1.BrokerState{GracefulActionState: v1beta1.GracefulActionState{CruiseControlState: state}}
+			}
+			cluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Spec: v1beta1.KafkaClusterSpec{
+					BrokerConfigGroups: map[string]v1beta1.BrokerConfig{
+						"default":  {},
+						"template": {BrokerLabels: map[string]string{"pool": "template"}},
+					},
+					Brokers:        brokers,
+					BrokerTemplate: template,
+				},
+				Status: v1beta1.KafkaClusterStatus{BrokersState: brokerStates},
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			r := New(mockClient, nil, cluster, new(kafkaclient.MockedProvider))
+			expectStatusUpdates(mockCtrl, mockClient, r).Times(1)
+			var updated *v1beta1.KafkaCluster
+			mockClient.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.KafkaCluster{})).Do(
+				func(ctx context.Context, kafkaCluster *v1beta1.KafkaCluster, opts ...client.UpdateOption) {
+					updated = kafkaCluster.DeepCopy()
+				}).Return(nil).MaxTimes(1)
+
+			err := r.reconcileBrokerTemplate(logf.Log)
+			if test.expectedUpdate {
+				require.True(t, errors.As(err, &errorfactory.ResourceNotReady{}))
+				require.NotNil(t, updated)
+				require.Equal(t, r.KafkaCluster.Spec, updated.Spec)
+			} else {
+				require.NoError(t, err)
+				require.Nil(t, updated)
+			}
+
+			brokerIDs := make([]int32, 0, len(r.KafkaCluster.Spec.Brokers))
+			for _, broker := range r.KafkaCluster.Spec.Brokers {
+				brokerIDs = append(brokerIDs, broker.Id)
+			}
+			require.Equal(t, test.expectedBrokerIDs, brokerIDs)
+			if test.replicas == nil {
+				require.Equal(t, util.Int32Pointer(test.expectedReplicas), r.KafkaCluster.Spec.BrokerTemplate.Replicas)
+			}
+			require.Equal(t, &v1beta1.BrokerTemplateStatus{
+				Replicas: test.expectedReplicas,
+				Selector: "app=kafka,isBrokerNode=true,kafka.banzaicloud.io/broker-template=true,kafka_cr=kafka",
+			}, r.KafkaCluster.Status.BrokerTemplate)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+)
+
+const (
+	canaryFailedReason  = "CanaryFailed"
+	canaryStartedReason = "CanaryStarted"
+)
+
+// currentCanary returns the canary of the rolling upgrade, a new one is returned when the spec changed since the
+// canary was started
+func (r *Reconciler) currentCanary() v1beta1.CanaryStatus {
+	canary := r.KafkaCluster.Status.RollingUpgrade.Canary
+	if canary == nil || canary.Generation != r.KafkaCluster.Generation {
+		return v1beta1.CanaryStatus{Generation: r.KafkaCluster.Generation, Phase: v1beta1.CanaryPhaseRestarting}
+	}
+	return *canary.DeepCopy()
+}
+
+// evaluateCanary watches the canary brokers while they soak. The rolling upgrade is halted for good when the brokers
+// with offline or out of sync replicas or the container terminations of the canary brokers exceed the thresholds, it only
+// continues with a new canary once the spec is changed.
+func (r *Reconciler) evaluateCanary(pods []corev1.Pod, impactedReplicas map[int32]struct{}, log logr.Logger) error {
+	config := r.KafkaCluster.Spec.RollingUpgradeConfig.Canary
+	if config == nil {
+		return nil
+	}
+	canary := r.currentCanary()
+
+	switch canary.Phase {
+	case v1beta1.CanaryPhaseFailed:
+		return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New(canary.Message),
+			"rolling upgrade is halted by the failed canary, change the spec to continue", "canaryBrokerIDs", strings.Join(canary.Brokers, ","))
+	case v1beta1.CanaryPhaseSoaking:
+	default:
+		return nil
+	}
+
+	var message string
+	switch terminations := r.canaryContainerTerminations(pods, canary); {
+	case len(impactedReplicas) > config.MaxUnhealthyBrokers:
+		message = fmt.Sprintf("%d brokers have offline or out of sync replicas, at most %d are allowed", len(impactedReplicas), config.MaxUnhealthyBrokers)
+	case terminations > config.MaxPodRestarts:
+		message = fmt.Sprintf("the canary broker containers terminated %d times, at most %d restarts are allowed", terminations, config.MaxPodRestarts)
+	}
+	if message != "" {
+		log.Info("canary of the rolling upgrade failed, halting the rolling upgrade", "reason", message, "canaryBrokerIDs", canary.Brokers)
+		canary.Phase = v1beta1.CanaryPhaseFailed
+		canary.Message = message
+		if err := r.updateCanary(canary, metav1.ConditionTrue, canaryFailedReason, message, log); err != nil {
+			return err
+		}
+		return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New(message),
+			"rolling upgrade is halted by the failed canary, change the spec to continue", "canaryBrokerIDs", strings.Join(canary.Brokers, ","))
+	}
+
+	if soaked := time.Since(canary.SoakStartTime.Time); soaked < config.GetSoakPeriod() {
+		return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("canary brokers are soaking"), "rolling upgrade in progress",
+			"canaryBrokerIDs", strings.Join(canary.Brokers, ","), "remaining", (config.GetSoakPeriod() - soaked).Round(time.Second).String())
+	}
+
+	log.Info("canary of the rolling upgrade passed, restarting the rest of the brokers", "canaryBrokerIDs", canary.Brokers)
+	canary.Phase = v1beta1.CanaryPhasePassed
+	return r.updateCanary(canary, "", "", "", log)
+}
+
+// admitCanaryBroker picks the brokers restarted as canaries. Once all of them are picked the rest of the brokers wait
+// until the canary pods are ready, the soak period starts then.
+func (r *Reconciler) admitCanaryBroker(pods []corev1.Pod, currentPod *corev1.Pod, log logr.Logger) error {
+	config := r.KafkaCluster.Spec.RollingUpgradeConfig.Canary
+	if config == nil {
+		return nil
+	}
+	canary := r.currentCanary()
+	if canary.Phase != v1beta1.CanaryPhaseRestarting {
+		return nil
+	}
+
+	brokerID := currentPod.Labels[v1beta1.BrokerIdLabelKey]
+	if slices.Contains(canary.Brokers, brokerID) {
+		return nil
+	}
+	if len(canary.Brokers) < config.GetBrokerCount() {
+		log.Info("restarting broker as a canary of the rolling upgrade", v1beta1.BrokerIdLabelKey, brokerID)
+		canary.Brokers = append(canary.Brokers, brokerID)
+		reason := ""
+		if len(canary.Brokers) == 1 {
+			// a new canary resumes the rolling upgrade halted by the previous one
+			reason = canaryStartedReason
+		}
+		return r.updateCanary(canary, metav1.ConditionFalse, reason, "canary brokers are restarted", log)
+	}
+
+	if notReady := notReadyCanaryBrokers(pods, canary.Brokers); len(notReady) > 0 {
+		return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("canary brokers are not ready yet"), "rolling upgrade in progress",
+			"canaryBrokerIDs", strings.Join(notReady, ","))
+	}
+
+	log.Info("canary brokers are ready, watching them before the rest of the brokers are restarted",
+		"canaryBrokerIDs", canary.Brokers, "soakPeriod", config.GetSoakPeriod().String())
+	now := metav1.Now()
+	canary.Phase = v1beta1.CanaryPhaseSoaking
+	canary.SoakStartTime = &now
+	if err := r.updateCanary(canary, "", "", "", log); err != nil {
+		return err
+	}
+	return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("canary brokers are soaking"), "rolling upgrade in progress",
+		"canaryBrokerIDs", strings.Join(canary.Brokers, ","))
+}
+
+// updateCanary persists the canary and, when a reason is given, the RollingUpgradeHalted condition
+func (r *Reconciler) updateCanary(canary v1beta1.CanaryStatus, conditionStatus metav1.ConditionStatus, reason, message string, log logr.Logger) error {
+	if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, canary, log); err != nil {
+		return errors.WrapIf(err, "could not update canary status")
+	}
+	if reason == "" {
+		return nil
+	}
+	condition := metav1.Condition{
+		Type:               v1beta1.RollingUpgradeHaltedCondition,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: r.KafkaCluster.Generation,
+	}
+	if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, condition, log); err != nil {
+		return errors.WrapIf(err, "could not update rolling upgrade condition")
+	}
+	return nil
+}
+
+func notReadyCanaryBrokers(pods []corev1.Pod, brokerIDs []string) []string {
+	var notReady []string
+	for _, brokerID := range brokerIDs {
+		i := slices.IndexFunc(pods, func(pod corev1.Pod) bool {
+			return pod.Labels[v1beta1.BrokerIdLabelKey] == brokerID
+		})
+		// a canary broker removed from the spec meanwhile does not hold back the rolling upgrade
+		if i < 0 {
+			continue
+		}
+		if pods[i].DeletionTimestamp != nil || !isPodReady(&pods[i]) {
+			notReady = append(notReady, brokerID)
+		}
+	}
+	return notReady
+}
+
+func (r *Reconciler) canaryContainerTerminations(pods []corev1.Pod, canary v1beta1.CanaryStatus) int32 {
+	var terminations int32
+	for _, brokerID := range canary.Brokers {
+		terminations += r.containerTerminations(pods, brokerID, *canary.SoakStartTime)
+	}
+	return terminations
+}
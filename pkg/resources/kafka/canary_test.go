@@ -0,0 +1,249 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+func newCanaryReconciler(t *testing.T, canary *v1beta1.CanaryStatus) *Reconciler {
+	mockCtrl := gomock.NewController(t)
+	mockClient := mocks.NewMockClient(mockCtrl)
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec: v1beta1.KafkaClusterSpec{
+			RollingUpgradeConfig: v1beta1.RollingUpgradeConfig{
+				Canary: &v1beta1.CanaryConfig{
+					BrokerCount:    2,
+					SoakPeriod:     &metav1.Duration{Duration: time.Minute},
+					MaxPodRestarts: 1,
+				},
+			},
+		},
+		Status: v1beta1.KafkaClusterStatus{
+			State:          v1beta1.KafkaClusterRollingUpgrading,
+			RollingUpgrade: v1beta1.RollingUpgradeStatus{Canary: canary},
+		},
+	}
+	return newStatusUpdatingReconciler(mockCtrl, mockClient, cluster, new(kafkaclient.MockedProvider))
+}
+
+func newCanaryPod(brokerID string, ready bool, restarts int32) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-" + brokerID, Labels: map[string]string{v1beta1.BrokerIdLabelKey: brokerID}},
+		// the broker pods never restart their containers
+		Spec: corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "kafka",
+				Ready:        ready,
+				RestartCount: restarts,
+				State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	}
+}
+
+// newTerminatedPod returns the pod of the broker whose container terminated at the given time
+func newTerminatedPod(brokerID string, finishedAt time.Time) corev1.Pod {
+	pod := newCanaryPod(brokerID, false, 0)
+	pod.Status.Phase = corev1.PodFailed
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", FinishedAt: metav1.NewTime(finishedAt)},
+	}
+	return pod
+}
+
+func containerTerminatedRestart(at time.Time) v1beta1.BrokerRestart {
+	return v1beta1.BrokerRestart{Time: metav1.NewTime(at), Trigger: v1beta1.BrokerRestartContainerTerminated}
+}
+
+func soakingCanary(since time.Duration) *v1beta1.CanaryStatus {
+	start := metav1.NewTime(time.Now().Add(-since))
+	return &v1beta1.CanaryStatus{
+		Generation:    2,
+		Phase:         v1beta1.CanaryPhaseSoaking,
+		Brokers:       []string{"0", "1"},
+		SoakStartTime: &start,
+	}
+}
+
+func TestEvaluateCanary(t *testing.T) {
+	tests := []struct {
+		testName         string
+		canary           *v1beta1.CanaryStatus
+		pods             []corev1.Pod
+		impactedReplicas map[int32]struct{}
+		restartHistory   map[string][]v1beta1.BrokerRestart
+		expectedHalt     bool
+		expectedPhase    v1beta1.CanaryPhase
+		expectedReason   string
+	}{
+		{
+			testName:      "canary is not started yet",
+			expectedPhase: "",
+		},
+		{
+			testName:       "failed canary halts the rolling upgrade",
+			canary:         &v1beta1.CanaryStatus{Generation: 2, Phase: v1beta1.CanaryPhaseFailed, Brokers: []string{"0"}, Message: "failed"},
+			expectedHalt:   true,
+			expectedPhase:  v1beta1.CanaryPhaseFailed,
+			expectedReason: "",
+		},
+		{
+			testName:      "failed canary of a previous generation does not halt the rolling upgrade",
+			canary:        &v1beta1.CanaryStatus{Generation: 1, Phase: v1beta1.CanaryPhaseFailed, Brokers: []string{"0"}, Message: "failed"},
+			expectedPhase: v1beta1.CanaryPhaseFailed,
+		},
+		{
+			testName:         "canary fails on brokers with out of sync replicas",
+			canary:           soakingCanary(time.Second),
+			pods:             []corev1.Pod{newCanaryPod("0", true, 0), newCanaryPod("1", true, 0)},
+			impactedReplicas: map[int32]struct{}{2: {}},
+			expectedHalt:     true,
+			expectedPhase:    v1beta1.CanaryPhaseFailed,
+			expectedReason:   canaryFailedReason,
+		},
+		{
+			testName: "canary fails on container terminations",
+			canary:   soakingCanary(30 * time.Second),
+			pods: []corev1.Pod{newCanaryPod("0", true, 0), newTerminatedPod("1", time.Now().Add(-time.Second)),
+				newTerminatedPod("2", time.Now())},
+			restartHistory: map[string][]v1beta1.BrokerRestart{
+				"0": {containerTerminatedRestart(time.Now().Add(-10 * time.Second))},
+			},
+			expectedHalt:   true,
+			expectedPhase:  v1beta1.CanaryPhaseFailed,
+			expectedReason: canaryFailedReason,
+		},
+		{
+			testName: "canary brokers are soaking",
+			canary:   soakingCanary(30 * time.Second),
+			pods:     []corev1.Pod{newCanaryPod("0", true, 0), newCanaryPod("1", true, 0), newTerminatedPod("2", time.Now())},
+			restartHistory: map[string][]v1beta1.BrokerRestart{
+				// a termination before the soak period and the restart of the canary broker are not counted
+				"0": {
+					containerTerminatedRestart(time.Now().Add(-time.Hour)),
+					{Time: metav1.NewTime(time.Now().Add(-time.Hour)), Trigger: v1beta1.BrokerRestartPodSpecChanged},
+				},
+				"1": {containerTerminatedRestart(time.Now().Add(-10 * time.Second))},
+				"2": {containerTerminatedRestart(time.Now().Add(-10 * time.Second))},
+			},
+			expectedHalt:  true,
+			expectedPhase: v1beta1.CanaryPhaseSoaking,
+		},
+		{
+			testName:      "canary passes after the soak period",
+			canary:        soakingCanary(2 * time.Minute),
+			pods:          []corev1.Pod{newCanaryPod("0", true, 0), newCanaryPod("1", true, 0)},
+			expectedPhase: v1beta1.CanaryPhasePassed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			r := newCanaryReconciler(t, test.canary)
+			r.KafkaCluster.Status.BrokersState = make(map[string]v1beta1.BrokerState)
+			for brokerID, restartHistory := range test.restartHistory {
+				r.KafkaCluster.Status.BrokersState[brokerID] = v1beta1.BrokerState{RestartHistory: restartHistory}
+			}
+
+			err := r.evaluateCanary(test.pods, test.impactedReplicas, logf.Log)
+			if test.expectedHalt {
+				require.True(t, errors.As(err, &errorfactory.ReconcileRollingUpgrade{}), "expected the rolling upgrade to be halted, got: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var phase v1beta1.CanaryPhase
+			if canary := r.KafkaCluster.Status.RollingUpgrade.Canary; canary != nil {
+				phase = canary.Phase
+			}
+			require.Equal(t, test.expectedPhase, phase)
+
+			condition := meta.FindStatusCondition(r.KafkaCluster.Status.Conditions, v1beta1.RollingUpgradeHaltedCondition)
+			if test.expectedReason == "" {
+				require.Nil(t, condition)
+				return
+			}
+			require.NotNil(t, condition)
+			require.Equal(t, metav1.ConditionTrue, condition.Status)
+			require.Equal(t, test.expectedReason, condition.Reason)
+		})
+	}
+}
+
+func TestAdmitCanaryBroker(t *testing.T) {
+	t.Run("first broker starts a new canary", func(t *testing.T) {
+		r := newCanaryReconciler(t, &v1beta1.CanaryStatus{Generation: 1, Phase: v1beta1.CanaryPhaseFailed, Brokers: []string{"3"}})
+		pod := newCanaryPod("0", true, 0)
+
+		require.NoError(t, r.admitCanaryBroker([]corev1.Pod{pod}, &pod, logf.Log))
+		require.Equal(t, &v1beta1.CanaryStatus{Generation: 2, Phase: v1beta1.CanaryPhaseRestarting, Brokers: []string{"0"}},
+			r.KafkaCluster.Status.RollingUpgrade.Canary)
+		condition := meta.FindStatusCondition(r.KafkaCluster.Status.Conditions, v1beta1.RollingUpgradeHaltedCondition)
+		require.NotNil(t, condition)
+		require.Equal(t, metav1.ConditionFalse, condition.Status)
+		require.Equal(t, canaryStartedReason, condition.Reason)
+	})
+
+	t.Run("rest of the brokers wait for the canary pods to be ready", func(t *testing.T) {
+		r := newCanaryReconciler(t, &v1beta1.CanaryStatus{Generation: 2, Phase: v1beta1.CanaryPhaseRestarting, Brokers: []string{"0", "1"}})
+		pods := []corev1.Pod{newCanaryPod("0", true, 0), newCanaryPod("1", false, 0), newCanaryPod("2", true, 0)}
+
+		err := r.admitCanaryBroker(pods, &pods[2], logf.Log)
+		require.True(t, errors.As(err, &errorfactory.ReconcileRollingUpgrade{}), "expected the rolling upgrade to wait, got: %v", err)
+		require.Equal(t, v1beta1.CanaryPhaseRestarting, r.KafkaCluster.Status.RollingUpgrade.Canary.Phase)
+	})
+
+	t.Run("soak period starts once the canary pods are ready", func(t *testing.T) {
+		r := newCanaryReconciler(t, &v1beta1.CanaryStatus{Generation: 2, Phase: v1beta1.CanaryPhaseRestarting, Brokers: []string{"0", "1"}})
+		pods := []corev1.Pod{newCanaryPod("0", true, 0), newCanaryPod("1", true, 0), newCanaryPod("2", true, 0)}
+
+		err := r.admitCanaryBroker(pods, &pods[2], logf.Log)
+		require.True(t, errors.As(err, &errorfactory.ReconcileRollingUpgrade{}), "expected the rolling upgrade to wait, got: %v", err)
+		canary := r.KafkaCluster.Status.RollingUpgrade.Canary
+		require.Equal(t, v1beta1.CanaryPhaseSoaking, canary.Phase)
+		require.NotNil(t, canary.SoakStartTime)
+	})
+
+	t.Run("brokers are not held back after the canary passed", func(t *testing.T) {
+		canary := &v1beta1.CanaryStatus{Generation: 2, Phase: v1beta1.CanaryPhasePassed, Brokers: []string{"0", "1"}}
+		r := newCanaryReconciler(t, canary)
+		pod := newCanaryPod("2", true, 0)
+
+		require.NoError(t, r.admitCanaryBroker([]corev1.Pod{pod}, &pod, logf.Log))
+		require.Equal(t, canary, r.KafkaCluster.Status.RollingUpgrade.Canary)
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+// newStatusUpdatingReconciler creates a reconciler of the cluster whose status updates are applied to the cluster
+// of the reconciler, so the tests observe the status the reconciler wrote
+func newStatusUpdatingReconciler(mockCtrl *gomock.Controller, mockClient *mocks.MockClient, cluster *v1beta1.KafkaCluster,
+	kafkaClientProvider kafkaclient.Provider) *Reconciler {
+	r := New(mockClient, nil, cluster, kafkaClientProvider)
+	expectStatusUpdates(mockCtrl, mockClient, r).AnyTimes()
+	return r
+}
+
+// expectStatusUpdates expects the status updates of the cluster of the reconciler and applies them to it, the
+// returned call limits the number of updates
+func expectStatusUpdates(mockCtrl *gomock.Controller, mockClient *mocks.MockClient, r *Reconciler) *gomock.Call {
+	mockSubResourceClient := mocks.NewMockSubResourceClient(mockCtrl)
+	mockClient.EXPECT().Status().Return(mockSubResourceClient).AnyTimes()
+	return mockSubResourceClient.EXPECT().Update(context.Background(), gomock.AssignableToTypeOf(&v1beta1.KafkaCluster{})).Do(
+		func(ctx context.Context, kafkaCluster *v1beta1.KafkaCluster, opts ...client.SubResourceUpdateOption) {
+			r.KafkaCluster.Status = kafkaCluster.Status
+		}).Return(nil)
+}
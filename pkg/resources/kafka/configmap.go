@@ -36,13 +36,14 @@ import (
 	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/resources/templates"
 	"github.com/banzaicloud/koperator/pkg/util"
+	clientutil "github.com/banzaicloud/koperator/pkg/util/client"
 	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
 	properties "github.com/banzaicloud/koperator/properties/pkg"
 )
 
 func (r *Reconciler) getConfigProperties(bConfig *v1beta1.BrokerConfig, broker v1beta1.Broker, quorumVoters []string,
 	extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses map[string]v1beta1.ListenerStatusList,
-	serverPasses map[string]string, clientPass string, superUsers []string, log logr.Logger) *properties.Properties {
+	serverPasses map[string]string, clientPass string, superUsers []string, log logr.Logger) (*properties.Properties, error) {
 	config := properties.NewProperties()
 
 	// bootstrapServers, err := kafkautils.GetBootstrapServersService(r.KafkaCluster)
@@ -69,9 +70,11 @@ func (r *Reconciler) getConfigProperties(bConfig *v1beta1.BrokerConfig, broker v
 
 	// Kafka Broker configurations
 	if r.KafkaCluster.Spec.KRaftMode {
-		configureBrokerKRaftMode(bConfig, broker.Id, r.KafkaCluster, config, quorumVoters, serverPasses, extListenerStatuses, intListenerStatuses, log, brokerReadOnlyConfig)
+		configureBrokerKRaftMode(bConfig, broker.Id, r.KafkaCluster, config, quorumVoters, serverPasses, extListenerStatuses, intListenerStatuses, log,
+			getMigrationNodeMode(r.KafkaCluster, bConfig, brokerReadOnlyConfig))
 	} else {
 		configureBrokerZKMode(broker.Id, r.KafkaCluster, config, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, log)
+		configureInterBrokerProtocolVersion(r.KafkaCluster, brokerReadOnlyConfig, config, log)
 	}
 
 	// This logic prevents the removal of the mountPath from the broker configmap
@@ -96,6 +99,21 @@ func (r *Reconciler) getConfigProperties(bConfig *v1beta1.BrokerConfig, broker v
 		}
 	}
 
+	if r.KafkaCluster.Spec.IsTieredStorageEnabled() {
+		rsmConfigKeys, err := r.getRemoteStorageManagerConfigKeys(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		configureTieredStorage(r.KafkaCluster.Spec, brokerReadOnlyConfig, config, rsmConfigKeys, log)
+	}
+
+	// Add the principal mapping rules which the super users and the KafkaUser ACLs are computed with
+	if rules := r.KafkaCluster.Spec.ListenersConfig.PrincipalMappingRules; len(rules) > 0 {
+		if err := config.Set(kafkautils.KafkaConfigSSLPrincipalMappingRules, strings.Join(rules, ",")); err != nil {
+			log.Error(err, fmt.Sprintf(kafkautils.BrokerConfigErrorMsgTemplate, kafkautils.KafkaConfigSSLPrincipalMappingRules))
+		}
+	}
+
 	// Add superuser configuration
 	su := strings.Join(generateSuperUsers(superUsers), ";")
 	if su != "" {
@@ -103,7 +121,7 @@ func (r *Reconciler) getConfigProperties(bConfig *v1beta1.BrokerConfig, broker v
 			log.Error(err, fmt.Sprintf(kafkautils.BrokerConfigErrorMsgTemplate, kafkautils.KafkaConfigSuperUsers))
 		}
 	}
-	return config
+	return config, nil
 }
 
 func (r *Reconciler) configCCMetricsReporter(broker v1beta1.Broker, bConfig *v1beta1.BrokerConfig, config *properties.Properties, clientPass string, log logr.Logger) {
@@ -128,6 +146,11 @@ func (r *Reconciler) configCCMetricsReporter(broker v1beta1.Broker, bConfig *v1b
 			kafkautils.KafkaConfigSSLKeyStorePassword:   clientPass,
 			kafkautils.KafkaConfigSSLTrustStorePassword: clientPass,
 		}
+		// the reporter connects through the listener used for inner broker communication
+		if listener := clientutil.GetInnerBrokerListener(r.KafkaCluster); listener != nil {
+			sslSecrets := r.KafkaCluster.Spec.ListenersConfig.SSLSecrets
+			maps.Copy(sslConfig, generateTLSConfig("", listener.GetTLSProtocols(sslSecrets), listener.GetCipherSuites(sslSecrets)))
+		}
 
 		for k, v := range sslConfig {
 			if err := config.Set(fmt.Sprintf("cruise.control.metrics.reporter.%s", k), v); err != nil {
@@ -158,12 +181,12 @@ func (r *Reconciler) configCCMetricsReporter(broker v1beta1.Broker, bConfig *v1b
 
 func configureBrokerKRaftMode(bConfig *v1beta1.BrokerConfig, brokerID int32, kafkaCluster *v1beta1.KafkaCluster, config *properties.Properties,
 	quorumVoters []string, serverPasses map[string]string, extListenerStatuses, intListenerStatuses map[string]v1beta1.ListenerStatusList, log logr.Logger,
-	brokerReadOnlyConfig *properties.Properties) {
+	nodeMode migrationNodeMode) {
 	controllerListenerName := generateControlPlaneListener(kafkaCluster.Spec.ListenersConfig.InternalListeners)
 
 	// when kRaft is enabled for the cluster, brokers can still be configured to use zookeeper for metadata.
 	// this is to support the zk to kRaft migration where both zookeeper and kRaft controllers are running in parallel.
-	if shouldUseKRaftModeForBroker(brokerReadOnlyConfig) {
+	if nodeMode.kRaftMode {
 		if err := config.Set(kafkautils.KafkaConfigNodeID, brokerID); err != nil {
 			log.Error(err, fmt.Sprintf(kafkautils.BrokerConfigErrorMsgTemplate, kafkautils.KafkaConfigNodeID))
 		}
@@ -184,9 +207,26 @@ func configureBrokerKRaftMode(bConfig *v1beta1.BrokerConfig, brokerID int32, kaf
 		}
 	}
 
-	if shouldConfigureControllerQuorumForBroker(brokerReadOnlyConfig) {
-		if err := config.Set(kafkautils.KafkaConfigControllerQuorumVoters, quorumVoters); err != nil {
-			log.Error(err, fmt.Sprintf(kafkautils.BrokerConfigErrorMsgTemplate, kafkautils.KafkaConfigControllerQuorumVoters))
+	// nodes taking part in the metadata migration connect to both ZooKeeper and the KRaft controllers
+	if nodeMode.metadataMigration {
+		if err := config.Set(kafkautils.KafkaConfigZooKeeperMigrationEnable, true); err != nil {
+			log.Error(err, fmt.Sprintf(kafkautils.BrokerConfigErrorMsgTemplate, kafkautils.KafkaConfigZooKeeperMigrationEnable))
+		}
+
+		if err := config.Set(kafkautils.KafkaConfigZooKeeperConnect, zookeeperutils.PrepareConnectionAddress(
+			kafkaCluster.Spec.ZKAddresses, kafkaCluster.Spec.GetZkPath())); err != nil {
+			log.Error(err, fmt.Sprintf(kafkautils.BrokerConfigErrorMsgTemplate, kafkautils.KafkaConfigZooKeeperConnect))
+		}
+	}
+
+	if nodeMode.controllerQuorum {
+		// in a dynamic quorum the voters are stored in the metadata log, the nodes only need controllers to bootstrap from
+		quorumConfig := kafkautils.KafkaConfigControllerQuorumVoters
+		if kafkaCluster.Spec.KRaftDynamicQuorum {
+			quorumConfig = kafkautils.KafkaConfigControllerQuorumBootstrapServers
+		}
+		if err := config.Set(quorumConfig, quorumVoters); err != nil {
+			log.Error(err, fmt.Sprintf(kafkautils.BrokerConfigErrorMsgTemplate, quorumConfig))
 		}
 
 		if controllerListenerName != "" {
@@ -285,6 +325,22 @@ func configureBrokerZKMode(brokerID int32, kafkaCluster *v1beta1.KafkaCluster, c
 	}
 }
 
+// configureInterBrokerProtocolVersion pins the inter broker protocol to the release the Kafka version upgrade is
+// finalized at, unless it is configured explicitly. A cluster not finalized yet is pinned to the lowest release its
+// brokers run by pinInterBrokerProtocolVersion before any broker is rolled.
+func configureInterBrokerProtocolVersion(kafkaCluster *v1beta1.KafkaCluster, brokerReadOnlyConfig, config *properties.Properties, log logr.Logger) {
+	versionUpgrade := kafkaCluster.Status.VersionUpgrade
+	if versionUpgrade == nil || versionUpgrade.FinalizedRelease == "" {
+		return
+	}
+	if _, found := brokerReadOnlyConfig.Get(kafkautils.KafkaConfigInterBrokerProtocolVersion); found {
+		return
+	}
+	if err := config.Set(kafkautils.KafkaConfigInterBrokerProtocolVersion, versionUpgrade.FinalizedRelease); err != nil {
+		log.Error(err, fmt.Sprintf(kafkautils.BrokerConfigErrorMsgTemplate, kafkautils.KafkaConfigInterBrokerProtocolVersion))
+	}
+}
+
 func getEffectiveLogDirsMountPaths(mountPathsOld, mountPathsNew []string, brokerID string, kafkaCluster *v1beta1.KafkaCluster) []string {
 	mountPathsEffective := append([]string{}, mountPathsNew...)
 	if len(mountPathsOld) == 0 {
@@ -344,7 +400,12 @@ func generateSuperUsers(users []string) (suStrings []string) {
 
 func (r *Reconciler) configMap(broker v1beta1.Broker, brokerConfig *v1beta1.BrokerConfig, quorumVoters []string,
 	extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses map[string]v1beta1.ListenerStatusList,
-	serverPasses map[string]string, clientPass string, superUsers []string, log logr.Logger) *corev1.ConfigMap {
+	serverPasses map[string]string, clientPass string, superUsers []string, log logr.Logger) (*corev1.ConfigMap, error) {
+	generatedConfig, err := r.generateBrokerConfig(broker, brokerConfig, quorumVoters, extListenerStatuses,
+		intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
+	if err != nil {
+		return nil, err
+	}
 	brokerConf := &corev1.ConfigMap{
 		ObjectMeta: templates.ObjectMeta(
 			fmt.Sprintf(brokerConfigTemplate+"-"+"%d", r.KafkaCluster.Name, broker.Id), //nolint:goconst
@@ -354,13 +415,16 @@ func (r *Reconciler) configMap(broker v1beta1.Broker, brokerConfig *v1beta1.Brok
 			),
 			r.KafkaCluster,
 		),
-		Data: map[string]string{kafkautils.ConfigPropertyName: r.generateBrokerConfig(broker, brokerConfig, quorumVoters, extListenerStatuses,
-			intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)},
+		Data: map[string]string{kafkautils.ConfigPropertyName: generatedConfig},
 	}
 	if brokerConfig.Log4jConfig != "" {
 		brokerConf.Data["log4j.properties"] = brokerConfig.Log4jConfig
 	}
-	return brokerConf
+	// the storage format arguments are not part of the broker config so changing them does not roll the broker
+	if formatArgs := getStorageFormatArgs(r.KafkaCluster, broker.Id, brokerConfig); formatArgs != "" {
+		brokerConf.Data[kafkautils.StorageFormatPropertyName] = formatArgs
+	}
+	return brokerConf, nil
 }
 
 func generateAdvertisedListenerConfig(id int32, l v1beta1.ListenersConfig,
@@ -498,7 +562,7 @@ func getListenerSpecificConfig(l *v1beta1.ListenersConfig, serverPasses map[stri
 		listenerConfig = append(listenerConfig, fmt.Sprintf("%s://:%d", upperedListenerName, eListener.ContainerPort))
 		// Add external listeners SSL configuration
 		if eListener.Type == v1beta1.SecurityProtocolSSL {
-			maps.Copy(externalListenerSSLConfig, generateListenerSSLConfig(eListener.Name, eListener.SSLClientAuth, serverPasses[eListener.Name]))
+			maps.Copy(externalListenerSSLConfig, generateListenerSSLConfig(&eListener.CommonListenerSpec, l.SSLSecrets, serverPasses[eListener.Name]))
 		}
 	}
 
@@ -517,15 +581,17 @@ func getListenerSpecificConfig(l *v1beta1.ListenersConfig, serverPasses map[stri
 
 		// Add internal listeners SSL configuration
 		if iListener.Type == v1beta1.SecurityProtocolSSL {
-			maps.Copy(internalListenerSSLConfig, generateListenerSSLConfig(iListener.Name, iListener.SSLClientAuth, serverPasses[iListener.Name]))
+			maps.Copy(internalListenerSSLConfig, generateListenerSSLConfig(&iListener.CommonListenerSpec, l.SSLSecrets, serverPasses[iListener.Name]))
 		}
 	}
 
 	return interBrokerListenerName, securityProtocolMapConfig, listenerConfig, internalListenerSSLConfig, externalListenerSSLConfig
 }
 
-func generateListenerSSLConfig(name string, sslClientAuth v1beta1.SSLClientAuthentication, password string) map[string]string {
+func generateListenerSSLConfig(listener *v1beta1.CommonListenerSpec, sslSecrets *v1beta1.SSLSecrets, password string) map[string]string {
 	var listenerSSLConfig map[string]string
+	name := listener.Name
+	sslClientAuth := listener.SSLClientAuth
 	namedKeystorePath := fmt.Sprintf(listenerServerKeyStorePathTemplate, serverKeystorePath, name)
 	keyStoreType := "JKS"
 	keyStoreLoc := namedKeystorePath + "/" + v1alpha1.TLSJKSKeyStore
@@ -548,9 +614,32 @@ func generateListenerSSLConfig(name string, sslClientAuth v1beta1.SSLClientAuthe
 		listenerSSLConfig[fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLClientAuth)] = string(sslClientAuth)
 	}
 
+	maps.Copy(listenerSSLConfig, generateTLSConfig(fmt.Sprintf("%s.%s.", kafkautils.KafkaConfigListenerName, name),
+		listener.GetTLSProtocols(sslSecrets), listener.GetCipherSuites(sslSecrets)))
+
 	return listenerSSLConfig
 }
 
+// generateTLSConfig returns the properties restricting the TLS protocol versions and cipher suites, prefixed with the given prefix
+func generateTLSConfig(prefix string, protocols []v1beta1.TLSProtocol, cipherSuites []v1beta1.CipherSuite) map[string]string {
+	tlsConfig := make(map[string]string)
+	if len(protocols) > 0 {
+		enabledProtocols := make([]string, 0, len(protocols))
+		for _, protocol := range protocols {
+			enabledProtocols = append(enabledProtocols, string(protocol))
+		}
+		tlsConfig[prefix+kafkautils.KafkaConfigSSLEnabledProtocols] = strings.Join(enabledProtocols, ",")
+	}
+	if len(cipherSuites) > 0 {
+		enabledCipherSuites := make([]string, 0, len(cipherSuites))
+		for _, cipherSuite := range cipherSuites {
+			enabledCipherSuites = append(enabledCipherSuites, string(cipherSuite))
+		}
+		tlsConfig[prefix+kafkautils.KafkaConfigSSLCipherSuites] = strings.Join(enabledCipherSuites, ",")
+	}
+	return tlsConfig
+}
+
 // mergeSuperUsersPropertyValue merges the target and source super.users property value, and returns it as string.
 // It returns empty string when there were no updates or any of the super.users property value was empty.
 func mergeSuperUsersPropertyValue(source *properties.Properties, target *properties.Properties) string {
@@ -591,12 +680,15 @@ func mergeSuperUsersPropertyValue(source *properties.Properties, target *propert
 
 func (r Reconciler) generateBrokerConfig(broker v1beta1.Broker, brokerConfig *v1beta1.BrokerConfig, quorumVoters []string,
 	extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses map[string]v1beta1.ListenerStatusList,
-	serverPasses map[string]string, clientPass string, superUsers []string, log logr.Logger) string {
+	serverPasses map[string]string, clientPass string, superUsers []string, log logr.Logger) (string, error) {
 	finalBrokerConfig := getBrokerReadOnlyConfig(broker, r.KafkaCluster, log)
 
 	// Get operator generated configuration
-	opGenConf := r.getConfigProperties(brokerConfig, broker, quorumVoters, extListenerStatuses, intListenerStatuses,
+	opGenConf, err := r.getConfigProperties(brokerConfig, broker, quorumVoters, extListenerStatuses, intListenerStatuses,
 		controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
+	if err != nil {
+		return "", err
+	}
 
 	// Merge operator generated configuration to the final one
 	if opGenConf != nil {
@@ -616,7 +708,7 @@ func (r Reconciler) generateBrokerConfig(broker v1beta1.Broker, brokerConfig *v1
 
 	finalBrokerConfig.Sort()
 
-	return finalBrokerConfig.String()
+	return finalBrokerConfig.String(), nil
 }
 
 // TODO move this into api in the future (adamantal)
@@ -238,6 +238,9 @@ func TestGenerateBrokerConfig(t *testing.T) { //nolint funlen
 		advertisedListenerAddress string
 		listenerType              string
 		sslClientAuth             v1beta1.SSLClientAuthentication
+		tlsProtocols              []v1beta1.TLSProtocol
+		cipherSuites              []v1beta1.CipherSuite
+		principalMappingRules     []string
 		expectedConfig            string
 		perBrokerStorageConfig    []v1beta1.StorageConfig
 	}{
@@ -702,6 +705,70 @@ listener.security.protocol.map=INTERNAL:SSL
 listeners=INTERNAL://:9092
 metric.reporters=com.linkedin.kafka.cruisecontrol.metricsreporter.CruiseControlMetricsReporter
 super.users=User:CN=kafka-headless.kafka.svc.cluster.local
+zookeeper.connect=example.zk:2181/`,
+		},
+		{
+			testName:                  "configWithSSL_custom_tls_settings",
+			readOnlyConfig:            ``,
+			zkAddresses:               []string{"example.zk:2181"},
+			zkPath:                    ``,
+			kubernetesClusterDomain:   ``,
+			clusterWideConfig:         ``,
+			perBrokerConfig:           ``,
+			perBrokerReadOnlyConfig:   ``,
+			advertisedListenerAddress: `kafka-0.kafka.svc.cluster.local:9092`,
+			listenerType:              "ssl",
+			sslClientAuth:             "required",
+			tlsProtocols:              []v1beta1.TLSProtocol{v1beta1.TLSProtocolV12, v1beta1.TLSProtocolV13},
+			cipherSuites:              []v1beta1.CipherSuite{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", "TLS_AES_256_GCM_SHA384"},
+			expectedConfig: `advertised.listeners=INTERNAL://kafka-0.kafka.svc.cluster.local:9092
+broker.id=0
+cruise.control.metrics.reporter.bootstrap.servers=kafka-all-broker.kafka.svc.cluster.local:9092
+cruise.control.metrics.reporter.kubernetes.mode=true
+cruise.control.metrics.reporter.security.protocol=SSL
+cruise.control.metrics.reporter.ssl.cipher.suites=TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,TLS_AES_256_GCM_SHA384
+cruise.control.metrics.reporter.ssl.enabled.protocols=TLSv1.2,TLSv1.3
+cruise.control.metrics.reporter.ssl.keystore.location=/var/run/secrets/java.io/keystores/client/keystore.jks
+cruise.control.metrics.reporter.ssl.keystore.password=keystore_clientpassword123
+cruise.control.metrics.reporter.ssl.truststore.location=/var/run/secrets/java.io/keystores/client/truststore.jks
+cruise.control.metrics.reporter.ssl.truststore.password=keystore_clientpassword123
+inter.broker.listener.name=INTERNAL
+listener.name.internal.ssl.cipher.suites=TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,TLS_AES_256_GCM_SHA384
+listener.name.internal.ssl.client.auth=required
+listener.name.internal.ssl.enabled.protocols=TLSv1.2,TLSv1.3
+listener.name.internal.ssl.keystore.location=/var/run/secrets/java.io/keystores/server/internal/keystore.jks
+listener.name.internal.ssl.keystore.password=keystore_serverpassword123
+listener.name.internal.ssl.keystore.type=JKS
+listener.name.internal.ssl.truststore.location=/var/run/secrets/java.io/keystores/server/internal/truststore.jks
+listener.name.internal.ssl.truststore.password=keystore_serverpassword123
+listener.name.internal.ssl.truststore.type=JKS
+listener.security.protocol.map=INTERNAL:SSL
+listeners=INTERNAL://:9092
+metric.reporters=com.linkedin.kafka.cruisecontrol.metricsreporter.CruiseControlMetricsReporter
+super.users=User:CN=kafka-headless.kafka.svc.cluster.local
+zookeeper.connect=example.zk:2181/`,
+		},
+		{
+			testName:                  "configWithSasl_principal_mapping_rules",
+			readOnlyConfig:            ``,
+			zkAddresses:               []string{"example.zk:2181"},
+			zkPath:                    ``,
+			kubernetesClusterDomain:   ``,
+			clusterWideConfig:         ``,
+			perBrokerConfig:           ``,
+			perBrokerReadOnlyConfig:   ``,
+			advertisedListenerAddress: `kafka-0.kafka.svc.cluster.local:9092`,
+			listenerType:              "sasl_plaintext",
+			principalMappingRules:     []string{`RULE:^CN=([^,]*),.*$/$1/L`, "DEFAULT"},
+			expectedConfig: `advertised.listeners=INTERNAL://kafka-0.kafka.svc.cluster.local:9092
+broker.id=0
+cruise.control.metrics.reporter.bootstrap.servers=kafka-all-broker.kafka.svc.cluster.local:9092
+cruise.control.metrics.reporter.kubernetes.mode=true
+inter.broker.listener.name=INTERNAL
+listener.security.protocol.map=INTERNAL:SASL_PLAINTEXT
+listeners=INTERNAL://:9092
+metric.reporters=com.linkedin.kafka.cruisecontrol.metricsreporter.CruiseControlMetricsReporter
+ssl.principal.mapping.rules=RULE:^CN=([^,]*),.*$/$1/L,DEFAULT
 zookeeper.connect=example.zk:2181/`,
 		},
 		{
@@ -761,9 +828,12 @@ zookeeper.connect=example.zk:2181/`,
 											},
 											SSLClientAuth:                   test.sslClientAuth,
 											UsedForInnerBrokerCommunication: true,
+											TLSProtocols:                    test.tlsProtocols,
+											CipherSuites:                    test.cipherSuites,
 										},
 									},
 								},
+								PrincipalMappingRules: test.principalMappingRules,
 							},
 							ReadOnlyConfig:          test.readOnlyConfig,
 							KubernetesClusterDomain: test.kubernetesClusterDomain,
@@ -802,8 +872,11 @@ zookeeper.connect=example.zk:2181/`,
 				superUsers = []string{"CN=kafka-headless.kafka.svc.cluster.local"}
 			}
 
-			generatedConfig := r.generateBrokerConfig(r.KafkaCluster.Spec.Brokers[0], r.KafkaCluster.Spec.Brokers[0].BrokerConfig, nil, map[string]v1beta1.ListenerStatusList{},
+			generatedConfig, err := r.generateBrokerConfig(r.KafkaCluster.Spec.Brokers[0], r.KafkaCluster.Spec.Brokers[0].BrokerConfig, nil, map[string]v1beta1.ListenerStatusList{},
 				map[string]v1beta1.ListenerStatusList{}, controllerListenerStatus, serverPasses, clientPass, superUsers, logr.Discard())
+			if err != nil {
+				t.Fatalf("failed generating the broker configuration: %s", err)
+			}
 
 			generated, err := properties.NewFromString(generatedConfig)
 			if err != nil {
@@ -1333,8 +1406,9 @@ process.roles=broker
 					t.Error(err)
 				}
 
-				generatedConfig := r.generateBrokerConfig(b, b.BrokerConfig, quorumVoters, map[string]v1beta1.ListenerStatusList{},
+				generatedConfig, err := r.generateBrokerConfig(b, b.BrokerConfig, quorumVoters, map[string]v1beta1.ListenerStatusList{},
 					test.internalListenerStatuses, test.controllerListenerStatus, nil, "", nil, logr.Discard())
+				require.NoError(t, err)
 
 				require.Equal(t, test.expectedBrokerConfigs[i], generatedConfig)
 			}
@@ -1647,8 +1721,9 @@ process.roles=controller
 				if err != nil {
 					t.Error(err)
 				}
-				generatedConfig := r.generateBrokerConfig(b, b.BrokerConfig, quorumVoters, map[string]v1beta1.ListenerStatusList{},
+				generatedConfig, err := r.generateBrokerConfig(b, b.BrokerConfig, quorumVoters, map[string]v1beta1.ListenerStatusList{},
 					test.internalListenerStatuses, test.controllerListenerStatus, nil, "", nil, logr.Discard())
+				require.NoError(t, err)
 
 				require.Equal(t, test.expectedBrokerConfigs[i], generatedConfig)
 			}
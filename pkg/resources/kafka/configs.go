@@ -46,7 +46,10 @@ func (r *Reconciler) reconcilePerBrokerDynamicConfig(brokerId int32, brokerConfi
 	}
 
 	currentPerBrokerConfigState := r.KafkaCluster.Status.BrokersState[strconv.Itoa(int(brokerId))].PerBrokerConfigurationState
-	if fullPerBrokerConfig.Len() == 0 && currentPerBrokerConfigState != v1beta1.PerBrokerConfigOutOfSync {
+	// the forced log flush is reverted by altering the per-broker config without it
+	logFlushForced := r.KafkaCluster.Status.BrokersState[strconv.Itoa(int(brokerId))].LogFlushForced
+	forceLogFlush := r.KafkaCluster.GetAnnotations()[v1beta1.FlushBrokersAnnotationKey] != ""
+	if fullPerBrokerConfig.Len() == 0 && currentPerBrokerConfigState != v1beta1.PerBrokerConfigOutOfSync && !logFlushForced && !forceLogFlush {
 		return nil
 	}
 
@@ -60,6 +63,13 @@ func (r *Reconciler) reconcilePerBrokerDynamicConfig(brokerId int32, brokerConfi
 			fullPerBrokerConfig.Put(configProperty)
 		}
 	}
+	if forceLogFlush {
+		// only the messages appended from now on are flushed, log.flush.scheduler.interval.ms is not dynamic so the
+		// messages already written to idle partitions cannot be flushed by the operator
+		if err := fullPerBrokerConfig.Set(kafka.KafkaConfigLogFlushIntervalMessages, "1"); err != nil {
+			return errors.WrapIf(err, "could not force the flush of broker logs")
+		}
+	}
 
 	// query the current config
 	brokerConfigKeys := fullPerBrokerConfig.Keys()
@@ -68,7 +78,7 @@ func (r *Reconciler) reconcilePerBrokerDynamicConfig(brokerId int32, brokerConfi
 		return errors.WrapIfWithDetails(err, "could not describe broker config", v1beta1.BrokerIdLabelKey, brokerId)
 	}
 
-	if shouldUpdatePerBrokerConfig(response, fullPerBrokerConfig) {
+	if shouldUpdatePerBrokerConfig(response, fullPerBrokerConfig) || logFlushForced != forceLogFlush {
 		if currentPerBrokerConfigState == v1beta1.PerBrokerConfigInSync {
 			log.V(1).Info("setting per broker config status to out of sync")
 			statusErr := k8sutil.UpdateBrokerStatus(r.Client, []string{strconv.Itoa(int(brokerId))}, r.KafkaCluster, v1beta1.PerBrokerConfigOutOfSync, log)
@@ -108,6 +118,13 @@ func (r *Reconciler) reconcilePerBrokerDynamicConfig(brokerId int32, brokerConfi
 		if statusErr != nil {
 			return errors.WrapIfWithDetails(err, "updating status for per-broker configuration status failed", v1beta1.BrokerIdLabelKey, brokerId)
 		}
+		if logFlushForced != forceLogFlush {
+			log.Info("forced flush of broker logs changed", v1beta1.BrokerIdLabelKey, brokerId, "forced", forceLogFlush)
+			statusErr := k8sutil.UpdateBrokerStatus(r.Client, []string{strconv.Itoa(int(brokerId))}, r.KafkaCluster, k8sutil.BrokerLogFlushForced(forceLogFlush), log)
+			if statusErr != nil {
+				return errors.WrapIfWithDetails(statusErr, "updating status for forced log flush failed", v1beta1.BrokerIdLabelKey, brokerId)
+			}
+		}
 	} else if currentPerBrokerConfigState != v1beta1.PerBrokerConfigInSync {
 		log.V(1).Info("setting per broker config status to in sync")
 		statusErr := k8sutil.UpdateBrokerStatus(r.Client, []string{strconv.Itoa(int(brokerId))}, r.KafkaCluster, v1beta1.PerBrokerConfigInSync, log)
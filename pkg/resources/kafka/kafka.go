@@ -22,17 +22,20 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 	ccTypes "github.com/banzaicloud/go-cruise-control/pkg/types"
 	"github.com/go-logr/logr"
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	properties "github.com/banzaicloud/koperator/properties/pkg"
@@ -84,8 +87,9 @@ const (
 
 	metricsPortName        = "metrics"
 	clusterIDEnvVarName    = "CLUSTER_ID"
+	brokerIDEnvVarName     = "BROKER_ID"
 	extensionsVolumeName   = "extensions"
-	mountPathAnnotationKey = "mountPath"
+	mountPathAnnotationKey = banzaiv1beta1.MountPathAnnotationKey
 	configValueTrue        = "true"
 
 	// missingBrokerDownScaleRunningPriority the priority is used  for missing brokers where there is an incomplete downscale operation
@@ -105,6 +109,8 @@ type Reconciler struct {
 	resources.Reconciler
 	kafkaClientProvider        kafkaclient.Provider
 	CruiseControlScalerFactory func(ctx context.Context, kafkaCluster *banzaiv1beta1.KafkaCluster) (scale.CruiseControlScaler, error)
+	// Recorder emits the events of the broker restarts, no event is emitted when it is not set
+	Recorder events.EventRecorder
 }
 
 // New creates a new reconciler for Kafka
@@ -176,8 +182,8 @@ func getCreatedPvcForBroker(
 		}
 	}
 	if len(missing) > 0 {
-		return nil, errors.NewWithDetails("broker mount paths missing persistent volume claim",
-			banzaiv1beta1.BrokerIdLabelKey, brokerID, "mount paths", missing)
+		return nil, errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("persistent volume claim is not created yet"),
+			"broker mount paths missing persistent volume claim", banzaiv1beta1.BrokerIdLabelKey, brokerID, "mount paths", missing)
 	}
 	sort.Slice(foundPvcList.Items, func(i, j int) bool {
 		return foundPvcList.Items[i].Name < foundPvcList.Items[j].Name
@@ -218,6 +224,14 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		log.Error(err, "failed to update broker configuration backup")
 	}
 
+	if err := r.reconcileBrokerTemplate(log); err != nil {
+		return err
+	}
+
+	if err := r.pinInterBrokerProtocolVersion(log); err != nil {
+		return err
+	}
+
 	if r.KafkaCluster.Spec.HeadlessServiceEnabled {
 		// reconcile headless service
 		headless_obj := r.headlessService()
@@ -300,12 +314,28 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		return err
 	}
 
+	// the volumes of a replaced broker are deleted before the persistent volume claims are reconciled
+	if err := r.reconcileBrokerReplacement(ctx, log); err != nil {
+		return err
+	}
+
+	// the volume of an offline log directory is deleted before the persistent volume claims are reconciled
+	if err := r.reconcileOfflineLogDirs(ctx, log); err != nil {
+		return err
+	}
+
+	storageMigrations, err := r.reconcileStorageMigrations(ctx, log)
+	if err != nil {
+		return err
+	}
+
 	brokersVolumes := make(map[string][]*corev1.PersistentVolumeClaim, len(r.KafkaCluster.Spec.Brokers))
 	for _, broker := range r.KafkaCluster.Spec.Brokers {
 		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
 		if err != nil {
 			return errors.WrapIf(err, "failed to reconcile resource")
 		}
+		brokerConfig = storageMigrations.brokerConfig(broker.Id, brokerConfig)
 
 		var brokerVolumes []*corev1.PersistentVolumeClaim
 		for index, storage := range brokerConfig.StorageConfigs {
@@ -341,6 +371,10 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		return errors.WrapIf(err, "failed to list broker pods that belong to Kafka cluster")
 	}
 
+	if err := r.reconcileForeignDataDirectoryCondition(brokerPods.Items, log); err != nil {
+		return err
+	}
+
 	runningBrokers := make(map[string]struct{})
 	for _, b := range brokerPods.Items {
 		brokerID := b.GetLabels()[banzaiv1beta1.BrokerIdLabelKey]
@@ -366,6 +400,10 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		log.Error(err, "could not find controller broker")
 	}
 
+	if err := r.startMigration(log); err != nil {
+		return err
+	}
+
 	var quorumVoters []string
 	if r.KafkaCluster.Spec.KRaftMode {
 		// all broker nodes under the same Kafka cluster must use the same cluster UUID
@@ -378,6 +416,15 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 				}
 			}
 
+			// the restored log dirs belong to the cluster the backup was taken of
+			if r.KafkaCluster.Status.ClusterID == "" && r.KafkaCluster.Spec.RestoreFromBackup != "" {
+				clusterID, err := r.restoreClusterID(ctx)
+				if err != nil {
+					return err
+				}
+				r.KafkaCluster.Status.ClusterID = clusterID
+			}
+
 			if r.KafkaCluster.Status.ClusterID == "" {
 				r.KafkaCluster.Status.ClusterID = generateRandomClusterID()
 			}
@@ -394,7 +441,14 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 			}
 		}
 
-		quorumVoters, err = generateQuorumVoters(r.KafkaCluster, controllerIntListenerStatuses)
+		if r.KafkaCluster.Spec.KRaftDynamicQuorum {
+			if err := r.reconcileKRaftQuorumBootstrapControllers(log); err != nil {
+				return err
+			}
+			quorumVoters, err = generateQuorumBootstrapServers(r.KafkaCluster, controllerIntListenerStatuses)
+		} else {
+			quorumVoters, err = generateQuorumVoters(r.KafkaCluster, controllerIntListenerStatuses)
+		}
 		if err != nil {
 			return errors.WrapIfWithDetails(err,
 				"failed to generate quorum voters configuration",
@@ -411,27 +465,47 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		controllerID = -1
 	}
 
+	rolledBackBrokers, err := r.reconcileConfigurationRollouts(brokerPods.Items, log)
+	if err != nil {
+		return err
+	}
+
+	if err := r.reconcileRestartRequest(brokerPods.Items, log); err != nil {
+		return err
+	}
+
 	reorderedBrokers := reorderBrokers(runningBrokers, boundPersistentVolumeClaims, r.KafkaCluster.Spec.Brokers, r.KafkaCluster.Status.BrokersState, controllerID, log)
 
 	allBrokerDynamicConfigSucceeded := true
 	brokerStatus := make(map[int32]*banzaiv1beta1.BrokerConfig)
 	for _, broker := range reorderedBrokers {
+		// a broker rolled back is rendered from its configuration backup until its configuration changes
+		if backupBroker, ok := rolledBackBrokers[broker.Id]; ok {
+			broker = backupBroker
+		}
 		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
 		if err != nil {
 			return errors.WrapIf(err, "failed to reconcile resource")
 		}
+		brokerConfig = storageMigrations.brokerConfig(broker.Id, brokerConfig)
 
 		var configMap *corev1.ConfigMap
 		if r.KafkaCluster.Spec.RackAwareness == nil {
-			configMap = r.configMap(broker, brokerConfig, quorumVoters, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
-			err := k8sutil.Reconcile(log, r.Client, configMap, r.KafkaCluster)
+			configMap, err = r.configMap(broker, brokerConfig, quorumVoters, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
+			if err != nil {
+				return err
+			}
+			err = k8sutil.Reconcile(log, r.Client, configMap, r.KafkaCluster)
 			if err != nil {
 				return errors.WrapIfWithDetails(err, "failed to reconcile resource", "resource", configMap.GetObjectKind().GroupVersionKind())
 			}
 		} else if brokerState, ok := r.KafkaCluster.Status.BrokersState[strconv.Itoa(int(broker.Id))]; ok {
 			if brokerState.RackAwarenessState != "" {
-				configMap = r.configMap(broker, brokerConfig, quorumVoters, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
-				err := k8sutil.Reconcile(log, r.Client, configMap, r.KafkaCluster)
+				configMap, err = r.configMap(broker, brokerConfig, quorumVoters, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
+				if err != nil {
+					return err
+				}
+				err = k8sutil.Reconcile(log, r.Client, configMap, r.KafkaCluster)
 				if err != nil {
 					return errors.WrapIfWithDetails(err, "failed to reconcile resource", "resource", configMap.GetObjectKind().GroupVersionKind())
 				}
@@ -481,10 +555,20 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		return err
 	}
 
+	if r.KafkaCluster.Spec.KRaftMode {
+		if err := r.reconcileKRaftQuorum(controllerIntListenerStatuses, log); err != nil {
+			return err
+		}
+	}
+
 	if err := r.updateStatusWithDockerImageAndVersion(brokerStatus, log); err != nil {
 		return err
 	}
 
+	if err := r.reconcileVersionUpgrade(log); err != nil {
+		return err
+	}
+
 	// in case HeadlessServiceEnabled is changed, delete the service that was created by the previous
 	// reconcile flow. The services must be deleted at the end of the reconcile flow after the new services
 	// were created and broker configurations reflecting the new services otherwise the Kafka brokers
@@ -525,6 +609,20 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		}
 	}
 
+	if err := r.reconcileMigration(log); err != nil {
+		return err
+	}
+
+	// the expansion of the volumes is followed until it finishes, Cruise Control gets the expanded capacities afterwards
+	if brokerID, mountPath, found := r.unfinishedVolumeResize(""); found {
+		return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("volume expansion in progress"),
+			"waiting for the expansion of the broker volume", banzaiv1beta1.BrokerIdLabelKey, brokerID, mountPathAnnotationKey, mountPath)
+	}
+
+	if err := r.reconcileStorageAutoscaling(ctx, log); err != nil {
+		return err
+	}
+
 	log.V(1).Info("Reconciled")
 
 	return nil
@@ -623,6 +721,13 @@ func (r *Reconciler) reconcileKafkaPodDelete(ctx context.Context, log logr.Logge
 				continue
 			}
 
+			// a controller of a dynamic quorum is removed from the voters before its pod is deleted
+			if isKRaftQuorumVoter(r.KafkaCluster, broker.Labels[banzaiv1beta1.BrokerIdLabelKey]) {
+				log.Info("controller is still a voter of the KRaft quorum, deferring its deletion",
+					banzaiv1beta1.BrokerIdLabelKey, broker.Labels[banzaiv1beta1.BrokerIdLabelKey])
+				continue
+			}
+
 			processRoles, found := broker.GetLabels()[banzaiv1beta1.ProcessRolesKey]
 			// Only applicable in KRaft: a controller-only node has no corresponding Cruise Control broker,
 			// so it has no graceful-downscale state to honor and can be deleted directly (this branch is skipped).
@@ -670,6 +775,12 @@ func (r *Reconciler) reconcileKafkaPodDelete(ctx context.Context, log logr.Logge
 			}
 			for _, volume := range broker.Spec.Volumes {
 				if strings.HasPrefix(volume.Name, kafkaDataVolumeMount) {
+					if r.KafkaCluster.Spec.PersistentVolumeClaimRetentionPolicy.RetainWhenScaled() {
+						if err := r.retainBrokerPvc(ctx, log, broker.Labels[banzaiv1beta1.BrokerIdLabelKey], volume.PersistentVolumeClaim.ClaimName); err != nil {
+							return err
+						}
+						continue
+					}
 					err = r.Delete(context.TODO(), &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
 						Name:      volume.PersistentVolumeClaim.ClaimName,
 						Namespace: r.KafkaCluster.Namespace,
@@ -834,6 +945,12 @@ func (r *Reconciler) getPasswordKeysAndSuperUsers() (clientPass string, serverPa
 	if superUser != "" {
 		superUsers = append(superUsers, superUser)
 	}
+	// the brokers map the certificate names to principals before checking them against the super users
+	for i, name := range superUsers {
+		if superUsers[i], err = kafka.MapPrincipal(r.KafkaCluster.Spec.ListenersConfig.PrincipalMappingRules, name); err != nil {
+			return "", nil, nil, errors.WrapIf(err, "could not map super user to principal")
+		}
+	}
 	return clientPass, serverPasses, superUsers, nil
 }
 
@@ -989,27 +1106,25 @@ func (r *Reconciler) updateStatusWithDockerImageAndVersion(brokers map[int32]*ba
 
 //gocyclo:ignore
 func (r *Reconciler) handleRollingUpgrade(log logr.Logger, desiredPod, currentPod *corev1.Pod, desiredType reflect.Type) error {
-	// Since toleration does not support patchStrategy:"merge,retainKeys",
-	// we need to add all toleration from the current pod if the toleration is set in the CR
-	if len(desiredPod.Spec.Tolerations) > 0 {
-		desiredPod.Spec.Tolerations = append(desiredPod.Spec.Tolerations, currentPod.Spec.Tolerations...)
-		uniqueTolerations := make([]corev1.Toleration, 0, len(desiredPod.Spec.Tolerations))
-		keys := make(map[corev1.Toleration]bool)
-		for _, t := range desiredPod.Spec.Tolerations {
-			if _, value := keys[t]; !value {
-				keys[t] = true
-				uniqueTolerations = append(uniqueTolerations, t)
-			}
-		}
-		desiredPod.Spec.Tolerations = uniqueTolerations
+	// a broker refusing to start on a foreign data directory is not recreated until the volume is fixed and its pod
+	// is deleted, the failed pod keeps the reason
+	if _, found := foreignDataDirectory(currentPod); found {
+		log.Info("broker refuses to start on a foreign data directory, leaving its pod failed", "pod", currentPod.GetName())
+		return nil
 	}
+	mergeTolerations(desiredPod, currentPod)
 	// Check if the resource actually updated or if labels match TaintedBrokersSelector
 	patchResult, err := patch.DefaultPatchMaker.Calculate(currentPod, desiredPod)
+	tainted := r.isPodTainted(log, currentPod)
 	switch {
 	case err != nil:
 		log.Error(err, "could not match objects", "kind", desiredType)
-	case r.isPodTainted(log, currentPod):
+	case tainted:
 		log.Info("pod has tainted labels, attempting to delete", "pod", currentPod)
+	case r.restartRequested(currentPod):
+		log.Info("restart of the broker is requested, attempting to delete", "pod", currentPod.GetName())
+	case r.fileSystemResizeRequiresRestart(currentPod):
+		log.Info("file system of an expanded volume can only be resized offline, attempting to delete", "pod", currentPod.GetName())
 	case patchResult.IsEmpty():
 		if !k8sutil.IsPodContainsTerminatedContainer(currentPod) &&
 			r.KafkaCluster.Status.BrokersState[currentPod.Labels[banzaiv1beta1.BrokerIdLabelKey]].ConfigurationState == banzaiv1beta1.ConfigInSync &&
@@ -1037,7 +1152,19 @@ func (r *Reconciler) handleRollingUpgrade(log logr.Logger, desiredPod, currentPo
 			}
 		}
 
-		if r.KafkaCluster.Status.State == banzaiv1beta1.KafkaClusterRollingUpgrading {
+		// A broker rolled back to its previous configuration is restarted right away, the rest of the rolling upgrade is
+		// halted until the configuration of the rolled back brokers is changed
+		rolledBack := r.rolledBackBrokers()
+		if r.KafkaCluster.Status.State == banzaiv1beta1.KafkaClusterRollingUpgrading && !slices.Contains(rolledBack, currentPod.Labels[banzaiv1beta1.BrokerIdLabelKey]) {
+			if len(rolledBack) > 0 {
+				return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("brokers are rolled back to their previous configuration"),
+					"rolling upgrade is halted, change the configuration of the rolled back brokers to continue", "rolledBackBrokerIDs", strings.Join(rolledBack, ","))
+			}
+
+			if err := r.checkMaintenanceWindow(log); err != nil {
+				return err
+			}
+
 			// Check if any kafka pod is in terminating or pending state
 			podList := &corev1.PodList{}
 			matchingLabels := client.MatchingLabels(apiutil.LabelsForKafka(r.KafkaCluster.Name))
@@ -1050,18 +1177,19 @@ func (r *Reconciler) handleRollingUpgrade(log logr.Logger, desiredPod, currentPo
 				return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("pod count differs from brokers spec"), "rolling upgrade in progress")
 			}
 
-			// In KRaft mode, do not delete a controller pod while any other controller is still catching up
-			// in the metadata quorum. A controller pod's readiness reflects quorum membership (see its
-			// readiness probe), so waiting for all other controllers to be Ready keeps the operator from
-			// restarting the next controller before the previously restarted one has rejoined the quorum -
-			// which could otherwise cost the quorum its majority. The pod being reconciled is excluded so an
-			// unhealthy controller can still be replaced. Broker-only restarts cannot affect quorum majority
+			// In KRaft mode, do not delete a controller pod while any other voter is still catching up
+			// in the metadata quorum. The lag of the voters behind the quorum leader is taken from the quorum
+			// itself, so the operator does not restart the next controller before the previously restarted one
+			// has caught up - which could otherwise cost the quorum its majority. When the quorum cannot be
+			// described the readiness of the controller pods, which reflects quorum membership (see their
+			// readiness probe), is checked instead. The pod being reconciled is excluded so an unhealthy
+			// controller can still be replaced. Broker-only restarts cannot affect quorum majority
 			// and are gated by the data-plane health check below, so they are not blocked here.
-			if notReadyControllers := controllersBlockingRollingUpgrade(r.KafkaCluster.Spec.KRaftMode, podList.Items, currentPod); len(notReadyControllers) > 0 {
+			if laggingControllers := r.kRaftQuorumBlockingRollingUpgrade(podList.Items, currentPod, log); len(laggingControllers) > 0 {
 				return errorfactory.New(errorfactory.ReconcileRollingUpgrade{},
 					errors.New("KRaft controller quorum is not stable yet"),
-					"waiting for KRaft controllers to rejoin the quorum before continuing rolling upgrade",
-					"notReadyControllerBrokerIDs", strings.Join(notReadyControllers, ","))
+					"waiting for KRaft controllers to catch up with the quorum before continuing rolling upgrade",
+					"laggingControllerBrokerIDs", strings.Join(laggingControllers, ","))
 			}
 
 			// Check if we support multiple broker restarts and restart only in same AZ, otherwise restart only 1 broker at once
@@ -1088,27 +1216,16 @@ func (r *Reconciler) handleRollingUpgrade(log logr.Logger, desiredPod, currentPo
 				return errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
 			}
 			defer close()
-			allOfflineReplicas, err := kClient.AllOfflineReplicas()
-			if err != nil {
-				return errors.WrapIf(err, "health check failed")
-			}
-			if len(allOfflineReplicas) > 0 {
-				log.V(1).Info("offline replicas", "IDs", allOfflineReplicas)
-			}
-			outOfSyncReplicas, err := kClient.OutOfSyncReplicas()
+			impactedReplicas, err := impactedBrokers(kClient, log)
 			if err != nil {
-				return errors.WrapIf(err, "health check failed")
-			}
-			if len(outOfSyncReplicas) > 0 {
-				log.V(1).Info("out-of-sync replicas", "IDs", outOfSyncReplicas)
-			}
-			impactedReplicas := make(map[int32]struct{})
-			for _, brokerID := range allOfflineReplicas {
-				impactedReplicas[brokerID] = struct{}{}
+				return err
 			}
-			for _, brokerID := range outOfSyncReplicas {
-				impactedReplicas[brokerID] = struct{}{}
+
+			// Watch the canary brokers before the health gates, so the canary fails when they break its thresholds
+			if err := r.evaluateCanary(podList.Items, impactedReplicas, log); err != nil {
+				return err
 			}
+
 			errorCount += len(impactedReplicas)
 			if errorCount >= r.KafkaCluster.Spec.RollingUpgradeConfig.FailureThreshold {
 				return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("cluster is not healthy"), "rolling upgrade in progress")
@@ -1120,13 +1237,25 @@ func (r *Reconciler) handleRollingUpgrade(log logr.Logger, desiredPod, currentPo
 					return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("broker is not healthy from another AZ"), "rolling upgrade in progress")
 				}
 			}
+
+			if err := r.admitCanaryBroker(podList.Items, currentPod, log); err != nil {
+				return err
+			}
 		}
 	}
 
+	if err := r.startConfigurationRollout(currentPod, log); err != nil {
+		return err
+	}
+
+	restart := r.brokerRestart(currentPod, patchResult, tainted)
 	err = r.Delete(context.TODO(), currentPod)
 	if err != nil {
 		return errorfactory.New(errorfactory.APIFailure{}, err, "deleting resource failed", "kind", desiredType)
 	}
+	if err := r.recordBrokerRestart(currentPod, restart, log); err != nil {
+		return err
+	}
 
 	// Print terminated container's statuses
 	if k8sutil.IsPodContainsTerminatedContainer(currentPod) {
@@ -1137,10 +1266,55 @@ func (r *Reconciler) handleRollingUpgrade(log logr.Logger, desiredPod, currentPo
 			}
 		}
 	}
-	log.Info("broker pod deleted", "pod", currentPod.GetName(), banzaiv1beta1.BrokerIdLabelKey, currentPod.Labels[banzaiv1beta1.BrokerIdLabelKey])
+	log.Info("broker pod deleted", "pod", currentPod.GetName(), banzaiv1beta1.BrokerIdLabelKey, currentPod.Labels[banzaiv1beta1.BrokerIdLabelKey],
+		"trigger", restart.Trigger, "diff", restart.Diff)
 	return nil
 }
 
+// impactedBrokers returns the ids of the brokers hosting offline or out-of-sync replicas
+func impactedBrokers(kClient kafkaclient.KafkaClient, log logr.Logger) (map[int32]struct{}, error) {
+	allOfflineReplicas, err := kClient.AllOfflineReplicas()
+	if err != nil {
+		return nil, errors.WrapIf(err, "health check failed")
+	}
+	if len(allOfflineReplicas) > 0 {
+		log.V(1).Info("offline replicas", "IDs", allOfflineReplicas)
+	}
+	outOfSyncReplicas, err := kClient.OutOfSyncReplicas()
+	if err != nil {
+		return nil, errors.WrapIf(err, "health check failed")
+	}
+	if len(outOfSyncReplicas) > 0 {
+		log.V(1).Info("out-of-sync replicas", "IDs", outOfSyncReplicas)
+	}
+	impactedReplicas := make(map[int32]struct{})
+	for _, brokerID := range allOfflineReplicas {
+		impactedReplicas[brokerID] = struct{}{}
+	}
+	for _, brokerID := range outOfSyncReplicas {
+		impactedReplicas[brokerID] = struct{}{}
+	}
+	return impactedReplicas, nil
+}
+
+// mergeTolerations adds the tolerations of the current pod to the desired one. Since toleration does not support
+// patchStrategy:"merge,retainKeys", we need to add all toleration from the current pod if the toleration is set in the CR
+func mergeTolerations(desiredPod, currentPod *corev1.Pod) {
+	if len(desiredPod.Spec.Tolerations) == 0 {
+		return
+	}
+	desiredPod.Spec.Tolerations = append(desiredPod.Spec.Tolerations, currentPod.Spec.Tolerations...)
+	uniqueTolerations := make([]corev1.Toleration, 0, len(desiredPod.Spec.Tolerations))
+	keys := make(map[corev1.Toleration]bool)
+	for _, t := range desiredPod.Spec.Tolerations {
+		if _, value := keys[t]; !value {
+			keys[t] = true
+			uniqueTolerations = append(uniqueTolerations, t)
+		}
+	}
+	desiredPod.Spec.Tolerations = uniqueTolerations
+}
+
 func (r *Reconciler) checkCCRackAwareDistributionGoal() error {
 	cruiseControlURL := scale.CruiseControlURLFromKafkaCluster(r.KafkaCluster)
 	cc, err := r.CruiseControlScalerFactory(context.TODO(), r.KafkaCluster)
@@ -1211,6 +1385,7 @@ func (r *Reconciler) reconcileKafkaPvc(ctx context.Context, log logr.Logger, bro
 	for brokerId, desiredPvcs := range brokersDesiredPvcs {
 		desiredType := reflect.TypeOf(&corev1.PersistentVolumeClaim{})
 		brokerVolumesState := make(map[string]banzaiv1beta1.VolumeState)
+		brokerVolumeResizes := make(map[string]banzaiv1beta1.VolumeResizeStatus)
 
 		pvcList := &corev1.PersistentVolumeClaimList{}
 
@@ -1266,12 +1441,18 @@ func (r *Reconciler) reconcileKafkaPvc(ctx context.Context, log logr.Logger, bro
 			mountPath := currentPvc.Annotations[mountPathAnnotationKey]
 			// Creating the first PersistentVolume For Pod
 			if len(pvcList.Items) == 0 {
+				if desiredPvc.Spec.DataSource, err = r.restoreDataSource(ctx, log, brokerId, mountPath); err != nil {
+					return err
+				}
 				if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(desiredPvc); err != nil {
 					return errors.WrapIf(err, "could not apply last state to annotation")
 				}
 				if err := r.Create(ctx, desiredPvc); err != nil {
 					return errorfactory.New(errorfactory.APIFailure{}, err, "creating resource failed", "kind", desiredType)
 				}
+				if err := r.clearRecreatedVolumeState(brokerId, mountPath, log); err != nil {
+					return err
+				}
 				log.Info("resource created")
 				continue
 			}
@@ -1281,6 +1462,9 @@ func (r *Reconciler) reconcileKafkaPvc(ctx context.Context, log logr.Logger, bro
 				if mountPath == pvc.Annotations[mountPathAnnotationKey] {
 					currentPvc = pvc.DeepCopy()
 					alreadyCreated = true
+					if err := r.adoptRetainedPvc(ctx, log, currentPvc, desiredPvc); err != nil {
+						return err
+					}
 					// Checking pvc state, if bounded, so the broker has already restarted and the CC GracefulDiskRebalance has not happened yet,
 					// then we make it happening with status update.
 					// If disk removal was set, and the disk was added back, we also need to mark the volume for rebalance
@@ -1289,20 +1473,36 @@ func (r *Reconciler) reconcileKafkaPvc(ctx context.Context, log logr.Logger, bro
 						(!found || volumeState.CruiseControlVolumeState.IsDiskRemoval()) {
 						brokerVolumesState[mountPath] = banzaiv1beta1.VolumeState{CruiseControlVolumeState: banzaiv1beta1.GracefulDiskRebalanceRequired}
 					}
+					if resize, ok := r.KafkaCluster.Status.BrokersState[brokerId].VolumeResizes[mountPath]; ok && resize.State != banzaiv1beta1.VolumeResizeSucceeded {
+						if tracked := trackVolumeResize(currentPvc, resize, time.Now()); !equality.Semantic.DeepEqual(tracked, resize) {
+							if tracked.State != resize.State {
+								log.Info("volume expansion state changed", banzaiv1beta1.BrokerIdLabelKey, brokerId, mountPathAnnotationKey, mountPath, "state", tracked.State)
+							}
+							brokerVolumeResizes[mountPath] = tracked
+						}
+					}
 					break
 				}
 			}
 
 			if !alreadyCreated {
 				// Creating the 2+ PersistentVolumes for Pod
+				if desiredPvc.Spec.DataSource, err = r.restoreDataSource(ctx, log, brokerId, mountPath); err != nil {
+					return err
+				}
 				if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(desiredPvc); err != nil {
 					return errors.WrapIf(err, "could not apply last state to annotation")
 				}
 				if err := r.Create(ctx, desiredPvc); err != nil {
 					return errorfactory.New(errorfactory.APIFailure{}, err, "creating resource failed", "kind", desiredType)
 				}
+				if err := r.clearRecreatedVolumeState(brokerId, mountPath, log); err != nil {
+					return err
+				}
 				continue
 			}
+			// the data source a volume was restored from is immutable
+			desiredPvc.Spec.DataSource = currentPvc.Spec.DataSource
 			if err == nil {
 				if k8sutil.CheckIfObjectUpdated(log, desiredType, currentPvc, desiredPvc) {
 					if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(desiredPvc); err != nil {
@@ -1314,6 +1514,17 @@ func (r *Reconciler) reconcileKafkaPvc(ctx context.Context, log logr.Logger, bro
 							"one can not reduce the size of a PVC", "kind", desiredType)
 					}
 
+					expanded := isDesiredStorageValueExpanded(desiredPvc, currentPvc)
+					if expanded {
+						allowed, err := r.volumeExpansionAllowed(ctx, log, brokerId, currentPvc)
+						if err != nil {
+							return err
+						}
+						if !allowed {
+							continue
+						}
+					}
+
 					resReq := desiredPvc.Spec.Resources.Requests
 					labels := desiredPvc.Labels
 					desiredPvc = currentPvc.DeepCopy()
@@ -1324,10 +1535,23 @@ func (r *Reconciler) reconcileKafkaPvc(ctx context.Context, log logr.Logger, bro
 						return errorfactory.New(errorfactory.APIFailure{}, err, "updating resource failed", "kind", desiredType)
 					}
 					log.Info("resource updated")
+
+					if expanded {
+						log.Info("volume expansion started", banzaiv1beta1.BrokerIdLabelKey, brokerId, mountPathAnnotationKey, mountPath,
+							"requestedSize", desiredPvc.Spec.Resources.Requests.Storage().String())
+						brokerVolumeResizes[mountPath] = newVolumeResize(desiredPvc, currentPvc, time.Now())
+					}
 				}
 			}
 		}
 
+		// the expansions are recorded broker by broker, so the volumes of the next broker wait for them
+		if len(brokerVolumeResizes) > 0 {
+			if err := k8sutil.UpdateBrokerStatus(r.Client, []string{brokerId}, r.KafkaCluster, brokerVolumeResizes, log); err != nil {
+				return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update volume expansion status", banzaiv1beta1.BrokerIdLabelKey, brokerId)
+			}
+		}
+
 		if len(brokerVolumesState) > 0 {
 			brokerIds = append(brokerIds, brokerId)
 			brokersVolumesState[brokerId] = brokerVolumesState
@@ -1395,6 +1619,9 @@ func handleDiskRemoval(ctx context.Context, pvcList *corev1.PersistentVolumeClai
 			case ccVolumeState.IsDiskRebalance():
 				log.Info("Graceful disk rebalance is in progress, waiting for it to finish before marking disk for removal", "brokerId", brokerId, mountPathAnnotationKey, mountPathToRemove)
 				waitForDiskRemovalToFinish = true
+			case ccVolumeState.IsOfflineReplicasFix():
+				log.Info("Offline replicas are being moved, waiting for it to finish before marking disk for removal", "brokerId", brokerId, mountPathAnnotationKey, mountPathToRemove)
+				waitForDiskRemovalToFinish = true
 			default:
 				brokerVolumesState[mountPathToRemove] = banzaiv1beta1.VolumeState{CruiseControlVolumeState: banzaiv1beta1.GracefulDiskRemovalRequired}
 				log.Info("Marked the volume for removal", "brokerId", brokerId, mountPathAnnotationKey, mountPathToRemove)
@@ -1418,7 +1645,7 @@ func GetBrokersWithPendingOrRunningCCTask(kafkaCluster *banzaiv1beta1.KafkaClust
 				// Check if the volumes are rebalancing or removing
 				for _, volumeState := range state.GracefulActionState.VolumeStates {
 					ccVolumeState := volumeState.CruiseControlVolumeState
-					if ccVolumeState.IsDiskRemoval() || ccVolumeState.IsDiskRebalance() {
+					if ccVolumeState.IsDiskRemoval() || ccVolumeState.IsDiskRebalance() || ccVolumeState.IsOfflineReplicasFix() {
 						brokerIDs = append(brokerIDs, kafkaCluster.Spec.Brokers[i].Id)
 					}
 				}
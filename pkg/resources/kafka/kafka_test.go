@@ -37,6 +37,7 @@ import (
 	"github.com/banzaicloud/koperator/api/v1alpha1"
 	"github.com/banzaicloud/koperator/api/v1beta1"
 	controllerMocks "github.com/banzaicloud/koperator/controllers/tests/mocks"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
 	"github.com/banzaicloud/koperator/pkg/kafkaclient"
 	"github.com/banzaicloud/koperator/pkg/resources"
 	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
@@ -637,6 +638,63 @@ func TestFirstRunningBrokerOutsideSpec(t *testing.T) {
 	}
 }
 
+func TestGetCreatedPvcForBroker(t *testing.T) {
+	storageConfigs := []v1beta1.StorageConfig{
+		{MountPath: "/kafka-logs", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+		{MountPath: "/kafka-logs2", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+	}
+	pvc := func(name, mountPath string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{mountPathAnnotationKey: mountPath},
+		}}
+	}
+	testCases := []struct {
+		testName     string
+		pvcs         []corev1.PersistentVolumeClaim
+		listErr      error
+		wantPvcs     []string
+		wantNotReady bool
+		wantErr      bool
+	}{
+		{
+			testName: "every mount path has a persistent volume claim",
+			pvcs:     []corev1.PersistentVolumeClaim{pvc("kafka-0-storage-1", "/kafka-logs2"), pvc("kafka-0-storage-0", "/kafka-logs")},
+			wantPvcs: []string{"kafka-0-storage-0", "kafka-0-storage-1"},
+		},
+		{
+			testName:     "the persistent volume claim of a mount path is not created yet",
+			pvcs:         []corev1.PersistentVolumeClaim{pvc("kafka-0-storage-0", "/kafka-logs")},
+			wantNotReady: true,
+			wantErr:      true,
+		},
+		{
+			testName: "the persistent volume claims cannot be listed",
+			listErr:  errors.New("connection refused"),
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			mockClient := mocks.NewMockClient(gomock.NewController(t))
+			mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaimList{}), gomock.Any(), gomock.Any()).Do(
+				func(ctx context.Context, list *corev1.PersistentVolumeClaimList, opts ...client.ListOption) {
+					list.Items = test.pvcs
+				}).Return(test.listErr)
+
+			pvcs, err := getCreatedPvcForBroker(context.Background(), mockClient, 0, storageConfigs, "kafka", "kafka")
+			assert.Equal(t, test.wantErr, err != nil)
+			assert.Equal(t, test.wantNotReady, errors.As(err, &errorfactory.ResourceNotReady{}))
+			var names []string
+			for _, pvc := range pvcs {
+				names = append(names, pvc.Name)
+			}
+			assert.Equal(t, test.wantPvcs, names)
+		})
+	}
+}
+
 func TestGetServerPasswordKeysAndUsers(t *testing.T) { //nolint funlen
 	t.Parallel()
 	testCases := []struct {
@@ -1919,6 +1977,10 @@ func TestReconcileConcurrentBrokerRestartsAllowed(t *testing.T) {
 			}).Return(nil)
 			if !test.errorExpected {
 				mockClient.EXPECT().Delete(context.TODO(), test.currentPod).Return(nil)
+				// the restart is recorded in the broker status
+				mockSubResourceClient := mocks.NewMockSubResourceClient(mockCtrl)
+				mockClient.EXPECT().Status().Return(mockSubResourceClient)
+				mockSubResourceClient.EXPECT().Update(context.Background(), gomock.AssignableToTypeOf(&v1beta1.KafkaCluster{})).Return(nil)
 			}
 
 			// Mock kafka client
@@ -0,0 +1,56 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/util/maintenance"
+)
+
+// checkMaintenanceWindow halts the rolling upgrade until a maintenance window opens
+func (r *Reconciler) checkMaintenanceWindow(log logr.Logger) error {
+	open, next, err := maintenance.Open(r.KafkaCluster.Spec.MaintenanceWindows, time.Now())
+	if err != nil {
+		return errors.WrapIf(err, "could not check the maintenance windows")
+	}
+
+	status := r.KafkaCluster.Status.Maintenance
+	waiting := status != nil && status.RollingUpgradeWaiting
+	if open {
+		if waiting {
+			log.Info("maintenance window is open, continuing rolling upgrade")
+			if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, k8sutil.RollingUpgradeMaintenance{}, log); err != nil {
+				return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update maintenance status")
+			}
+		}
+		return nil
+	}
+
+	nextWindow := metav1.NewTime(next)
+	if !waiting || status.NextWindow == nil || !status.NextWindow.Equal(&nextWindow) {
+		if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, k8sutil.RollingUpgradeMaintenance{Waiting: true, NextWindow: &nextWindow}, log); err != nil {
+			return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update maintenance status")
+		}
+	}
+	return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("no maintenance window is open"),
+		"rolling upgrade is waiting for the next maintenance window", "nextWindow", next)
+}
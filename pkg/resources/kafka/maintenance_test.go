@@ -0,0 +1,109 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+func TestCheckMaintenanceWindow(t *testing.T) {
+	now := time.Now().UTC()
+	// the window opened an hour ago on every day
+	openWindow := v1beta1.MaintenanceWindow{
+		Schedule: fmt.Sprintf("%d %d * * *", now.Add(-time.Hour).Minute(), now.Add(-time.Hour).Hour()),
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+	}
+	// the window opens in an hour on every day
+	closedWindow := v1beta1.MaintenanceWindow{
+		Schedule: fmt.Sprintf("%d %d * * *", now.Add(time.Hour).Minute(), now.Add(time.Hour).Hour()),
+		Duration: metav1.Duration{Duration: 30 * time.Minute},
+	}
+
+	tests := []struct {
+		testName             string
+		windows              []v1beta1.MaintenanceWindow
+		maintenance          *v1beta1.MaintenanceStatus
+		expectedErr          bool
+		expectedWaiting      bool
+		expectedCCOperations []string
+	}{
+		{
+			testName: "no maintenance window",
+		},
+		{
+			testName:    "maintenance window is open",
+			windows:     []v1beta1.MaintenanceWindow{openWindow},
+			maintenance: &v1beta1.MaintenanceStatus{RollingUpgradeWaiting: true},
+		},
+		{
+			testName:        "maintenance window is closed",
+			windows:         []v1beta1.MaintenanceWindow{closedWindow},
+			expectedErr:     true,
+			expectedWaiting: true,
+		},
+		{
+			testName:    "maintenance window is open while rebalances wait",
+			windows:     []v1beta1.MaintenanceWindow{openWindow},
+			maintenance: &v1beta1.MaintenanceStatus{RollingUpgradeWaiting: true, WaitingCruiseControlOperations: []string{"rebalance"}},
+			// the operations are released by the CruiseControlOperation controller
+			expectedCCOperations: []string{"rebalance"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				Spec:   v1beta1.KafkaClusterSpec{MaintenanceWindows: test.windows},
+				Status: v1beta1.KafkaClusterStatus{Maintenance: test.maintenance},
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			r := newStatusUpdatingReconciler(mockCtrl, mockClient, cluster, new(kafkaclient.MockedProvider))
+
+			err := r.checkMaintenanceWindow(logf.Log)
+			if !test.expectedErr {
+				require.NoError(t, err)
+			} else {
+				require.True(t, errors.As(err, &errorfactory.ReconcileRollingUpgrade{}))
+			}
+
+			maintenance := r.KafkaCluster.Status.Maintenance
+			if !test.expectedWaiting && test.expectedCCOperations == nil {
+				require.Nil(t, maintenance)
+				return
+			}
+			require.NotNil(t, maintenance)
+			require.Equal(t, test.expectedWaiting, maintenance.RollingUpgradeWaiting)
+			require.Equal(t, test.expectedCCOperations, maintenance.WaitingCruiseControlOperations)
+			if test.expectedWaiting {
+				require.NotNil(t, maintenance.NextWindow)
+				require.True(t, maintenance.NextWindow.After(now))
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	banzaiv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+)
+
+const (
+	// foreignDataDirectoryExitCode is the exit code of the kafka container refusing to start on a log dir whose
+	// meta.properties belongs to another broker or cluster, see wait-for-envoy-sidecar.sh
+	foreignDataDirectoryExitCode = 78
+
+	metaPropertiesMismatchReason = "MetaPropertiesMismatch"
+	metaPropertiesMatchReason    = "MetaPropertiesMatch"
+)
+
+// foreignDataDirectory returns the termination message of the kafka container of the broker pod when it refused to
+// start on a foreign data directory
+func foreignDataDirectory(pod *corev1.Pod) (string, bool) {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name != "kafka" || containerStatus.State.Terminated == nil {
+			continue
+		}
+		if containerStatus.State.Terminated.ExitCode == foreignDataDirectoryExitCode {
+			return strings.TrimSpace(containerStatus.State.Terminated.Message), true
+		}
+	}
+	return "", false
+}
+
+// reconcileForeignDataDirectoryCondition reports the brokers refusing to start on foreign data directories in the
+// ForeignDataDirectory condition, the pods of these brokers are left failed until they are deleted
+func (r *Reconciler) reconcileForeignDataDirectoryCondition(pods []corev1.Pod, log logr.Logger) error {
+	var messages []string
+	for i := range pods {
+		if message, found := foreignDataDirectory(&pods[i]); found {
+			messages = append(messages, fmt.Sprintf("broker %s: %s", pods[i].Labels[banzaiv1beta1.BrokerIdLabelKey], message))
+		}
+	}
+	sort.Strings(messages)
+
+	current := meta.FindStatusCondition(r.KafkaCluster.Status.Conditions, banzaiv1beta1.ForeignDataDirectoryCondition)
+	condition := metav1.Condition{
+		Type:               banzaiv1beta1.ForeignDataDirectoryCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             metaPropertiesMismatchReason,
+		Message:            strings.Join(messages, "; "),
+		ObservedGeneration: r.KafkaCluster.Generation,
+	}
+	switch {
+	case len(messages) > 0:
+		if current != nil && current.Status == metav1.ConditionTrue && current.Message == condition.Message {
+			return nil
+		}
+		log.Info("brokers refuse to start on foreign data directories", "reason", condition.Message)
+	case current != nil && current.Status == metav1.ConditionTrue:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = metaPropertiesMatchReason
+		condition.Message = "no broker refuses to start on a foreign data directory"
+	default:
+		return nil
+	}
+	if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, condition, log); err != nil {
+		return errors.WrapIf(err, "could not update foreign data directory condition")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+func newMetaPropertiesPod(brokerID string, terminated *corev1.ContainerStateTerminated) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-" + brokerID, Labels: map[string]string{v1beta1.BrokerIdLabelKey: brokerID}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "kafka", State: corev1.ContainerState{Terminated: terminated}}},
+		},
+	}
+}
+
+func TestForeignDataDirectory(t *testing.T) {
+	pod := newMetaPropertiesPod("1", &corev1.ContainerStateTerminated{
+		ExitCode: foreignDataDirectoryExitCode,
+		Message:  "foreign data directory /kafka-logs: meta.properties has node id 2 instead of 1\n",
+	})
+	message, found := foreignDataDirectory(&pod)
+	require.True(t, found)
+	require.Equal(t, "foreign data directory /kafka-logs: meta.properties has node id 2 instead of 1", message)
+
+	pod = newMetaPropertiesPod("1", &corev1.ContainerStateTerminated{ExitCode: 1})
+	_, found = foreignDataDirectory(&pod)
+	require.False(t, found)
+
+	pod = newMetaPropertiesPod("1", nil)
+	_, found = foreignDataDirectory(&pod)
+	require.False(t, found)
+}
+
+func TestReconcileForeignDataDirectoryCondition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockClient := mocks.NewMockClient(mockCtrl)
+	r := New(mockClient, nil, &v1beta1.KafkaCluster{}, new(kafkaclient.MockedProvider))
+	expectStatusUpdates(mockCtrl, mockClient, r).Times(2)
+
+	healthy := []corev1.Pod{newMetaPropertiesPod("0", nil)}
+	foreign := []corev1.Pod{
+		newMetaPropertiesPod("2", &corev1.ContainerStateTerminated{ExitCode: foreignDataDirectoryExitCode, Message: "cluster id mismatch"}),
+		newMetaPropertiesPod("1", &corev1.ContainerStateTerminated{ExitCode: foreignDataDirectoryExitCode, Message: "node id mismatch"}),
+	}
+
+	// no condition is reported while every broker starts on its own data directories
+	require.NoError(t, r.reconcileForeignDataDirectoryCondition(healthy, logf.Log))
+	require.Nil(t, meta.FindStatusCondition(r.KafkaCluster.Status.Conditions, v1beta1.ForeignDataDirectoryCondition))
+
+	require.NoError(t, r.reconcileForeignDataDirectoryCondition(foreign, logf.Log))
+	condition := meta.FindStatusCondition(r.KafkaCluster.Status.Conditions, v1beta1.ForeignDataDirectoryCondition)
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Equal(t, "broker 1: node id mismatch; broker 2: cluster id mismatch", condition.Message)
+
+	// an unchanged condition is not updated again
+	require.NoError(t, r.reconcileForeignDataDirectoryCondition(foreign, logf.Log))
+
+	require.NoError(t, r.reconcileForeignDataDirectoryCondition(healthy, logf.Log))
+	condition = meta.FindStatusCondition(r.KafkaCluster.Status.Conditions, v1beta1.ForeignDataDirectoryCondition)
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+}
@@ -0,0 +1,383 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/jmxextractor"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+const (
+	migrationWaitingForControllersMsg = "waiting for the KRaft controllers to become ready"
+	migrationWaitingForBrokersMsg     = "waiting for the brokers to be rolled and become ready"
+	migrationWaitingForMetadataMsg    = "waiting for the KRaft controllers to take over the metadata from ZooKeeper"
+	migrationWaitingForFinalizeMsg    = "brokers run in KRaft mode, set spec.migration.finalize to drop ZooKeeper"
+	migrationRolledBackMsg            = "brokers run in ZooKeeper mode, delete the /controller znode to let a ZooKeeper broker be elected, " +
+		"then remove the controllers, disable kRaft and remove spec.migration"
+)
+
+// migrationNodeMode tells how a node has to be configured during the ZooKeeper to KRaft migration
+type migrationNodeMode struct {
+	// kRaftMode configures the node with node.id and process.roles instead of broker.id
+	kRaftMode bool
+	// controllerQuorum configures the node to connect to the KRaft controller quorum
+	controllerQuorum bool
+	// metadataMigration configures the node with zookeeper.metadata.migration.enable and the ZooKeeper connection
+	metadataMigration bool
+}
+
+// getMigrationNodeMode returns how the node has to be configured in the current phase of the migration.
+// When the migration is not driven by spec.migration the per-broker migration read-only configs are honored.
+func getMigrationNodeMode(cluster *v1beta1.KafkaCluster, bConfig *v1beta1.BrokerConfig, brokerReadOnlyConfig *properties.Properties) migrationNodeMode {
+	status := cluster.Status.Migration
+	if status == nil || status.Phase == "" || (status.Phase == v1beta1.MigrationPhaseRolledBack && cluster.Spec.Migration == nil) {
+		return migrationNodeMode{
+			kRaftMode:        shouldUseKRaftModeForBroker(brokerReadOnlyConfig),
+			controllerQuorum: shouldConfigureControllerQuorumForBroker(brokerReadOnlyConfig),
+		}
+	}
+	return migrationNodeModeForPhase(status.Phase, bConfig.IsControllerOnlyNode())
+}
+
+func migrationNodeModeForPhase(phase v1beta1.MigrationPhase, controllerOnly bool) migrationNodeMode {
+	if controllerOnly {
+		return migrationNodeMode{
+			kRaftMode:         true,
+			controllerQuorum:  true,
+			metadataMigration: phase != v1beta1.MigrationPhaseFinalizing && phase != v1beta1.MigrationPhaseCompleted,
+		}
+	}
+
+	switch phase {
+	case v1beta1.MigrationPhaseBrokersDualWrite, v1beta1.MigrationPhaseMetadataMigrating, v1beta1.MigrationPhaseRollbackBrokersDualWrite:
+		return migrationNodeMode{controllerQuorum: true, metadataMigration: true}
+	case v1beta1.MigrationPhaseBrokersKRaft, v1beta1.MigrationPhaseFinalizing, v1beta1.MigrationPhaseCompleted:
+		return migrationNodeMode{kRaftMode: true, controllerQuorum: true}
+	default:
+		// ControllersDeploying, RollbackBrokersZooKeeper and RolledBack
+		return migrationNodeMode{}
+	}
+}
+
+// startMigration enters the first phase of the migration when it is requested by spec.migration.
+// The KRaft controllers have to join the existing cluster so its id is read from the ZooKeeper based brokers.
+func (r *Reconciler) startMigration(log logr.Logger) error {
+	spec := r.KafkaCluster.Spec.Migration
+	status := r.KafkaCluster.Status.Migration
+	if spec == nil || !spec.Enabled || spec.Rollback {
+		return nil
+	}
+	if status != nil && status.Phase != "" && status.Phase != v1beta1.MigrationPhaseRolledBack {
+		return nil
+	}
+
+	if err := validateMigrationPrerequisites(r.KafkaCluster); err != nil {
+		return errorfactory.New(errorfactory.FatalReconcileError{}, err, "cluster is not ready for the migration to KRaft")
+	}
+
+	if r.KafkaCluster.Status.ClusterID == "" {
+		kClient, close, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+		if err != nil {
+			return errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
+		}
+		defer close()
+
+		clusterID, err := kClient.ClusterID()
+		if err != nil {
+			return errorfactory.New(errorfactory.BrokersRequestError{}, err, "could not get the cluster id from the ZooKeeper based brokers")
+		}
+		// the controllers must join the cluster with its existing id, so it is persisted before they are deployed
+		r.KafkaCluster.Status.ClusterID = clusterID
+		err = r.Client.Status().Update(context.Background(), r.KafkaCluster)
+		if apierrors.IsNotFound(err) {
+			err = r.Update(context.Background(), r.KafkaCluster)
+		}
+		if err != nil {
+			return errors.WrapIf(err, "could not update ClusterID status")
+		}
+	}
+
+	log.Info("starting the migration from ZooKeeper to KRaft", "clusterId", r.KafkaCluster.Status.ClusterID)
+	return r.updateMigrationPhase(v1beta1.MigrationPhaseControllersDeploying, migrationWaitingForControllersMsg, log)
+}
+
+// validateMigrationPrerequisites checks that the cluster spec describes the topology the migration needs:
+// ZooKeeper to migrate from and dedicated KRaft controllers to migrate to
+func validateMigrationPrerequisites(cluster *v1beta1.KafkaCluster) error {
+	if !cluster.Spec.KRaftMode {
+		return errors.New("kRaft has to be enabled")
+	}
+	if len(cluster.Spec.ZKAddresses) == 0 {
+		return errors.New("zkAddresses have to be kept until the migration is finalized")
+	}
+
+	var controllers int
+	for _, broker := range cluster.Spec.Brokers {
+		bConfig, err := broker.GetBrokerConfig(cluster.Spec)
+		if err != nil {
+			return err
+		}
+		if bConfig.IsCombinedNode() {
+			return errors.NewWithDetails("combined nodes are not supported by the migration", v1beta1.BrokerIdLabelKey, broker.Id)
+		}
+		if bConfig.IsControllerOnlyNode() {
+			controllers++
+		}
+	}
+	if controllers == 0 {
+		return errors.New("at least one controller-only node is needed")
+	}
+	return nil
+}
+
+// reconcileMigration advances the migration to its next phase once the health gates of the current one pass
+func (r *Reconciler) reconcileMigration(log logr.Logger) error {
+	spec := r.KafkaCluster.Spec.Migration
+	status := r.KafkaCluster.Status.Migration
+	if spec == nil || !status.IsInProgress() {
+		return nil
+	}
+
+	phase := status.Phase
+	if spec.Rollback && phase.IsRollbackAllowed() {
+		next := v1beta1.MigrationPhaseRollbackBrokersZooKeeper
+		if phase == v1beta1.MigrationPhaseBrokersKRaft {
+			// KRaft brokers have to be rolled through dual-write so the controllers keep writing the metadata to ZooKeeper
+			next = v1beta1.MigrationPhaseRollbackBrokersDualWrite
+		}
+		log.Info("rolling back the migration from ZooKeeper to KRaft", "phase", phase)
+		if err := r.updateMigrationPhase(next, migrationWaitingForBrokersMsg, log); err != nil {
+			return err
+		}
+		return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New(migrationWaitingForBrokersMsg), "migration rollback started")
+	}
+
+	ready, err := r.migrationGatePassed(phase, log)
+	if err != nil {
+		return err
+	}
+
+	next, message := nextMigrationPhase(phase, spec, ready)
+	if next == phase {
+		if message != status.Message {
+			if err := r.updateMigrationPhase(phase, message, log); err != nil {
+				return err
+			}
+		}
+		if phase == v1beta1.MigrationPhaseBrokersKRaft && ready {
+			// nothing to wait for until finalization is requested through the spec
+			return nil
+		}
+		return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New(message), "migration in progress", "phase", phase)
+	}
+
+	log.Info("migration from ZooKeeper to KRaft advanced", "from", phase, "to", next)
+	if err := r.updateMigrationPhase(next, message, log); err != nil {
+		return err
+	}
+	if next != v1beta1.MigrationPhaseCompleted && next != v1beta1.MigrationPhaseRolledBack {
+		// the nodes are reconfigured for the new phase by the next reconcile
+		return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New(message), "migration in progress", "phase", next)
+	}
+	return nil
+}
+
+// nextMigrationPhase returns the phase the migration moves to and what it waits for from there
+func nextMigrationPhase(phase v1beta1.MigrationPhase, spec *v1beta1.MigrationConfig, gatePassed bool) (v1beta1.MigrationPhase, string) {
+	switch phase {
+	case v1beta1.MigrationPhaseControllersDeploying:
+		if gatePassed {
+			return v1beta1.MigrationPhaseBrokersDualWrite, migrationWaitingForBrokersMsg
+		}
+		return phase, migrationWaitingForControllersMsg
+	case v1beta1.MigrationPhaseBrokersDualWrite:
+		if gatePassed {
+			return v1beta1.MigrationPhaseMetadataMigrating, migrationWaitingForMetadataMsg
+		}
+		return phase, migrationWaitingForBrokersMsg
+	case v1beta1.MigrationPhaseMetadataMigrating:
+		if gatePassed {
+			return v1beta1.MigrationPhaseBrokersKRaft, migrationWaitingForBrokersMsg
+		}
+		return phase, migrationWaitingForMetadataMsg
+	case v1beta1.MigrationPhaseBrokersKRaft:
+		if !gatePassed {
+			return phase, migrationWaitingForBrokersMsg
+		}
+		if spec.Finalize {
+			return v1beta1.MigrationPhaseFinalizing, migrationWaitingForControllersMsg
+		}
+		return phase, migrationWaitingForFinalizeMsg
+	case v1beta1.MigrationPhaseFinalizing:
+		if gatePassed {
+			return v1beta1.MigrationPhaseCompleted, ""
+		}
+		return phase, migrationWaitingForControllersMsg
+	case v1beta1.MigrationPhaseRollbackBrokersDualWrite:
+		if gatePassed {
+			return v1beta1.MigrationPhaseRollbackBrokersZooKeeper, migrationWaitingForBrokersMsg
+		}
+		return phase, migrationWaitingForBrokersMsg
+	case v1beta1.MigrationPhaseRollbackBrokersZooKeeper:
+		if gatePassed {
+			return v1beta1.MigrationPhaseRolledBack, migrationRolledBackMsg
+		}
+		return phase, migrationWaitingForBrokersMsg
+	default:
+		return phase, ""
+	}
+}
+
+// migrationGatePassed checks the health gate of the phase
+func (r *Reconciler) migrationGatePassed(phase v1beta1.MigrationPhase, log logr.Logger) (bool, error) {
+	switch phase {
+	case v1beta1.MigrationPhaseControllersDeploying, v1beta1.MigrationPhaseFinalizing:
+		return r.migrationNodesReady(true)
+	case v1beta1.MigrationPhaseMetadataMigrating:
+		return r.migrationControllerTookOver(log)
+	default:
+		ready, err := r.migrationNodesReady(false)
+		if err != nil || !ready {
+			return false, err
+		}
+		return r.noOfflineReplicas()
+	}
+}
+
+// migrationNodesReady returns whether every controller-only node (or every broker) runs with the configuration
+// of the current phase and its pod is ready
+func (r *Reconciler) migrationNodesReady(controllers bool) (bool, error) {
+	var podList corev1.PodList
+	err := r.List(context.Background(), &podList,
+		client.InNamespace(r.KafkaCluster.Namespace),
+		client.MatchingLabels(apiutil.LabelsForKafka(r.KafkaCluster.Name)),
+	)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to list broker pods that belong to Kafka cluster")
+	}
+	pods := make(map[string]*corev1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pods[podList.Items[i].Labels[v1beta1.BrokerIdLabelKey]] = &podList.Items[i]
+	}
+
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		bConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
+		if err != nil {
+			return false, errors.WrapIf(err, "failed to get broker config")
+		}
+		if bConfig.IsControllerOnlyNode() != controllers {
+			continue
+		}
+
+		id := strconv.Itoa(int(broker.Id))
+		if r.KafkaCluster.Status.BrokersState[id].ConfigurationState != v1beta1.ConfigInSync {
+			return false, nil
+		}
+		pod, ok := pods[id]
+		if !ok || k8sutil.IsMarkedForDeletion(pod.ObjectMeta) || !isPodReady(pod) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *Reconciler) noOfflineReplicas() (bool, error) {
+	kClient, close, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+	if err != nil {
+		return false, errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
+	}
+	defer close()
+
+	offlineReplicas, err := kClient.AllOfflineReplicas()
+	if err != nil {
+		return false, errors.WrapIf(err, "health check failed")
+	}
+	return len(offlineReplicas) == 0, nil
+}
+
+// migrationControllerTookOver returns whether the brokers report a KRaft controller as their controller and the
+// KRaft controllers finished copying the metadata from ZooKeeper. The active KRaft controller claims the ZooKeeper
+// controller role once every broker registered in dual-write mode, the controllers report the MIGRATION
+// ZkMigrationState once the metadata is copied.
+func (r *Reconciler) migrationControllerTookOver(log logr.Logger) (bool, error) {
+	kClient, close, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+	if err != nil {
+		return false, errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
+	}
+	defer close()
+
+	_, controllerID, err := kClient.DescribeCluster()
+	if err != nil {
+		return false, errors.WrapIf(err, "could not describe the cluster")
+	}
+
+	controllers := make(map[int32]*v1beta1.BrokerConfig)
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		bConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
+		if err != nil {
+			return false, errors.WrapIf(err, "failed to get broker config")
+		}
+		if bConfig.IsControllerOnlyNode() {
+			controllers[broker.Id] = bConfig
+		}
+	}
+	if _, ok := controllers[controllerID]; !ok {
+		return false, nil
+	}
+
+	jmxExp := jmxextractor.NewJMXExtractor(r.KafkaCluster.GetNamespace(), r.KafkaCluster.Spec.GetKubernetesClusterDomain(), r.KafkaCluster.GetName(), log)
+	for id, bConfig := range controllers {
+		state, err := jmxExp.ExtractZkMigrationState(id, bConfig, r.KafkaCluster.Spec.HeadlessServiceEnabled)
+		if err != nil {
+			return false, err
+		}
+		// the controllers only see the committed ZkMigrationState, any of them reporting MIGRATION is enough
+		if state == jmxextractor.ZkMigrationStateMigration {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *Reconciler) updateMigrationPhase(phase v1beta1.MigrationPhase, message string, log logr.Logger) error {
+	status := v1beta1.MigrationStatus{
+		Phase:              phase,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	if current := r.KafkaCluster.Status.Migration; current != nil && current.Phase == phase {
+		status.LastTransitionTime = current.LastTransitionTime
+	}
+	if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, status, log); err != nil {
+		return errors.WrapIfWithDetails(err, "could not update migration status", "phase", phase)
+	}
+	return nil
+}
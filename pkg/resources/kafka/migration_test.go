@@ -0,0 +1,234 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/jmxextractor"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+func TestGetMigrationNodeMode(t *testing.T) {
+	broker := &v1beta1.BrokerConfig{Roles: []string{"broker"}}
+	controller := &v1beta1.BrokerConfig{Roles: []string{"controller"}}
+
+	zkReadOnlyConfig := properties.NewProperties()
+	zkReadOnlyConfig.Set(kafkautils.MigrationBrokerKRaftMode, false)                     //nolint:errcheck
+	zkReadOnlyConfig.Set(kafkautils.MigrationBrokerControllerQuorumConfigEnabled, false) //nolint:errcheck
+
+	tests := []struct {
+		testName       string
+		phase          v1beta1.MigrationPhase
+		bConfig        *v1beta1.BrokerConfig
+		readOnlyConfig *properties.Properties
+		expected       migrationNodeMode
+	}{
+		{
+			testName:       "no migration",
+			bConfig:        broker,
+			readOnlyConfig: properties.NewProperties(),
+			expected:       migrationNodeMode{kRaftMode: true, controllerQuorum: true},
+		},
+		{
+			testName:       "manual migration with read-only configs",
+			bConfig:        broker,
+			readOnlyConfig: zkReadOnlyConfig,
+			expected:       migrationNodeMode{},
+		},
+		{
+			testName:       "controller while controllers are deployed",
+			phase:          v1beta1.MigrationPhaseControllersDeploying,
+			bConfig:        controller,
+			readOnlyConfig: zkReadOnlyConfig,
+			expected:       migrationNodeMode{kRaftMode: true, controllerQuorum: true, metadataMigration: true},
+		},
+		{
+			testName:       "broker while controllers are deployed",
+			phase:          v1beta1.MigrationPhaseControllersDeploying,
+			bConfig:        broker,
+			readOnlyConfig: properties.NewProperties(),
+			expected:       migrationNodeMode{},
+		},
+		{
+			testName:       "broker in dual-write",
+			phase:          v1beta1.MigrationPhaseBrokersDualWrite,
+			bConfig:        broker,
+			readOnlyConfig: properties.NewProperties(),
+			expected:       migrationNodeMode{controllerQuorum: true, metadataMigration: true},
+		},
+		{
+			testName:       "broker in KRaft mode",
+			phase:          v1beta1.MigrationPhaseBrokersKRaft,
+			bConfig:        broker,
+			readOnlyConfig: zkReadOnlyConfig,
+			expected:       migrationNodeMode{kRaftMode: true, controllerQuorum: true},
+		},
+		{
+			testName:       "controller after finalization",
+			phase:          v1beta1.MigrationPhaseFinalizing,
+			bConfig:        controller,
+			readOnlyConfig: properties.NewProperties(),
+			expected:       migrationNodeMode{kRaftMode: true, controllerQuorum: true},
+		},
+		{
+			testName:       "broker rolled back to dual-write",
+			phase:          v1beta1.MigrationPhaseRollbackBrokersDualWrite,
+			bConfig:        broker,
+			readOnlyConfig: properties.NewProperties(),
+			expected:       migrationNodeMode{controllerQuorum: true, metadataMigration: true},
+		},
+		{
+			testName:       "broker rolled back to ZooKeeper",
+			phase:          v1beta1.MigrationPhaseRolledBack,
+			bConfig:        broker,
+			readOnlyConfig: properties.NewProperties(),
+			expected:       migrationNodeMode{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				Spec: v1beta1.KafkaClusterSpec{Migration: &v1beta1.MigrationConfig{Enabled: true}},
+			}
+			if test.phase != "" {
+				cluster.Status.Migration = &v1beta1.MigrationStatus{Phase: test.phase}
+			}
+			if got := getMigrationNodeMode(cluster, test.bConfig, test.readOnlyConfig); got != test.expected {
+				t.Errorf("expected node mode: %+v, got: %+v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestNextMigrationPhase(t *testing.T) {
+	tests := []struct {
+		phase      v1beta1.MigrationPhase
+		spec       v1beta1.MigrationConfig
+		gatePassed bool
+		expected   v1beta1.MigrationPhase
+	}{
+		{phase: v1beta1.MigrationPhaseControllersDeploying, expected: v1beta1.MigrationPhaseControllersDeploying},
+		{phase: v1beta1.MigrationPhaseControllersDeploying, gatePassed: true, expected: v1beta1.MigrationPhaseBrokersDualWrite},
+		{phase: v1beta1.MigrationPhaseBrokersDualWrite, gatePassed: true, expected: v1beta1.MigrationPhaseMetadataMigrating},
+		{phase: v1beta1.MigrationPhaseMetadataMigrating, expected: v1beta1.MigrationPhaseMetadataMigrating},
+		{phase: v1beta1.MigrationPhaseMetadataMigrating, gatePassed: true, expected: v1beta1.MigrationPhaseBrokersKRaft},
+		{phase: v1beta1.MigrationPhaseBrokersKRaft, gatePassed: true, expected: v1beta1.MigrationPhaseBrokersKRaft},
+		{phase: v1beta1.MigrationPhaseBrokersKRaft, spec: v1beta1.MigrationConfig{Finalize: true}, expected: v1beta1.MigrationPhaseBrokersKRaft},
+		{phase: v1beta1.MigrationPhaseBrokersKRaft, spec: v1beta1.MigrationConfig{Finalize: true}, gatePassed: true, expected: v1beta1.MigrationPhaseFinalizing},
+		{phase: v1beta1.MigrationPhaseFinalizing, gatePassed: true, expected: v1beta1.MigrationPhaseCompleted},
+		{phase: v1beta1.MigrationPhaseRollbackBrokersDualWrite, gatePassed: true, expected: v1beta1.MigrationPhaseRollbackBrokersZooKeeper},
+		{phase: v1beta1.MigrationPhaseRollbackBrokersZooKeeper, gatePassed: true, expected: v1beta1.MigrationPhaseRolledBack},
+	}
+
+	for _, test := range tests {
+		next, message := nextMigrationPhase(test.phase, &test.spec, test.gatePassed)
+		if next != test.expected {
+			t.Errorf("phase %s (gate passed: %t): expected next phase: %s, got: %s", test.phase, test.gatePassed, test.expected, next)
+		}
+		if next != v1beta1.MigrationPhaseCompleted && message == "" {
+			t.Errorf("phase %s: expected a message about what the migration waits for", next)
+		}
+	}
+}
+
+func TestValidateMigrationPrerequisites(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		Spec: v1beta1.KafkaClusterSpec{
+			KRaftMode:   true,
+			ZKAddresses: []string{"zk:2181"},
+			Brokers: []v1beta1.Broker{
+				{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"broker"}}},
+				{Id: 100, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"controller"}}},
+			},
+		},
+	}
+	if err := validateMigrationPrerequisites(cluster); err != nil {
+		t.Errorf("expected nil error, got: %v", err)
+	}
+
+	cluster.Spec.Brokers[1].BrokerConfig.Roles = []string{"broker", "controller"}
+	if err := validateMigrationPrerequisites(cluster); err == nil {
+		t.Error("expected error for combined nodes")
+	}
+}
+
+func TestMigrationControllerTookOver(t *testing.T) {
+	tests := []struct {
+		testName         string
+		controllerID     int32
+		zkMigrationState jmxextractor.ZkMigrationState
+		expected         bool
+	}{
+		{
+			testName:         "ZooKeeper broker is still the controller",
+			controllerID:     0,
+			zkMigrationState: jmxextractor.ZkMigrationStatePreMigration,
+			expected:         false,
+		},
+		{
+			testName:         "KRaft controller is still copying the metadata",
+			controllerID:     100,
+			zkMigrationState: jmxextractor.ZkMigrationStatePreMigration,
+			expected:         false,
+		},
+		{
+			testName:         "KRaft controller copied the metadata",
+			controllerID:     100,
+			zkMigrationState: jmxextractor.ZkMigrationStateMigration,
+			expected:         true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			restore := jmxextractor.NewMockJMXExtractorWithZkMigrationState(test.zkMigrationState)
+			defer restore()
+
+			cluster := &v1beta1.KafkaCluster{
+				Spec: v1beta1.KafkaClusterSpec{
+					Brokers: []v1beta1.Broker{
+						{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"broker"}}},
+						{Id: 100, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"controller"}}},
+					},
+				},
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			mockKafkaClientProvider := new(kafkaclient.MockedProvider)
+			mockedKafkaClient := mocks.NewMockKafkaClient(mockCtrl)
+			mockedKafkaClient.EXPECT().DescribeCluster().Return(nil, test.controllerID, nil)
+			mockKafkaClientProvider.On("NewFromCluster", mockClient, cluster).Return(mockedKafkaClient, func() {}, nil)
+			r := New(mockClient, nil, cluster, mockKafkaClientProvider)
+
+			tookOver, err := r.migrationControllerTookOver(logf.Log)
+			if err != nil {
+				t.Fatalf("expected nil error, got: %v", err)
+			}
+			if tookOver != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, tookOver)
+			}
+		})
+	}
+}
@@ -58,6 +58,20 @@ func (m *MockKafkaClient) EXPECT() *MockKafkaClientMockRecorder {
 	return m.recorder
 }
 
+// AddRaftVoter mocks base method.
+func (m *MockKafkaClient) AddRaftVoter(id int32, directoryID kafkaclient.Uuid, endpoints []kafkaclient.RaftVoterEndpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRaftVoter", id, directoryID, endpoints)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRaftVoter indicates an expected call of AddRaftVoter.
+func (mr *MockKafkaClientMockRecorder) AddRaftVoter(id, directoryID, endpoints any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRaftVoter", reflect.TypeOf((*MockKafkaClient)(nil).AddRaftVoter), id, directoryID, endpoints)
+}
+
 // AllOfflineReplicas mocks base method.
 func (m *MockKafkaClient) AllOfflineReplicas() ([]int32, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +143,21 @@ func (mr *MockKafkaClientMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockKafkaClient)(nil).Close))
 }
 
+// ClusterID mocks base method.
+func (m *MockKafkaClient) ClusterID() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterID")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClusterID indicates an expected call of ClusterID.
+func (mr *MockKafkaClientMockRecorder) ClusterID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterID", reflect.TypeOf((*MockKafkaClient)(nil).ClusterID))
+}
+
 // CreateTopic mocks base method.
 func (m *MockKafkaClient) CreateTopic(arg0 *kafkaclient.CreateTopicOptions) error {
 	m.ctrl.T.Helper()
@@ -216,6 +245,21 @@ func (mr *MockKafkaClientMockRecorder) DescribeClusterWideConfig() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeClusterWideConfig", reflect.TypeOf((*MockKafkaClient)(nil).DescribeClusterWideConfig))
 }
 
+// DescribeFeatures mocks base method.
+func (m *MockKafkaClient) DescribeFeatures() (*kafkaclient.FeaturesInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeFeatures")
+	ret0, _ := ret[0].(*kafkaclient.FeaturesInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeFeatures indicates an expected call of DescribeFeatures.
+func (mr *MockKafkaClientMockRecorder) DescribeFeatures() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeFeatures", reflect.TypeOf((*MockKafkaClient)(nil).DescribeFeatures))
+}
+
 // DescribePerBrokerConfig mocks base method.
 func (m *MockKafkaClient) DescribePerBrokerConfig(arg0 int32, arg1 []string) ([]*sarama.ConfigEntry, error) {
 	m.ctrl.T.Helper()
@@ -231,6 +275,21 @@ func (mr *MockKafkaClientMockRecorder) DescribePerBrokerConfig(arg0, arg1 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribePerBrokerConfig", reflect.TypeOf((*MockKafkaClient)(nil).DescribePerBrokerConfig), arg0, arg1)
 }
 
+// DescribeQuorum mocks base method.
+func (m *MockKafkaClient) DescribeQuorum() (*kafkaclient.QuorumInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeQuorum")
+	ret0, _ := ret[0].(*kafkaclient.QuorumInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeQuorum indicates an expected call of DescribeQuorum.
+func (mr *MockKafkaClientMockRecorder) DescribeQuorum() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeQuorum", reflect.TypeOf((*MockKafkaClient)(nil).DescribeQuorum))
+}
+
 // DescribeTopic mocks base method.
 func (m *MockKafkaClient) DescribeTopic(arg0 string) (*sarama.TopicMetadata, error) {
 	m.ctrl.T.Helper()
@@ -363,6 +422,20 @@ func (mr *MockKafkaClientMockRecorder) OutOfSyncReplicas() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutOfSyncReplicas", reflect.TypeOf((*MockKafkaClient)(nil).OutOfSyncReplicas))
 }
 
+// RemoveRaftVoter mocks base method.
+func (m *MockKafkaClient) RemoveRaftVoter(id int32, directoryID kafkaclient.Uuid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRaftVoter", id, directoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRaftVoter indicates an expected call of RemoveRaftVoter.
+func (mr *MockKafkaClientMockRecorder) RemoveRaftVoter(id, directoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRaftVoter", reflect.TypeOf((*MockKafkaClient)(nil).RemoveRaftVoter), id, directoryID)
+}
+
 // TopicMetaToStatus mocks base method.
 func (m *MockKafkaClient) TopicMetaToStatus(meta *sarama.TopicMetadata) *v1alpha1.KafkaTopicStatus {
 	m.ctrl.T.Helper()
@@ -376,3 +449,32 @@ func (mr *MockKafkaClientMockRecorder) TopicMetaToStatus(meta any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopicMetaToStatus", reflect.TypeOf((*MockKafkaClient)(nil).TopicMetaToStatus), meta)
 }
+
+// UnsafeToLoseBrokerPartitions mocks base method.
+func (m *MockKafkaClient) UnsafeToLoseBrokerPartitions(brokerID int32) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsafeToLoseBrokerPartitions", brokerID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnsafeToLoseBrokerPartitions indicates an expected call of UnsafeToLoseBrokerPartitions.
+func (mr *MockKafkaClientMockRecorder) UnsafeToLoseBrokerPartitions(brokerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsafeToLoseBrokerPartitions", reflect.TypeOf((*MockKafkaClient)(nil).UnsafeToLoseBrokerPartitions), brokerID)
+}
+
+// UpdateFeatures mocks base method.
+func (m *MockKafkaClient) UpdateFeatures(levels map[string]int16) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFeatures", levels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFeatures indicates an expected call of UpdateFeatures.
+func (mr *MockKafkaClientMockRecorder) UpdateFeatures(levels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeatures", reflect.TypeOf((*MockKafkaClient)(nil).UpdateFeatures), levels)
+}
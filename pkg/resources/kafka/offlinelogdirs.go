@@ -0,0 +1,272 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/scale"
+)
+
+// reconcileOfflineLogDirs records the log directories of the broker volumes Kafka marked offline in their volume
+// states and handles them according to the offline log dirs policy. The log directories are reported by Cruise
+// Control, they are left unchanged while it is not available. A log directory found online again is cleared from the
+// volume state.
+func (r *Reconciler) reconcileOfflineLogDirs(ctx context.Context, log logr.Logger) error {
+	config := r.KafkaCluster.Spec.OfflineLogDirs
+	if config == nil || r.KafkaCluster.Status.CruiseControlTopicStatus != v1beta1.CruiseControlTopicReady {
+		return nil
+	}
+	cc, err := r.CruiseControlScalerFactory(ctx, r.KafkaCluster)
+	if err != nil {
+		log.Error(err, "could not check the log directories of the brokers, failed to initialize Cruise Control")
+		return nil
+	}
+	logDirsByBroker, err := cc.LogDirsByBroker(ctx)
+	if err != nil {
+		log.Error(err, "could not check the log directories of the brokers")
+		return nil
+	}
+
+	brokerIDs := make([]string, 0, len(r.KafkaCluster.Status.BrokersState))
+	for brokerID := range r.KafkaCluster.Status.BrokersState {
+		brokerIDs = append(brokerIDs, brokerID)
+	}
+	sort.Strings(brokerIDs)
+
+	for _, brokerID := range brokerIDs {
+		volumeStates := r.KafkaCluster.Status.BrokersState[brokerID].GracefulActionState.VolumeStates
+		mountPaths := make([]string, 0, len(volumeStates))
+		for mountPath := range volumeStates {
+			mountPaths = append(mountPaths, mountPath)
+		}
+		sort.Strings(mountPaths)
+
+		changedVolumeStates := make(map[string]v1beta1.VolumeState)
+		for _, mountPath := range mountPaths {
+			volumeState := volumeStates[mountPath]
+			offlineDir, offline := logDirOfVolume(logDirsByBroker[brokerID][scale.LogDirStateOffline], mountPath)
+			_, online := logDirOfVolume(logDirsByBroker[brokerID][scale.LogDirStateOnline], mountPath)
+			switch {
+			case offline && volumeState.OfflineLogDir == nil:
+				log.Info("log directory of broker is offline", v1beta1.BrokerIdLabelKey, brokerID,
+					mountPathAnnotationKey, mountPath, "logDir", offlineDir, "policy", config.GetPolicy())
+				volumeState.OfflineLogDir = &v1beta1.OfflineLogDir{Path: offlineDir, Since: metav1.Now()}
+			case online && volumeState.OfflineLogDir != nil:
+				log.Info("log directory of broker is back online", v1beta1.BrokerIdLabelKey, brokerID,
+					mountPathAnnotationKey, mountPath, "logDir", volumeState.OfflineLogDir.Path)
+				volumeState.OfflineLogDir = nil
+				// the replicas moved away from the volume are balanced back onto it
+				if volumeState.CruiseControlVolumeState.IsOfflineReplicasFixSucceeded() {
+					volumeState.CruiseControlVolumeState = v1beta1.GracefulDiskRebalanceRequired
+					volumeState.CruiseControlOperationReference = nil
+				}
+			}
+			if volumeState.OfflineLogDir != nil && config.GetPolicy() == v1beta1.OfflineLogDirsPolicyMoveReplicas &&
+				isOfflineReplicasFixPending(volumeState.CruiseControlVolumeState) {
+				volumeState.CruiseControlVolumeState = v1beta1.GracefulOfflineReplicasFixRequired
+				volumeState.CruiseControlOperationReference = nil
+			}
+			if !equality.Semantic.DeepEqual(volumeState, volumeStates[mountPath]) {
+				changedVolumeStates[mountPath] = volumeState
+			}
+		}
+		if len(changedVolumeStates) > 0 {
+			if err := k8sutil.UpdateBrokerStatus(r.Client, []string{brokerID}, r.KafkaCluster, changedVolumeStates, log); err != nil {
+				return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update offline log directories of broker",
+					v1beta1.BrokerIdLabelKey, brokerID)
+			}
+		}
+
+		if config.GetPolicy() != v1beta1.OfflineLogDirsPolicyRecreateVolume {
+			continue
+		}
+		for _, mountPath := range mountPaths {
+			if r.KafkaCluster.Status.BrokersState[brokerID].GracefulActionState.VolumeStates[mountPath].OfflineLogDir == nil {
+				continue
+			}
+			// the volumes are recreated one at a time
+			if err := r.recreateOfflineVolume(ctx, brokerID, mountPath, log); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isRecreatedVolume tells whether the volume is recreated for its offline log directory, it is created empty
+func (r *Reconciler) isRecreatedVolume(brokerID, mountPath string) bool {
+	config := r.KafkaCluster.Spec.OfflineLogDirs
+	return config != nil && config.GetPolicy() == v1beta1.OfflineLogDirsPolicyRecreateVolume &&
+		r.KafkaCluster.Status.BrokersState[brokerID].GracefulActionState.VolumeStates[mountPath].OfflineLogDir != nil
+}
+
+// clearRecreatedVolumeState removes the volume state of the volume recreated for its offline log directory once its
+// persistent volume claim is created, so the recreated volume is rebalanced once the broker is restarted on it
+func (r *Reconciler) clearRecreatedVolumeState(brokerID, mountPath string, log logr.Logger) error {
+	if !r.isRecreatedVolume(brokerID, mountPath) {
+		return nil
+	}
+	if err := k8sutil.DeleteVolumeStatus(r.Client, brokerID, mountPath, r.KafkaCluster, log); err != nil {
+		return errors.WrapIfWithDetails(err, "could not delete volume status for broker volume",
+			v1beta1.BrokerIdLabelKey, brokerID, mountPathAnnotationKey, mountPath)
+	}
+	return nil
+}
+
+// logDirOfVolume returns the log directory of the list which is on the volume mounted at the mount path
+func logDirOfVolume(logDirs []string, mountPath string) (string, bool) {
+	mountPath = strings.TrimSuffix(strings.TrimSpace(mountPath), "/")
+	for _, logDir := range logDirs {
+		logDir = strings.TrimSpace(logDir)
+		if logDir == mountPath || strings.HasPrefix(logDir, mountPath+"/") {
+			return logDir, true
+		}
+	}
+	return "", false
+}
+
+// isOfflineReplicasFixPending tells whether the offline replicas of the volume still have to be moved. The fix waits
+// for the Cruise Control operation running for the volume.
+func isOfflineReplicasFixPending(state v1beta1.CruiseControlVolumeState) bool {
+	return !state.IsOfflineReplicasFix() && !state.IsOfflineReplicasFixSucceeded() &&
+		!state.IsDiskRebalanceRunning() && !state.IsDiskRemovalRunning()
+}
+
+// recreateOfflineVolume deletes the pod of the broker and the persistent volume claim of the offline log directory.
+// The reconciliation of the cluster is held until they are gone. The volume state keeps the offline log directory until
+// the persistent volume claim is recreated empty, see clearRecreatedVolumeState. Nothing is deleted until
+// checkVolumeRecreationSafety admits the broker.
+func (r *Reconciler) recreateOfflineVolume(ctx context.Context, brokerID, mountPath string, log logr.Logger) error {
+	matchingLabels := client.MatchingLabels(
+		apiutil.MergeLabels(
+			apiutil.LabelsForKafka(r.KafkaCluster.Name),
+			map[string]string{v1beta1.BrokerIdLabelKey: brokerID},
+		),
+	)
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(r.KafkaCluster.Namespace), matchingLabels); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not list broker pods")
+	}
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := r.List(ctx, pvcList, client.InNamespace(r.KafkaCluster.Namespace), matchingLabels); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not list broker persistent volume claims")
+	}
+
+	objects := make([]client.Object, 0, len(podList.Items)+1)
+	for i := range podList.Items {
+		objects = append(objects, &podList.Items[i])
+	}
+	for i := range pvcList.Items {
+		if pvcList.Items[i].GetAnnotations()[mountPathAnnotationKey] == mountPath {
+			objects = append(objects, &pvcList.Items[i])
+		}
+	}
+	if len(objects) == 0 {
+		log.Info("volume of offline log directory deleted, recreating it", v1beta1.BrokerIdLabelKey, brokerID,
+			mountPathAnnotationKey, mountPath)
+		return nil
+	}
+
+	deleting := true
+	for _, object := range objects {
+		deleting = deleting && object.GetDeletionTimestamp() != nil
+	}
+	if !deleting {
+		if err := r.checkVolumeRecreationSafety(ctx, brokerID, log); err != nil {
+			return err
+		}
+	}
+
+	for _, object := range objects {
+		if object.GetDeletionTimestamp() != nil {
+			continue
+		}
+		log.Info("deleting resource of the volume of offline log directory", v1beta1.BrokerIdLabelKey, brokerID,
+			mountPathAnnotationKey, mountPath, "name", object.GetName())
+		if err := r.Delete(ctx, object); client.IgnoreNotFound(err) != nil {
+			return errorfactory.New(errorfactory.APIFailure{}, err, "could not delete resource of the volume of offline log directory",
+				"name", object.GetName())
+		}
+	}
+	return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("broker pod or volume are being deleted"),
+		"recreating volume of offline log directory", v1beta1.BrokerIdLabelKey, brokerID, mountPathAnnotationKey, mountPath)
+}
+
+// checkVolumeRecreationSafety runs the failure threshold checks of the rolling upgrade before the broker is taken down to
+// recreate its volume, and refuses it while any partition of the broker would go offline or under min ISR without it.
+// The offline and out-of-sync replicas of the broker itself are the failure being fixed, they are not counted.
+func (r *Reconciler) checkVolumeRecreationSafety(ctx context.Context, brokerID string, log logr.Logger) error {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(r.KafkaCluster.Namespace),
+		client.MatchingLabels(apiutil.LabelsForKafka(r.KafkaCluster.Name))); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not list broker pods")
+	}
+	if len(podList.Items) < len(r.KafkaCluster.Spec.Brokers) {
+		return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("pod count differs from brokers spec"),
+			"volume recreation of offline log directory is postponed", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	if len(getPodsInTerminatingOrPendingState(podList.Items)) >= r.KafkaCluster.Spec.RollingUpgradeConfig.ConcurrentBrokerRestartCountPerRack {
+		return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("pod(s) is still terminating or creating"),
+			"volume recreation of offline log directory is postponed", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+
+	id, err := strconv.ParseInt(brokerID, 10, 32)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not parse broker id", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	kClient, closeClient, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+	if err != nil {
+		return errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
+	}
+	defer closeClient()
+
+	impactedReplicas, err := impactedBrokers(kClient, log)
+	if err != nil {
+		return err
+	}
+	delete(impactedReplicas, int32(id))
+	if r.KafkaCluster.Status.RollingUpgrade.ErrorCount+len(impactedReplicas) >= r.KafkaCluster.Spec.RollingUpgradeConfig.FailureThreshold {
+		return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("cluster is not healthy"),
+			"volume recreation of offline log directory is postponed", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+
+	unsafePartitions, err := kClient.UnsafeToLoseBrokerPartitions(int32(id))
+	if err != nil {
+		return errors.WrapIf(err, "health check failed")
+	}
+	if len(unsafePartitions) > 0 {
+		sort.Strings(unsafePartitions)
+		return errorfactory.New(errorfactory.ReconcileRollingUpgrade{},
+			errors.New("partitions of the broker would go offline or under min ISR"),
+			"volume recreation of offline log directory is postponed", v1beta1.BrokerIdLabelKey, brokerID,
+			"partitions", strings.Join(unsafePartitions, ","))
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	controllerMocks "github.com/banzaicloud/koperator/controllers/tests/mocks"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	"github.com/banzaicloud/koperator/pkg/scale"
+)
+
+func TestLogDirOfVolume(t *testing.T) {
+	logDirs := []string{"/kafka-logs2/kafka", "/kafka-logs/kafka"}
+
+	logDir, found := logDirOfVolume(logDirs, "/kafka-logs")
+	require.True(t, found)
+	require.Equal(t, "/kafka-logs/kafka", logDir)
+
+	_, found = logDirOfVolume(logDirs, "/kafka-logs3")
+	require.False(t, found)
+}
+
+func TestReconcileOfflineLogDirs(t *testing.T) {
+	since := metav1.NewTime(time.Now().Add(-time.Hour))
+	offlineLogDir := &v1beta1.OfflineLogDir{Path: "/kafka-logs/kafka", Since: since}
+
+	tests := []struct {
+		testName            string
+		policy              v1beta1.OfflineLogDirsPolicy
+		volumeState         v1beta1.VolumeState
+		onlineLogDirs       []string
+		offlineLogDirs      []string
+		pods                []corev1.Pod
+		pvcs                []corev1.PersistentVolumeClaim
+		impactedReplicas    []int32
+		unsafePartitions    []string
+		expectedDeletes     int
+		expectedNotReady    bool
+		expectedPostponed   bool
+		expectedOffline     bool
+		expectedVolumeState v1beta1.CruiseControlVolumeState
+	}{
+		{
+			testName:            "online log directories are left unchanged",
+			policy:              v1beta1.OfflineLogDirsPolicyMoveReplicas,
+			volumeState:         v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+			onlineLogDirs:       []string{"/kafka-logs/kafka"},
+			expectedVolumeState: v1beta1.GracefulDiskRebalanceSucceeded,
+		},
+		{
+			testName:            "offline log directory is reported",
+			policy:              v1beta1.OfflineLogDirsPolicyReport,
+			volumeState:         v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+			offlineLogDirs:      []string{"/kafka-logs/kafka"},
+			expectedOffline:     true,
+			expectedVolumeState: v1beta1.GracefulDiskRebalanceSucceeded,
+		},
+		{
+			testName:            "offline replicas are moved",
+			policy:              v1beta1.OfflineLogDirsPolicyMoveReplicas,
+			volumeState:         v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+			offlineLogDirs:      []string{"/kafka-logs/kafka"},
+			expectedOffline:     true,
+			expectedVolumeState: v1beta1.GracefulOfflineReplicasFixRequired,
+		},
+		{
+			testName: "offline replicas are moved once the running disk rebalance finished",
+			policy:   v1beta1.OfflineLogDirsPolicyMoveReplicas,
+			volumeState: v1beta1.VolumeState{
+				CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceRunning,
+				OfflineLogDir:            offlineLogDir,
+			},
+			offlineLogDirs:      []string{"/kafka-logs/kafka"},
+			expectedOffline:     true,
+			expectedVolumeState: v1beta1.GracefulDiskRebalanceRunning,
+		},
+		{
+			testName: "offline replicas are not moved again",
+			policy:   v1beta1.OfflineLogDirsPolicyMoveReplicas,
+			volumeState: v1beta1.VolumeState{
+				CruiseControlVolumeState: v1beta1.GracefulOfflineReplicasFixSucceeded,
+				OfflineLogDir:            offlineLogDir,
+			},
+			offlineLogDirs:      []string{"/kafka-logs/kafka"},
+			expectedOffline:     true,
+			expectedVolumeState: v1beta1.GracefulOfflineReplicasFixSucceeded,
+		},
+		{
+			testName: "volume is rebalanced once the log directory is back online",
+			policy:   v1beta1.OfflineLogDirsPolicyMoveReplicas,
+			volumeState: v1beta1.VolumeState{
+				CruiseControlVolumeState: v1beta1.GracefulOfflineReplicasFixSucceeded,
+				OfflineLogDir:            offlineLogDir,
+			},
+			onlineLogDirs:       []string{"/kafka-logs/kafka"},
+			expectedVolumeState: v1beta1.GracefulDiskRebalanceRequired,
+		},
+		{
+			testName:            "volume of offline log directory is deleted",
+			policy:              v1beta1.OfflineLogDirsPolicyRecreateVolume,
+			volumeState:         v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+			offlineLogDirs:      []string{"/kafka-logs/kafka"},
+			pods:                []corev1.Pod{newCanaryPod("1", true, 0)},
+			pvcs:                []corev1.PersistentVolumeClaim{*createPvc("kafka-1-storage-0", "1", "/kafka-logs"), *createPvc("kafka-1-storage-1", "1", "/kafka-logs2")},
+			impactedReplicas:    []int32{1},
+			expectedDeletes:     2,
+			expectedNotReady:    true,
+			expectedOffline:     true,
+			expectedVolumeState: v1beta1.GracefulDiskRebalanceSucceeded,
+		},
+		{
+			testName:            "volume recreation is postponed while another broker is unhealthy",
+			policy:              v1beta1.OfflineLogDirsPolicyRecreateVolume,
+			volumeState:         v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+			offlineLogDirs:      []string{"/kafka-logs/kafka"},
+			pods:                []corev1.Pod{newCanaryPod("1", true, 0)},
+			pvcs:                []corev1.PersistentVolumeClaim{*createPvc("kafka-1-storage-0", "1", "/kafka-logs")},
+			impactedReplicas:    []int32{1, 2},
+			expectedPostponed:   true,
+			expectedOffline:     true,
+			expectedVolumeState: v1beta1.GracefulDiskRebalanceSucceeded,
+		},
+		{
+			testName:            "volume recreation is postponed while partitions would go under min ISR",
+			policy:              v1beta1.OfflineLogDirsPolicyRecreateVolume,
+			volumeState:         v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+			offlineLogDirs:      []string{"/kafka-logs/kafka"},
+			pods:                []corev1.Pod{newCanaryPod("1", true, 0)},
+			pvcs:                []corev1.PersistentVolumeClaim{*createPvc("kafka-1-storage-0", "1", "/kafka-logs")},
+			impactedReplicas:    []int32{1},
+			unsafePartitions:    []string{"test-topic-0"},
+			expectedPostponed:   true,
+			expectedOffline:     true,
+			expectedVolumeState: v1beta1.GracefulDiskRebalanceSucceeded,
+		},
+		{
+			testName: "offline log directory is kept until the volume is recreated",
+			policy:   v1beta1.OfflineLogDirsPolicyRecreateVolume,
+			volumeState: v1beta1.VolumeState{
+				CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded,
+				OfflineLogDir:            offlineLogDir,
+			},
+			pvcs:                []corev1.PersistentVolumeClaim{*createPvc("kafka-1-storage-1", "1", "/kafka-logs2")},
+			expectedOffline:     true,
+			expectedVolumeState: v1beta1.GracefulDiskRebalanceSucceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Spec: v1beta1.KafkaClusterSpec{
+					Brokers:        []v1beta1.Broker{{Id: 1}},
+					OfflineLogDirs: &v1beta1.OfflineLogDirsConfig{Policy: test.policy},
+					RollingUpgradeConfig: v1beta1.RollingUpgradeConfig{
+						FailureThreshold:                    1,
+						ConcurrentBrokerRestartCountPerRack: 1,
+					},
+				},
+				Status: v1beta1.KafkaClusterStatus{
+					CruiseControlTopicStatus: v1beta1.CruiseControlTopicReady,
+					BrokersState: map[string]v1beta1.BrokerState{
+						"1": {
+							GracefulActionState: v1beta1.GracefulActionState{
+								CruiseControlState: v1beta1.GracefulUpscaleSucceeded,
+								VolumeStates:       map[string]v1beta1.VolumeState{"/kafka-logs": test.volumeState},
+							},
+						},
+					},
+				},
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			mockKafkaClientProvider := new(kafkaclient.MockedProvider)
+			r := newStatusUpdatingReconciler(mockCtrl, mockClient, cluster, mockKafkaClientProvider)
+
+			mockedKafkaClient := mocks.NewMockKafkaClient(mockCtrl)
+			mockedKafkaClient.EXPECT().AllOfflineReplicas().Return(test.impactedReplicas, nil).AnyTimes()
+			mockedKafkaClient.EXPECT().OutOfSyncReplicas().Return(nil, nil).AnyTimes()
+			mockedKafkaClient.EXPECT().UnsafeToLoseBrokerPartitions(int32(1)).Return(test.unsafePartitions, nil).AnyTimes()
+			mockKafkaClientProvider.On("NewFromCluster", mockClient, cluster).Return(mockedKafkaClient, func() {}, nil).Maybe()
+
+			mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any(), gomock.Any()).Do(
+				func(ctx context.Context, list *corev1.PodList, opts ...client.ListOption) {
+					list.Items = test.pods
+				}).Return(nil).AnyTimes()
+			mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaimList{}), gomock.Any(), gomock.Any()).Do(
+				func(ctx context.Context, list *corev1.PersistentVolumeClaimList, opts ...client.ListOption) {
+					list.Items = test.pvcs
+				}).Return(nil).AnyTimes()
+			mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil).Times(test.expectedDeletes)
+
+			mockCruiseControl := controllerMocks.NewMockCruiseControlScaler(mockCtrl)
+			mockCruiseControl.EXPECT().LogDirsByBroker(gomock.Any()).Return(map[string]map[scale.LogDirState][]string{
+				"1": {
+					scale.LogDirStateOnline:  append([]string{"/kafka-logs2/kafka"}, test.onlineLogDirs...),
+					scale.LogDirStateOffline: test.offlineLogDirs,
+				},
+			}, nil)
+			r.CruiseControlScalerFactory = controllerMocks.NewMockScaleFactory(mockCruiseControl)
+
+			err := r.reconcileOfflineLogDirs(context.Background(), logf.Log)
+			switch {
+			case test.expectedNotReady:
+				require.True(t, errors.As(err, &errorfactory.ResourceNotReady{}))
+			case test.expectedPostponed:
+				require.True(t, errors.As(err, &errorfactory.ReconcileRollingUpgrade{}))
+			default:
+				require.NoError(t, err)
+			}
+
+			volumeState, found := r.KafkaCluster.Status.BrokersState["1"].GracefulActionState.VolumeStates["/kafka-logs"]
+			require.True(t, found)
+			require.Equal(t, test.expectedVolumeState, volumeState.CruiseControlVolumeState)
+			if test.expectedOffline {
+				require.NotNil(t, volumeState.OfflineLogDir)
+				require.Equal(t, "/kafka-logs/kafka", volumeState.OfflineLogDir.Path)
+			} else {
+				require.Nil(t, volumeState.OfflineLogDir)
+			}
+		})
+	}
+}
+
+func TestReconcileOfflineLogDirsWithoutCruiseControl(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			OfflineLogDirs: &v1beta1.OfflineLogDirsConfig{Policy: v1beta1.OfflineLogDirsPolicyRecreateVolume},
+		},
+		Status: v1beta1.KafkaClusterStatus{CruiseControlTopicStatus: v1beta1.CruiseControlTopicNotReady},
+	}
+	mockCtrl := gomock.NewController(t)
+	r := New(mocks.NewMockClient(mockCtrl), nil, cluster, new(kafkaclient.MockedProvider))
+	// Cruise Control is not called while its topic is not ready
+	r.CruiseControlScalerFactory = controllerMocks.NewMockScaleFactory(controllerMocks.NewMockCruiseControlScaler(mockCtrl))
+
+	require.NoError(t, r.reconcileOfflineLogDirs(context.Background(), logf.Log))
+}
+
+func TestClearRecreatedVolumeState(t *testing.T) {
+	offlineLogDir := &v1beta1.OfflineLogDir{Path: "/kafka-logs/kafka", Since: metav1.Now()}
+	tests := []struct {
+		testName        string
+		policy          v1beta1.OfflineLogDirsPolicy
+		volumeState     v1beta1.VolumeState
+		expectedRemoved bool
+	}{
+		{
+			testName:        "volume state of the recreated volume is removed",
+			policy:          v1beta1.OfflineLogDirsPolicyRecreateVolume,
+			volumeState:     v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded, OfflineLogDir: offlineLogDir},
+			expectedRemoved: true,
+		},
+		{
+			testName:    "volume state of an online volume is kept",
+			policy:      v1beta1.OfflineLogDirsPolicyRecreateVolume,
+			volumeState: v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+		},
+		{
+			testName:    "volume state of an offline volume which is not recreated is kept",
+			policy:      v1beta1.OfflineLogDirsPolicyReport,
+			volumeState: v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded, OfflineLogDir: offlineLogDir},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Spec:       v1beta1.KafkaClusterSpec{OfflineLogDirs: &v1beta1.OfflineLogDirsConfig{Policy: test.policy}},
+				Status: v1beta1.KafkaClusterStatus{
+					BrokersState: map[string]v1beta1.BrokerState{
+						"1": {GracefulActionState: v1beta1.GracefulActionState{
+							VolumeStates: map[string]v1beta1.VolumeState{"/kafka-logs": test.volumeState},
+						}},
+					},
+				},
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			r := newStatusUpdatingReconciler(mockCtrl, mockClient, cluster, new(kafkaclient.MockedProvider))
+
+			require.Equal(t, test.expectedRemoved, r.isRecreatedVolume("1", "/kafka-logs"))
+			require.NoError(t, r.clearRecreatedVolumeState("1", "/kafka-logs", logf.Log))
+			_, found := r.KafkaCluster.Status.BrokersState["1"].GracefulActionState.VolumeStates["/kafka-logs"]
+			require.Equal(t, !test.expectedRemoved, found)
+		})
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/resources/templates"
+	"github.com/banzaicloud/koperator/pkg/util"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+const (
+	rollingUpgradePlanTemplate = "%s-rolling-upgrade-plan"
+	rollingUpgradePlanKey      = "plan.yaml"
+)
+
+// brokerPlanAction is what the reconciliation of the spec does with a broker
+type brokerPlanAction string
+
+const (
+	brokerPlanActionNone          brokerPlanAction = "None"
+	brokerPlanActionCreate        brokerPlanAction = "Create"
+	brokerPlanActionRestart       brokerPlanAction = "Restart"
+	brokerPlanActionDynamicUpdate brokerPlanAction = "DynamicUpdate"
+	brokerPlanActionRemove        brokerPlanAction = "Remove"
+)
+
+// rollingUpgradePlan is the summary of the changes the reconciliation of the spec makes on the brokers
+type rollingUpgradePlan struct {
+	// Generation is the generation of the KafkaCluster the plan is computed for
+	Generation int64 `json:"generation"`
+	// Restart are the ids of the brokers which are restarted
+	Restart []string `json:"restart,omitempty"`
+	// DynamicUpdate are the ids of the brokers whose configs are updated without a restart
+	DynamicUpdate []string `json:"dynamicUpdate,omitempty"`
+	// Brokers are the plans of the brokers
+	Brokers []brokerPlan `json:"brokers"`
+}
+
+// brokerPlan is the change the reconciliation of the spec makes on a broker
+type brokerPlan struct {
+	BrokerID string           `json:"brokerId"`
+	Action   brokerPlanAction `json:"action"`
+	Reasons  []string         `json:"reasons,omitempty"`
+	// ReadOnlyConfigs are the changed configs which are applied by the restart of the broker
+	ReadOnlyConfigs []string `json:"readOnlyConfigs,omitempty"`
+	// DynamicConfigs are the changed configs which are updated without a restart
+	DynamicConfigs []string `json:"dynamicConfigs,omitempty"`
+	// PodPatch is the patch of the broker pod
+	PodPatch string `json:"podPatch,omitempty"`
+}
+
+// Plan computes the pods and the configs of the brokers like Reconcile does and writes which brokers would be
+// restarted and which would get their configs updated dynamically into the rolling upgrade plan ConfigMap, without
+// changing the brokers
+func (r *Reconciler) Plan(log logr.Logger) error {
+	log = log.WithValues("component", componentName, "clusterName", r.KafkaCluster.Name, "clusterNamespace", r.KafkaCluster.Namespace)
+	ctx := context.Background()
+
+	extListenerStatuses, err := r.createExternalListenerStatuses(log)
+	if err != nil {
+		return errors.WrapIf(err, "could not determine the statuses of the external listeners")
+	}
+	intListenerStatuses, controllerIntListenerStatuses, err := k8sutil.CreateInternalListenerStatuses(r.KafkaCluster, extListenerStatuses)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get listener statuses")
+	}
+	clientPass, serverPasses, superUsers, err := r.getPasswordKeysAndSuperUsers()
+	if err != nil {
+		return err
+	}
+	var quorumVoters []string
+	if r.KafkaCluster.Spec.KRaftMode {
+		if r.KafkaCluster.Spec.KRaftDynamicQuorum {
+			quorumVoters, err = generateQuorumBootstrapServers(r.KafkaCluster, controllerIntListenerStatuses)
+		} else {
+			quorumVoters, err = generateQuorumVoters(r.KafkaCluster, controllerIntListenerStatuses)
+		}
+		if err != nil {
+			return errors.WrapIf(err, "failed to generate quorum voters configuration")
+		}
+	}
+
+	var brokerPods corev1.PodList
+	err = r.List(ctx, &brokerPods, client.InNamespace(r.KafkaCluster.Namespace), client.MatchingLabels(apiutil.LabelsForKafka(r.KafkaCluster.Name)))
+	if err != nil {
+		return errors.WrapIf(err, "failed to list broker pods that belong to Kafka cluster")
+	}
+
+	storageMigrations, _, err := r.storageMigrations(ctx, log)
+	if err != nil {
+		return err
+	}
+
+	plan := rollingUpgradePlan{Generation: r.KafkaCluster.Generation}
+	inSpec := make(map[string]struct{}, len(r.KafkaCluster.Spec.Brokers))
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		brokerID := strconv.Itoa(int(broker.Id))
+		inSpec[brokerID] = struct{}{}
+		bPlan := brokerPlan{BrokerID: brokerID, Action: brokerPlanActionNone}
+
+		if backupBroker, ok, err := rolledBackBroker(r.KafkaCluster, broker); err != nil {
+			return err
+		} else if ok {
+			broker = backupBroker
+			bPlan.Reasons = append(bPlan.Reasons, "broker is rolled back to its previous configuration")
+		}
+		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "could not get the configuration of the broker", v1beta1.BrokerIdLabelKey, brokerID)
+		}
+		brokerConfig = storageMigrations.brokerConfig(broker.Id, brokerConfig)
+
+		currentPod := brokerPod(brokerPods.Items, brokerID)
+		if currentPod == nil {
+			bPlan.Action = brokerPlanActionCreate
+			bPlan.Reasons = append(bPlan.Reasons, "broker pod does not exist")
+			plan.Brokers = append(plan.Brokers, bPlan)
+			continue
+		}
+
+		desiredConfigMap, err := r.configMap(broker, brokerConfig, quorumVoters, extListenerStatuses, intListenerStatuses,
+			controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
+		if err != nil {
+			return err
+		}
+		if err := r.planBrokerConfig(ctx, &bPlan, desiredConfigMap); err != nil {
+			return err
+		}
+
+		pvcs, err := getCreatedPvcForBroker(ctx, r.Client, broker.Id, brokerConfig.StorageConfigs, r.KafkaCluster.Namespace, r.KafkaCluster.Name)
+		switch {
+		case errors.As(err, &errorfactory.ResourceNotReady{}):
+			bPlan.restart("persistent volume claims of new storage are created")
+		case err != nil:
+			return errors.WrapIfWithDetails(err, "failed to list PVC's", v1beta1.BrokerIdLabelKey, brokerID)
+		default:
+			desiredPod := r.pod(broker.Id, brokerConfig, pvcs, log).(*corev1.Pod)
+			if err := r.planBrokerPod(&bPlan, desiredPod, currentPod, log); err != nil {
+				return err
+			}
+		}
+		plan.Brokers = append(plan.Brokers, bPlan)
+	}
+
+	for i := range brokerPods.Items {
+		brokerID := brokerPods.Items[i].Labels[v1beta1.BrokerIdLabelKey]
+		if _, ok := inSpec[brokerID]; ok || brokerPods.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+		plan.Brokers = append(plan.Brokers, brokerPlan{
+			BrokerID: brokerID,
+			Action:   brokerPlanActionRemove,
+			Reasons:  []string{"broker is removed from the spec, its pod is deleted once its partitions are moved off"},
+		})
+	}
+
+	for _, bPlan := range plan.Brokers {
+		switch bPlan.Action {
+		case brokerPlanActionRestart:
+			plan.Restart = append(plan.Restart, bPlan.BrokerID)
+		case brokerPlanActionDynamicUpdate:
+			plan.DynamicUpdate = append(plan.DynamicUpdate, bPlan.BrokerID)
+		}
+	}
+
+	data, err := yaml.Marshal(plan)
+	if err != nil {
+		return errors.WrapIf(err, "could not marshal the rolling upgrade plan")
+	}
+	planConfigMap := &corev1.ConfigMap{
+		ObjectMeta: templates.ObjectMeta(fmt.Sprintf(rollingUpgradePlanTemplate, r.KafkaCluster.Name),
+			apiutil.LabelsForKafka(r.KafkaCluster.Name), r.KafkaCluster),
+		Data: map[string]string{rollingUpgradePlanKey: string(data)},
+	}
+	log.Info("rolling upgrade plan computed", "generation", plan.Generation, "restart", plan.Restart, "dynamicUpdate", plan.DynamicUpdate)
+	return k8sutil.Reconcile(log, r.Client, planConfigMap, r.KafkaCluster)
+}
+
+// planBrokerConfig compares the broker configuration with the current one, only the per-broker configs are updated
+// without a restart
+func (r *Reconciler) planBrokerConfig(ctx context.Context, bPlan *brokerPlan, desiredConfigMap *corev1.ConfigMap) error {
+	state := r.KafkaCluster.Status.BrokersState[bPlan.BrokerID]
+	if state.ConfigurationState == v1beta1.ConfigOutOfSync {
+		bPlan.restart("broker configuration changed earlier is not applied yet")
+	}
+	if state.PerBrokerConfigurationState == v1beta1.PerBrokerConfigOutOfSync {
+		bPlan.dynamicUpdate("per-broker configuration changed earlier is not applied yet")
+	}
+
+	currentConfigMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: desiredConfigMap.Namespace, Name: desiredConfigMap.Name}, currentConfigMap)
+	if apierrors.IsNotFound(err) {
+		bPlan.restart("broker configuration does not exist")
+		return nil
+	}
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not get the broker configuration", v1beta1.BrokerIdLabelKey, bPlan.BrokerID)
+	}
+
+	currentConfigs, err := properties.NewFromString(currentConfigMap.Data[kafkautils.ConfigPropertyName])
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not parse the current configuration for broker", v1beta1.BrokerIdLabelKey, bPlan.BrokerID)
+	}
+	desiredConfigs, err := properties.NewFromString(desiredConfigMap.Data[kafkautils.ConfigPropertyName])
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not parse the desired configuration for broker", v1beta1.BrokerIdLabelKey, bPlan.BrokerID)
+	}
+	configDiff := currentConfigs.Diff(desiredConfigs)
+	if len(configDiff) == 0 {
+		return nil
+	}
+	changed := make([]string, 0, len(configDiff))
+	for key := range configDiff {
+		changed = append(changed, key)
+	}
+	sort.Strings(changed)
+
+	if kafkautils.ShouldRefreshOnlyPerBrokerConfigs(currentConfigs, desiredConfigs, logr.Discard()) {
+		bPlan.DynamicConfigs = changed
+		bPlan.dynamicUpdate("per-broker configs changed")
+		return nil
+	}
+	bPlan.ReadOnlyConfigs = changed
+	if _, ok := configDiff[kafkautils.KafkaConfigListenerSecurityProtocolMap]; ok {
+		bPlan.restart("security protocol of a listener changed")
+	} else {
+		bPlan.restart("read-only broker configs changed")
+	}
+	return nil
+}
+
+// planBrokerPod compares the broker pod with the current one the way the rolling upgrade does
+func (r *Reconciler) planBrokerPod(bPlan *brokerPlan, desiredPod, currentPod *corev1.Pod, log logr.Logger) error {
+	if _, found := foreignDataDirectory(currentPod); found {
+		bPlan.Reasons = append(bPlan.Reasons, "broker refuses to start on a foreign data directory, its pod is left failed")
+		return nil
+	}
+	mergeTolerations(desiredPod, currentPod)
+	patchResult, err := patch.DefaultPatchMaker.Calculate(currentPod, desiredPod)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not compare the broker pod", v1beta1.BrokerIdLabelKey, bPlan.BrokerID)
+	}
+	if !patchResult.IsEmpty() {
+		bPlan.PodPatch = string(patchResult.Patch)
+		bPlan.restart("broker pod changed")
+	}
+	if r.isPodTainted(log, currentPod) {
+		bPlan.restart("broker pod matches the tainted brokers selector")
+	}
+	if brokerIDs, err := util.RequestedRestartBrokers(r.KafkaCluster); err == nil && slices.Contains(brokerIDs, bPlan.BrokerID) {
+		bPlan.restart("restart of the broker is requested with the " + v1beta1.RestartBrokersAnnotationKey + " annotation")
+	}
+	if k8sutil.IsPodContainsTerminatedContainer(currentPod) || k8sutil.IsPodContainsEvictedContainer(currentPod) ||
+		k8sutil.IsPodContainsShutdownContainer(currentPod) {
+		bPlan.restart("broker pod has a terminated container")
+	}
+	return nil
+}
+
+// restart marks the broker to be restarted, a restart applies the dynamic configs too
+func (p *brokerPlan) restart(reason string) {
+	p.Action = brokerPlanActionRestart
+	p.Reasons = append(p.Reasons, reason)
+}
+
+func (p *brokerPlan) dynamicUpdate(reason string) {
+	if p.Action != brokerPlanActionRestart {
+		p.Action = brokerPlanActionDynamicUpdate
+	}
+	p.Reasons = append(p.Reasons, reason)
+}
+
+// rolledBackBroker returns the configuration backup of the broker when the broker is rolled back to it
+func rolledBackBroker(kafkaCluster *v1beta1.KafkaCluster, broker v1beta1.Broker) (v1beta1.Broker, bool, error) {
+	state := kafkaCluster.Status.BrokersState[strconv.Itoa(int(broker.Id))]
+	rollout := state.ConfigurationRollout
+	if kafkaCluster.Spec.RollingUpgradeConfig.ConfigRollback == nil || rollout == nil || rollout.Phase != v1beta1.ConfigurationRolloutRolledBack {
+		return v1beta1.Broker{}, false, nil
+	}
+	backup, err := util.GzipAndBase64ResolvedBrokerConfiguration(&broker, kafkaCluster.Spec)
+	if err != nil {
+		return v1beta1.Broker{}, false, errors.WrapIfWithDetails(err, "could not generate broker configuration backup", v1beta1.BrokerIdLabelKey, broker.Id)
+	}
+	if util.BrokerConfigurationRevision(backup) != rollout.Revision {
+		return v1beta1.Broker{}, false, nil
+	}
+	backupBroker, err := util.GetBrokerFromBrokerConfigurationBackup(state.ConfigurationBackup)
+	if err != nil {
+		return v1beta1.Broker{}, false, errors.WrapIfWithDetails(err, "could not restore broker configuration from backup", v1beta1.BrokerIdLabelKey, broker.Id)
+	}
+	return backupBroker, true, nil
+}
+
+func brokerPod(pods []corev1.Pod, brokerID string) *corev1.Pod {
+	for i := range pods {
+		if pods[i].Labels[v1beta1.BrokerIdLabelKey] == brokerID && pods[i].DeletionTimestamp == nil {
+			return &pods[i]
+		}
+	}
+	return nil
+}
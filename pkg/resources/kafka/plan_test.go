@@ -0,0 +1,183 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	"github.com/banzaicloud/koperator/pkg/util"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+)
+
+func TestPlanBrokerConfig(t *testing.T) {
+	const currentConfig = "advertised.listeners=INTERNAL://kafka-0:9092\nnum.io.threads=8"
+
+	tests := []struct {
+		testName                string
+		currentConfig           *string
+		desiredConfig           string
+		configurationState      v1beta1.ConfigurationState
+		expectedAction          brokerPlanAction
+		expectedReadOnlyConfigs []string
+		expectedDynamicConfigs  []string
+	}{
+		{
+			testName:       "configuration is not changed",
+			currentConfig:  util.StringPointer(currentConfig),
+			desiredConfig:  currentConfig,
+			expectedAction: brokerPlanActionNone,
+		},
+		{
+			testName:               "per-broker config is updated dynamically",
+			currentConfig:          util.StringPointer(currentConfig),
+			desiredConfig:          "advertised.listeners=INTERNAL://kafka-0.kafka:9092\nnum.io.threads=8",
+			expectedAction:         brokerPlanActionDynamicUpdate,
+			expectedDynamicConfigs: []string{kafkautils.KafkaConfigAdvertisedListeners},
+		},
+		{
+			testName:                "read-only config restarts the broker",
+			currentConfig:           util.StringPointer(currentConfig),
+			desiredConfig:           "advertised.listeners=INTERNAL://kafka-0.kafka:9092\nnum.io.threads=16",
+			expectedAction:          brokerPlanActionRestart,
+			expectedReadOnlyConfigs: []string{kafkautils.KafkaConfigAdvertisedListeners, "num.io.threads"},
+		},
+		{
+			testName:           "configuration changed earlier is not applied yet",
+			currentConfig:      util.StringPointer(currentConfig),
+			desiredConfig:      currentConfig,
+			configurationState: v1beta1.ConfigOutOfSync,
+			expectedAction:     brokerPlanActionRestart,
+		},
+		{
+			testName:       "configuration does not exist",
+			desiredConfig:  currentConfig,
+			expectedAction: brokerPlanActionRestart,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			cluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Status: v1beta1.KafkaClusterStatus{
+					BrokersState: map[string]v1beta1.BrokerState{"0": {ConfigurationState: test.configurationState}},
+				},
+			}
+			r := New(mockClient, nil, cluster, new(kafkaclient.MockedProvider))
+
+			key := types.NamespacedName{Namespace: "kafka", Name: "kafka-config-0"}
+			getConfigMap := mockClient.EXPECT().Get(context.Background(), key, gomock.AssignableToTypeOf(&corev1.ConfigMap{}))
+			if test.currentConfig == nil {
+				getConfigMap.Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name))
+			} else {
+				getConfigMap.DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					obj.(*corev1.ConfigMap).Data = map[string]string{kafkautils.ConfigPropertyName: *test.currentConfig}
+					return nil
+				})
+			}
+
+			bPlan := brokerPlan{BrokerID: "0", Action: brokerPlanActionNone}
+			desired := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Data:       map[string]string{kafkautils.ConfigPropertyName: test.desiredConfig},
+			}
+			require.NoError(t, r.planBrokerConfig(context.Background(), &bPlan, desired))
+			require.Equal(t, test.expectedAction, bPlan.Action)
+			require.Equal(t, test.expectedReadOnlyConfigs, bPlan.ReadOnlyConfigs)
+			require.Equal(t, test.expectedDynamicConfigs, bPlan.DynamicConfigs)
+			if test.expectedAction != brokerPlanActionNone {
+				require.NotEmpty(t, bPlan.Reasons)
+			}
+		})
+	}
+}
+
+func TestPlanBrokerPod(t *testing.T) {
+	newPod := func(image string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "kafka-0", Namespace: "kafka", Labels: map[string]string{v1beta1.BrokerIdLabelKey: "0"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "kafka", Image: image}}},
+		}
+	}
+
+	tests := []struct {
+		testName       string
+		desiredImage   string
+		tainted        bool
+		expectedAction brokerPlanAction
+		expectedPatch  bool
+	}{
+		{
+			testName:       "pod is not changed",
+			desiredImage:   "apache/kafka:3.9.1",
+			expectedAction: brokerPlanActionNone,
+		},
+		{
+			testName:       "changed pod restarts the broker",
+			desiredImage:   "apache/kafka:4.0.0",
+			expectedAction: brokerPlanActionRestart,
+			expectedPatch:  true,
+		},
+		{
+			testName:       "tainted pod restarts the broker",
+			desiredImage:   "apache/kafka:3.9.1",
+			tainted:        true,
+			expectedAction: brokerPlanActionRestart,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{}
+			if test.tainted {
+				cluster.Spec.TaintedBrokersSelector = &metav1.LabelSelector{MatchLabels: map[string]string{v1beta1.BrokerIdLabelKey: "0"}}
+			}
+			r := New(nil, nil, cluster, new(kafkaclient.MockedProvider))
+
+			currentPod := newPod("apache/kafka:3.9.1")
+			require.NoError(t, patch.DefaultAnnotator.SetLastAppliedAnnotation(currentPod))
+
+			bPlan := brokerPlan{BrokerID: "0", Action: brokerPlanActionNone}
+			require.NoError(t, r.planBrokerPod(&bPlan, newPod(test.desiredImage), currentPod, logf.Log))
+			require.Equal(t, test.expectedAction, bPlan.Action)
+			require.Equal(t, test.expectedPatch, bPlan.PodPatch != "")
+		})
+	}
+}
+
+func TestBrokerPlanDynamicUpdateAfterRestart(t *testing.T) {
+	bPlan := brokerPlan{BrokerID: "0", Action: brokerPlanActionNone}
+	bPlan.restart("broker pod changed")
+	bPlan.dynamicUpdate("per-broker configs changed")
+	require.Equal(t, brokerPlanActionRestart, bPlan.Action)
+	require.Equal(t, []string{"broker pod changed", "per-broker configs changed"}, bPlan.Reasons)
+}
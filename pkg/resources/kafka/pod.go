@@ -165,24 +165,29 @@ fi`},
 		}
 	}
 
-	if r.KafkaCluster.Spec.KRaftMode {
-		for i, container := range pod.Spec.Containers {
-			if container.Name == kafkaContainerName {
+	for i, container := range pod.Spec.Containers {
+		if container.Name == kafkaContainerName {
+			if r.KafkaCluster.Spec.KRaftMode {
 				// in KRaft mode, all broker nodes within the same Kafka cluster need to use the same cluster ID to format the storage
 				addClusterIdEnv(r, pod, i)
+			}
 
-				// see how this env var is used in wait-for-envoy-sidecars.sh
-				storageMountPaths := brokerConfig.GetStorageMountPaths()
-				if storageMountPaths != "" {
-					pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env,
-						corev1.EnvVar{
-							Name:  "LOG_DIRS",
-							Value: brokerConfig.GetStorageMountPaths(),
-						},
-					)
-				}
-				break
+			// see how these env vars are used in wait-for-envoy-sidecar.sh, the meta.properties of the log dirs are
+			// checked against them before the broker is started
+			storageMountPaths := brokerConfig.GetStorageMountPaths()
+			if storageMountPaths != "" {
+				pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env,
+					corev1.EnvVar{
+						Name:  "LOG_DIRS",
+						Value: storageMountPaths,
+					},
+					corev1.EnvVar{
+						Name:  brokerIDEnvVarName,
+						Value: strconv.Itoa(int(id)),
+					},
+				)
 			}
+			break
 		}
 	}
 
@@ -283,6 +288,7 @@ func getInitContainers(brokerConfig *v1beta1.BrokerConfig, kafkaClusterSpec v1be
 			Resources: k8sutil.GetDefaultInitContainerResourceRequirements(),
 		},
 	}...)
+	initContainers = append(initContainers, getTieredStorageInitContainers(kafkaClusterSpec)...)
 
 	sort.Slice(initContainers, func(i, j int) bool {
 		return initContainers[i].Name < initContainers[j].Name
@@ -325,6 +331,7 @@ func getVolumeMounts(brokerConfigVolumeMounts, dataVolumeMount []corev1.VolumeMo
 			MountPath: "/var/run/wait",
 		},
 	}...)
+	volumeMounts = append(volumeMounts, getTieredStorageVolumeMounts(kafkaClusterSpec)...)
 
 	sort.Slice(volumeMounts, func(i, j int) bool {
 		return volumeMounts[i].Name < volumeMounts[j].Name
@@ -382,6 +389,7 @@ func getVolumes(brokerConfigVolumes, dataVolume []corev1.Volume, kafkaClusterSpe
 			},
 		},
 	}...)
+	volumes = append(volumes, getTieredStorageVolumes(kafkaClusterSpec)...)
 
 	sort.Slice(volumes, func(i, j int) bool {
 		return volumes[i].Name < volumes[j].Name
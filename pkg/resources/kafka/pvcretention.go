@@ -0,0 +1,89 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	banzaiv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+// retainBrokerPvc marks the PVC of a broker removed from the spec retained instead of deleting it
+func (r *Reconciler) retainBrokerPvc(ctx context.Context, log logr.Logger, brokerID, pvcName string) error {
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: r.KafkaCluster.Namespace}}
+	patch := client.MergeFrom(pvc.DeepCopy())
+	pvc.SetAnnotations(map[string]string{banzaiv1beta1.RetainedPvcAnnotationKey: time.Now().UTC().Format(time.RFC3339)})
+	if err := r.Patch(ctx, pvc, patch); err != nil {
+		return errors.WrapIfWithDetails(err, "could not retain pvc for broker", "id", brokerID, "pvc name", pvcName)
+	}
+	log.Info("pvc for broker retained", "pvc name", pvcName, banzaiv1beta1.BrokerIdLabelKey, brokerID)
+	return nil
+}
+
+// RetainBrokerPvcs releases the PVCs of the brokers of a deleted cluster from the ownership of the cluster and marks
+// them retained, so they are not garbage collected with the cluster
+func RetainBrokerPvcs(ctx context.Context, c client.Client, cluster *banzaiv1beta1.KafkaCluster, log logr.Logger) error {
+	var pvcList corev1.PersistentVolumeClaimList
+	if err := c.List(ctx, &pvcList, client.InNamespace(cluster.Namespace), client.MatchingLabels(apiutil.LabelsForKafka(cluster.Name))); err != nil {
+		return errors.WrapIf(err, "failed to list broker pvcs that belong to Kafka cluster")
+	}
+
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if _, retained := pvc.Annotations[banzaiv1beta1.RetainedPvcAnnotationKey]; retained && !metav1.IsControlledBy(pvc, cluster) {
+			continue
+		}
+		patch := client.MergeFrom(pvc.DeepCopy())
+		ownerReferences := make([]metav1.OwnerReference, 0, len(pvc.OwnerReferences))
+		for _, ownerReference := range pvc.OwnerReferences {
+			if ownerReference.UID != cluster.UID {
+				ownerReferences = append(ownerReferences, ownerReference)
+			}
+		}
+		pvc.SetOwnerReferences(ownerReferences)
+		pvc.SetAnnotations(apiutil.MergeLabels(pvc.GetAnnotations(),
+			map[string]string{banzaiv1beta1.RetainedPvcAnnotationKey: time.Now().UTC().Format(time.RFC3339)}))
+		if err := c.Patch(ctx, pvc, patch); err != nil {
+			return errors.WrapIfWithDetails(err, "could not retain pvc for broker", "id", pvc.Labels[banzaiv1beta1.BrokerIdLabelKey], "pvc name", pvc.Name)
+		}
+		log.Info("pvc for broker retained", "pvc name", pvc.Name, banzaiv1beta1.BrokerIdLabelKey, pvc.Labels[banzaiv1beta1.BrokerIdLabelKey])
+	}
+	return nil
+}
+
+// adoptRetainedPvc takes a retained PVC into use again by a broker with the same id and mount path, the PVC of a
+// deleted cluster is owned by the cluster again
+func (r *Reconciler) adoptRetainedPvc(ctx context.Context, log logr.Logger, current, desired *corev1.PersistentVolumeClaim) error {
+	if _, retained := current.Annotations[banzaiv1beta1.RetainedPvcAnnotationKey]; !retained {
+		return nil
+	}
+	delete(current.Annotations, banzaiv1beta1.RetainedPvcAnnotationKey)
+	if !metav1.IsControlledBy(current, r.KafkaCluster) {
+		current.OwnerReferences = append(current.OwnerReferences, desired.OwnerReferences...)
+	}
+	if err := r.Update(ctx, current); err != nil {
+		return errors.WrapIfWithDetails(err, "could not adopt retained pvc", "pvc name", current.Name)
+	}
+	log.Info("retained pvc adopted", "pvc name", current.Name, banzaiv1beta1.BrokerIdLabelKey, current.Labels[banzaiv1beta1.BrokerIdLabelKey])
+	return nil
+}
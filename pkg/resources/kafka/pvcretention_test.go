@@ -0,0 +1,124 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	"github.com/banzaicloud/koperator/pkg/resources/templates"
+)
+
+func newRetentionCluster() *v1beta1.KafkaCluster {
+	return &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka", UID: types.UID("cluster-uid")},
+		Spec: v1beta1.KafkaClusterSpec{
+			PersistentVolumeClaimRetentionPolicy: &v1beta1.PersistentVolumeClaimRetentionPolicy{
+				WhenScaled:  v1beta1.RetainPersistentVolumeClaimRetentionPolicyType,
+				WhenDeleted: v1beta1.RetainPersistentVolumeClaimRetentionPolicyType,
+			},
+		},
+	}
+}
+
+func TestRetainBrokerPvcs(t *testing.T) {
+	cluster := newRetentionCluster()
+	owned := createPvc("kafka-0-storage-0-abcde", "0", "/kafka-logs")
+	owned.OwnerReferences = templates.ObjectMeta(owned.Name, owned.Labels, cluster).OwnerReferences
+	released := createPvc("kafka-1-storage-0-fghij", "1", "/kafka-logs")
+	released.Annotations[v1beta1.RetainedPvcAnnotationKey] = "2026-01-01T00:00:00Z"
+
+	mockCtrl := gomock.NewController(t)
+	mockClient := mocks.NewMockClient(mockCtrl)
+	mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaimList{}), client.InNamespace("kafka"), gomock.Any()).Do(
+		func(ctx context.Context, list *corev1.PersistentVolumeClaimList, opts ...client.ListOption) {
+			list.Items = []corev1.PersistentVolumeClaim{*owned, *released}
+		}).Return(nil)
+	var patched []*corev1.PersistentVolumeClaim
+	mockClient.EXPECT().Patch(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaim{}), gomock.Any()).Do(
+		func(ctx context.Context, pvc *corev1.PersistentVolumeClaim, patch client.Patch, opts ...client.PatchOption) {
+			patched = append(patched, pvc)
+		}).Return(nil)
+
+	require.NoError(t, RetainBrokerPvcs(context.Background(), mockClient, cluster, logf.Log))
+
+	// the claim already released from a deleted cluster is left alone
+	require.Len(t, patched, 1)
+	require.Equal(t, owned.Name, patched[0].Name)
+	require.Empty(t, patched[0].OwnerReferences)
+	require.Contains(t, patched[0].Annotations, v1beta1.RetainedPvcAnnotationKey)
+	require.Equal(t, "/kafka-logs", patched[0].Annotations[mountPathAnnotationKey])
+}
+
+func TestAdoptRetainedPvc(t *testing.T) {
+	cluster := newRetentionCluster()
+	desired := createPvc("", "0", "/kafka-logs")
+	desired.ObjectMeta = templates.ObjectMetaWithGeneratedNameAndAnnotations("kafka-0-storage-0-", desired.Labels, desired.Annotations, cluster)
+
+	tests := []struct {
+		testName         string
+		annotations      map[string]string
+		ownerReferences  []metav1.OwnerReference
+		expectedAdoption bool
+	}{
+		{
+			testName:        "claim is not retained",
+			annotations:     map[string]string{mountPathAnnotationKey: "/kafka-logs"},
+			ownerReferences: desired.OwnerReferences,
+		},
+		{
+			testName:         "claim of a removed broker is retained",
+			annotations:      map[string]string{mountPathAnnotationKey: "/kafka-logs", v1beta1.RetainedPvcAnnotationKey: "2026-01-01T00:00:00Z"},
+			ownerReferences:  desired.OwnerReferences,
+			expectedAdoption: true,
+		},
+		{
+			testName:         "claim of a deleted cluster is retained",
+			annotations:      map[string]string{mountPathAnnotationKey: "/kafka-logs", v1beta1.RetainedPvcAnnotationKey: "2026-01-01T00:00:00Z"},
+			expectedAdoption: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			current := createPvc("kafka-0-storage-0-abcde", "0", "/kafka-logs")
+			current.Annotations = test.annotations
+			current.OwnerReferences = test.ownerReferences
+
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			if test.expectedAdoption {
+				mockClient.EXPECT().Update(gomock.Any(), current).Return(nil)
+			}
+			r := New(mockClient, nil, cluster, new(kafkaclient.MockedProvider))
+
+			require.NoError(t, r.adoptRetainedPvc(context.Background(), logf.Log, current, desired))
+			require.NotContains(t, current.Annotations, v1beta1.RetainedPvcAnnotationKey)
+			require.True(t, metav1.IsControlledBy(current, cluster))
+			require.Len(t, current.OwnerReferences, 1)
+		})
+	}
+}
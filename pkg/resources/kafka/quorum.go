@@ -0,0 +1,365 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+)
+
+const (
+	storageFormatStandalone           = "--standalone"
+	storageFormatNoInitialControllers = "--no-initial-controllers"
+
+	// quorumMaxFetchAge is how long a voter may not fetch from the quorum leader before it is considered lagging,
+	// it matches the default controller.quorum.fetch.timeout.ms after which a voter starts an election
+	quorumMaxFetchAge = 2 * time.Second
+)
+
+// getControllerIDs returns the ids of the controller nodes (including the combined ones) in ascending order
+func getControllerIDs(kafkaCluster *v1beta1.KafkaCluster) ([]int32, error) {
+	var ids []int32
+	for _, broker := range kafkaCluster.Spec.Brokers {
+		bConfig, err := broker.GetBrokerConfig(kafkaCluster.Spec)
+		if err != nil {
+			return nil, err
+		}
+		if bConfig.IsControllerNode() {
+			ids = append(ids, broker.Id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// getStorageFormatArgs returns the arguments the KRaft storage of the node is formatted with.
+// The first bootstrap controller forms the dynamic quorum on its own, every other controller joins it as an
+// observer and is promoted to a voter by the operator. Once the quorum exists no controller may form a new one.
+func getStorageFormatArgs(kafkaCluster *v1beta1.KafkaCluster, brokerID int32, bConfig *v1beta1.BrokerConfig) string {
+	if !kafkaCluster.Spec.KRaftMode || !kafkaCluster.Spec.KRaftDynamicQuorum || !bConfig.IsControllerNode() {
+		return ""
+	}
+	quorum := kafkaCluster.Status.KRaftQuorum
+	if quorum == nil || len(quorum.Voters) > 0 || len(quorum.BootstrapControllers) == 0 {
+		return storageFormatNoInitialControllers
+	}
+	if brokerID == slices.Min(quorum.BootstrapControllers) {
+		return storageFormatStandalone
+	}
+	return storageFormatNoInitialControllers
+}
+
+// reconcileKRaftQuorumBootstrapControllers keeps the bootstrap controllers of a dynamic quorum in the status.
+// Controllers removed from the spec are dropped from the list, new controllers are only listed when none of the
+// bootstrap controllers is left.
+func (r *Reconciler) reconcileKRaftQuorumBootstrapControllers(log logr.Logger) error {
+	controllerIDs, err := getControllerIDs(r.KafkaCluster)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get controller nodes")
+	}
+
+	var status v1beta1.KRaftQuorumStatus
+	if r.KafkaCluster.Status.KRaftQuorum != nil {
+		status = *r.KafkaCluster.Status.KRaftQuorum.DeepCopy()
+	}
+	bootstrapControllers := slices.DeleteFunc(slices.Clone(status.BootstrapControllers), func(id int32) bool {
+		return !slices.Contains(controllerIDs, id)
+	})
+	if len(bootstrapControllers) == 0 {
+		bootstrapControllers = controllerIDs
+	}
+	if slices.Equal(bootstrapControllers, status.BootstrapControllers) {
+		return nil
+	}
+
+	log.Info("updating the bootstrap controllers of the KRaft quorum", "bootstrapControllers", bootstrapControllers)
+	status.BootstrapControllers = bootstrapControllers
+	if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, status, log); err != nil {
+		return errors.WrapIf(err, "could not update KRaft quorum status")
+	}
+	return nil
+}
+
+// reconcileKRaftQuorum publishes the state of the KRaft controller quorum and the finalized features in the status
+// and keeps the voters of a dynamic quorum in sync with the controllers of the spec
+func (r *Reconciler) reconcileKRaftQuorum(controllerIntListenerStatuses map[string]v1beta1.ListenerStatusList, log logr.Logger) error {
+	kClient, close, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+	if err != nil {
+		return errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
+	}
+	defer close()
+
+	// the features are informational, failing to describe them must not hold back the reconcile
+	if features, err := kClient.DescribeFeatures(); err != nil {
+		log.Error(err, "could not describe the features of the cluster")
+	} else if err := r.updateFeaturesStatus(features, log); err != nil {
+		return err
+	}
+
+	quorum, err := kClient.DescribeQuorum()
+	if err != nil {
+		if !r.KafkaCluster.Spec.KRaftDynamicQuorum {
+			log.Error(err, "could not describe the KRaft quorum")
+			return nil
+		}
+		return errorfactory.New(errorfactory.BrokersRequestError{}, err, "could not describe the KRaft quorum")
+	}
+	if err := r.updateKRaftQuorumStatus(quorum, log); err != nil {
+		return err
+	}
+
+	if !r.KafkaCluster.Spec.KRaftDynamicQuorum {
+		return nil
+	}
+	return r.reconcileKRaftQuorumVoters(kClient, quorum, controllerIntListenerStatuses, log)
+}
+
+// reconcileKRaftQuorumVoters adds the controllers of the spec to the voters of a dynamic quorum and removes the ones
+// which are no longer in the spec. Kafka accepts one voter change at a time, so the reconcile is requeued after each.
+func (r *Reconciler) reconcileKRaftQuorumVoters(kClient kafkaclient.KafkaClient, quorum *kafkaclient.QuorumInfo,
+	controllerIntListenerStatuses map[string]v1beta1.ListenerStatusList, log logr.Logger) error {
+	controllerIDs, err := getControllerIDs(r.KafkaCluster)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get controller nodes")
+	}
+
+	for _, voter := range quorum.Voters {
+		if slices.Contains(controllerIDs, voter.ID) {
+			continue
+		}
+		if len(quorum.Voters) == 1 {
+			return errors.NewWithDetails("the last voter of the KRaft quorum cannot be removed", v1beta1.BrokerIdLabelKey, voter.ID)
+		}
+		log.Info("removing controller from the voters of the KRaft quorum", v1beta1.BrokerIdLabelKey, voter.ID)
+		if err := kClient.RemoveRaftVoter(voter.ID, voter.DirectoryID); err != nil {
+			return errorfactory.New(errorfactory.BrokersRequestError{}, err, "could not remove voter from the KRaft quorum")
+		}
+		return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("KRaft quorum voters are changing"), "voter removed", v1beta1.BrokerIdLabelKey, voter.ID)
+	}
+
+	addresses := make(map[int32]string)
+	for _, listenerStatuses := range controllerIntListenerStatuses {
+		for _, status := range listenerStatuses {
+			for _, id := range controllerIDs {
+				if status.Name == fmt.Sprintf("broker-%d", id) {
+					addresses[id] = status.Address
+				}
+			}
+		}
+	}
+
+	for _, id := range controllerIDs {
+		if _, ok := quorum.Voter(id); ok {
+			continue
+		}
+		// the controller joins the quorum as an observer once it is started, it can be promoted from then on
+		observer, ok := quorum.Observer(id)
+		if !ok {
+			continue
+		}
+		endpoint, err := r.controllerEndpoint(addresses[id])
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "could not get the controller endpoint", v1beta1.BrokerIdLabelKey, id)
+		}
+		log.Info("adding controller to the voters of the KRaft quorum", v1beta1.BrokerIdLabelKey, id)
+		if err := kClient.AddRaftVoter(id, observer.DirectoryID, []kafkaclient.RaftVoterEndpoint{endpoint}); err != nil {
+			// the controller is not added until it caught up with the leader
+			return errorfactory.New(errorfactory.ResourceNotReady{}, err, "could not add voter to the KRaft quorum", v1beta1.BrokerIdLabelKey, id)
+		}
+		return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("KRaft quorum voters are changing"), "voter added", v1beta1.BrokerIdLabelKey, id)
+	}
+	return nil
+}
+
+func (r *Reconciler) controllerEndpoint(address string) (kafkaclient.RaftVoterEndpoint, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return kafkaclient.RaftVoterEndpoint{}, err
+	}
+	portNumber, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return kafkaclient.RaftVoterEndpoint{}, err
+	}
+	return kafkaclient.RaftVoterEndpoint{
+		Name: generateControlPlaneListener(r.KafkaCluster.Spec.ListenersConfig.InternalListeners),
+		Host: host,
+		Port: uint16(portNumber),
+	}, nil
+}
+
+// kRaftQuorumStatus returns the status of the quorum, the bootstrap controllers are kept from the current status
+func kRaftQuorumStatus(current *v1beta1.KRaftQuorumStatus, quorum *kafkaclient.QuorumInfo) v1beta1.KRaftQuorumStatus {
+	var status v1beta1.KRaftQuorumStatus
+	if current != nil {
+		status.BootstrapControllers = slices.Clone(current.BootstrapControllers)
+	}
+	leaderID := quorum.LeaderID
+	status.LeaderID = &leaderID
+	status.LeaderEpoch = quorum.LeaderEpoch
+	status.HighWatermark = quorum.HighWatermark
+
+	for _, voter := range quorum.Voters {
+		status.Voters = append(status.Voters, voter.ID)
+	}
+	slices.Sort(status.Voters)
+
+	replicaStatus := func(replica kafkaclient.QuorumReplica, role v1beta1.KRaftQuorumRole) v1beta1.KRaftQuorumReplicaStatus {
+		return v1beta1.KRaftQuorumReplicaStatus{
+			ID:               replica.ID,
+			Role:             role,
+			Lag:              quorum.Lag(replica),
+			LastFetchTime:    quorumTimestamp(replica.LastFetchTimestamp),
+			LastCaughtUpTime: quorumTimestamp(replica.LastCaughtUpTimestamp),
+		}
+	}
+	for _, voter := range quorum.Voters {
+		status.Replicas = append(status.Replicas, replicaStatus(voter, v1beta1.KRaftQuorumRoleVoter))
+	}
+	for _, observer := range quorum.Observers {
+		status.Replicas = append(status.Replicas, replicaStatus(observer, v1beta1.KRaftQuorumRoleObserver))
+	}
+	slices.SortStableFunc(status.Replicas, func(a, b v1beta1.KRaftQuorumReplicaStatus) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return status
+}
+
+// quorumTimestamp converts the milliseconds reported by the quorum leader, negative values are unknown times
+func quorumTimestamp(millis int64) *metav1.Time {
+	if millis < 0 {
+		return nil
+	}
+	t := metav1.NewTime(time.UnixMilli(millis))
+	return &t
+}
+
+// kRaftQuorumStatusChanged tells whether the members, the roles or the leader of the quorum changed. The high watermark,
+// the lags and the fetch times change with every metadata record so they are refreshed only along with the rest
+func kRaftQuorumStatusChanged(current *v1beta1.KRaftQuorumStatus, status v1beta1.KRaftQuorumStatus) bool {
+	if current == nil {
+		return true
+	}
+	stable := func(s v1beta1.KRaftQuorumStatus) v1beta1.KRaftQuorumStatus {
+		s.HighWatermark = 0
+		s.Replicas = slices.Clone(s.Replicas)
+		for i := range s.Replicas {
+			s.Replicas[i].Lag = 0
+			s.Replicas[i].LastFetchTime = nil
+			s.Replicas[i].LastCaughtUpTime = nil
+		}
+		return s
+	}
+	return !equality.Semantic.DeepEqual(stable(*current), stable(status))
+}
+
+func (r *Reconciler) updateKRaftQuorumStatus(quorum *kafkaclient.QuorumInfo, log logr.Logger) error {
+	status := kRaftQuorumStatus(r.KafkaCluster.Status.KRaftQuorum, quorum)
+	if !kRaftQuorumStatusChanged(r.KafkaCluster.Status.KRaftQuorum, status) {
+		return nil
+	}
+	if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, status, log); err != nil {
+		return errors.WrapIf(err, "could not update KRaft quorum status")
+	}
+	return nil
+}
+
+func (r *Reconciler) updateFeaturesStatus(features *kafkaclient.FeaturesInfo, log logr.Logger) error {
+	status := v1beta1.FeaturesStatus{MetadataVersion: features.MetadataVersion()}
+	if len(features.Finalized) > 0 {
+		status.Finalized = maps.Clone(features.Finalized)
+	}
+	if r.KafkaCluster.Status.Features != nil && equality.Semantic.DeepEqual(*r.KafkaCluster.Status.Features, status) {
+		return nil
+	}
+	if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, status, log); err != nil {
+		return errors.WrapIf(err, "could not update features status")
+	}
+	return nil
+}
+
+// laggingQuorumVoters returns the ids of the voters, besides the node to be restarted, which do not follow the quorum
+// leader closely enough to let the node be restarted: they lag more than maxLag records behind the leader or have not
+// fetched from it recently. Every other voter is returned when the quorum has no leader.
+func laggingQuorumVoters(quorum *kafkaclient.QuorumInfo, restartedID int32, maxLag int64) []string {
+	leader, hasLeader := quorum.Leader()
+	var lagging []string
+	for _, voter := range quorum.Voters {
+		if voter.ID == restartedID || (hasLeader && voter.ID == leader.ID) {
+			continue
+		}
+		// the fetch times are compared to the time the leader reports for itself, so clock skew does not matter
+		stale := hasLeader && voter.LastFetchTimestamp >= 0 && leader.LastFetchTimestamp >= 0 &&
+			leader.LastFetchTimestamp-voter.LastFetchTimestamp > quorumMaxFetchAge.Milliseconds()
+		if !hasLeader || stale || quorum.Lag(voter) > maxLag {
+			lagging = append(lagging, strconv.Itoa(int(voter.ID)))
+		}
+	}
+	return lagging
+}
+
+// kRaftQuorumBlockingRollingUpgrade returns the ids of the controllers which must catch up with the quorum leader
+// before the controller pod is restarted. The readiness of the controller pods is checked instead when the quorum
+// cannot be described.
+func (r *Reconciler) kRaftQuorumBlockingRollingUpgrade(pods []corev1.Pod, currentPod *corev1.Pod, log logr.Logger) []string {
+	if !r.KafkaCluster.Spec.KRaftMode || currentPod.Labels[v1beta1.IsControllerNodeKey] != configValueTrue {
+		return nil
+	}
+	restartedID, err := strconv.ParseInt(currentPod.Labels[v1beta1.BrokerIdLabelKey], 10, 32)
+	if err != nil {
+		return controllersBlockingRollingUpgrade(true, pods, currentPod)
+	}
+
+	kClient, close, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+	if err != nil {
+		log.Info("could not connect to kafka brokers, checking the readiness of the controllers instead", "error", err.Error())
+		return controllersBlockingRollingUpgrade(true, pods, currentPod)
+	}
+	defer close()
+
+	quorum, err := kClient.DescribeQuorum()
+	if err != nil {
+		log.Info("could not describe the KRaft quorum, checking the readiness of the controllers instead", "error", err.Error())
+		return controllersBlockingRollingUpgrade(true, pods, currentPod)
+	}
+	return laggingQuorumVoters(quorum, int32(restartedID), r.KafkaCluster.Spec.RollingUpgradeConfig.GetControllerQuorumMaxLag())
+}
+
+// isKRaftQuorumVoter returns whether the controller node is still a voter of the dynamic quorum
+func isKRaftQuorumVoter(kafkaCluster *v1beta1.KafkaCluster, brokerID string) bool {
+	if !kafkaCluster.Spec.KRaftMode || !kafkaCluster.Spec.KRaftDynamicQuorum || kafkaCluster.Status.KRaftQuorum == nil {
+		return false
+	}
+	id, err := strconv.ParseInt(brokerID, 10, 32)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(kafkaCluster.Status.KRaftQuorum.Voters, int32(id))
+}
@@ -0,0 +1,376 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+func newDynamicQuorumCluster(quorum *v1beta1.KRaftQuorumStatus) *v1beta1.KafkaCluster {
+	return &v1beta1.KafkaCluster{
+		Spec: v1beta1.KafkaClusterSpec{
+			KRaftMode:          true,
+			KRaftDynamicQuorum: true,
+			ListenersConfig: v1beta1.ListenersConfig{
+				InternalListeners: []v1beta1.InternalListenerConfig{
+					{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "controller", ContainerPort: 29093}, UsedForControllerCommunication: true},
+				},
+			},
+			Brokers: []v1beta1.Broker{
+				{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"broker"}}},
+				{Id: 100, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"controller"}}},
+				{Id: 101, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"controller"}}},
+				{Id: 102, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"controller"}}},
+			},
+		},
+		Status: v1beta1.KafkaClusterStatus{KRaftQuorum: quorum},
+	}
+}
+
+var dynamicQuorumControllerListenerStatuses = map[string]v1beta1.ListenerStatusList{
+	"controller": {
+		{Name: "broker-100", Address: "kafka-100.kafka-headless.kafka.svc.cluster.local:29093"},
+		{Name: "broker-101", Address: "kafka-101.kafka-headless.kafka.svc.cluster.local:29093"},
+		{Name: "broker-102", Address: "kafka-102.kafka-headless.kafka.svc.cluster.local:29093"},
+	},
+}
+
+func TestGetStorageFormatArgs(t *testing.T) {
+	broker := &v1beta1.BrokerConfig{Roles: []string{"broker"}}
+	controller := &v1beta1.BrokerConfig{Roles: []string{"controller"}}
+
+	tests := []struct {
+		testName string
+		quorum   *v1beta1.KRaftQuorumStatus
+		brokerID int32
+		bConfig  *v1beta1.BrokerConfig
+		expected string
+	}{
+		{
+			testName: "broker",
+			quorum:   &v1beta1.KRaftQuorumStatus{BootstrapControllers: []int32{100, 101}},
+			brokerID: 0,
+			bConfig:  broker,
+			expected: "",
+		},
+		{
+			testName: "first bootstrap controller forms the quorum",
+			quorum:   &v1beta1.KRaftQuorumStatus{BootstrapControllers: []int32{101, 100}},
+			brokerID: 100,
+			bConfig:  controller,
+			expected: storageFormatStandalone,
+		},
+		{
+			testName: "other bootstrap controller joins the quorum",
+			quorum:   &v1beta1.KRaftQuorumStatus{BootstrapControllers: []int32{100, 101}},
+			brokerID: 101,
+			bConfig:  controller,
+			expected: storageFormatNoInitialControllers,
+		},
+		{
+			testName: "first bootstrap controller joins the existing quorum",
+			quorum:   &v1beta1.KRaftQuorumStatus{BootstrapControllers: []int32{100, 101}, Voters: []int32{101}},
+			brokerID: 100,
+			bConfig:  controller,
+			expected: storageFormatNoInitialControllers,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			if got := getStorageFormatArgs(newDynamicQuorumCluster(test.quorum), test.brokerID, test.bConfig); got != test.expected {
+				t.Errorf("expected storage format arguments: %q, got: %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateQuorumBootstrapServers(t *testing.T) {
+	cluster := newDynamicQuorumCluster(&v1beta1.KRaftQuorumStatus{BootstrapControllers: []int32{102, 100}})
+
+	bootstrapServers, err := generateQuorumBootstrapServers(cluster, dynamicQuorumControllerListenerStatuses)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	expected := []string{
+		"kafka-100.kafka-headless.kafka.svc.cluster.local:29093",
+		"kafka-102.kafka-headless.kafka.svc.cluster.local:29093",
+	}
+	if !reflect.DeepEqual(bootstrapServers, expected) {
+		t.Errorf("expected bootstrap servers: %v, got: %v", expected, bootstrapServers)
+	}
+}
+
+func TestReconcileKRaftQuorumVoters(t *testing.T) {
+	tests := []struct {
+		testName        string
+		voters          []kafkaclient.QuorumReplica
+		observers       []kafkaclient.QuorumReplica
+		expectedAdded   int32
+		expectedRemoved int32
+	}{
+		{
+			testName:  "quorum matches the spec",
+			voters:    []kafkaclient.QuorumReplica{{ID: 100}, {ID: 101}, {ID: 102}},
+			observers: []kafkaclient.QuorumReplica{{ID: 0}},
+		},
+		{
+			testName:      "observer controller is added",
+			voters:        []kafkaclient.QuorumReplica{{ID: 100}, {ID: 101}},
+			observers:     []kafkaclient.QuorumReplica{{ID: 0}, {ID: 102, DirectoryID: kafkaclient.Uuid{2}}},
+			expectedAdded: 102,
+		},
+		{
+			testName:        "controller removed from the spec is removed first",
+			voters:          []kafkaclient.QuorumReplica{{ID: 100}, {ID: 101}, {ID: 103, DirectoryID: kafkaclient.Uuid{3}}},
+			observers:       []kafkaclient.QuorumReplica{{ID: 102, DirectoryID: kafkaclient.Uuid{2}}},
+			expectedRemoved: 103,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+
+			var voterIDs []int32
+			for _, voter := range test.voters {
+				voterIDs = append(voterIDs, voter.ID)
+			}
+			cluster := newDynamicQuorumCluster(&v1beta1.KRaftQuorumStatus{BootstrapControllers: []int32{100}, Voters: voterIDs})
+			r := New(mockClient, nil, cluster, new(kafkaclient.MockedProvider))
+
+			mockedKafkaClient := mocks.NewMockKafkaClient(mockCtrl)
+			if test.expectedAdded != 0 {
+				mockedKafkaClient.EXPECT().AddRaftVoter(test.expectedAdded, kafkaclient.Uuid{2}, []kafkaclient.RaftVoterEndpoint{
+					{Name: "CONTROLLER", Host: "kafka-102.kafka-headless.kafka.svc.cluster.local", Port: 29093},
+				}).Return(nil)
+			}
+			if test.expectedRemoved != 0 {
+				mockedKafkaClient.EXPECT().RemoveRaftVoter(test.expectedRemoved, kafkaclient.Uuid{3}).Return(nil)
+			}
+			quorum := &kafkaclient.QuorumInfo{Voters: test.voters, Observers: test.observers}
+			err := r.reconcileKRaftQuorumVoters(mockedKafkaClient, quorum, dynamicQuorumControllerListenerStatuses, logf.Log)
+			if test.expectedAdded == 0 && test.expectedRemoved == 0 {
+				if err != nil {
+					t.Errorf("expected nil error, got: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &errorfactory.ResourceNotReady{}) {
+				t.Errorf("expected the reconcile to be requeued after the voter change, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestKRaftQuorumStatus(t *testing.T) {
+	quorum := &kafkaclient.QuorumInfo{
+		LeaderID:      101,
+		LeaderEpoch:   4,
+		HighWatermark: 90,
+		Voters: []kafkaclient.QuorumReplica{
+			{ID: 101, LogEndOffset: 100, LastFetchTimestamp: 5000, LastCaughtUpTimestamp: 5000},
+			{ID: 100, LogEndOffset: 95, LastFetchTimestamp: 4000, LastCaughtUpTimestamp: 3000},
+		},
+		Observers: []kafkaclient.QuorumReplica{
+			{ID: 0, LogEndOffset: 70, LastFetchTimestamp: -1, LastCaughtUpTimestamp: -1},
+		},
+	}
+	timestamp := func(millis int64) *metav1.Time {
+		t := metav1.NewTime(time.UnixMilli(millis))
+		return &t
+	}
+	leaderID := int32(101)
+
+	status := kRaftQuorumStatus(&v1beta1.KRaftQuorumStatus{BootstrapControllers: []int32{100}, Voters: []int32{100}}, quorum)
+	expected := v1beta1.KRaftQuorumStatus{
+		BootstrapControllers: []int32{100},
+		Voters:               []int32{100, 101},
+		LeaderID:             &leaderID,
+		LeaderEpoch:          4,
+		HighWatermark:        90,
+		Replicas: []v1beta1.KRaftQuorumReplicaStatus{
+			{ID: 0, Role: v1beta1.KRaftQuorumRoleObserver, Lag: 30},
+			{ID: 100, Role: v1beta1.KRaftQuorumRoleVoter, Lag: 5, LastFetchTime: timestamp(4000), LastCaughtUpTime: timestamp(3000)},
+			{ID: 101, Role: v1beta1.KRaftQuorumRoleVoter, Lag: 0, LastFetchTime: timestamp(5000), LastCaughtUpTime: timestamp(5000)},
+		},
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("expected KRaft quorum status: %+v, got: %+v", expected, status)
+	}
+}
+
+func TestKRaftQuorumStatusChanged(t *testing.T) {
+	leaderID := int32(101)
+	current := v1beta1.KRaftQuorumStatus{
+		Voters:        []int32{100, 101},
+		LeaderID:      &leaderID,
+		LeaderEpoch:   4,
+		HighWatermark: 90,
+		Replicas: []v1beta1.KRaftQuorumReplicaStatus{
+			{ID: 100, Role: v1beta1.KRaftQuorumRoleVoter, Lag: 5, LastFetchTime: &metav1.Time{Time: time.UnixMilli(4000)}},
+			{ID: 101, Role: v1beta1.KRaftQuorumRoleVoter},
+		},
+	}
+	if !kRaftQuorumStatusChanged(nil, current) {
+		t.Error("expected the missing KRaft quorum status to be changed")
+	}
+
+	// the replication progress alone does not update the status
+	progressed := *current.DeepCopy()
+	progressed.HighWatermark = 120
+	progressed.Replicas[0].Lag = 0
+	progressed.Replicas[0].LastFetchTime = &metav1.Time{Time: time.UnixMilli(6000)}
+	progressed.Replicas[1].LastCaughtUpTime = &metav1.Time{Time: time.UnixMilli(6000)}
+	if kRaftQuorumStatusChanged(&current, progressed) {
+		t.Error("expected the KRaft quorum status not to be changed by the replication progress")
+	}
+
+	newLeader := *current.DeepCopy()
+	newLeader.LeaderEpoch = 5
+	if !kRaftQuorumStatusChanged(&current, newLeader) {
+		t.Error("expected the KRaft quorum status to be changed by a new leader epoch")
+	}
+
+	newObserver := *current.DeepCopy()
+	newObserver.Replicas = append(newObserver.Replicas, v1beta1.KRaftQuorumReplicaStatus{ID: 0, Role: v1beta1.KRaftQuorumRoleObserver})
+	if !kRaftQuorumStatusChanged(&current, newObserver) {
+		t.Error("expected the KRaft quorum status to be changed by a new observer")
+	}
+	if current.Replicas[0].LastFetchTime == nil {
+		t.Error("expected the current KRaft quorum status not to be modified")
+	}
+}
+
+func TestLaggingQuorumVoters(t *testing.T) {
+	tests := []struct {
+		testName    string
+		quorum      *kafkaclient.QuorumInfo
+		restartedID int32
+		expected    []string
+	}{
+		{
+			testName: "voters follow the leader",
+			quorum: &kafkaclient.QuorumInfo{LeaderID: 100, Voters: []kafkaclient.QuorumReplica{
+				{ID: 100, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+				{ID: 101, LogEndOffset: 4500, LastFetchTimestamp: 9500},
+				{ID: 102, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+			}},
+			restartedID: 102,
+		},
+		{
+			testName: "voter lags too many records behind",
+			quorum: &kafkaclient.QuorumInfo{LeaderID: 100, Voters: []kafkaclient.QuorumReplica{
+				{ID: 100, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+				{ID: 101, LogEndOffset: 3000, LastFetchTimestamp: 10000},
+				{ID: 102, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+			}},
+			restartedID: 102,
+			expected:    []string{"101"},
+		},
+		{
+			testName: "voter has not fetched recently",
+			quorum: &kafkaclient.QuorumInfo{LeaderID: 100, Voters: []kafkaclient.QuorumReplica{
+				{ID: 100, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+				{ID: 101, LogEndOffset: 5000, LastFetchTimestamp: 1000},
+				{ID: 102, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+			}},
+			restartedID: 100,
+			expected:    []string{"101"},
+		},
+		{
+			testName: "restarted voter is not checked",
+			quorum: &kafkaclient.QuorumInfo{LeaderID: 100, Voters: []kafkaclient.QuorumReplica{
+				{ID: 100, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+				{ID: 101, LogEndOffset: 0, LastFetchTimestamp: -1},
+			}},
+			restartedID: 101,
+		},
+		{
+			testName: "quorum without leader",
+			quorum: &kafkaclient.QuorumInfo{LeaderID: -1, Voters: []kafkaclient.QuorumReplica{
+				{ID: 100, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+				{ID: 101, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+				{ID: 102, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+			}},
+			restartedID: 100,
+			expected:    []string{"101", "102"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			if got := laggingQuorumVoters(test.quorum, test.restartedID, 1000); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected lagging voters: %v, got: %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestKRaftQuorumBlockingRollingUpgrade(t *testing.T) {
+	controllerPod := func(id string, ready bool) corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				v1beta1.BrokerIdLabelKey:    id,
+				v1beta1.IsControllerNodeKey: "true",
+			}},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+		}
+	}
+	pods := []corev1.Pod{controllerPod("100", true), controllerPod("101", false), controllerPod("102", true)}
+
+	mockCtrl := gomock.NewController(t)
+	mockClient := mocks.NewMockClient(mockCtrl)
+	mockKafkaClientProvider := new(kafkaclient.MockedProvider)
+	cluster := newDynamicQuorumCluster(nil)
+	r := New(mockClient, nil, cluster, mockKafkaClientProvider)
+
+	mockedKafkaClient := mocks.NewMockKafkaClient(mockCtrl)
+	mockKafkaClientProvider.On("NewFromCluster", mockClient, cluster).Return(mockedKafkaClient, func() {}, nil)
+
+	// the not ready controller already caught up with the leader
+	mockedKafkaClient.EXPECT().DescribeQuorum().Return(&kafkaclient.QuorumInfo{LeaderID: 100, Voters: []kafkaclient.QuorumReplica{
+		{ID: 100, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+		{ID: 101, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+		{ID: 102, LogEndOffset: 5000, LastFetchTimestamp: 10000},
+	}}, nil)
+	if got := r.kRaftQuorumBlockingRollingUpgrade(pods, &pods[2], logf.Log); len(got) != 0 {
+		t.Errorf("expected no controllers blocking the rolling upgrade, got: %v", got)
+	}
+
+	// the readiness of the controllers is checked when the quorum cannot be described
+	mockedKafkaClient.EXPECT().DescribeQuorum().Return(nil, errors.New("unsupported version"))
+	if got := r.kRaftQuorumBlockingRollingUpgrade(pods, &pods[2], logf.Log); !reflect.DeepEqual(got, []string{"101"}) {
+		t.Errorf("expected the not ready controller to block the rolling upgrade, got: %v", got)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+)
+
+// reconcileBrokerReplacement replaces the broker requested with the replace-broker annotation. The reconciliation of
+// the cluster is held until the pod and the persistent volume claims of the broker are deleted, then the broker is
+// recreated on new volumes and once it registered the graceful upscale of the broker re-replicates its partitions.
+func (r *Reconciler) reconcileBrokerReplacement(ctx context.Context, log logr.Logger) error {
+	value := strings.TrimSpace(r.KafkaCluster.GetAnnotations()[v1beta1.ReplaceBrokerAnnotationKey])
+	if value == "" {
+		return nil
+	}
+	brokerID, err := r.replacedBroker(value)
+	if err != nil {
+		// the request is not retried until the annotation is changed
+		log.Error(err, "ignoring the requested replacement of broker", "value", value)
+		return nil
+	}
+	log = log.WithValues(v1beta1.BrokerIdLabelKey, brokerID)
+
+	replacement := r.KafkaCluster.Status.BrokersState[brokerID].Replacement
+	if replacement == nil || replacement.Phase == v1beta1.BrokerReplacementSucceeded {
+		log.Info("replacement of broker requested")
+		now := metav1.Now()
+		replacement = &v1beta1.BrokerReplacementStatus{
+			Phase:              v1beta1.BrokerReplacementDeletingVolumes,
+			StartTime:          now,
+			LastTransitionTime: now,
+		}
+		if err := r.updateBrokerReplacement(brokerID, replacement, log); err != nil {
+			return err
+		}
+	}
+
+	switch replacement.Phase {
+	case v1beta1.BrokerReplacementDeletingVolumes:
+		deleted, err := r.deleteBrokerPodAndVolumes(ctx, brokerID, log)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("broker pod or volumes are being deleted"),
+				"replacing broker", v1beta1.BrokerIdLabelKey, brokerID)
+		}
+		log.Info("broker pod and volumes deleted, recreating the broker")
+		return r.setBrokerReplacementPhase(brokerID, replacement, v1beta1.BrokerReplacementWaitingForBroker, log)
+	case v1beta1.BrokerReplacementWaitingForBroker:
+		registered, err := r.isBrokerRegistered(ctx, brokerID)
+		if err != nil || !registered {
+			return err
+		}
+		if r.KafkaCluster.Status.CruiseControlTopicStatus != v1beta1.CruiseControlTopicReady {
+			log.Info("broker registered, its partitions are re-replicated by the brokers as Cruise Control is not ready")
+			return r.finishBrokerReplacement(brokerID, replacement, log)
+		}
+		log.Info("broker registered, re-replicating its partitions with Cruise Control")
+		err = k8sutil.UpdateBrokerStatus(r.Client, []string{brokerID}, r.KafkaCluster,
+			v1beta1.GracefulActionState{CruiseControlState: v1beta1.GracefulUpscaleRequired}, log)
+		if err != nil {
+			return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update broker graceful action state")
+		}
+		return r.setBrokerReplacementPhase(brokerID, replacement, v1beta1.BrokerReplacementReplicating, log)
+	case v1beta1.BrokerReplacementReplicating:
+		if r.KafkaCluster.Status.BrokersState[brokerID].GracefulActionState.CruiseControlState != v1beta1.GracefulUpscaleSucceeded {
+			return nil
+		}
+		log.Info("partitions of the broker re-replicated")
+		return r.finishBrokerReplacement(brokerID, replacement, log)
+	}
+	return nil
+}
+
+// replacedBroker returns the id of the broker requested to be replaced, it has to be a broker of the spec which is not
+// a KRaft controller as the metadata log of a controller cannot be recreated
+func (r *Reconciler) replacedBroker(value string) (string, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return "", errors.WrapIf(err, "invalid broker id in the replace-broker annotation")
+	}
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		if int(broker.Id) != id {
+			continue
+		}
+		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
+		if err != nil {
+			return "", errors.WrapIf(err, "failed to determine broker config")
+		}
+		if r.KafkaCluster.Spec.KRaftMode && brokerConfig.IsControllerNode() {
+			return "", errors.New("KRaft controllers cannot be replaced")
+		}
+		return value, nil
+	}
+	return "", errors.New("broker is not in the spec")
+}
+
+// deleteBrokerPodAndVolumes deletes the pod and the persistent volume claims of the broker, it tells whether they are
+// gone. The persistent volume claims are only removed once the pod using them is gone.
+func (r *Reconciler) deleteBrokerPodAndVolumes(ctx context.Context, brokerID string, log logr.Logger) (bool, error) {
+	matchingLabels := client.MatchingLabels(
+		apiutil.MergeLabels(
+			apiutil.LabelsForKafka(r.KafkaCluster.Name),
+			map[string]string{v1beta1.BrokerIdLabelKey: brokerID},
+		),
+	)
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(r.KafkaCluster.Namespace), matchingLabels); err != nil {
+		return false, errorfactory.New(errorfactory.APIFailure{}, err, "could not list broker pods")
+	}
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := r.List(ctx, pvcList, client.InNamespace(r.KafkaCluster.Namespace), matchingLabels); err != nil {
+		return false, errorfactory.New(errorfactory.APIFailure{}, err, "could not list broker persistent volume claims")
+	}
+
+	objects := make([]client.Object, 0, len(podList.Items)+len(pvcList.Items))
+	for i := range podList.Items {
+		objects = append(objects, &podList.Items[i])
+	}
+	for i := range pvcList.Items {
+		objects = append(objects, &pvcList.Items[i])
+	}
+	for _, object := range objects {
+		if object.GetDeletionTimestamp() != nil {
+			continue
+		}
+		log.Info("deleting resource of the replaced broker", "name", object.GetName())
+		// the pod is deleted immediately as its node may be gone, which would keep the pod terminating
+		if err := r.Delete(ctx, object, client.GracePeriodSeconds(0)); client.IgnoreNotFound(err) != nil {
+			return false, errorfactory.New(errorfactory.APIFailure{}, err, "could not delete resource of the replaced broker", "name", object.GetName())
+		}
+	}
+	return len(objects) == 0, nil
+}
+
+// isBrokerRegistered tells whether the ready pod of the broker registered the broker in the Kafka cluster
+func (r *Reconciler) isBrokerRegistered(ctx context.Context, brokerID string) (bool, error) {
+	podList := &corev1.PodList{}
+	matchingLabels := client.MatchingLabels(
+		apiutil.MergeLabels(
+			apiutil.LabelsForKafka(r.KafkaCluster.Name),
+			map[string]string{v1beta1.BrokerIdLabelKey: brokerID},
+		),
+	)
+	if err := r.List(ctx, podList, client.InNamespace(r.KafkaCluster.Namespace), matchingLabels); err != nil {
+		return false, errorfactory.New(errorfactory.APIFailure{}, err, "could not list broker pods")
+	}
+	// the readiness of the pod triggers the next reconciliation
+	if len(podList.Items) != 1 || !isPodReady(&podList.Items[0]) {
+		return false, nil
+	}
+
+	kClient, close, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+	if err != nil {
+		return false, errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
+	}
+	defer close()
+	id, err := strconv.Atoi(brokerID)
+	if err != nil {
+		return false, errors.WrapIf(err, "invalid broker id")
+	}
+	if _, ok := kClient.Brokers()[int32(id)]; !ok {
+		return false, errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("broker is not registered yet"),
+			"replacing broker", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	return true, nil
+}
+
+// finishBrokerReplacement removes the replace-broker annotation before recording the replacement as succeeded, so a
+// failed removal is retried
+func (r *Reconciler) finishBrokerReplacement(brokerID string, replacement *v1beta1.BrokerReplacementStatus, log logr.Logger) error {
+	original := r.KafkaCluster.DeepCopy()
+	annotations := r.KafkaCluster.GetAnnotations()
+	delete(annotations, v1beta1.ReplaceBrokerAnnotationKey)
+	r.KafkaCluster.SetAnnotations(annotations)
+	if err := r.Patch(context.TODO(), r.KafkaCluster, client.MergeFrom(original)); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not remove the replace-broker annotation")
+	}
+	log.Info("broker replaced")
+	return r.setBrokerReplacementPhase(brokerID, replacement, v1beta1.BrokerReplacementSucceeded, log)
+}
+
+func (r *Reconciler) setBrokerReplacementPhase(brokerID string, replacement *v1beta1.BrokerReplacementStatus,
+	phase v1beta1.BrokerReplacementPhase, log logr.Logger) error {
+	replacement = replacement.DeepCopy()
+	replacement.Phase = phase
+	replacement.LastTransitionTime = metav1.Now()
+	return r.updateBrokerReplacement(brokerID, replacement, log)
+}
+
+func (r *Reconciler) updateBrokerReplacement(brokerID string, replacement *v1beta1.BrokerReplacementStatus, log logr.Logger) error {
+	if err := k8sutil.UpdateBrokerStatus(r.Client, []string{brokerID}, r.KafkaCluster, replacement, log); err != nil {
+		return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update broker replacement status")
+	}
+	return nil
+}
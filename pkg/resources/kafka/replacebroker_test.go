@@ -0,0 +1,208 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+func TestReconcileBrokerReplacement(t *testing.T) {
+	startTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	replacement := func(phase v1beta1.BrokerReplacementPhase) *v1beta1.BrokerReplacementStatus {
+		return &v1beta1.BrokerReplacementStatus{Phase: phase, StartTime: startTime, LastTransitionTime: startTime}
+	}
+
+	tests := []struct {
+		testName                 string
+		annotation               string
+		replacement              *v1beta1.BrokerReplacementStatus
+		ccState                  v1beta1.CruiseControlState
+		ccTopicReady             bool
+		pods                     []corev1.Pod
+		pvcs                     []corev1.PersistentVolumeClaim
+		registeredBrokers        map[int32]string
+		expectedDeletes          int
+		expectedNotReady         bool
+		expectedPhase            v1beta1.BrokerReplacementPhase
+		expectedCCState          v1beta1.CruiseControlState
+		expectedAnnotationRemove bool
+	}{
+		{
+			testName:         "replacement is requested",
+			annotation:       "1",
+			ccState:          v1beta1.GracefulUpscaleSucceeded,
+			pods:             []corev1.Pod{newCanaryPod("1", true, 0)},
+			pvcs:             []corev1.PersistentVolumeClaim{*createPvc("kafka-1-storage-0", "1", "/kafka-logs")},
+			expectedDeletes:  2,
+			expectedNotReady: true,
+			expectedPhase:    v1beta1.BrokerReplacementDeletingVolumes,
+			expectedCCState:  v1beta1.GracefulUpscaleSucceeded,
+		},
+		{
+			testName:        "pod and volumes are deleted",
+			annotation:      "1",
+			replacement:     replacement(v1beta1.BrokerReplacementDeletingVolumes),
+			ccState:         v1beta1.GracefulUpscaleSucceeded,
+			expectedPhase:   v1beta1.BrokerReplacementWaitingForBroker,
+			expectedCCState: v1beta1.GracefulUpscaleSucceeded,
+		},
+		{
+			testName:        "recreated broker is not ready yet",
+			annotation:      "1",
+			replacement:     replacement(v1beta1.BrokerReplacementWaitingForBroker),
+			ccState:         v1beta1.GracefulUpscaleSucceeded,
+			ccTopicReady:    true,
+			pods:            []corev1.Pod{newCanaryPod("1", false, 0)},
+			expectedPhase:   v1beta1.BrokerReplacementWaitingForBroker,
+			expectedCCState: v1beta1.GracefulUpscaleSucceeded,
+		},
+		{
+			testName:          "recreated broker is not registered yet",
+			annotation:        "1",
+			replacement:       replacement(v1beta1.BrokerReplacementWaitingForBroker),
+			ccState:           v1beta1.GracefulUpscaleSucceeded,
+			ccTopicReady:      true,
+			pods:              []corev1.Pod{newCanaryPod("1", true, 0)},
+			registeredBrokers: map[int32]string{0: "kafka-0:9092"},
+			expectedNotReady:  true,
+			expectedPhase:     v1beta1.BrokerReplacementWaitingForBroker,
+			expectedCCState:   v1beta1.GracefulUpscaleSucceeded,
+		},
+		{
+			testName:          "recreated broker is registered",
+			annotation:        "1",
+			replacement:       replacement(v1beta1.BrokerReplacementWaitingForBroker),
+			ccState:           v1beta1.GracefulUpscaleSucceeded,
+			ccTopicReady:      true,
+			pods:              []corev1.Pod{newCanaryPod("1", true, 0)},
+			registeredBrokers: map[int32]string{0: "kafka-0:9092", 1: "kafka-1:9092"},
+			expectedPhase:     v1beta1.BrokerReplacementReplicating,
+			expectedCCState:   v1beta1.GracefulUpscaleRequired,
+		},
+		{
+			testName:                 "recreated broker is registered without Cruise Control",
+			annotation:               "1",
+			replacement:              replacement(v1beta1.BrokerReplacementWaitingForBroker),
+			ccState:                  v1beta1.GracefulUpscaleSucceeded,
+			pods:                     []corev1.Pod{newCanaryPod("1", true, 0)},
+			registeredBrokers:        map[int32]string{0: "kafka-0:9092", 1: "kafka-1:9092"},
+			expectedPhase:            v1beta1.BrokerReplacementSucceeded,
+			expectedCCState:          v1beta1.GracefulUpscaleSucceeded,
+			expectedAnnotationRemove: true,
+		},
+		{
+			testName:        "partitions are being re-replicated",
+			annotation:      "1",
+			replacement:     replacement(v1beta1.BrokerReplacementReplicating),
+			ccState:         v1beta1.GracefulUpscaleRunning,
+			expectedPhase:   v1beta1.BrokerReplacementReplicating,
+			expectedCCState: v1beta1.GracefulUpscaleRunning,
+		},
+		{
+			testName:                 "partitions are re-replicated",
+			annotation:               "1",
+			replacement:              replacement(v1beta1.BrokerReplacementReplicating),
+			ccState:                  v1beta1.GracefulUpscaleSucceeded,
+			expectedPhase:            v1beta1.BrokerReplacementSucceeded,
+			expectedCCState:          v1beta1.GracefulUpscaleSucceeded,
+			expectedAnnotationRemove: true,
+		},
+		{
+			testName:        "broker is not in the spec",
+			annotation:      "3",
+			ccState:         v1beta1.GracefulUpscaleSucceeded,
+			expectedCCState: v1beta1.GracefulUpscaleSucceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "kafka",
+					Namespace:   "kafka",
+					Annotations: map[string]string{v1beta1.ReplaceBrokerAnnotationKey: test.annotation},
+				},
+				Spec: v1beta1.KafkaClusterSpec{Brokers: []v1beta1.Broker{{Id: 0}, {Id: 1}}},
+				Status: v1beta1.KafkaClusterStatus{
+					BrokersState: map[string]v1beta1.BrokerState{
+						"1": {
+							GracefulActionState: v1beta1.GracefulActionState{CruiseControlState: test.ccState},
+							Replacement:         test.replacement,
+						},
+					},
+				},
+			}
+			if test.ccTopicReady {
+				cluster.Status.CruiseControlTopicStatus = v1beta1.CruiseControlTopicReady
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			mockKafkaClientProvider := new(kafkaclient.MockedProvider)
+			r := newStatusUpdatingReconciler(mockCtrl, mockClient, cluster, mockKafkaClientProvider)
+			mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any(), gomock.Any()).Do(
+				func(ctx context.Context, list *corev1.PodList, opts ...client.ListOption) {
+					list.Items = test.pods
+				}).Return(nil).AnyTimes()
+			mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaimList{}), gomock.Any(), gomock.Any()).Do(
+				func(ctx context.Context, list *corev1.PersistentVolumeClaimList, opts ...client.ListOption) {
+					list.Items = test.pvcs
+				}).Return(nil).AnyTimes()
+			mockClient.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(test.expectedDeletes)
+			if test.registeredBrokers != nil {
+				mockedKafkaClient := mocks.NewMockKafkaClient(mockCtrl)
+				mockedKafkaClient.EXPECT().Brokers().Return(test.registeredBrokers)
+				mockKafkaClientProvider.On("NewFromCluster", mockClient, cluster).Return(mockedKafkaClient, func() {}, nil)
+			}
+			if test.expectedAnnotationRemove {
+				mockClient.EXPECT().Patch(context.TODO(), gomock.AssignableToTypeOf(&v1beta1.KafkaCluster{}), gomock.Any()).Return(nil)
+			}
+
+			err := r.reconcileBrokerReplacement(context.Background(), logf.Log)
+			if test.expectedNotReady {
+				require.True(t, errors.As(err, &errorfactory.ResourceNotReady{}))
+			} else {
+				require.NoError(t, err)
+			}
+
+			brokerState := r.KafkaCluster.Status.BrokersState["1"]
+			if test.expectedPhase == "" {
+				require.Nil(t, brokerState.Replacement)
+			} else {
+				require.NotNil(t, brokerState.Replacement)
+				require.Equal(t, test.expectedPhase, brokerState.Replacement.Phase)
+			}
+			require.Equal(t, test.expectedCCState, brokerState.GracefulActionState.CruiseControlState)
+			if test.expectedAnnotationRemove {
+				require.NotContains(t, r.KafkaCluster.GetAnnotations(), v1beta1.ReplaceBrokerAnnotationKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+)
+
+const (
+	brokerRestartedReason = "BrokerRestarted"
+	brokerRestartAction   = "RestartBroker"
+
+	// maxRestartDiffFields is the number of changed fields listed in the diff of a restart
+	maxRestartDiffFields = 5
+)
+
+// brokerRestart describes why the broker pod is deleted by the rolling upgrade
+func (r *Reconciler) brokerRestart(currentPod *corev1.Pod, patchResult *patch.PatchResult, tainted bool) v1beta1.BrokerRestart {
+	restart := v1beta1.BrokerRestart{Time: metav1.Now()}
+	if patchResult != nil && !patchResult.IsEmpty() {
+		restart.Diff = podPatchSummary(patchResult.Patch)
+	}
+
+	switch {
+	case tainted:
+		restart.Trigger = v1beta1.BrokerRestartTainted
+	case k8sutil.IsPodContainsEvictedContainer(currentPod):
+		restart.Trigger = v1beta1.BrokerRestartPodEvicted
+	case k8sutil.IsPodContainsShutdownContainer(currentPod):
+		restart.Trigger = v1beta1.BrokerRestartNodeShutdown
+	case k8sutil.IsPodContainsTerminatedContainer(currentPod):
+		restart.Trigger = v1beta1.BrokerRestartContainerTerminated
+	case r.restartRequested(currentPod):
+		restart.Trigger = v1beta1.BrokerRestartRequested
+	case r.fileSystemResizeRequiresRestart(currentPod):
+		restart.Trigger = v1beta1.BrokerRestartFileSystemResize
+	case restart.Diff == "" && r.KafkaCluster.Status.BrokersState[currentPod.Labels[v1beta1.BrokerIdLabelKey]].ConfigurationState != v1beta1.ConfigInSync:
+		restart.Trigger = v1beta1.BrokerRestartConfigOutOfSync
+	default:
+		restart.Trigger = v1beta1.BrokerRestartPodSpecChanged
+	}
+	return restart
+}
+
+// recordBrokerRestart adds the restart to the restart history of the broker and emits an event of it
+func (r *Reconciler) recordBrokerRestart(currentPod *corev1.Pod, restart v1beta1.BrokerRestart, log logr.Logger) error {
+	brokerID := currentPod.Labels[v1beta1.BrokerIdLabelKey]
+	if r.Recorder != nil {
+		note := fmt.Sprintf("broker %s is restarted, trigger: %s", brokerID, restart.Trigger)
+		if restart.Diff != "" {
+			note += ", changed: " + restart.Diff
+		}
+		r.Recorder.Eventf(r.KafkaCluster, currentPod, corev1.EventTypeNormal, brokerRestartedReason, brokerRestartAction, "%s", note)
+	}
+	if err := k8sutil.UpdateBrokerStatus(r.Client, []string{brokerID}, r.KafkaCluster, restart, log); err != nil {
+		return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update broker restart history", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	return nil
+}
+
+// containerTerminations counts the terminations of the broker container since the given time. The broker pods never
+// restart their containers, the operator recreates the pod of a terminated container instead. The recreations recorded
+// in the restart history with the ContainerTerminated trigger are counted together with the containers of the current
+// pods which terminated and are not restarted yet.
+func (r *Reconciler) containerTerminations(pods []corev1.Pod, brokerID string, since metav1.Time) int32 {
+	// the times in the status have a precision of seconds
+	start := since.Time.Truncate(time.Second)
+	var terminations int32
+	for _, restart := range r.KafkaCluster.Status.BrokersState[brokerID].RestartHistory {
+		if restart.Trigger == v1beta1.BrokerRestartContainerTerminated && !restart.Time.Time.Before(start) {
+			terminations++
+		}
+	}
+	for i := range pods {
+		pod := &pods[i]
+		// the terminated containers of a pod being deleted are already recorded in the restart history
+		if pod.Labels[v1beta1.BrokerIdLabelKey] != brokerID || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && !status.State.Terminated.FinishedAt.Time.Before(start) {
+				terminations++
+			}
+		}
+	}
+	return terminations
+}
+
+// podPatchSummary lists the paths of the fields changed by the strategic merge patch of a pod, the containers and
+// other named list items are referred to by their names
+func podPatchSummary(podPatch []byte) string {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(podPatch, &fields); err != nil {
+		return ""
+	}
+	var paths []string
+	collectPatchPaths("", fields, &paths)
+	sort.Strings(paths)
+	paths = slices.Compact(paths)
+	if len(paths) > maxRestartDiffFields {
+		return fmt.Sprintf("%s and %d more", strings.Join(paths[:maxRestartDiffFields], ", "), len(paths)-maxRestartDiffFields)
+	}
+	return strings.Join(paths, ", ")
+}
+
+func collectPatchPaths(prefix string, value interface{}, paths *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			// directives of the strategic merge patch like $setElementOrder and $patch
+			if strings.HasPrefix(key, "$") {
+				continue
+			}
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			collectPatchPaths(path, field, paths)
+		}
+	case []interface{}:
+		if !namedListItems(v) {
+			*paths = append(*paths, prefix)
+			return
+		}
+		for _, item := range v {
+			fields := item.(map[string]interface{})
+			path := fmt.Sprintf("%s[%s]", prefix, fields["name"])
+			if _, ok := fields["$patch"]; ok {
+				// the item is deleted or replaced
+				*paths = append(*paths, path)
+				continue
+			}
+			for key, field := range fields {
+				if key == "name" || strings.HasPrefix(key, "$") {
+					continue
+				}
+				collectPatchPaths(path+"."+key, field, paths)
+			}
+		}
+	default:
+		*paths = append(*paths, prefix)
+	}
+}
+
+// namedListItems tells whether every item of a list is an object with a name, so the items are referred to by
+// their names instead of the whole list
+func namedListItems(items []interface{}) bool {
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := fields["name"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
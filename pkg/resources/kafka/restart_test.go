@@ -0,0 +1,195 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+func TestPodPatchSummary(t *testing.T) {
+	tests := []struct {
+		testName string
+		patch    string
+		expected string
+	}{
+		{
+			testName: "container image changed",
+			patch:    `{"spec":{"$setElementOrder/containers":[{"name":"kafka"}],"containers":[{"image":"kafka:3.9","name":"kafka"}]}}`,
+			expected: "spec.containers[kafka].image",
+		},
+		{
+			testName: "container removed and labels changed",
+			patch:    `{"metadata":{"labels":{"app":"kafka"}},"spec":{"containers":[{"$patch":"delete","name":"sidecar"}]}}`,
+			expected: "metadata.labels.app, spec.containers[sidecar]",
+		},
+		{
+			testName: "list of values changed",
+			patch:    `{"spec":{"containers":[{"args":["--debug"],"name":"kafka"}]}}`,
+			expected: "spec.containers[kafka].args",
+		},
+		{
+			testName: "list items without names changed",
+			patch:    `{"spec":{"tolerations":[{"name":"a","value":"1"},{"key":"b","value":"2"}]}}`,
+			expected: "spec.tolerations",
+		},
+		{
+			testName: "too many fields changed",
+			patch:    `{"metadata":{"annotations":{"a":"1","b":"2","c":"3","d":"4","e":"5","f":"6","g":"7"}}}`,
+			expected: "metadata.annotations.a, metadata.annotations.b, metadata.annotations.c, metadata.annotations.d, metadata.annotations.e and 2 more",
+		},
+		{
+			testName: "invalid patch",
+			patch:    `{`,
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			require.Equal(t, test.expected, podPatchSummary([]byte(test.patch)))
+		})
+	}
+}
+
+func TestBrokerRestart(t *testing.T) {
+	imagePatch := &patch.PatchResult{Patch: []byte(`{"spec":{"containers":[{"image":"kafka:3.9","name":"kafka"}]}}`)}
+	emptyPatch := &patch.PatchResult{Patch: []byte(`{}`)}
+
+	tests := []struct {
+		testName        string
+		podStatus       corev1.PodStatus
+		patchResult     *patch.PatchResult
+		tainted         bool
+		configState     v1beta1.ConfigurationState
+		expectedTrigger v1beta1.BrokerRestartTrigger
+		expectedDiff    string
+	}{
+		{
+			testName:        "pod spec changed",
+			patchResult:     imagePatch,
+			configState:     v1beta1.ConfigInSync,
+			expectedTrigger: v1beta1.BrokerRestartPodSpecChanged,
+			expectedDiff:    "spec.containers[kafka].image",
+		},
+		{
+			testName:        "configuration out of sync",
+			patchResult:     emptyPatch,
+			configState:     v1beta1.ConfigOutOfSync,
+			expectedTrigger: v1beta1.BrokerRestartConfigOutOfSync,
+		},
+		{
+			testName:        "pod spec changed while configuration out of sync",
+			patchResult:     imagePatch,
+			configState:     v1beta1.ConfigOutOfSync,
+			expectedTrigger: v1beta1.BrokerRestartPodSpecChanged,
+			expectedDiff:    "spec.containers[kafka].image",
+		},
+		{
+			testName:        "broker matched by the tainted brokers selector",
+			patchResult:     imagePatch,
+			tainted:         true,
+			configState:     v1beta1.ConfigInSync,
+			expectedTrigger: v1beta1.BrokerRestartTainted,
+			expectedDiff:    "spec.containers[kafka].image",
+		},
+		{
+			testName:        "pod evicted",
+			podStatus:       corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"},
+			configState:     v1beta1.ConfigInSync,
+			expectedTrigger: v1beta1.BrokerRestartPodEvicted,
+		},
+		{
+			testName:        "node shutdown",
+			podStatus:       corev1.PodStatus{Phase: corev1.PodFailed, Reason: "NodeShutdown"},
+			configState:     v1beta1.ConfigInSync,
+			expectedTrigger: v1beta1.BrokerRestartNodeShutdown,
+		},
+		{
+			testName: "container terminated",
+			podStatus: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error"}},
+			}}},
+			configState:     v1beta1.ConfigInSync,
+			expectedTrigger: v1beta1.BrokerRestartContainerTerminated,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			r := New(nil, nil, &v1beta1.KafkaCluster{
+				Status: v1beta1.KafkaClusterStatus{BrokersState: map[string]v1beta1.BrokerState{
+					"0": {ConfigurationState: test.configState},
+				}},
+			}, new(kafkaclient.MockedProvider))
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1beta1.BrokerIdLabelKey: "0"}},
+				Status:     test.podStatus,
+			}
+
+			restart := r.brokerRestart(pod, test.patchResult, test.tainted)
+			require.Equal(t, test.expectedTrigger, restart.Trigger)
+			require.Equal(t, test.expectedDiff, restart.Diff)
+			require.False(t, restart.Time.IsZero())
+		})
+	}
+}
+
+func TestRecordBrokerRestart(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		Status: v1beta1.KafkaClusterStatus{BrokersState: map[string]v1beta1.BrokerState{"0": {}}},
+	}
+	mockCtrl := gomock.NewController(t)
+	mockClient := mocks.NewMockClient(mockCtrl)
+	recorder := events.NewFakeRecorder(20)
+	r := newStatusUpdatingReconciler(mockCtrl, mockClient, cluster, new(kafkaclient.MockedProvider))
+	r.Recorder = recorder
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1beta1.BrokerIdLabelKey: "0"}}}
+	restart := v1beta1.BrokerRestart{
+		Time:    metav1.Now(),
+		Trigger: v1beta1.BrokerRestartPodSpecChanged,
+		Diff:    "spec.containers[kafka].image",
+	}
+	require.NoError(t, r.recordBrokerRestart(pod, restart, logf.Log))
+	require.Equal(t, []v1beta1.BrokerRestart{restart}, r.KafkaCluster.Status.BrokersState["0"].RestartHistory)
+	require.Equal(t, "Normal BrokerRestarted broker 0 is restarted, trigger: PodSpecChanged, changed: spec.containers[kafka].image",
+		<-recorder.Events)
+
+	// the history keeps the latest restarts only
+	for i := 0; i < 15; i++ {
+		require.NoError(t, r.recordBrokerRestart(pod, v1beta1.BrokerRestart{
+			Time:    metav1.Now(),
+			Trigger: v1beta1.BrokerRestartConfigOutOfSync,
+			Diff:    strconv.Itoa(i),
+		}, logf.Log))
+	}
+	history := r.KafkaCluster.Status.BrokersState["0"].RestartHistory
+	require.Len(t, history, 10)
+	require.Equal(t, "5", history[0].Diff)
+	require.Equal(t, "14", history[9].Diff)
+}
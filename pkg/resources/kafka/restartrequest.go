@@ -0,0 +1,97 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+// reconcileRestartRequest records the restart of brokers requested with the restart-brokers annotation, the rolling
+// upgrade restarts the requested brokers. The annotation is removed once every requested broker runs in a pod created
+// since the request and it is ready.
+func (r *Reconciler) reconcileRestartRequest(pods []corev1.Pod, log logr.Logger) error {
+	value := r.KafkaCluster.GetAnnotations()[v1beta1.RestartBrokersAnnotationKey]
+	request := r.KafkaCluster.Status.RestartRequest
+	if value == "" {
+		if request != nil {
+			return r.updateRestartRequest(nil, log)
+		}
+		return nil
+	}
+
+	brokerIDs, err := util.RequestedRestartBrokers(r.KafkaCluster)
+	if err != nil {
+		// the request is not retried until the annotation is changed
+		log.Error(err, "ignoring the requested restart of brokers")
+		return nil
+	}
+	if request == nil || request.Brokers != value {
+		log.Info("restart of brokers requested", "brokers", value)
+		// the creation timestamp of the pods has a precision of seconds
+		return r.updateRestartRequest(&v1beta1.RestartRequestStatus{
+			Brokers:     value,
+			RequestTime: metav1.NewTime(time.Now().Truncate(time.Second)),
+		}, log)
+	}
+
+	for _, brokerID := range brokerIDs {
+		pod := brokerPod(pods, brokerID)
+		if pod == nil || pod.CreationTimestamp.Before(&request.RequestTime) || !isPodReady(pod) {
+			return nil
+		}
+	}
+
+	log.Info("requested restart of brokers finished", "brokers", value)
+	original := r.KafkaCluster.DeepCopy()
+	annotations := r.KafkaCluster.GetAnnotations()
+	delete(annotations, v1beta1.RestartBrokersAnnotationKey)
+	r.KafkaCluster.SetAnnotations(annotations)
+	if err := r.Patch(context.TODO(), r.KafkaCluster, client.MergeFrom(original)); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not remove the restart-brokers annotation")
+	}
+	return r.updateRestartRequest(nil, log)
+}
+
+// restartRequested tells whether the restart of the broker is requested and its pod was created before the request
+func (r *Reconciler) restartRequested(pod *corev1.Pod) bool {
+	request := r.KafkaCluster.Status.RestartRequest
+	if request == nil || request.Brokers != r.KafkaCluster.GetAnnotations()[v1beta1.RestartBrokersAnnotationKey] {
+		return false
+	}
+	brokerIDs, err := util.RequestedRestartBrokers(r.KafkaCluster)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(brokerIDs, pod.Labels[v1beta1.BrokerIdLabelKey]) && pod.CreationTimestamp.Before(&request.RequestTime)
+}
+
+func (r *Reconciler) updateRestartRequest(request *v1beta1.RestartRequestStatus, log logr.Logger) error {
+	if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, request, log); err != nil {
+		return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update restart request status")
+	}
+	return nil
+}
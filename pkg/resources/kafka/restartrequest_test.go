@@ -0,0 +1,127 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+func TestReconcileRestartRequest(t *testing.T) {
+	requestTime := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	request := &v1beta1.RestartRequestStatus{Brokers: "0", RequestTime: requestTime}
+
+	tests := []struct {
+		testName                 string
+		annotation               string
+		request                  *v1beta1.RestartRequestStatus
+		pods                     []corev1.Pod
+		expectedRequest          *v1beta1.RestartRequestStatus
+		expectedRecorded         bool
+		expectedAnnotationRemove bool
+		expectedRestartRequested bool
+	}{
+		{
+			testName:                 "restart is requested",
+			annotation:               "0",
+			pods:                     []corev1.Pod{newRollbackPod(requestTime.Add(-time.Hour), true, 0)},
+			expectedRecorded:         true,
+			expectedRestartRequested: true,
+		},
+		{
+			testName:                 "requested brokers are changed",
+			annotation:               "all",
+			request:                  request,
+			pods:                     []corev1.Pod{newRollbackPod(requestTime.Add(-time.Hour), true, 0)},
+			expectedRecorded:         true,
+			expectedRestartRequested: true,
+		},
+		{
+			testName:                 "broker is not restarted yet",
+			annotation:               "0",
+			request:                  request,
+			pods:                     []corev1.Pod{newRollbackPod(requestTime.Add(-time.Hour), true, 0)},
+			expectedRequest:          request,
+			expectedRestartRequested: true,
+		},
+		{
+			testName:        "restarted broker is not ready yet",
+			annotation:      "0",
+			request:         request,
+			pods:            []corev1.Pod{newRollbackPod(requestTime.Add(time.Second), false, 0)},
+			expectedRequest: request,
+		},
+		{
+			testName:                 "restart finished",
+			annotation:               "0",
+			request:                  request,
+			pods:                     []corev1.Pod{newRollbackPod(requestTime.Time, true, 0)},
+			expectedAnnotationRemove: true,
+		},
+		{
+			testName: "annotation removed by the user",
+			request:  request,
+			pods:     []corev1.Pod{newRollbackPod(requestTime.Add(-time.Hour), true, 0)},
+		},
+		{
+			testName:   "invalid request",
+			annotation: "zero",
+			pods:       []corev1.Pod{newRollbackPod(requestTime.Add(-time.Hour), true, 0)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				Spec:   v1beta1.KafkaClusterSpec{Brokers: []v1beta1.Broker{{Id: 0}}},
+				Status: v1beta1.KafkaClusterStatus{RestartRequest: test.request},
+			}
+			if test.annotation != "" {
+				cluster.SetAnnotations(map[string]string{v1beta1.RestartBrokersAnnotationKey: test.annotation})
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			r := newStatusUpdatingReconciler(mockCtrl, mockClient, cluster, new(kafkaclient.MockedProvider))
+			if test.expectedAnnotationRemove {
+				mockClient.EXPECT().Patch(context.TODO(), gomock.AssignableToTypeOf(&v1beta1.KafkaCluster{}), gomock.Any()).Return(nil)
+			}
+
+			require.NoError(t, r.reconcileRestartRequest(test.pods, logf.Log))
+
+			if test.expectedRecorded {
+				require.NotNil(t, r.KafkaCluster.Status.RestartRequest)
+				require.Equal(t, test.annotation, r.KafkaCluster.Status.RestartRequest.Brokers)
+				require.True(t, r.KafkaCluster.Status.RestartRequest.RequestTime.After(requestTime.Time))
+			} else {
+				require.Equal(t, test.expectedRequest, r.KafkaCluster.Status.RestartRequest)
+			}
+			if test.expectedAnnotationRemove {
+				require.NotContains(t, r.KafkaCluster.GetAnnotations(), v1beta1.RestartBrokersAnnotationKey)
+			}
+			require.Equal(t, test.expectedRestartRequested, r.restartRequested(&test.pods[0]))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	banzaiv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+const (
+	// volumeSnapshotAPIGroup is the API group of the CSI volume snapshots
+	volumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+	// volumeSnapshotKind is the kind of the CSI volume snapshots
+	volumeSnapshotKind = "VolumeSnapshot"
+)
+
+// restoreDataSource returns the data source a new PVC of a broker volume is restored from, which is the volume snapshot
+// the backup of the cluster took of the volume with the same broker id and mount path. The volume is created empty when
+// the cluster is not restored, the backup has no snapshot of it or the volume is recreated to replace the broker or
+// its offline log directory.
+func (r *Reconciler) restoreDataSource(ctx context.Context, log logr.Logger, brokerID, mountPath string) (*corev1.TypedLocalObjectReference, error) {
+	backupName := r.KafkaCluster.Spec.RestoreFromBackup
+	if backupName == "" {
+		return nil, nil
+	}
+	// the replaced broker starts on empty volumes and gets its partitions re-replicated
+	if replacement := r.KafkaCluster.Status.BrokersState[brokerID].Replacement; replacement != nil &&
+		replacement.Phase != banzaiv1beta1.BrokerReplacementSucceeded {
+		return nil, nil
+	}
+	if r.isRecreatedVolume(brokerID, mountPath) {
+		return nil, nil
+	}
+
+	backup, err := r.getRestoreBackup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range backup.Status.VolumeSnapshots {
+		if util.ConvertStringToInt32(brokerID) == snapshot.BrokerID && snapshot.MountPath == mountPath {
+			log.Info("restoring broker volume from volume snapshot", banzaiv1beta1.BrokerIdLabelKey, brokerID,
+				mountPathAnnotationKey, mountPath, "backup", backupName, "volumeSnapshot", snapshot.VolumeSnapshotName)
+			return &corev1.TypedLocalObjectReference{
+				APIGroup: util.StringPointer(volumeSnapshotAPIGroup),
+				Kind:     volumeSnapshotKind,
+				Name:     snapshot.VolumeSnapshotName,
+			}, nil
+		}
+	}
+	log.Info("backup to restore from has no volume snapshot of broker volume", banzaiv1beta1.BrokerIdLabelKey, brokerID,
+		mountPathAnnotationKey, mountPath, "backup", backupName)
+	return nil, nil
+}
+
+// restoreClusterID returns the id of the KRaft cluster the backup of the cluster was taken of, the restored log dirs
+// carry it in their meta.properties. Restoring a backup without a cluster id is refused.
+func (r *Reconciler) restoreClusterID(ctx context.Context) (string, error) {
+	backup, err := r.getRestoreBackup(ctx)
+	if err != nil {
+		return "", err
+	}
+	if backup.Status.ClusterID == "" {
+		return "", errorfactory.New(errorfactory.InternalError{}, errors.New("backup has no cluster id"),
+			"the cluster cannot be restored from the backup", "name", backup.Name)
+	}
+	return backup.Status.ClusterID, nil
+}
+
+// getRestoreBackup returns the succeeded backup the cluster is restored from
+func (r *Reconciler) getRestoreBackup(ctx context.Context) (*v1alpha1.KafkaClusterBackup, error) {
+	backupName := r.KafkaCluster.Spec.RestoreFromBackup
+	backup := &v1alpha1.KafkaClusterBackup{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: r.KafkaCluster.Namespace, Name: backupName}, backup); err != nil {
+		return nil, errorfactory.New(errorfactory.APIFailure{}, err, "getting backup to restore from failed", "name", backupName)
+	}
+	if backup.Status.Phase != v1alpha1.BackupSucceeded {
+		return nil, errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("backup has not succeeded"),
+			"waiting for backup to restore from", "name", backupName, "phase", backup.Status.Phase)
+	}
+	return backup, nil
+}
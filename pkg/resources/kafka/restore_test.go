@@ -0,0 +1,175 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+func TestRestoreDataSource(t *testing.T) {
+	tests := []struct {
+		testName           string
+		restoreFromBackup  string
+		phase              v1alpha1.KafkaClusterBackupPhase
+		brokerID           string
+		replacementPhase   v1beta1.BrokerReplacementPhase
+		expectedDataSource *corev1.TypedLocalObjectReference
+		expectedNotReady   bool
+	}{
+		{
+			testName: "cluster is not restored",
+			brokerID: "0",
+		},
+		{
+			testName:          "volume is restored from the snapshot of the same broker and mount path",
+			restoreFromBackup: "backup",
+			phase:             v1alpha1.BackupSucceeded,
+			brokerID:          "1",
+			expectedDataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: util.StringPointer(volumeSnapshotAPIGroup),
+				Kind:     volumeSnapshotKind,
+				Name:     "backup-kafka-1-storage-0-abcde",
+			},
+		},
+		{
+			testName:          "volume of the replaced broker is not restored",
+			restoreFromBackup: "backup",
+			phase:             v1alpha1.BackupSucceeded,
+			brokerID:          "1",
+			replacementPhase:  v1beta1.BrokerReplacementWaitingForBroker,
+		},
+		{
+			testName:          "volume of a broker replaced before is restored",
+			restoreFromBackup: "backup",
+			phase:             v1alpha1.BackupSucceeded,
+			brokerID:          "1",
+			replacementPhase:  v1beta1.BrokerReplacementSucceeded,
+			expectedDataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: util.StringPointer(volumeSnapshotAPIGroup),
+				Kind:     volumeSnapshotKind,
+				Name:     "backup-kafka-1-storage-0-abcde",
+			},
+		},
+		{
+			testName:          "backup has no snapshot of the volume",
+			restoreFromBackup: "backup",
+			phase:             v1alpha1.BackupSucceeded,
+			brokerID:          "2",
+		},
+		{
+			testName:          "backup has not succeeded yet",
+			restoreFromBackup: "backup",
+			phase:             v1alpha1.BackupSnapshotting,
+			brokerID:          "1",
+			expectedNotReady:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Spec:       v1beta1.KafkaClusterSpec{RestoreFromBackup: test.restoreFromBackup},
+			}
+			if test.replacementPhase != "" {
+				cluster.Status.BrokersState = map[string]v1beta1.BrokerState{
+					test.brokerID: {Replacement: &v1beta1.BrokerReplacementStatus{Phase: test.replacementPhase}},
+				}
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			mockClient.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "kafka", Name: "backup"}, gomock.AssignableToTypeOf(&v1alpha1.KafkaClusterBackup{})).Do(
+				func(ctx context.Context, key client.ObjectKey, backup *v1alpha1.KafkaClusterBackup, opts ...client.GetOption) {
+					backup.Status = v1alpha1.KafkaClusterBackupStatus{
+						Phase: test.phase,
+						VolumeSnapshots: []v1alpha1.BrokerVolumeSnapshot{
+							{BrokerID: 0, MountPath: "/kafka-logs", VolumeSnapshotName: "backup-kafka-0-storage-0-fghij"},
+							{BrokerID: 1, MountPath: "/kafka-logs", VolumeSnapshotName: "backup-kafka-1-storage-0-abcde"},
+							{BrokerID: 1, MountPath: "/kafka-logs-2", VolumeSnapshotName: "backup-kafka-1-storage-1-pqrst"},
+						},
+					}
+				}).Return(nil).AnyTimes()
+			r := New(mockClient, nil, cluster, new(kafkaclient.MockedProvider))
+
+			dataSource, err := r.restoreDataSource(context.Background(), logf.Log, test.brokerID, "/kafka-logs")
+			if test.expectedNotReady {
+				require.True(t, errors.As(err, &errorfactory.ResourceNotReady{}))
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.expectedDataSource, dataSource)
+		})
+	}
+}
+
+func TestRestoreClusterID(t *testing.T) {
+	tests := []struct {
+		testName          string
+		clusterID         string
+		expectedClusterID string
+		expectedError     bool
+	}{
+		{
+			testName:          "cluster is restored with the id of the cluster of the backup",
+			clusterID:         "Rb3Vd1LbRk2uSSOLRkvxBw",
+			expectedClusterID: "Rb3Vd1LbRk2uSSOLRkvxBw",
+		},
+		{
+			testName:      "backup without cluster id is refused",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Spec:       v1beta1.KafkaClusterSpec{RestoreFromBackup: "backup"},
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			mockClient.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "kafka", Name: "backup"}, gomock.AssignableToTypeOf(&v1alpha1.KafkaClusterBackup{})).Do(
+				func(ctx context.Context, key client.ObjectKey, backup *v1alpha1.KafkaClusterBackup, opts ...client.GetOption) {
+					backup.Name = key.Name
+					backup.Status = v1alpha1.KafkaClusterBackupStatus{Phase: v1alpha1.BackupSucceeded, ClusterID: test.clusterID}
+				}).Return(nil)
+			r := New(mockClient, nil, cluster, new(kafkaclient.MockedProvider))
+
+			clusterID, err := r.restoreClusterID(context.Background())
+			if test.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.expectedClusterID, clusterID)
+		})
+	}
+}
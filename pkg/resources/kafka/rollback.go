@@ -0,0 +1,222 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+const (
+	configurationRolledBackReason = "ConfigurationRolledBack"
+	configurationChangedReason    = "ConfigurationChanged"
+)
+
+// reconcileConfigurationRollouts verifies the brokers restarted with a changed configuration. A broker which became
+// ready makes its configuration the new backup, a broker which crash-loops or does not become ready in time is rolled
+// back to its backup. It returns the brokers to render from their configuration backup.
+func (r *Reconciler) reconcileConfigurationRollouts(pods []corev1.Pod, log logr.Logger) (map[int32]v1beta1.Broker, error) {
+	config := r.KafkaCluster.Spec.RollingUpgradeConfig.ConfigRollback
+	rolledBack := make(map[int32]v1beta1.Broker)
+	var changed bool
+
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		brokerID := strconv.Itoa(int(broker.Id))
+		state := r.KafkaCluster.Status.BrokersState[brokerID]
+		rollout := state.ConfigurationRollout
+		if rollout == nil {
+			continue
+		}
+
+		var revision, backup string
+		if config != nil {
+			var err error
+			backup, err = util.GzipAndBase64ResolvedBrokerConfiguration(&broker, r.KafkaCluster.Spec)
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "could not generate broker configuration backup", v1beta1.BrokerIdLabelKey, broker.Id)
+			}
+			revision = util.BrokerConfigurationRevision(backup)
+		}
+		if rollout.Revision != revision {
+			// the configuration of the broker changed since, the rolling upgrade restarts the broker with it
+			log.Info("configuration of the broker changed, its previous rollout is dropped",
+				v1beta1.BrokerIdLabelKey, brokerID, "revision", rollout.Revision, "phase", rollout.Phase)
+			if err := r.updateConfigurationRollout(brokerID, nil, log); err != nil {
+				return nil, err
+			}
+			changed = changed || rollout.Phase == v1beta1.ConfigurationRolloutRolledBack
+			continue
+		}
+
+		switch rollout.Phase {
+		case v1beta1.ConfigurationRolloutRolledBack:
+			backupBroker, err := util.GetBrokerFromBrokerConfigurationBackup(state.ConfigurationBackup)
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "could not restore broker configuration from backup", v1beta1.BrokerIdLabelKey, brokerID)
+			}
+			rolledBack[broker.Id] = backupBroker
+		case v1beta1.ConfigurationRolloutVerifying:
+			pod := restartedBrokerPod(pods, brokerID, rollout.StartTime)
+			if pod != nil && isPodReady(pod) {
+				log.Info("broker became ready with its changed configuration", v1beta1.BrokerIdLabelKey, brokerID, "revision", revision)
+				if err := k8sutil.UpdateBrokerStatus(r.Client, []string{brokerID}, r.KafkaCluster, k8sutil.BrokerConfigurationBackup(backup), log); err != nil {
+					return nil, errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update broker configuration backup", v1beta1.BrokerIdLabelKey, brokerID)
+				}
+				continue
+			}
+
+			var message string
+			if terminations := r.containerTerminations(pods, brokerID, rollout.StartTime); terminations >= config.GetMaxContainerRestarts() {
+				message = fmt.Sprintf("the broker container terminated %d times", terminations)
+			} else if time.Since(rollout.StartTime.Time) > config.GetReadyTimeout() {
+				message = fmt.Sprintf("the broker did not become ready in %s", config.GetReadyTimeout())
+			}
+			if message == "" {
+				continue
+			}
+
+			backupBroker, err := util.GetBrokerFromBrokerConfigurationBackup(state.ConfigurationBackup)
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "could not restore broker configuration from backup", v1beta1.BrokerIdLabelKey, brokerID)
+			}
+			log.Info("rolling back the configuration of the broker, halting the rolling upgrade",
+				v1beta1.BrokerIdLabelKey, brokerID, "failedRevision", revision, "reason", message)
+			rollout = rollout.DeepCopy()
+			rollout.Phase = v1beta1.ConfigurationRolloutRolledBack
+			rollout.Message = message
+			if err := r.updateConfigurationRollout(brokerID, rollout, log); err != nil {
+				return nil, err
+			}
+			condition := metav1.Condition{
+				Type:   v1beta1.RollingUpgradeHaltedCondition,
+				Status: metav1.ConditionTrue,
+				Reason: configurationRolledBackReason,
+				Message: fmt.Sprintf("broker %s is rolled back to its previous configuration, revision %s failed: %s",
+					brokerID, revision, message),
+				ObservedGeneration: r.KafkaCluster.Generation,
+			}
+			if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, condition, log); err != nil {
+				return nil, errors.WrapIf(err, "could not update rolling upgrade condition")
+			}
+			rolledBack[broker.Id] = backupBroker
+		}
+	}
+
+	if changed && len(rolledBack) == 0 {
+		halted := meta.FindStatusCondition(r.KafkaCluster.Status.Conditions, v1beta1.RollingUpgradeHaltedCondition)
+		if halted != nil && halted.Reason == configurationRolledBackReason {
+			condition := metav1.Condition{
+				Type:               v1beta1.RollingUpgradeHaltedCondition,
+				Status:             metav1.ConditionFalse,
+				Reason:             configurationChangedReason,
+				Message:            "the configuration of the rolled back brokers changed",
+				ObservedGeneration: r.KafkaCluster.Generation,
+			}
+			if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, condition, log); err != nil {
+				return nil, errors.WrapIf(err, "could not update rolling upgrade condition")
+			}
+		}
+	}
+	return rolledBack, nil
+}
+
+// startConfigurationRollout starts verifying the broker when its pod is restarted with a changed configuration. Only
+// the broker configuration is compared, a pod restarted for a change of the cluster-level settings is not verified.
+func (r *Reconciler) startConfigurationRollout(currentPod *corev1.Pod, log logr.Logger) error {
+	if r.KafkaCluster.Spec.RollingUpgradeConfig.ConfigRollback == nil {
+		return nil
+	}
+	brokerID := currentPod.Labels[v1beta1.BrokerIdLabelKey]
+	state, ok := r.KafkaCluster.Status.BrokersState[brokerID]
+	if !ok || state.ConfigurationBackup == "" {
+		return nil
+	}
+	i := slices.IndexFunc(r.KafkaCluster.Spec.Brokers, func(broker v1beta1.Broker) bool {
+		return strconv.Itoa(int(broker.Id)) == brokerID
+	})
+	if i < 0 {
+		return nil
+	}
+
+	backup, err := util.GzipAndBase64ResolvedBrokerConfiguration(&r.KafkaCluster.Spec.Brokers[i], r.KafkaCluster.Spec)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not generate broker configuration backup", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	if backup == state.ConfigurationBackup {
+		return nil
+	}
+	revision := util.BrokerConfigurationRevision(backup)
+	// the broker is restarted again with the same configuration, e.g. its container terminated, the rollout goes on
+	if state.ConfigurationRollout != nil && state.ConfigurationRollout.Revision == revision {
+		return nil
+	}
+
+	log.Info("restarting broker with a changed configuration, it is rolled back unless it becomes ready",
+		v1beta1.BrokerIdLabelKey, brokerID, "revision", revision)
+	return r.updateConfigurationRollout(brokerID, &v1beta1.ConfigurationRolloutStatus{
+		Revision:  revision,
+		Phase:     v1beta1.ConfigurationRolloutVerifying,
+		StartTime: metav1.Now(),
+	}, log)
+}
+
+// rolledBackBrokers returns the ids of the brokers rolled back to their configuration backup
+func (r *Reconciler) rolledBackBrokers() []string {
+	var brokerIDs []string
+	for brokerID, state := range r.KafkaCluster.Status.BrokersState {
+		if state.ConfigurationRollout != nil && state.ConfigurationRollout.Phase == v1beta1.ConfigurationRolloutRolledBack {
+			brokerIDs = append(brokerIDs, brokerID)
+		}
+	}
+	sort.Strings(brokerIDs)
+	return brokerIDs
+}
+
+func (r *Reconciler) updateConfigurationRollout(brokerID string, rollout *v1beta1.ConfigurationRolloutStatus, log logr.Logger) error {
+	if err := k8sutil.UpdateBrokerStatus(r.Client, []string{brokerID}, r.KafkaCluster, rollout, log); err != nil {
+		return errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update broker configuration rollout", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	return nil
+}
+
+// restartedBrokerPod returns the pod of the broker created since the rollout started, nil while it is not recreated
+func restartedBrokerPod(pods []corev1.Pod, brokerID string, startTime metav1.Time) *corev1.Pod {
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Labels[v1beta1.BrokerIdLabelKey] != brokerID || pod.DeletionTimestamp != nil {
+			continue
+		}
+		// the creation timestamp of the pod has a precision of seconds
+		if pod.CreationTimestamp.Time.Before(startTime.Time.Truncate(time.Second)) {
+			continue
+		}
+		return pod
+	}
+	return nil
+}
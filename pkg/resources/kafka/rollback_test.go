@@ -0,0 +1,251 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+const (
+	rollbackPreviousConfig = "num.io.threads=8"
+	rollbackChangedConfig  = "num.io.threads=16"
+)
+
+func newRollbackReconciler(t *testing.T, config string, rollout *v1beta1.ConfigurationRolloutStatus) (*Reconciler, string) {
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Generation: 3},
+		Spec: v1beta1.KafkaClusterSpec{
+			Brokers: []v1beta1.Broker{{Id: 0, BrokerConfigGroup: "default"}},
+			BrokerConfigGroups: map[string]v1beta1.BrokerConfig{
+				"default": {Config: rollbackPreviousConfig},
+			},
+			RollingUpgradeConfig: v1beta1.RollingUpgradeConfig{
+				ConfigRollback: &v1beta1.ConfigRollbackConfig{
+					ReadyTimeout:         &metav1.Duration{Duration: 5 * time.Minute},
+					MaxContainerRestarts: 3,
+				},
+			},
+		},
+	}
+	backup, err := util.GzipAndBase64ResolvedBrokerConfiguration(&cluster.Spec.Brokers[0], cluster.Spec)
+	require.NoError(t, err)
+	cluster.Spec.BrokerConfigGroups["default"] = v1beta1.BrokerConfig{Config: config}
+	cluster.Status.BrokersState = map[string]v1beta1.BrokerState{
+		"0": {ConfigurationBackup: backup, ConfigurationRollout: rollout},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	mockClient := mocks.NewMockClient(mockCtrl)
+	r := newStatusUpdatingReconciler(mockCtrl, mockClient, cluster, new(kafkaclient.MockedProvider))
+	return r, backup
+}
+
+func changedConfigRevision(t *testing.T) string {
+	broker := v1beta1.Broker{Id: 0, BrokerConfigGroup: "default"}
+	backup, err := util.GzipAndBase64ResolvedBrokerConfiguration(&broker, v1beta1.KafkaClusterSpec{
+		BrokerConfigGroups: map[string]v1beta1.BrokerConfig{"default": {Config: rollbackChangedConfig}},
+	})
+	require.NoError(t, err)
+	return util.BrokerConfigurationRevision(backup)
+}
+
+func newRollbackPod(created time.Time, ready bool, restarts int32) corev1.Pod {
+	pod := newCanaryPod("0", ready, restarts)
+	pod.CreationTimestamp = metav1.NewTime(created)
+	return pod
+}
+
+func TestReconcileConfigurationRollouts(t *testing.T) {
+	revision := changedConfigRevision(t)
+	startTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	verifying := &v1beta1.ConfigurationRolloutStatus{Revision: revision, Phase: v1beta1.ConfigurationRolloutVerifying, StartTime: startTime}
+	rolledBack := &v1beta1.ConfigurationRolloutStatus{Revision: revision, Phase: v1beta1.ConfigurationRolloutRolledBack, StartTime: startTime}
+
+	tests := []struct {
+		testName           string
+		config             string
+		rollout            *v1beta1.ConfigurationRolloutStatus
+		pods               []corev1.Pod
+		restartHistory     []v1beta1.BrokerRestart
+		expectedRolledBack bool
+		expectedPhase      v1beta1.ConfigurationRolloutPhase
+		expectedBackedUp   bool
+		expectedCondition  *metav1.Condition
+	}{
+		{
+			testName:         "broker became ready with the changed configuration",
+			config:           rollbackChangedConfig,
+			rollout:          verifying,
+			pods:             []corev1.Pod{newRollbackPod(time.Now(), true, 0)},
+			expectedBackedUp: true,
+		},
+		{
+			testName: "broker is not ready yet",
+			config:   rollbackChangedConfig,
+			rollout:  verifying,
+			// the restart count of the container is not counted, the broker pods never restart their containers
+			pods: []corev1.Pod{newRollbackPod(time.Now(), false, 3)},
+			// the container terminations before the rollout started are not counted
+			restartHistory: []v1beta1.BrokerRestart{
+				containerTerminatedRestart(time.Now().Add(-time.Hour)),
+				containerTerminatedRestart(time.Now().Add(-30 * time.Second)),
+				containerTerminatedRestart(time.Now().Add(-10 * time.Second)),
+			},
+			expectedPhase: v1beta1.ConfigurationRolloutVerifying,
+		},
+		{
+			testName:      "ready pod created before the restart is not verified",
+			config:        rollbackChangedConfig,
+			rollout:       verifying,
+			pods:          []corev1.Pod{newRollbackPod(time.Now().Add(-time.Hour), true, 0)},
+			expectedPhase: v1beta1.ConfigurationRolloutVerifying,
+		},
+		{
+			testName: "crash-looping broker is rolled back",
+			config:   rollbackChangedConfig,
+			rollout:  verifying,
+			pods: []corev1.Pod{func() corev1.Pod {
+				pod := newTerminatedPod("0", time.Now())
+				pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-5 * time.Second))
+				return pod
+			}()},
+			restartHistory: []v1beta1.BrokerRestart{
+				containerTerminatedRestart(time.Now().Add(-30 * time.Second)),
+				containerTerminatedRestart(time.Now().Add(-10 * time.Second)),
+			},
+			expectedRolledBack: true,
+			expectedPhase:      v1beta1.ConfigurationRolloutRolledBack,
+			expectedCondition:  &metav1.Condition{Status: metav1.ConditionTrue, Reason: configurationRolledBackReason},
+		},
+		{
+			testName: "broker not ready in time is rolled back",
+			config:   rollbackChangedConfig,
+			rollout: &v1beta1.ConfigurationRolloutStatus{Revision: revision, Phase: v1beta1.ConfigurationRolloutVerifying,
+				StartTime: metav1.NewTime(time.Now().Add(-10 * time.Minute))},
+			expectedRolledBack: true,
+			expectedPhase:      v1beta1.ConfigurationRolloutRolledBack,
+			expectedCondition:  &metav1.Condition{Status: metav1.ConditionTrue, Reason: configurationRolledBackReason},
+		},
+		{
+			testName:           "rolled back broker is rendered from its backup",
+			config:             rollbackChangedConfig,
+			rollout:            rolledBack,
+			pods:               []corev1.Pod{newRollbackPod(time.Now(), true, 0)},
+			expectedRolledBack: true,
+			expectedPhase:      v1beta1.ConfigurationRolloutRolledBack,
+		},
+		{
+			testName:          "rollback is dropped once the configuration changes",
+			config:            "num.io.threads=12",
+			rollout:           rolledBack,
+			pods:              []corev1.Pod{newRollbackPod(time.Now(), true, 0)},
+			expectedCondition: &metav1.Condition{Status: metav1.ConditionFalse, Reason: configurationChangedReason},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			r, backup := newRollbackReconciler(t, test.config, test.rollout.DeepCopy())
+			brokerState := r.KafkaCluster.Status.BrokersState["0"]
+			brokerState.RestartHistory = test.restartHistory
+			r.KafkaCluster.Status.BrokersState["0"] = brokerState
+			if test.rollout.Phase == v1beta1.ConfigurationRolloutRolledBack {
+				r.KafkaCluster.Status.Conditions = []metav1.Condition{{
+					Type: v1beta1.RollingUpgradeHaltedCondition, Status: metav1.ConditionTrue, Reason: configurationRolledBackReason,
+				}}
+			}
+
+			brokers, err := r.reconcileConfigurationRollouts(test.pods, logf.Log)
+			require.NoError(t, err)
+
+			state := r.KafkaCluster.Status.BrokersState["0"]
+			if test.expectedRolledBack {
+				require.Equal(t, map[int32]v1beta1.Broker{0: {Id: 0, BrokerConfig: &v1beta1.BrokerConfig{Config: rollbackPreviousConfig}}}, brokers)
+			} else {
+				require.Empty(t, brokers)
+			}
+			if test.expectedPhase == "" {
+				require.Nil(t, state.ConfigurationRollout)
+			} else {
+				require.Equal(t, test.expectedPhase, state.ConfigurationRollout.Phase)
+			}
+			if test.expectedBackedUp {
+				require.NotEqual(t, backup, state.ConfigurationBackup)
+				require.Equal(t, revision, util.BrokerConfigurationRevision(state.ConfigurationBackup))
+			} else {
+				require.Equal(t, backup, state.ConfigurationBackup)
+			}
+
+			condition := meta.FindStatusCondition(r.KafkaCluster.Status.Conditions, v1beta1.RollingUpgradeHaltedCondition)
+			if test.expectedCondition == nil {
+				if condition != nil {
+					require.Equal(t, metav1.ConditionTrue, condition.Status)
+				}
+				return
+			}
+			require.NotNil(t, condition)
+			require.Equal(t, test.expectedCondition.Status, condition.Status)
+			require.Equal(t, test.expectedCondition.Reason, condition.Reason)
+		})
+	}
+}
+
+func TestStartConfigurationRollout(t *testing.T) {
+	revision := changedConfigRevision(t)
+	pod := newCanaryPod("0", true, 0)
+
+	t.Run("restart with the previous configuration is not verified", func(t *testing.T) {
+		r, _ := newRollbackReconciler(t, rollbackPreviousConfig, nil)
+		require.NoError(t, r.startConfigurationRollout(&pod, logf.Log))
+		require.Nil(t, r.KafkaCluster.Status.BrokersState["0"].ConfigurationRollout)
+	})
+
+	t.Run("restart with a changed configuration is verified", func(t *testing.T) {
+		r, _ := newRollbackReconciler(t, rollbackChangedConfig, nil)
+		require.NoError(t, r.startConfigurationRollout(&pod, logf.Log))
+		rollout := r.KafkaCluster.Status.BrokersState["0"].ConfigurationRollout
+		require.NotNil(t, rollout)
+		require.Equal(t, revision, rollout.Revision)
+		require.Equal(t, v1beta1.ConfigurationRolloutVerifying, rollout.Phase)
+	})
+
+	t.Run("restart during the rollout keeps its start time", func(t *testing.T) {
+		rollout := &v1beta1.ConfigurationRolloutStatus{Revision: revision, Phase: v1beta1.ConfigurationRolloutVerifying,
+			StartTime: metav1.NewTime(time.Now().Add(-time.Hour))}
+		r, _ := newRollbackReconciler(t, rollbackChangedConfig, rollout.DeepCopy())
+		require.NoError(t, r.startConfigurationRollout(&pod, logf.Log))
+		require.Equal(t, rollout, r.KafkaCluster.Status.BrokersState["0"].ConfigurationRollout)
+	})
+
+	t.Run("rollback disabled", func(t *testing.T) {
+		r, _ := newRollbackReconciler(t, rollbackChangedConfig, nil)
+		r.KafkaCluster.Spec.RollingUpgradeConfig.ConfigRollback = nil
+		require.NoError(t, r.startConfigurationRollout(&pod, logf.Log))
+		require.Nil(t, r.KafkaCluster.Status.BrokersState["0"].ConfigurationRollout)
+	})
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+// reconcileStorageAutoscaling scales the storage of the brokers whose disk usage reported by Cruise Control reached
+// the threshold of the autoscaling of their storage configs. The spec is changed the same way as by the resizePvc and
+// addPvc alert commands, a single storage is scaled per reconciliation as the changed spec triggers the next one.
+func (r *Reconciler) reconcileStorageAutoscaling(ctx context.Context, log logr.Logger) error {
+	if r.KafkaCluster.Status.CruiseControlTopicStatus != v1beta1.CruiseControlTopicReady ||
+		!r.KafkaCluster.Spec.IsStorageAutoscalingEnabled() {
+		return nil
+	}
+	cc, err := r.CruiseControlScalerFactory(ctx, r.KafkaCluster)
+	if err != nil {
+		log.Error(err, "could not check the disk usage of the brokers, failed to initialize Cruise Control")
+		return nil
+	}
+	load, err := cc.KafkaClusterLoad(ctx)
+	if err != nil || load == nil || load.Result == nil {
+		log.Error(err, "could not check the disk usage of the brokers")
+		return nil
+	}
+	diskUsageByBroker := make(map[string]map[string]float64, len(load.Result.Brokers))
+	for _, broker := range load.Result.Brokers {
+		diskUsage := make(map[string]float64, len(broker.DiskState))
+		for logDir, stats := range broker.DiskState {
+			if !stats.DiskPct.Dead {
+				diskUsage[logDir] = stats.DiskPct.Usage
+			}
+		}
+		diskUsageByBroker[strconv.Itoa(int(broker.Broker))] = diskUsage
+	}
+
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		brokerID := strconv.Itoa(int(broker.Id))
+		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
+		if err != nil {
+			return errors.WrapIf(err, "failed to determine broker config")
+		}
+		for _, storageConfig := range brokerConfig.StorageConfigs {
+			if storageConfig.Autoscaling == nil || storageConfig.PvcSpec == nil {
+				continue
+			}
+			scaled, err := r.scaleBrokerStorage(brokerID, brokerConfig, storageConfig, diskUsageByBroker[brokerID], log)
+			if err != nil || scaled {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scaleBrokerStorage scales the storage of the broker when its disk usage reached the threshold and the cooldown
+// since its last scaling is over, it tells whether the storage was scaled
+func (r *Reconciler) scaleBrokerStorage(brokerID string, brokerConfig *v1beta1.BrokerConfig, storageConfig v1beta1.StorageConfig,
+	diskUsage map[string]float64, log logr.Logger) (bool, error) {
+	autoscaling := storageConfig.Autoscaling
+	if last, ok := r.KafkaCluster.Status.BrokersState[brokerID].StorageAutoscaling[storageConfig.MountPath]; ok &&
+		time.Since(last.LastScaleTime.Time) < autoscaling.GetCooldown() {
+		return false, nil
+	}
+
+	disks := []v1beta1.StorageConfig{storageConfig}
+	if autoscaling.GetMode() == v1beta1.StorageAutoscalingModeAddDisk {
+		disks = append(disks, addedDisks(brokerConfig, storageConfig.MountPath)...)
+	}
+	usage, reported := averageDiskUsage(disks, diskUsage)
+	if !reported || usage < float64(autoscaling.ThresholdPercentage) {
+		return false, nil
+	}
+	log = log.WithValues(v1beta1.BrokerIdLabelKey, brokerID, mountPathAnnotationKey, storageConfig.MountPath,
+		"diskUsagePercentage", int32(usage))
+
+	var size resource.Quantity
+	switch autoscaling.GetMode() {
+	case v1beta1.StorageAutoscalingModeAddDisk:
+		// the added disk is rebalanced by Cruise Control, so no disk is added while a Cruise Control task is pending
+		if ids := GetBrokersWithPendingOrRunningCCTask(r.KafkaCluster); len(ids) > 0 {
+			log.Info("adding a disk to the broker is deferred as there are brokers with pending or running Cruise Control tasks")
+			return false, nil
+		}
+		size = autoscaling.StepSize.DeepCopy()
+		if autoscaling.MaxSize != nil {
+			total := size.DeepCopy()
+			for _, disk := range disks {
+				total.Add(*disk.PvcSpec.Resources.Requests.Storage())
+			}
+			if total.Cmp(*autoscaling.MaxSize) > 0 {
+				log.V(1).Info("disk usage of the broker storage reached the threshold but the storage is at its maximum size")
+				return false, nil
+			}
+		}
+		randomIdentifier, err := util.GetRandomString(6)
+		if err != nil {
+			return false, err
+		}
+		disk := v1beta1.StorageConfig{
+			MountPath: storageConfig.MountPath + "-" + randomIdentifier,
+			PvcSpec:   storageConfig.PvcSpec.DeepCopy(),
+		}
+		disk.PvcSpec.Resources.Requests[corev1.ResourceStorage] = size
+		log.Info("disk usage of the broker storage reached the threshold, adding a disk to the broker", "disk", disk.MountPath, "size", size.String())
+		if err := k8sutil.AddPvToSpecificBroker(brokerID, r.KafkaCluster.Name, r.KafkaCluster.Namespace, &disk, r.Client); err != nil {
+			return false, errorfactory.New(errorfactory.APIFailure{}, err, "could not add disk to broker", v1beta1.BrokerIdLabelKey, brokerID)
+		}
+	default:
+		current := storageConfig.PvcSpec.Resources.Requests.Storage()
+		incrementBy := autoscaling.StepSize.DeepCopy()
+		if autoscaling.MaxSize != nil {
+			remaining := autoscaling.MaxSize.DeepCopy()
+			remaining.Sub(*current)
+			if remaining.Sign() <= 0 {
+				log.V(1).Info("disk usage of the broker storage reached the threshold but the storage is at its maximum size")
+				return false, nil
+			}
+			if remaining.Cmp(incrementBy) < 0 {
+				incrementBy = remaining
+			}
+		}
+		size = current.DeepCopy()
+		size.Add(incrementBy)
+		log.Info("disk usage of the broker storage reached the threshold, expanding the volume", "size", size.String())
+		if err := k8sutil.ResizePvOfSpecificBroker(brokerID, r.KafkaCluster.Name, r.KafkaCluster.Namespace, storageConfig.MountPath, incrementBy, r.Client); err != nil {
+			return false, errorfactory.New(errorfactory.APIFailure{}, err, "could not resize broker volume", v1beta1.BrokerIdLabelKey, brokerID)
+		}
+	}
+
+	scaling := map[string]v1beta1.StorageAutoscalingStatus{
+		storageConfig.MountPath: {
+			LastScaleTime:       metav1.Now(),
+			Mode:                autoscaling.GetMode(),
+			DiskUsagePercentage: int32(usage),
+			Size:                size,
+		},
+	}
+	if err := k8sutil.UpdateBrokerStatus(r.Client, []string{brokerID}, r.KafkaCluster, scaling, log); err != nil {
+		return true, errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update storage autoscaling status of broker",
+			v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	return true, nil
+}
+
+// addedDisks returns the storage configs of the disks added to the broker for the storage config mounted at the mount
+// path, their mount paths are the mount path with a random suffix
+func addedDisks(brokerConfig *v1beta1.BrokerConfig, mountPath string) []v1beta1.StorageConfig {
+	var disks []v1beta1.StorageConfig
+	for _, storageConfig := range brokerConfig.StorageConfigs {
+		if storageConfig.PvcSpec != nil && strings.HasPrefix(storageConfig.MountPath, mountPath+"-") {
+			disks = append(disks, storageConfig)
+		}
+	}
+	return disks
+}
+
+// averageDiskUsage returns the average disk usage percentage of the disks, it tells whether Cruise Control reported
+// the usage of all of them
+func averageDiskUsage(disks []v1beta1.StorageConfig, diskUsage map[string]float64) (float64, bool) {
+	logDirs := make([]string, 0, len(diskUsage))
+	for logDir := range diskUsage {
+		logDirs = append(logDirs, logDir)
+	}
+	var total float64
+	for _, disk := range disks {
+		logDir, found := logDirOfVolume(logDirs, disk.MountPath)
+		if !found {
+			return 0, false
+		}
+		total += diskUsage[logDir]
+	}
+	return total / float64(len(disks)), true
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banzaicloud/go-cruise-control/pkg/api"
+	"github.com/banzaicloud/go-cruise-control/pkg/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	controllerMocks "github.com/banzaicloud/koperator/controllers/tests/mocks"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+func autoscaledStorageConfig(mountPath, size string, autoscaling *v1beta1.StorageAutoscalingConfig) v1beta1.StorageConfig {
+	return v1beta1.StorageConfig{
+		MountPath: mountPath,
+		PvcSpec: &corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+		Autoscaling: autoscaling,
+	}
+}
+
+func TestReconcileStorageAutoscaling(t *testing.T) {
+	maxSize := resource.MustParse("20Gi")
+	resize := &v1beta1.StorageAutoscalingConfig{ThresholdPercentage: 80, StepSize: resource.MustParse("5Gi"), MaxSize: &maxSize}
+	addDisk := &v1beta1.StorageAutoscalingConfig{ThresholdPercentage: 80, Mode: v1beta1.StorageAutoscalingModeAddDisk,
+		StepSize: resource.MustParse("5Gi"), MaxSize: &maxSize}
+
+	tests := []struct {
+		testName               string
+		storageConfigs         []v1beta1.StorageConfig
+		diskUsage              map[string]float64
+		lastScaleTime          time.Time
+		expectedStorageConfigs map[string]string
+		expectedAddedDiskSize  string
+	}{
+		{
+			testName:               "storage below the threshold is not scaled",
+			storageConfigs:         []v1beta1.StorageConfig{autoscaledStorageConfig("/kafka-logs", "10Gi", resize)},
+			diskUsage:              map[string]float64{"/kafka-logs/kafka": 79.5},
+			expectedStorageConfigs: map[string]string{"/kafka-logs": "10Gi"},
+		},
+		{
+			testName:               "storage above the threshold is expanded",
+			storageConfigs:         []v1beta1.StorageConfig{autoscaledStorageConfig("/kafka-logs", "10Gi", resize)},
+			diskUsage:              map[string]float64{"/kafka-logs/kafka": 85},
+			expectedStorageConfigs: map[string]string{"/kafka-logs": "15Gi"},
+		},
+		{
+			testName:               "storage expansion is capped at the maximum size",
+			storageConfigs:         []v1beta1.StorageConfig{autoscaledStorageConfig("/kafka-logs", "18Gi", resize)},
+			diskUsage:              map[string]float64{"/kafka-logs/kafka": 85},
+			expectedStorageConfigs: map[string]string{"/kafka-logs": "20Gi"},
+		},
+		{
+			testName:               "storage at the maximum size is not expanded",
+			storageConfigs:         []v1beta1.StorageConfig{autoscaledStorageConfig("/kafka-logs", "20Gi", resize)},
+			diskUsage:              map[string]float64{"/kafka-logs/kafka": 95},
+			expectedStorageConfigs: map[string]string{"/kafka-logs": "20Gi"},
+		},
+		{
+			testName:               "storage is not scaled during the cooldown",
+			storageConfigs:         []v1beta1.StorageConfig{autoscaledStorageConfig("/kafka-logs", "10Gi", resize)},
+			diskUsage:              map[string]float64{"/kafka-logs/kafka": 85},
+			lastScaleTime:          time.Now().Add(-10 * time.Minute),
+			expectedStorageConfigs: map[string]string{"/kafka-logs": "10Gi"},
+		},
+		{
+			testName:               "storage is scaled after the cooldown",
+			storageConfigs:         []v1beta1.StorageConfig{autoscaledStorageConfig("/kafka-logs", "10Gi", resize)},
+			diskUsage:              map[string]float64{"/kafka-logs/kafka": 85},
+			lastScaleTime:          time.Now().Add(-time.Hour),
+			expectedStorageConfigs: map[string]string{"/kafka-logs": "15Gi"},
+		},
+		{
+			testName:               "disk is added to the broker",
+			storageConfigs:         []v1beta1.StorageConfig{autoscaledStorageConfig("/kafka-logs", "10Gi", addDisk)},
+			diskUsage:              map[string]float64{"/kafka-logs/kafka": 85},
+			expectedStorageConfigs: map[string]string{"/kafka-logs": "10Gi"},
+			expectedAddedDiskSize:  "5Gi",
+		},
+		{
+			testName: "disk is not added until the usage of the added disks is reported",
+			storageConfigs: []v1beta1.StorageConfig{
+				autoscaledStorageConfig("/kafka-logs", "10Gi", addDisk),
+				autoscaledStorageConfig("/kafka-logs-abcdef", "5Gi", nil),
+			},
+			diskUsage:              map[string]float64{"/kafka-logs/kafka": 85},
+			expectedStorageConfigs: map[string]string{"/kafka-logs": "10Gi", "/kafka-logs-abcdef": "5Gi"},
+		},
+		{
+			testName: "disk is not added above the maximum size",
+			storageConfigs: []v1beta1.StorageConfig{
+				autoscaledStorageConfig("/kafka-logs", "10Gi", addDisk),
+				autoscaledStorageConfig("/kafka-logs-abcdef", "10Gi", nil),
+			},
+			diskUsage:              map[string]float64{"/kafka-logs/kafka": 85, "/kafka-logs-abcdef/kafka": 90},
+			expectedStorageConfigs: map[string]string{"/kafka-logs": "10Gi", "/kafka-logs-abcdef": "10Gi"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			brokerState := v1beta1.BrokerState{}
+			if !test.lastScaleTime.IsZero() {
+				brokerState.StorageAutoscaling = map[string]v1beta1.StorageAutoscalingStatus{
+					"/kafka-logs": {LastScaleTime: metav1.NewTime(test.lastScaleTime)},
+				}
+			}
+			cluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Spec: v1beta1.KafkaClusterSpec{
+					Brokers: []v1beta1.Broker{{Id: 1, BrokerConfig: &v1beta1.BrokerConfig{StorageConfigs: test.storageConfigs}}},
+				},
+				Status: v1beta1.KafkaClusterStatus{
+					CruiseControlTopicStatus: v1beta1.CruiseControlTopicReady,
+					BrokersState:             map[string]v1beta1.BrokerState{"1": brokerState},
+				},
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			r := newStatusUpdatingReconciler(mockCtrl, mockClient, cluster.DeepCopy(), new(kafkaclient.MockedProvider))
+			mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.KafkaCluster{})).Do(
+				func(ctx context.Context, key client.ObjectKey, kafkaCluster *v1beta1.KafkaCluster, opts ...client.GetOption) {
+					cluster.DeepCopyInto(kafkaCluster)
+				}).Return(nil).AnyTimes()
+			mockClient.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.KafkaCluster{})).Do(
+				func(ctx context.Context, kafkaCluster *v1beta1.KafkaCluster, opts ...client.UpdateOption) {
+					cluster.Spec = kafkaCluster.Spec
+				}).Return(nil).AnyTimes()
+
+			diskState := make(map[string]types.DiskStats, len(test.diskUsage))
+			for logDir, usage := range test.diskUsage {
+				diskState[logDir] = types.DiskStats{DiskPct: types.DiskUsageStat{Usage: usage}}
+			}
+			mockCruiseControl := controllerMocks.NewMockCruiseControlScaler(mockCtrl)
+			mockCruiseControl.EXPECT().KafkaClusterLoad(gomock.Any()).Return(&api.KafkaClusterLoadResponse{
+				Result: &types.BrokerStats{Brokers: []types.BrokerLoadStats{{Broker: 1, DiskState: diskState}}},
+			}, nil)
+			r.CruiseControlScalerFactory = controllerMocks.NewMockScaleFactory(mockCruiseControl)
+
+			require.NoError(t, r.reconcileStorageAutoscaling(context.Background(), logf.Log))
+
+			storageConfigs := make(map[string]string)
+			var addedDiskSize string
+			for _, storageConfig := range cluster.Spec.Brokers[0].BrokerConfig.StorageConfigs {
+				size := storageConfig.PvcSpec.Resources.Requests.Storage().String()
+				if _, ok := test.expectedStorageConfigs[storageConfig.MountPath]; !ok && strings.HasPrefix(storageConfig.MountPath, "/kafka-logs-") {
+					require.Empty(t, addedDiskSize)
+					require.Nil(t, storageConfig.Autoscaling)
+					addedDiskSize = size
+					continue
+				}
+				storageConfigs[storageConfig.MountPath] = size
+			}
+			require.Equal(t, test.expectedStorageConfigs, storageConfigs)
+			require.Equal(t, test.expectedAddedDiskSize, addedDiskSize)
+
+			scaled := test.expectedAddedDiskSize != "" || test.expectedStorageConfigs["/kafka-logs"] != test.storageConfigs[0].PvcSpec.Resources.Requests.Storage().String()
+			status, found := r.KafkaCluster.Status.BrokersState["1"].StorageAutoscaling["/kafka-logs"]
+			if scaled {
+				require.True(t, found)
+				require.True(t, time.Since(status.LastScaleTime.Time) < time.Minute)
+				require.Equal(t, int32(85), status.DiskUsagePercentage)
+			} else {
+				require.Equal(t, !test.lastScaleTime.IsZero(), found)
+			}
+		})
+	}
+}
+
+func TestReconcileStorageAutoscalingDisabled(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			Brokers: []v1beta1.Broker{{Id: 1, BrokerConfig: &v1beta1.BrokerConfig{
+				StorageConfigs: []v1beta1.StorageConfig{autoscaledStorageConfig("/kafka-logs", "10Gi", nil)},
+			}}},
+		},
+		Status: v1beta1.KafkaClusterStatus{CruiseControlTopicStatus: v1beta1.CruiseControlTopicReady},
+	}
+	mockCtrl := gomock.NewController(t)
+	r := New(mocks.NewMockClient(mockCtrl), nil, cluster, new(kafkaclient.MockedProvider))
+	// Cruise Control is not called when none of the storage configs is autoscaled
+	r.CruiseControlScalerFactory = controllerMocks.NewMockScaleFactory(controllerMocks.NewMockCruiseControlScaler(mockCtrl))
+
+	require.NoError(t, r.reconcileStorageAutoscaling(context.Background(), logf.Log))
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	banzaiv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+)
+
+// brokerStorageMigrations are the phases of the migrations of the broker volumes by the broker ids and the mount paths
+// of the storage configs migrated to
+type brokerStorageMigrations map[string]map[string]banzaiv1beta1.VolumeMigrationPhase
+
+// brokerConfig returns the configuration of the broker with its storage configs in the phase of their migrations. A
+// broker waiting for its migration keeps the volume migrated from, while the volume is added the broker has both
+// volumes, once the broker runs with the added volume the volume migrated from is left to the graceful disk removal.
+func (m brokerStorageMigrations) brokerConfig(brokerID int32, brokerConfig *banzaiv1beta1.BrokerConfig) *banzaiv1beta1.BrokerConfig {
+	phases := m[strconv.Itoa(int(brokerID))]
+	if len(phases) == 0 {
+		return brokerConfig
+	}
+
+	migrated := brokerConfig.DeepCopy()
+	migrated.StorageConfigs = make([]banzaiv1beta1.StorageConfig, 0, len(brokerConfig.StorageConfigs)+len(phases))
+	for _, storage := range brokerConfig.StorageConfigs {
+		migratedFrom := *storage.DeepCopy()
+		migratedFrom.MountPath = storage.MigrateFrom
+		migratedFrom.MigrateFrom = ""
+
+		switch phases[storage.MountPath] {
+		case banzaiv1beta1.VolumeMigrationWaiting:
+			migrated.StorageConfigs = append(migrated.StorageConfigs, migratedFrom)
+		case banzaiv1beta1.VolumeMigrationAddingVolume:
+			migrated.StorageConfigs = append(migrated.StorageConfigs, storage, migratedFrom)
+		default:
+			migrated.StorageConfigs = append(migrated.StorageConfigs, storage)
+		}
+	}
+	return migrated
+}
+
+// reconcileStorageMigrations records the phases of the migrations of the broker volumes in their volume states
+func (r *Reconciler) reconcileStorageMigrations(ctx context.Context, log logr.Logger) (brokerStorageMigrations, error) {
+	migrations, volumeStates, err := r.storageMigrations(ctx, log)
+	if err != nil {
+		return nil, err
+	}
+	if len(volumeStates) == 0 {
+		return migrations, nil
+	}
+
+	brokerIDs := make([]string, 0, len(volumeStates))
+	for brokerID := range volumeStates {
+		brokerIDs = append(brokerIDs, brokerID)
+	}
+	sort.Strings(brokerIDs)
+	if err := k8sutil.UpdateBrokerStatus(r.Client, brokerIDs, r.KafkaCluster, volumeStates, log); err != nil {
+		return nil, errorfactory.New(errorfactory.StatusUpdateError{}, err, "could not update storage migration status")
+	}
+	return migrations, nil
+}
+
+// storageMigrations determines the phases of the migrations of the broker volumes to the storage configs replacing
+// them, and the volume states to be recorded for them. The brokers are migrated one by one in the order of the spec.
+func (r *Reconciler) storageMigrations(ctx context.Context, log logr.Logger) (brokerStorageMigrations, map[string]map[string]banzaiv1beta1.VolumeState, error) {
+	brokersStorageConfigs := make(map[string][]banzaiv1beta1.StorageConfig)
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
+		if err != nil {
+			return nil, nil, errors.WrapIf(err, "failed to reconcile resource")
+		}
+		for _, storage := range brokerConfig.StorageConfigs {
+			if storage.MigrateFrom != "" && storage.PvcSpec != nil {
+				brokerID := strconv.Itoa(int(broker.Id))
+				brokersStorageConfigs[brokerID] = append(brokersStorageConfigs[brokerID], storage)
+			}
+		}
+	}
+	if len(brokersStorageConfigs) == 0 {
+		return nil, nil, nil
+	}
+
+	matchingLabels := client.MatchingLabels(apiutil.LabelsForKafka(r.KafkaCluster.Name))
+	var pvcList corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcList, client.InNamespace(r.KafkaCluster.Namespace), matchingLabels); err != nil {
+		return nil, nil, errors.WrapIf(err, "failed to list broker pvcs that belong to Kafka cluster")
+	}
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(r.KafkaCluster.Namespace), matchingLabels); err != nil {
+		return nil, nil, errors.WrapIf(err, "failed to list broker pods that belong to Kafka cluster")
+	}
+	brokersPvcs := make(map[string]map[string]*corev1.PersistentVolumeClaim)
+	for i := range pvcList.Items {
+		brokerID := pvcList.Items[i].Labels[banzaiv1beta1.BrokerIdLabelKey]
+		if brokersPvcs[brokerID] == nil {
+			brokersPvcs[brokerID] = make(map[string]*corev1.PersistentVolumeClaim)
+		}
+		brokersPvcs[brokerID][pvcList.Items[i].Annotations[mountPathAnnotationKey]] = &pvcList.Items[i]
+	}
+
+	// the broker being migrated is carried on, otherwise the first broker still having a volume to migrate from is
+	migratingBrokerID := ""
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		brokerID := strconv.Itoa(int(broker.Id))
+		for _, storage := range brokersStorageConfigs[brokerID] {
+			if brokersPvcs[brokerID][storage.MigrateFrom] == nil {
+				continue
+			}
+			if migratingBrokerID == "" {
+				migratingBrokerID = brokerID
+			}
+			if migration := r.volumeStorageMigration(brokerID, storage.MountPath); migration != nil &&
+				(migration.Phase == banzaiv1beta1.VolumeMigrationAddingVolume || migration.Phase == banzaiv1beta1.VolumeMigrationDraining) {
+				migratingBrokerID = brokerID
+				break
+			}
+		}
+	}
+
+	migrations := make(brokerStorageMigrations)
+	volumeStates := make(map[string]map[string]banzaiv1beta1.VolumeState)
+	setVolumeState := func(brokerID, mountPath string, volumeState banzaiv1beta1.VolumeState) {
+		if volumeStates[brokerID] == nil {
+			volumeStates[brokerID] = make(map[string]banzaiv1beta1.VolumeState)
+		}
+		volumeStates[brokerID][mountPath] = volumeState
+	}
+	for brokerID, storageConfigs := range brokersStorageConfigs {
+		pod := brokerPod(podList.Items, brokerID)
+		for _, storage := range storageConfigs {
+			migration := r.volumeStorageMigration(brokerID, storage.MountPath)
+			migratedFrom := brokersPvcs[brokerID][storage.MigrateFrom]
+			migratedTo := brokersPvcs[brokerID][storage.MountPath]
+
+			var phase banzaiv1beta1.VolumeMigrationPhase
+			switch {
+			case migratedFrom == nil:
+				// a new broker gets the volume of the storage config right away
+				if migration == nil {
+					continue
+				}
+				phase = banzaiv1beta1.VolumeMigrationSucceeded
+			case brokerID != migratingBrokerID:
+				phase = banzaiv1beta1.VolumeMigrationWaiting
+			case migration != nil && migration.Phase == banzaiv1beta1.VolumeMigrationDraining,
+				migratedTo != nil && migratedTo.Status.Phase == corev1.ClaimBound && pod != nil && isPodReady(pod) && podMountsPvc(pod, migratedTo.Name):
+				phase = banzaiv1beta1.VolumeMigrationDraining
+			default:
+				phase = banzaiv1beta1.VolumeMigrationAddingVolume
+			}
+			if migrations[brokerID] == nil {
+				migrations[brokerID] = make(map[string]banzaiv1beta1.VolumeMigrationPhase)
+			}
+			migrations[brokerID][storage.MountPath] = phase
+
+			if migration == nil || migration.Phase != phase || migration.MigrateFrom != storage.MigrateFrom {
+				log.Info("storage migration phase changed", banzaiv1beta1.BrokerIdLabelKey, brokerID,
+					mountPathAnnotationKey, storage.MountPath, "migrateFrom", storage.MigrateFrom, "phase", phase)
+				// the volume state of an added volume is recorded before its volume is bound, so it is not rebalanced
+				// by Cruise Control as the draining of the volume migrated from moves the data to it
+				volumeState := r.KafkaCluster.Status.BrokersState[brokerID].GracefulActionState.VolumeStates[storage.MountPath]
+				volumeState.StorageMigration = &banzaiv1beta1.VolumeStorageMigration{MigrateFrom: storage.MigrateFrom, Phase: phase}
+				setVolumeState(brokerID, storage.MountPath, volumeState)
+			}
+			// the graceful disk removal takes a volume without volume state as removed already
+			if _, found := r.KafkaCluster.Status.BrokersState[brokerID].GracefulActionState.VolumeStates[storage.MigrateFrom]; !found &&
+				phase == banzaiv1beta1.VolumeMigrationDraining {
+				setVolumeState(brokerID, storage.MigrateFrom, banzaiv1beta1.VolumeState{CruiseControlVolumeState: banzaiv1beta1.GracefulDiskRemovalRequired})
+			}
+		}
+	}
+	return migrations, volumeStates, nil
+}
+
+func (r *Reconciler) volumeStorageMigration(brokerID, mountPath string) *banzaiv1beta1.VolumeStorageMigration {
+	return r.KafkaCluster.Status.BrokersState[brokerID].GracefulActionState.VolumeStates[mountPath].StorageMigration
+}
+
+func podMountsPvc(pod *corev1.Pod, pvcName string) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvcName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+)
+
+const (
+	migratedFromMountPath = "/kafka-logs"
+	migratedToMountPath   = "/kafka-logs-gp3"
+)
+
+func newMigrationPvc(brokerID, mountPath string, phase corev1.PersistentVolumeClaimPhase) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "kafka-" + brokerID + "-storage" + mountPath,
+			Labels:      map[string]string{v1beta1.BrokerIdLabelKey: brokerID},
+			Annotations: map[string]string{mountPathAnnotationKey: mountPath},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func newMigrationVolumeStates(phase v1beta1.VolumeMigrationPhase) map[string]v1beta1.VolumeState {
+	return map[string]v1beta1.VolumeState{
+		migratedFromMountPath: {CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+		migratedToMountPath: {StorageMigration: &v1beta1.VolumeStorageMigration{
+			MigrateFrom: migratedFromMountPath,
+			Phase:       phase,
+		}},
+	}
+}
+
+//nolint:funlen
+func TestStorageMigrations(t *testing.T) {
+	mountedPod := newRollbackPod(time.Now(), true, 0)
+	mountedPod.Spec.Volumes = []corev1.Volume{{
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: "kafka-0-storage" + migratedToMountPath,
+		}},
+	}}
+
+	tests := []struct {
+		testName               string
+		pvcs                   []corev1.PersistentVolumeClaim
+		pods                   []corev1.Pod
+		brokersVolumeStates    map[string]map[string]v1beta1.VolumeState
+		expectedMigrations     brokerStorageMigrations
+		expectedRecordedPhases map[string]v1beta1.VolumeMigrationPhase
+		expectedDiskRemoval    bool
+	}{
+		{
+			testName: "migration starts with the first broker",
+			pvcs: []corev1.PersistentVolumeClaim{
+				newMigrationPvc("0", migratedFromMountPath, corev1.ClaimBound),
+				newMigrationPvc("1", migratedFromMountPath, corev1.ClaimBound),
+			},
+			expectedMigrations: brokerStorageMigrations{
+				"0": {migratedToMountPath: v1beta1.VolumeMigrationAddingVolume},
+				"1": {migratedToMountPath: v1beta1.VolumeMigrationWaiting},
+			},
+			expectedRecordedPhases: map[string]v1beta1.VolumeMigrationPhase{
+				"0": v1beta1.VolumeMigrationAddingVolume,
+				"1": v1beta1.VolumeMigrationWaiting,
+			},
+		},
+		{
+			testName: "added volume is not mounted yet",
+			pvcs: []corev1.PersistentVolumeClaim{
+				newMigrationPvc("0", migratedFromMountPath, corev1.ClaimBound),
+				newMigrationPvc("0", migratedToMountPath, corev1.ClaimBound),
+				newMigrationPvc("1", migratedFromMountPath, corev1.ClaimBound),
+			},
+			pods: []corev1.Pod{newRollbackPod(time.Now(), true, 0)},
+			brokersVolumeStates: map[string]map[string]v1beta1.VolumeState{
+				"0": newMigrationVolumeStates(v1beta1.VolumeMigrationAddingVolume),
+				"1": newMigrationVolumeStates(v1beta1.VolumeMigrationWaiting),
+			},
+			expectedMigrations: brokerStorageMigrations{
+				"0": {migratedToMountPath: v1beta1.VolumeMigrationAddingVolume},
+				"1": {migratedToMountPath: v1beta1.VolumeMigrationWaiting},
+			},
+		},
+		{
+			testName: "broker runs with the added volume",
+			pvcs: []corev1.PersistentVolumeClaim{
+				newMigrationPvc("0", migratedFromMountPath, corev1.ClaimBound),
+				newMigrationPvc("0", migratedToMountPath, corev1.ClaimBound),
+				newMigrationPvc("1", migratedFromMountPath, corev1.ClaimBound),
+			},
+			pods: []corev1.Pod{mountedPod},
+			brokersVolumeStates: map[string]map[string]v1beta1.VolumeState{
+				"0": newMigrationVolumeStates(v1beta1.VolumeMigrationAddingVolume),
+				"1": newMigrationVolumeStates(v1beta1.VolumeMigrationWaiting),
+			},
+			expectedMigrations: brokerStorageMigrations{
+				"0": {migratedToMountPath: v1beta1.VolumeMigrationDraining},
+				"1": {migratedToMountPath: v1beta1.VolumeMigrationWaiting},
+			},
+			expectedRecordedPhases: map[string]v1beta1.VolumeMigrationPhase{"0": v1beta1.VolumeMigrationDraining},
+		},
+		{
+			testName: "draining goes on while the broker is restarted",
+			pvcs: []corev1.PersistentVolumeClaim{
+				newMigrationPvc("0", migratedFromMountPath, corev1.ClaimBound),
+				newMigrationPvc("0", migratedToMountPath, corev1.ClaimBound),
+				newMigrationPvc("1", migratedFromMountPath, corev1.ClaimBound),
+			},
+			brokersVolumeStates: map[string]map[string]v1beta1.VolumeState{
+				"0": {migratedToMountPath: newMigrationVolumeStates(v1beta1.VolumeMigrationDraining)[migratedToMountPath]},
+				"1": newMigrationVolumeStates(v1beta1.VolumeMigrationWaiting),
+			},
+			expectedMigrations: brokerStorageMigrations{
+				"0": {migratedToMountPath: v1beta1.VolumeMigrationDraining},
+				"1": {migratedToMountPath: v1beta1.VolumeMigrationWaiting},
+			},
+			expectedDiskRemoval: true,
+		},
+		{
+			testName: "next broker is migrated",
+			pvcs: []corev1.PersistentVolumeClaim{
+				newMigrationPvc("0", migratedToMountPath, corev1.ClaimBound),
+				newMigrationPvc("1", migratedFromMountPath, corev1.ClaimBound),
+			},
+			brokersVolumeStates: map[string]map[string]v1beta1.VolumeState{
+				"0": newMigrationVolumeStates(v1beta1.VolumeMigrationDraining),
+				"1": newMigrationVolumeStates(v1beta1.VolumeMigrationWaiting),
+			},
+			expectedMigrations: brokerStorageMigrations{
+				"0": {migratedToMountPath: v1beta1.VolumeMigrationSucceeded},
+				"1": {migratedToMountPath: v1beta1.VolumeMigrationAddingVolume},
+			},
+			expectedRecordedPhases: map[string]v1beta1.VolumeMigrationPhase{
+				"0": v1beta1.VolumeMigrationSucceeded,
+				"1": v1beta1.VolumeMigrationAddingVolume,
+			},
+		},
+		{
+			testName:           "new brokers are not migrated",
+			pvcs:               []corev1.PersistentVolumeClaim{newMigrationPvc("0", migratedToMountPath, corev1.ClaimBound)},
+			expectedMigrations: brokerStorageMigrations{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			brokersState := make(map[string]v1beta1.BrokerState)
+			for brokerID, volumeStates := range test.brokersVolumeStates {
+				brokersState[brokerID] = v1beta1.BrokerState{GracefulActionState: v1beta1.GracefulActionState{VolumeStates: volumeStates}}
+			}
+			brokerConfig := &v1beta1.BrokerConfig{StorageConfigs: []v1beta1.StorageConfig{{
+				MountPath:   migratedToMountPath,
+				MigrateFrom: migratedFromMountPath,
+				PvcSpec:     &corev1.PersistentVolumeClaimSpec{},
+			}}}
+			cluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Spec:       v1beta1.KafkaClusterSpec{Brokers: []v1beta1.Broker{{Id: 0, BrokerConfig: brokerConfig}, {Id: 1, BrokerConfig: brokerConfig}}},
+				Status:     v1beta1.KafkaClusterStatus{BrokersState: brokersState},
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			r := New(mockClient, nil, cluster, new(kafkaclient.MockedProvider))
+			mockClient.EXPECT().List(context.Background(), gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaimList{}), client.InNamespace("kafka"), gomock.Any()).Do(
+				func(ctx context.Context, list *corev1.PersistentVolumeClaimList, opts ...client.ListOption) {
+					list.Items = test.pvcs
+				}).Return(nil)
+			mockClient.EXPECT().List(context.Background(), gomock.AssignableToTypeOf(&corev1.PodList{}), client.InNamespace("kafka"), gomock.Any()).Do(
+				func(ctx context.Context, list *corev1.PodList, opts ...client.ListOption) {
+					list.Items = test.pods
+				}).Return(nil)
+			statusUpdates := 0
+			if len(test.expectedRecordedPhases) > 0 || test.expectedDiskRemoval {
+				statusUpdates = 1
+			}
+			expectStatusUpdates(mockCtrl, mockClient, r).Times(statusUpdates)
+
+			migrations, err := r.reconcileStorageMigrations(context.Background(), logf.Log)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedMigrations, migrations)
+
+			for brokerID, phase := range test.expectedRecordedPhases {
+				migration := r.volumeStorageMigration(brokerID, migratedToMountPath)
+				require.NotNil(t, migration)
+				require.Equal(t, migratedFromMountPath, migration.MigrateFrom)
+				require.Equal(t, phase, migration.Phase)
+			}
+			if test.expectedDiskRemoval {
+				require.Equal(t, v1beta1.GracefulDiskRemovalRequired,
+					r.KafkaCluster.Status.BrokersState["0"].GracefulActionState.VolumeStates[migratedFromMountPath].CruiseControlVolumeState)
+			}
+		})
+	}
+}
+
+func TestStorageMigrationsBrokerConfig(t *testing.T) {
+	brokerConfig := &v1beta1.BrokerConfig{StorageConfigs: []v1beta1.StorageConfig{
+		{MountPath: "/kafka-logs-2"},
+		{MountPath: migratedToMountPath, MigrateFrom: migratedFromMountPath, PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+	}}
+
+	tests := []struct {
+		testName           string
+		phase              v1beta1.VolumeMigrationPhase
+		expectedMountPaths string
+	}{
+		{
+			testName:           "broker is not migrated",
+			expectedMountPaths: "/kafka-logs-2," + migratedToMountPath,
+		},
+		{
+			testName:           "broker waits for its migration",
+			phase:              v1beta1.VolumeMigrationWaiting,
+			expectedMountPaths: "/kafka-logs-2," + migratedFromMountPath,
+		},
+		{
+			testName:           "volume is added to the broker",
+			phase:              v1beta1.VolumeMigrationAddingVolume,
+			expectedMountPaths: "/kafka-logs-2," + migratedToMountPath + "," + migratedFromMountPath,
+		},
+		{
+			testName:           "volume migrated from is drained",
+			phase:              v1beta1.VolumeMigrationDraining,
+			expectedMountPaths: "/kafka-logs-2," + migratedToMountPath,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			migrations := brokerStorageMigrations{}
+			if test.phase != "" {
+				migrations["0"] = map[string]v1beta1.VolumeMigrationPhase{migratedToMountPath: test.phase}
+			}
+
+			require.Equal(t, test.expectedMountPaths, migrations.brokerConfig(0, brokerConfig).GetStorageMountPaths())
+			// the configuration in the spec is not changed
+			require.Equal(t, "/kafka-logs-2,"+migratedToMountPath, brokerConfig.GetStorageMountPaths())
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+const (
+	tieredStoragePluginVolumeName = "tiered-storage-plugin"
+	tieredStoragePluginPath       = "/opt/kafka/libs/tiered-storage"
+	rsmConfigVolumeName           = "tiered-storage-rsm-config"
+	rsmConfigPath                 = "/etc/kafka-tiered-storage/rsm-config"
+
+	// rsmConfigProvider is the config provider the values of the remote storage manager config secret are read with
+	rsmConfigProvider       = "rsmconfig"
+	directoryConfigProvider = "org.apache.kafka.common.config.provider.DirectoryConfigProvider"
+)
+
+// getRemoteStorageManagerConfigKeys returns the sorted keys of the remote storage manager config secret
+func (r *Reconciler) getRemoteStorageManagerConfigKeys(ctx context.Context) ([]string, error) {
+	tieredStorage := r.KafkaCluster.Spec.TieredStorage
+	if tieredStorage == nil || tieredStorage.RemoteStorageManagerConfigSecret == nil {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: tieredStorage.RemoteStorageManagerConfigSecret.Name, Namespace: r.KafkaCluster.Namespace}
+	if err := r.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errorfactory.New(errorfactory.ResourceNotReady{}, err, "remote storage manager config secret not found", "secret", key.Name)
+		}
+		return nil, errors.WrapIfWithDetails(err, "failed to get remote storage manager config secret", "secret", key.Name)
+	}
+	keys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+// configureTieredStorage adds the tiered storage configuration to the broker configuration, the remote storage
+// manager config is referenced from the mounted secret through the directory config provider
+func configureTieredStorage(kafkaClusterSpec v1beta1.KafkaClusterSpec, brokerReadOnlyConfig, config *properties.Properties,
+	rsmConfigKeys []string, log logr.Logger) {
+	tieredStorage := kafkaClusterSpec.TieredStorage
+	if tieredStorage == nil {
+		return
+	}
+
+	tieredStorageConfig := [][2]string{
+		{kafkautils.KafkaConfigRemoteLogStorageSystemEnable, "true"},
+		{kafkautils.KafkaConfigRemoteLogStorageManagerClassName, tieredStorage.RemoteStorageManagerClassName},
+	}
+	classPath := tieredStorage.RemoteStorageManagerClassPath
+	if classPath == "" && tieredStorage.PluginImage != "" {
+		classPath = tieredStoragePluginPath + "/*"
+	}
+	if classPath != "" {
+		tieredStorageConfig = append(tieredStorageConfig, [2]string{kafkautils.KafkaConfigRemoteLogStorageManagerClassPath, classPath})
+	}
+	if tieredStorage.RemoteLogMetadataManagerClassName != "" {
+		tieredStorageConfig = append(tieredStorageConfig,
+			[2]string{kafkautils.KafkaConfigRemoteLogMetadataManagerClassName, tieredStorage.RemoteLogMetadataManagerClassName})
+	}
+	// the topic based remote log metadata manager connects to the brokers on the inter broker listener
+	for _, iListener := range kafkaClusterSpec.ListenersConfig.InternalListeners {
+		if iListener.UsedForInnerBrokerCommunication {
+			tieredStorageConfig = append(tieredStorageConfig,
+				[2]string{kafkautils.KafkaConfigRemoteLogMetadataManagerListenerName, strings.ToUpper(iListener.Name)})
+			break
+		}
+	}
+	if tieredStorage.LocalRetentionMs != nil {
+		tieredStorageConfig = append(tieredStorageConfig,
+			[2]string{kafkautils.KafkaConfigLogLocalRetentionMs, strconv.FormatInt(*tieredStorage.LocalRetentionMs, 10)})
+	}
+	if tieredStorage.LocalRetentionBytes != nil {
+		tieredStorageConfig = append(tieredStorageConfig,
+			[2]string{kafkautils.KafkaConfigLogLocalRetentionBytes, strconv.FormatInt(*tieredStorage.LocalRetentionBytes, 10)})
+	}
+
+	if len(rsmConfigKeys) > 0 {
+		// keep the config providers of the read-only config next to the one of the remote storage manager config
+		providers := []string{}
+		if readOnlyProviders, found := brokerReadOnlyConfig.Get(kafkautils.KafkaConfigConfigProviders); found && readOnlyProviders.Value() != "" {
+			providers, _ = readOnlyProviders.List()
+		}
+		if !slices.Contains(providers, rsmConfigProvider) {
+			providers = append(providers, rsmConfigProvider)
+		}
+		tieredStorageConfig = append(tieredStorageConfig,
+			[2]string{kafkautils.KafkaConfigConfigProviders, strings.Join(providers, ",")},
+			[2]string{fmt.Sprintf("%s.%s.class", kafkautils.KafkaConfigConfigProviders, rsmConfigProvider), directoryConfigProvider})
+		for _, key := range rsmConfigKeys {
+			tieredStorageConfig = append(tieredStorageConfig, [2]string{kafkautils.KafkaConfigRemoteStorageManagerConfigPrefix + key,
+				fmt.Sprintf("${%s:%s:%s}", rsmConfigProvider, rsmConfigPath, key)})
+		}
+	}
+
+	for _, kv := range tieredStorageConfig {
+		if err := config.Set(kv[0], kv[1]); err != nil {
+			log.Error(err, fmt.Sprintf(kafkautils.BrokerConfigErrorMsgTemplate, kv[0]))
+		}
+	}
+}
+
+// getTieredStorageInitContainers returns the init container copying the remote storage manager plugin
+func getTieredStorageInitContainers(kafkaClusterSpec v1beta1.KafkaClusterSpec) []corev1.Container {
+	tieredStorage := kafkaClusterSpec.TieredStorage
+	if tieredStorage == nil || tieredStorage.PluginImage == "" {
+		return nil
+	}
+	return []corev1.Container{{
+		Name:    tieredStoragePluginVolumeName,
+		Image:   tieredStorage.PluginImage,
+		Command: []string{"cp", "-rv", tieredStorage.GetPluginPath() + "/.", tieredStoragePluginPath},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      tieredStoragePluginVolumeName,
+			MountPath: tieredStoragePluginPath,
+		}},
+		Resources: k8sutil.GetDefaultInitContainerResourceRequirements(),
+	}}
+}
+
+// getTieredStorageVolumeMounts returns the volume mounts of the remote storage manager plugin and config
+func getTieredStorageVolumeMounts(kafkaClusterSpec v1beta1.KafkaClusterSpec) []corev1.VolumeMount {
+	tieredStorage := kafkaClusterSpec.TieredStorage
+	if tieredStorage == nil {
+		return nil
+	}
+	var volumeMounts []corev1.VolumeMount
+	if tieredStorage.PluginImage != "" {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      tieredStoragePluginVolumeName,
+			MountPath: tieredStoragePluginPath,
+		})
+	}
+	if tieredStorage.RemoteStorageManagerConfigSecret != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      rsmConfigVolumeName,
+			MountPath: rsmConfigPath,
+			ReadOnly:  true,
+		})
+	}
+	return volumeMounts
+}
+
+// getTieredStorageVolumes returns the volumes of the remote storage manager plugin and config
+func getTieredStorageVolumes(kafkaClusterSpec v1beta1.KafkaClusterSpec) []corev1.Volume {
+	tieredStorage := kafkaClusterSpec.TieredStorage
+	if tieredStorage == nil {
+		return nil
+	}
+	var volumes []corev1.Volume
+	if tieredStorage.PluginImage != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: tieredStoragePluginVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+	if tieredStorage.RemoteStorageManagerConfigSecret != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: rsmConfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: tieredStorage.RemoteStorageManagerConfigSecret.Name,
+				},
+			},
+		})
+	}
+	return volumes
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	"github.com/banzaicloud/koperator/pkg/util"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+func TestConfigureTieredStorage(t *testing.T) {
+	tests := []struct {
+		testName       string
+		tieredStorage  *v1beta1.TieredStorageConfig
+		readOnlyConfig string
+		rsmConfigKeys  []string
+		expectedConfig string
+	}{
+		{
+			testName: "tiered storage disabled",
+		},
+		{
+			testName:      "plugin on the class path of the broker",
+			tieredStorage: &v1beta1.TieredStorageConfig{RemoteStorageManagerClassName: "org.example.RemoteStorageManager"},
+			expectedConfig: `remote.log.metadata.manager.listener.name=INTERNAL
+remote.log.storage.manager.class.name=org.example.RemoteStorageManager
+remote.log.storage.system.enable=true
+`,
+		},
+		{
+			testName: "plugin image with local retention",
+			tieredStorage: &v1beta1.TieredStorageConfig{
+				RemoteStorageManagerClassName:     "org.example.RemoteStorageManager",
+				PluginImage:                       "example/tiered-storage:1.0",
+				RemoteLogMetadataManagerClassName: "org.example.RemoteLogMetadataManager",
+				LocalRetentionMs:                  util.Int64Pointer(3600000),
+				LocalRetentionBytes:               util.Int64Pointer(-2),
+			},
+			expectedConfig: `log.local.retention.bytes=-2
+log.local.retention.ms=3600000
+remote.log.metadata.manager.class.name=org.example.RemoteLogMetadataManager
+remote.log.metadata.manager.listener.name=INTERNAL
+remote.log.storage.manager.class.name=org.example.RemoteStorageManager
+remote.log.storage.manager.class.path=/opt/kafka/libs/tiered-storage/*
+remote.log.storage.system.enable=true
+`,
+		},
+		{
+			testName: "remote storage manager config next to the config providers of the read-only config",
+			tieredStorage: &v1beta1.TieredStorageConfig{
+				RemoteStorageManagerClassName:    "org.example.RemoteStorageManager",
+				RemoteStorageManagerClassPath:    "/opt/plugins/*",
+				RemoteStorageManagerConfigSecret: &corev1.LocalObjectReference{Name: "rsm-config"},
+			},
+			readOnlyConfig: "config.providers=env",
+			rsmConfigKeys:  []string{"s3.bucket.name", "storage.aws.secret.access.key"},
+			expectedConfig: `config.providers=env,rsmconfig
+config.providers.rsmconfig.class=org.apache.kafka.common.config.provider.DirectoryConfigProvider
+remote.log.metadata.manager.listener.name=INTERNAL
+remote.log.storage.manager.class.name=org.example.RemoteStorageManager
+remote.log.storage.manager.class.path=/opt/plugins/*
+remote.log.storage.system.enable=true
+rsm.config.s3.bucket.name=${rsmconfig:/etc/kafka-tiered-storage/rsm-config:s3.bucket.name}
+rsm.config.storage.aws.secret.access.key=${rsmconfig:/etc/kafka-tiered-storage/rsm-config:storage.aws.secret.access.key}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			spec := v1beta1.KafkaClusterSpec{
+				ListenersConfig: v1beta1.ListenersConfig{
+					InternalListeners: []v1beta1.InternalListenerConfig{
+						{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "controller"}, UsedForControllerCommunication: true},
+						{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "internal", UsedForInnerBrokerCommunication: true}},
+					},
+				},
+				TieredStorage: test.tieredStorage,
+			}
+			readOnlyConfig, err := properties.NewFromString(test.readOnlyConfig)
+			require.NoError(t, err)
+			config := properties.NewProperties()
+
+			configureTieredStorage(spec, readOnlyConfig, config, test.rsmConfigKeys, logf.Log)
+
+			config.Sort()
+			require.Equal(t, test.expectedConfig, config.String())
+		})
+	}
+}
+
+func TestGetRemoteStorageManagerConfigKeys(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			TieredStorage: &v1beta1.TieredStorageConfig{
+				RemoteStorageManagerClassName:    "org.example.RemoteStorageManager",
+				RemoteStorageManagerConfigSecret: &corev1.LocalObjectReference{Name: "rsm-config"},
+			},
+		},
+	}
+	mockCtrl := gomock.NewController(t)
+	mockClient := mocks.NewMockClient(mockCtrl)
+	r := New(mockClient, nil, cluster, new(kafkaclient.MockedProvider))
+
+	mockClient.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "kafka", Name: "rsm-config"}, gomock.AssignableToTypeOf(&corev1.Secret{})).Do(
+		func(ctx context.Context, key client.ObjectKey, secret *corev1.Secret, opts ...client.GetOption) {
+			secret.Data = map[string][]byte{"storage.backend.class": []byte("S3Storage"), "s3.bucket.name": []byte("kafka")}
+		}).Return(nil)
+	keys, err := r.getRemoteStorageManagerConfigKeys(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"s3.bucket.name", "storage.backend.class"}, keys)
+
+	// the broker configuration is not rendered until the secret is created
+	mockClient.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "kafka", Name: "rsm-config"}, gomock.AssignableToTypeOf(&corev1.Secret{})).
+		Return(apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "rsm-config"))
+	_, err = r.getRemoteStorageManagerConfigKeys(context.Background())
+	require.True(t, errors.As(err, &errorfactory.ResourceNotReady{}))
+
+	mockClient.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "kafka", Name: "rsm-config"}, gomock.AssignableToTypeOf(&corev1.Secret{})).
+		Return(apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "rsm-config"))
+	mockClient.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: "kafka", Name: "kafka-config-0"}, gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+		Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "kafka-config-0"))
+	_, err = r.configMap(v1beta1.Broker{Id: 0}, &v1beta1.BrokerConfig{}, nil, nil, nil, nil, nil, "", nil, logf.Log)
+	require.True(t, errors.As(err, &errorfactory.ResourceNotReady{}))
+}
+
+func TestTieredStoragePodResources(t *testing.T) {
+	spec := v1beta1.KafkaClusterSpec{}
+	require.Empty(t, getTieredStorageInitContainers(spec))
+	require.Empty(t, getTieredStorageVolumeMounts(spec))
+	require.Empty(t, getTieredStorageVolumes(spec))
+
+	spec.TieredStorage = &v1beta1.TieredStorageConfig{
+		RemoteStorageManagerClassName:    "org.example.RemoteStorageManager",
+		PluginImage:                      "example/tiered-storage:1.0",
+		RemoteStorageManagerConfigSecret: &corev1.LocalObjectReference{Name: "rsm-config"},
+	}
+	initContainers := getTieredStorageInitContainers(spec)
+	require.Len(t, initContainers, 1)
+	require.Equal(t, "example/tiered-storage:1.0", initContainers[0].Image)
+	require.Equal(t, []string{"cp", "-rv", "/tiered-storage/.", tieredStoragePluginPath}, initContainers[0].Command)
+
+	require.Equal(t, []corev1.VolumeMount{
+		{Name: tieredStoragePluginVolumeName, MountPath: tieredStoragePluginPath},
+		{Name: rsmConfigVolumeName, MountPath: rsmConfigPath, ReadOnly: true},
+	}, getTieredStorageVolumeMounts(spec))
+
+	volumes := getTieredStorageVolumes(spec)
+	require.Len(t, volumes, 2)
+	require.NotNil(t, volumes[0].EmptyDir)
+	require.Equal(t, "rsm-config", volumes[1].Secret.SecretName)
+}
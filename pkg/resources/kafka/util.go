@@ -18,6 +18,7 @@ package kafka
 import (
 	"encoding/base64"
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/google/uuid"
@@ -30,17 +31,53 @@ import (
 // regardless of the order of brokers and controllerListenerStatuses are passed in - this is needed to avoid triggering
 // unnecessary rolling upgrade operations
 func generateQuorumVoters(kafkaCluster *v1beta1.KafkaCluster, controllerListenerStatuses map[string]v1beta1.ListenerStatusList) ([]string, error) {
-	var (
-		quorumVoters []string
-		brokerIDs    []int32
-	)
+	var quorumVoters []string
+
+	brokerIDs, idToListenerAddrMap, err := getControllerListenerAddresses(kafkaCluster, controllerListenerStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, brokerId := range brokerIDs {
+		quorumVoters = append(quorumVoters, fmt.Sprintf("%d@%s", brokerId, idToListenerAddrMap[brokerId]))
+	}
+
+	return quorumVoters, nil
+}
+
+// generateQuorumBootstrapServers generates the nodeAddress:listenerPort list of the bootstrap controllers of a dynamic quorum
+// in ascending order by broker IDs. Only the bootstrap controllers recorded in the status are listed so adding controllers
+// does not change the configuration of every node in the cluster.
+func generateQuorumBootstrapServers(kafkaCluster *v1beta1.KafkaCluster, controllerListenerStatuses map[string]v1beta1.ListenerStatusList) ([]string, error) {
+	var bootstrapServers []string
+
+	brokerIDs, idToListenerAddrMap, err := getControllerListenerAddresses(kafkaCluster, controllerListenerStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	if kafkaCluster.Status.KRaftQuorum == nil {
+		return nil, nil
+	}
+	for _, brokerId := range brokerIDs {
+		if slices.Contains(kafkaCluster.Status.KRaftQuorum.BootstrapControllers, brokerId) {
+			bootstrapServers = append(bootstrapServers, idToListenerAddrMap[brokerId])
+		}
+	}
+
+	return bootstrapServers, nil
+}
+
+// getControllerListenerAddresses returns the ids of the controller nodes in ascending order and their controller listener addresses
+func getControllerListenerAddresses(kafkaCluster *v1beta1.KafkaCluster, controllerListenerStatuses map[string]v1beta1.ListenerStatusList) ([]int32, map[int32]string, error) {
+	var brokerIDs []int32
 	idToListenerAddrMap := make(map[int32]string)
 
 	// find the controller nodes and their corresponding listener addresses
 	for _, b := range kafkaCluster.Spec.Brokers {
 		brokerConfig, err := b.GetBrokerConfig(kafkaCluster.Spec)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if brokerConfig.IsControllerNode() {
@@ -60,11 +97,7 @@ func generateQuorumVoters(kafkaCluster *v1beta1.KafkaCluster, controllerListener
 		return brokerIDs[i] < brokerIDs[j]
 	})
 
-	for _, brokerId := range brokerIDs {
-		quorumVoters = append(quorumVoters, fmt.Sprintf("%d@%s", brokerId, idToListenerAddrMap[brokerId]))
-	}
-
-	return quorumVoters, nil
+	return brokerIDs, idToListenerAddrMap, nil
 }
 
 // generateRandomClusterID() generates a base64-encoded random UUID with 16 bytes as the cluster ID.
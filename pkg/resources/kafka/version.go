@@ -0,0 +1,203 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/util"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+)
+
+// clusterRelease returns the Kafka release every broker of the spec runs, false while the brokers are upgraded: some
+// of them run a different release, their image or configuration is not updated yet or their version is not known
+func clusterRelease(kafkaCluster *v1beta1.KafkaCluster) (kafkautils.Release, bool, error) {
+	var release *kafkautils.Release
+	for _, broker := range kafkaCluster.Spec.Brokers {
+		bConfig, err := broker.GetBrokerConfig(kafkaCluster.Spec)
+		if err != nil {
+			return kafkautils.Release{}, false, err
+		}
+		state, ok := kafkaCluster.Status.BrokersState[strconv.Itoa(int(broker.Id))]
+		if !ok || state.Version == "" || state.ConfigurationState != v1beta1.ConfigInSync ||
+			state.Image != util.GetBrokerImage(bConfig, kafkaCluster.Spec.GetClusterImage()) {
+			return kafkautils.Release{}, false, nil
+		}
+		brokerRelease, err := kafkautils.ParseRelease(state.Version)
+		if err != nil || (release != nil && *release != brokerRelease) {
+			return kafkautils.Release{}, false, nil
+		}
+		release = &brokerRelease
+	}
+	if release == nil {
+		return kafkautils.Release{}, false, nil
+	}
+	return *release, true, nil
+}
+
+// lowestRunningRelease returns the lowest Kafka release the brokers of the spec run, false when no version is known
+func lowestRunningRelease(kafkaCluster *v1beta1.KafkaCluster) (kafkautils.Release, bool) {
+	var lowest *kafkautils.Release
+	for _, broker := range kafkaCluster.Spec.Brokers {
+		release, err := kafkautils.ParseRelease(kafkaCluster.Status.BrokersState[strconv.Itoa(int(broker.Id))].Version)
+		if err != nil {
+			continue
+		}
+		if lowest == nil || release.Compare(*lowest) < 0 {
+			lowest = &release
+		}
+	}
+	if lowest == nil {
+		return kafkautils.Release{}, false
+	}
+	return *lowest, true
+}
+
+// interBrokerProtocolVersionConfigured returns whether inter.broker.protocol.version is set in the read-only config of
+// any broker, the operator does not manage it then
+func interBrokerProtocolVersionConfigured(kafkaCluster *v1beta1.KafkaCluster, log logr.Logger) bool {
+	for _, broker := range kafkaCluster.Spec.Brokers {
+		if _, found := getBrokerReadOnlyConfig(broker, kafkaCluster, log).Get(kafkautils.KafkaConfigInterBrokerProtocolVersion); found {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileVersionUpgrade records the Kafka release the brokers run and, when spec.autoFinalizeVersion is set,
+// finalizes the upgrade to it once every broker runs it. The metadata.version of a KRaft cluster is upgraded through
+// the UpdateFeatures API, a ZooKeeper based cluster is rolled with the new inter.broker.protocol.version.
+func (r *Reconciler) reconcileVersionUpgrade(log logr.Logger) error {
+	if r.KafkaCluster.Status.Migration.IsInProgress() {
+		return nil
+	}
+
+	release, ok, err := clusterRelease(r.KafkaCluster)
+	if err != nil {
+		return errors.WrapIf(err, "could not determine the Kafka release of the brokers")
+	}
+
+	var status v1beta1.VersionUpgradeStatus
+	if r.KafkaCluster.Status.VersionUpgrade != nil {
+		status = *r.KafkaCluster.Status.VersionUpgrade.DeepCopy()
+	}
+	current := status
+	status.Release = ""
+	if ok {
+		status.Release = release.String()
+	}
+
+	finalize := ok && r.KafkaCluster.Spec.AutoFinalizeVersion
+	if finalize {
+		if finalized, err := kafkautils.ParseRelease(status.FinalizedRelease); err == nil && release.Compare(finalized) < 0 {
+			// the brokers run an older release than the finalized one, it is not lowered
+			finalize = false
+		}
+	}
+
+	var requeue bool
+	switch {
+	case !finalize:
+	case r.KafkaCluster.Spec.KRaftMode:
+		if err := r.finalizeMetadataVersion(release, log); err != nil {
+			return err
+		}
+		status.FinalizedRelease = release.String()
+	case interBrokerProtocolVersionConfigured(r.KafkaCluster, log):
+		log.V(1).Info("inter.broker.protocol.version is configured explicitly, the upgrade is not finalized")
+	case status.FinalizedRelease != release.String():
+		log.Info("finalizing the Kafka version upgrade, the brokers are rolled with the new inter.broker.protocol.version",
+			"release", release.String())
+		status.FinalizedRelease = release.String()
+		requeue = true
+	}
+
+	if status != current {
+		if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, status, log); err != nil {
+			return errors.WrapIf(err, "could not update version upgrade status")
+		}
+	}
+	if requeue {
+		// the broker configuration is regenerated with the finalized inter.broker.protocol.version on the next reconcile
+		return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("inter.broker.protocol.version changed"),
+			"finalizing Kafka version upgrade", "release", release.String())
+	}
+	return nil
+}
+
+// pinInterBrokerProtocolVersion pins the inter.broker.protocol.version of a ZooKeeper based cluster which is not
+// finalized yet to the lowest release its brokers run, before their configuration is rendered. Brokers restarted with
+// a newer image keep talking the protocol of the running ones until the upgrade is finalized.
+func (r *Reconciler) pinInterBrokerProtocolVersion(log logr.Logger) error {
+	if !r.KafkaCluster.Spec.AutoFinalizeVersion || r.KafkaCluster.Spec.KRaftMode || r.KafkaCluster.Status.Migration.IsInProgress() {
+		return nil
+	}
+	if r.KafkaCluster.Status.VersionUpgrade != nil && r.KafkaCluster.Status.VersionUpgrade.FinalizedRelease != "" {
+		return nil
+	}
+	if interBrokerProtocolVersionConfigured(r.KafkaCluster, log) {
+		return nil
+	}
+	release, ok := lowestRunningRelease(r.KafkaCluster)
+	if !ok {
+		return nil
+	}
+
+	var status v1beta1.VersionUpgradeStatus
+	if r.KafkaCluster.Status.VersionUpgrade != nil {
+		status = *r.KafkaCluster.Status.VersionUpgrade.DeepCopy()
+	}
+	status.FinalizedRelease = release.String()
+	log.Info("pinning inter.broker.protocol.version to the release the brokers run", "release", release.String())
+	if err := k8sutil.UpdateCRStatus(r.Client, r.KafkaCluster, status, log); err != nil {
+		return errors.WrapIf(err, "could not update version upgrade status")
+	}
+	return nil
+}
+
+// finalizeMetadataVersion upgrades the metadata.version feature to the highest level the brokers of the release support
+func (r *Reconciler) finalizeMetadataVersion(release kafkautils.Release, log logr.Logger) error {
+	kClient, close, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+	if err != nil {
+		return errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
+	}
+	defer close()
+
+	features, err := kClient.DescribeFeatures()
+	if err != nil {
+		return errorfactory.New(errorfactory.BrokersRequestError{}, err, "could not describe the features of the cluster")
+	}
+	level := features.Supported[kafkaclient.MetadataVersionFeature].MaxVersion
+	if releaseLevel, ok := kafkautils.MetadataVersionLevel(release); ok {
+		level = min(level, releaseLevel)
+	}
+	if level <= features.MetadataVersion() {
+		return nil
+	}
+
+	log.Info("finalizing the Kafka version upgrade, upgrading metadata.version",
+		"release", release.String(), "from", features.MetadataVersion(), "to", level)
+	if err := kClient.UpdateFeatures(map[string]int16{kafkaclient.MetadataVersionFeature: level}); err != nil {
+		return errorfactory.New(errorfactory.BrokersRequestError{}, err, "could not upgrade metadata.version")
+	}
+	return nil
+}
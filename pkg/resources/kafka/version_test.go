@@ -0,0 +1,218 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"strconv"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+const versionUpgradeClusterImage = "apache/kafka:3.9.1"
+
+func newVersionUpgradeCluster(kRaftMode bool, versions ...string) *v1beta1.KafkaCluster {
+	cluster := &v1beta1.KafkaCluster{
+		Spec: v1beta1.KafkaClusterSpec{
+			KRaftMode:           kRaftMode,
+			AutoFinalizeVersion: true,
+			ClusterImage:        versionUpgradeClusterImage,
+		},
+		Status: v1beta1.KafkaClusterStatus{BrokersState: make(map[string]v1beta1.BrokerState)},
+	}
+	for i, version := range versions {
+		cluster.Spec.Brokers = append(cluster.Spec.Brokers, v1beta1.Broker{Id: int32(i), BrokerConfig: &v1beta1.BrokerConfig{}})
+		cluster.Status.BrokersState[strconv.Itoa(i)] = v1beta1.BrokerState{
+			Version:            version,
+			Image:              versionUpgradeClusterImage,
+			ConfigurationState: v1beta1.ConfigInSync,
+		}
+	}
+	return cluster
+}
+
+func TestClusterRelease(t *testing.T) {
+	release, ok, err := clusterRelease(newVersionUpgradeCluster(false, "3.9.1", "3.9.0"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, kafkautils.Release{Major: 3, Minor: 9}, release)
+
+	_, ok, err = clusterRelease(newVersionUpgradeCluster(false, "3.9.1", "3.8.1"))
+	require.NoError(t, err)
+	require.False(t, ok, "brokers running different releases")
+
+	cluster := newVersionUpgradeCluster(false, "3.9.1", "3.9.1")
+	cluster.Spec.ClusterImage = "apache/kafka:4.0.0"
+	_, ok, err = clusterRelease(cluster)
+	require.NoError(t, err)
+	require.False(t, ok, "brokers not running the desired image yet")
+
+	cluster = newVersionUpgradeCluster(false, "3.9.1", "3.9.1")
+	state := cluster.Status.BrokersState["1"]
+	state.ConfigurationState = v1beta1.ConfigOutOfSync
+	cluster.Status.BrokersState["1"] = state
+	_, ok, err = clusterRelease(cluster)
+	require.NoError(t, err)
+	require.False(t, ok, "broker waiting to be rolled")
+}
+
+func TestReconcileVersionUpgrade(t *testing.T) {
+	tests := []struct {
+		testName                      string
+		cluster                       *v1beta1.KafkaCluster
+		features                      *kafkaclient.FeaturesInfo
+		expectedLevel                 int16
+		expectedStatus                *v1beta1.VersionUpgradeStatus
+		expectedRequeue               bool
+		configuredInterBrokerProtocol bool
+	}{
+		{
+			testName:        "ZooKeeper based cluster is finalized",
+			cluster:         newVersionUpgradeCluster(false, "3.9.1", "3.9.1"),
+			expectedStatus:  &v1beta1.VersionUpgradeStatus{Release: "3.9", FinalizedRelease: "3.9"},
+			expectedRequeue: true,
+		},
+		{
+			testName: "ZooKeeper based cluster is being upgraded",
+			cluster:  newVersionUpgradeCluster(false, "3.9.1", "3.8.1"),
+		},
+		{
+			testName:                      "inter.broker.protocol.version is configured explicitly",
+			cluster:                       newVersionUpgradeCluster(false, "3.9.1", "3.9.1"),
+			configuredInterBrokerProtocol: true,
+			expectedStatus:                &v1beta1.VersionUpgradeStatus{Release: "3.9"},
+		},
+		{
+			testName: "metadata.version is upgraded",
+			cluster:  newVersionUpgradeCluster(true, "3.9.1", "3.9.1"),
+			features: &kafkaclient.FeaturesInfo{
+				Finalized: map[string]int16{kafkaclient.MetadataVersionFeature: 20},
+				Supported: map[string]kafkaclient.FeatureVersionRange{kafkaclient.MetadataVersionFeature: {MinVersion: 1, MaxVersion: 21}},
+			},
+			expectedLevel:  21,
+			expectedStatus: &v1beta1.VersionUpgradeStatus{Release: "3.9", FinalizedRelease: "3.9"},
+		},
+		{
+			testName: "metadata.version is already finalized",
+			cluster:  newVersionUpgradeCluster(true, "3.9.1", "3.9.1"),
+			features: &kafkaclient.FeaturesInfo{
+				Finalized: map[string]int16{kafkaclient.MetadataVersionFeature: 21},
+				Supported: map[string]kafkaclient.FeatureVersionRange{kafkaclient.MetadataVersionFeature: {MinVersion: 1, MaxVersion: 21}},
+			},
+			expectedStatus: &v1beta1.VersionUpgradeStatus{Release: "3.9", FinalizedRelease: "3.9"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			mockKafkaClientProvider := new(kafkaclient.MockedProvider)
+			if test.configuredInterBrokerProtocol {
+				test.cluster.Spec.ReadOnlyConfig = "inter.broker.protocol.version=3.8"
+			}
+			r := newStatusUpdatingReconciler(mockCtrl, mockClient, test.cluster, mockKafkaClientProvider)
+
+			if test.features != nil {
+				mockedKafkaClient := mocks.NewMockKafkaClient(mockCtrl)
+				mockedKafkaClient.EXPECT().DescribeFeatures().Return(test.features, nil)
+				if test.expectedLevel != 0 {
+					mockedKafkaClient.EXPECT().UpdateFeatures(map[string]int16{kafkaclient.MetadataVersionFeature: test.expectedLevel}).Return(nil)
+				}
+				mockKafkaClientProvider.On("NewFromCluster", mockClient, test.cluster).Return(mockedKafkaClient, func() {}, nil)
+			}
+
+			err := r.reconcileVersionUpgrade(logf.Log)
+			if test.expectedRequeue {
+				require.True(t, errors.As(err, &errorfactory.ResourceNotReady{}), "expected the reconcile to be requeued, got: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.expectedStatus, r.KafkaCluster.Status.VersionUpgrade)
+		})
+	}
+}
+
+func TestConfigureInterBrokerProtocolVersion(t *testing.T) {
+	cluster := newVersionUpgradeCluster(false, "3.9.1")
+	cluster.Status.VersionUpgrade = &v1beta1.VersionUpgradeStatus{Release: "3.9", FinalizedRelease: "3.9"}
+
+	config := properties.NewProperties()
+	configureInterBrokerProtocolVersion(cluster, properties.NewProperties(), config, logf.Log)
+	ibp, found := config.Get(kafkautils.KafkaConfigInterBrokerProtocolVersion)
+	require.True(t, found)
+	require.Equal(t, "3.9", ibp.Value())
+
+	// the explicitly configured version is kept
+	readOnlyConfig, err := properties.NewFromString("inter.broker.protocol.version=3.8")
+	require.NoError(t, err)
+	config = properties.NewProperties()
+	configureInterBrokerProtocolVersion(cluster, readOnlyConfig, config, logf.Log)
+	_, found = config.Get(kafkautils.KafkaConfigInterBrokerProtocolVersion)
+	require.False(t, found)
+}
+
+func TestPinInterBrokerProtocolVersion(t *testing.T) {
+	tests := []struct {
+		testName       string
+		cluster        *v1beta1.KafkaCluster
+		status         *v1beta1.VersionUpgradeStatus
+		expectedStatus *v1beta1.VersionUpgradeStatus
+	}{
+		{
+			testName:       "cluster upgraded before it is finalized",
+			cluster:        newVersionUpgradeCluster(false, "3.9.1", "3.8.1"),
+			expectedStatus: &v1beta1.VersionUpgradeStatus{FinalizedRelease: "3.8"},
+		},
+		{
+			testName:       "cluster already finalized",
+			cluster:        newVersionUpgradeCluster(false, "3.9.1", "3.8.1"),
+			status:         &v1beta1.VersionUpgradeStatus{FinalizedRelease: "3.7"},
+			expectedStatus: &v1beta1.VersionUpgradeStatus{FinalizedRelease: "3.7"},
+		},
+		{
+			testName: "KRaft cluster",
+			cluster:  newVersionUpgradeCluster(true, "3.9.1", "3.8.1"),
+		},
+		{
+			testName: "versions of the brokers are not known yet",
+			cluster:  newVersionUpgradeCluster(false, "", ""),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			// the cluster is rolled to a new image together with enabling the finalization
+			test.cluster.Spec.ClusterImage = "apache/kafka:4.0.0"
+			test.cluster.Status.VersionUpgrade = test.status
+			r := newStatusUpdatingReconciler(mockCtrl, mockClient, test.cluster, nil)
+
+			require.NoError(t, r.pinInterBrokerProtocolVersion(logf.Log))
+			require.Equal(t, test.expectedStatus, r.KafkaCluster.Status.VersionUpgrade)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	banzaiv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+)
+
+// fileSystemResizeGracePeriod is how long the kubelet is given to resize the file system of an expanded volume online,
+// when the resize is still pending afterwards the CSI driver requires offline resize and the broker is restarted
+const fileSystemResizeGracePeriod = 5 * time.Minute
+
+// isDesiredStorageValueExpanded tells whether the storage request of the PVC is increased
+func isDesiredStorageValueExpanded(desired, current *corev1.PersistentVolumeClaim) bool {
+	return desired.Spec.Resources.Requests.Storage().Cmp(*current.Spec.Resources.Requests.Storage()) > 0
+}
+
+// volumeExpansionAllowed checks whether the volume of the PVC can be expanded. The volumes are expanded broker by
+// broker, false is returned while the expansion of the volumes of another broker is unfinished.
+func (r *Reconciler) volumeExpansionAllowed(ctx context.Context, log logr.Logger, brokerID string, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if resizingBrokerID, mountPath, found := r.unfinishedVolumeResize(brokerID); found {
+		log.Info("volume expansion is waiting for the expansion of the volume of another broker",
+			banzaiv1beta1.BrokerIdLabelKey, brokerID, "resizingBrokerId", resizingBrokerID, mountPathAnnotationKey, mountPath)
+		return false, nil
+	}
+
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false, errorfactory.New(errorfactory.InternalError{}, errors.New("persistent volume claim has no storage class"),
+			"could not expand volume", "name", pvc.GetName())
+	}
+	storageClass := &storagev1.StorageClass{}
+	if err := r.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, storageClass); err != nil {
+		return false, errorfactory.New(errorfactory.APIFailure{}, err, "getting storage class failed", "name", *pvc.Spec.StorageClassName)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return false, errorfactory.New(errorfactory.InternalError{}, errors.New("storage class does not allow volume expansion"),
+			"could not expand volume", "name", pvc.GetName(), "storageClass", storageClass.GetName())
+	}
+	return true, nil
+}
+
+// unfinishedVolumeResize returns a broker in the spec, apart from the given one, with a volume being expanded
+func (r *Reconciler) unfinishedVolumeResize(exceptBrokerID string) (string, string, bool) {
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		brokerID := strconv.Itoa(int(broker.Id))
+		if brokerID == exceptBrokerID {
+			continue
+		}
+		for mountPath, resize := range r.KafkaCluster.Status.BrokersState[brokerID].VolumeResizes {
+			if resize.State != banzaiv1beta1.VolumeResizeSucceeded {
+				return brokerID, mountPath, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// newVolumeResize starts following the expansion of the volume of the PVC patched with the desired storage request
+func newVolumeResize(desired, current *corev1.PersistentVolumeClaim, now time.Time) banzaiv1beta1.VolumeResizeStatus {
+	resize := banzaiv1beta1.VolumeResizeStatus{
+		State:              banzaiv1beta1.VolumeResizeInProgress,
+		RequestedSize:      desired.Spec.Resources.Requests.Storage().DeepCopy(),
+		StartTime:          metav1.NewTime(now),
+		LastTransitionTime: metav1.NewTime(now),
+	}
+	if capacity, ok := current.Status.Capacity[corev1.ResourceStorage]; ok {
+		resize.Capacity = &capacity
+	}
+	return resize
+}
+
+// trackVolumeResize updates the state of the expansion of the volume from the capacity and the conditions of the PVC
+func trackVolumeResize(pvc *corev1.PersistentVolumeClaim, resize banzaiv1beta1.VolumeResizeStatus, now time.Time) banzaiv1beta1.VolumeResizeStatus {
+	tracked := *resize.DeepCopy()
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		tracked.Capacity = &capacity
+	}
+
+	state := banzaiv1beta1.VolumeResizeInProgress
+	tracked.Message = ""
+	switch fileSystemResizePending := pvcCondition(pvc, corev1.PersistentVolumeClaimFileSystemResizePending); {
+	case fileSystemResizePending != nil:
+		tracked.Message = fileSystemResizePending.Message
+		state = banzaiv1beta1.VolumeResizeFileSystemResizePending
+		// the restart is not repeated while the resize stays pending after it
+		if resize.State == banzaiv1beta1.VolumeResizeRestartRequired ||
+			now.Sub(fileSystemResizePending.LastTransitionTime.Time) >= fileSystemResizeGracePeriod {
+			state = banzaiv1beta1.VolumeResizeRestartRequired
+		}
+	case pvcCondition(pvc, corev1.PersistentVolumeClaimResizing) == nil &&
+		tracked.Capacity != nil && tracked.Capacity.Cmp(tracked.RequestedSize) >= 0:
+		state = banzaiv1beta1.VolumeResizeSucceeded
+	}
+
+	if state != tracked.State {
+		tracked.State = state
+		tracked.LastTransitionTime = metav1.NewTime(now)
+	}
+	return tracked
+}
+
+func pvcCondition(pvc *corev1.PersistentVolumeClaim, conditionType corev1.PersistentVolumeClaimConditionType) *corev1.PersistentVolumeClaimCondition {
+	for i := range pvc.Status.Conditions {
+		if pvc.Status.Conditions[i].Type == conditionType && pvc.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return &pvc.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// fileSystemResizeRequiresRestart tells whether the file system of an expanded volume of the broker can only be resized
+// offline and its pod was created before that turned out
+func (r *Reconciler) fileSystemResizeRequiresRestart(pod *corev1.Pod) bool {
+	for _, resize := range r.KafkaCluster.Status.BrokersState[pod.Labels[banzaiv1beta1.BrokerIdLabelKey]].VolumeResizes {
+		if resize.State == banzaiv1beta1.VolumeResizeRestartRequired && pod.CreationTimestamp.Before(&resize.LastTransitionTime) {
+			return true
+		}
+	}
+	return false
+}
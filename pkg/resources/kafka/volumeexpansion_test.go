@@ -0,0 +1,246 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+func newResizedPvc(capacity string, conditions ...corev1.PersistentVolumeClaimCondition) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: util.StringPointer("standard"),
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Capacity:   corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+			Conditions: conditions,
+		},
+	}
+}
+
+func TestTrackVolumeResize(t *testing.T) {
+	now := time.Now()
+	start := metav1.NewTime(now.Add(-time.Hour))
+	capacity := resource.MustParse("10Gi")
+	resize := v1beta1.VolumeResizeStatus{
+		State:              v1beta1.VolumeResizeInProgress,
+		RequestedSize:      resource.MustParse("20Gi"),
+		Capacity:           &capacity,
+		StartTime:          start,
+		LastTransitionTime: start,
+	}
+	fileSystemResizePending := func(since time.Time) corev1.PersistentVolumeClaimCondition {
+		return corev1.PersistentVolumeClaimCondition{
+			Type:               corev1.PersistentVolumeClaimFileSystemResizePending,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(since),
+			Message:            "Waiting for user to (re-)start a pod to finish file system resize of volume on node.",
+		}
+	}
+
+	tests := []struct {
+		testName         string
+		pvc              *corev1.PersistentVolumeClaim
+		state            v1beta1.VolumeResizeState
+		expectedState    v1beta1.VolumeResizeState
+		expectedCapacity string
+	}{
+		{
+			testName: "volume is being expanded",
+			pvc: newResizedPvc("10Gi", corev1.PersistentVolumeClaimCondition{
+				Type:   corev1.PersistentVolumeClaimResizing,
+				Status: corev1.ConditionTrue,
+			}),
+			state:            v1beta1.VolumeResizeInProgress,
+			expectedState:    v1beta1.VolumeResizeInProgress,
+			expectedCapacity: "10Gi",
+		},
+		{
+			testName:         "file system resize is pending",
+			pvc:              newResizedPvc("10Gi", fileSystemResizePending(now.Add(-time.Minute))),
+			state:            v1beta1.VolumeResizeInProgress,
+			expectedState:    v1beta1.VolumeResizeFileSystemResizePending,
+			expectedCapacity: "10Gi",
+		},
+		{
+			testName:         "file system resize is pending after the grace period",
+			pvc:              newResizedPvc("10Gi", fileSystemResizePending(now.Add(-fileSystemResizeGracePeriod))),
+			state:            v1beta1.VolumeResizeFileSystemResizePending,
+			expectedState:    v1beta1.VolumeResizeRestartRequired,
+			expectedCapacity: "10Gi",
+		},
+		{
+			testName:         "file system resize is pending after the restart",
+			pvc:              newResizedPvc("10Gi", fileSystemResizePending(now.Add(-time.Minute))),
+			state:            v1beta1.VolumeResizeRestartRequired,
+			expectedState:    v1beta1.VolumeResizeRestartRequired,
+			expectedCapacity: "10Gi",
+		},
+		{
+			testName:         "volume is expanded",
+			pvc:              newResizedPvc("20Gi"),
+			state:            v1beta1.VolumeResizeFileSystemResizePending,
+			expectedState:    v1beta1.VolumeResizeSucceeded,
+			expectedCapacity: "20Gi",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			current := resize
+			current.State = test.state
+
+			tracked := trackVolumeResize(test.pvc, current, now)
+
+			require.Equal(t, test.expectedState, tracked.State)
+			require.Equal(t, test.expectedCapacity, tracked.Capacity.String())
+			require.Equal(t, start, tracked.StartTime)
+			if test.expectedState != test.state {
+				require.Equal(t, metav1.NewTime(now), tracked.LastTransitionTime)
+			} else {
+				require.Equal(t, start, tracked.LastTransitionTime)
+			}
+			// the original status is not changed
+			require.Equal(t, "10Gi", resize.Capacity.String())
+		})
+	}
+}
+
+func TestVolumeExpansionAllowed(t *testing.T) {
+	tests := []struct {
+		testName        string
+		allowExpansion  *bool
+		volumeResizes   map[string]v1beta1.VolumeResizeStatus
+		expectedAllowed bool
+		expectedErr     bool
+	}{
+		{
+			testName:        "storage class allows volume expansion",
+			allowExpansion:  util.BoolPointer(true),
+			expectedAllowed: true,
+		},
+		{
+			testName:    "storage class does not allow volume expansion",
+			expectedErr: true,
+		},
+		{
+			testName:       "volume of another broker is being expanded",
+			allowExpansion: util.BoolPointer(true),
+			volumeResizes: map[string]v1beta1.VolumeResizeStatus{
+				"/kafka-logs": {State: v1beta1.VolumeResizeFileSystemResizePending},
+			},
+		},
+		{
+			testName:       "volume of another broker is expanded",
+			allowExpansion: util.BoolPointer(true),
+			volumeResizes: map[string]v1beta1.VolumeResizeStatus{
+				"/kafka-logs": {State: v1beta1.VolumeResizeSucceeded},
+			},
+			expectedAllowed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				Spec: v1beta1.KafkaClusterSpec{Brokers: []v1beta1.Broker{{Id: 0}, {Id: 1}}},
+				Status: v1beta1.KafkaClusterStatus{
+					BrokersState: map[string]v1beta1.BrokerState{"1": {VolumeResizes: test.volumeResizes}},
+				},
+			}
+			mockCtrl := gomock.NewController(t)
+			mockClient := mocks.NewMockClient(mockCtrl)
+			mockClient.EXPECT().Get(gomock.Any(), client.ObjectKey{Name: "standard"}, gomock.AssignableToTypeOf(&storagev1.StorageClass{})).Do(
+				func(ctx context.Context, key client.ObjectKey, storageClass *storagev1.StorageClass, opts ...client.GetOption) {
+					storageClass.Name = key.Name
+					storageClass.AllowVolumeExpansion = test.allowExpansion
+				}).Return(nil).AnyTimes()
+			r := New(mockClient, nil, cluster, new(kafkaclient.MockedProvider))
+
+			allowed, err := r.volumeExpansionAllowed(context.Background(), logf.Log, "0", newResizedPvc("10Gi"))
+			if test.expectedErr {
+				require.True(t, errors.As(err, &errorfactory.InternalError{}))
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.expectedAllowed, allowed)
+		})
+	}
+}
+
+func TestFileSystemResizeRequiresRestart(t *testing.T) {
+	transition := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+
+	tests := []struct {
+		testName        string
+		state           v1beta1.VolumeResizeState
+		podCreated      time.Time
+		expectedRestart bool
+	}{
+		{
+			testName:   "file system is resized online",
+			state:      v1beta1.VolumeResizeFileSystemResizePending,
+			podCreated: transition.Add(-time.Hour),
+		},
+		{
+			testName:        "file system can only be resized offline",
+			state:           v1beta1.VolumeResizeRestartRequired,
+			podCreated:      transition.Add(-time.Hour),
+			expectedRestart: true,
+		},
+		{
+			testName:   "broker is restarted to resize the file system",
+			state:      v1beta1.VolumeResizeRestartRequired,
+			podCreated: transition.Time,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{
+				Status: v1beta1.KafkaClusterStatus{
+					BrokersState: map[string]v1beta1.BrokerState{"0": {VolumeResizes: map[string]v1beta1.VolumeResizeStatus{
+						"/kafka-logs": {State: test.state, LastTransitionTime: transition},
+					}}},
+				},
+			}
+			r := New(nil, nil, cluster, new(kafkaclient.MockedProvider))
+			pod := newRollbackPod(test.podCreated, true, 0)
+
+			require.Equal(t, test.expectedRestart, r.fileSystemResizeRequiresRestart(&pod))
+		})
+	}
+}
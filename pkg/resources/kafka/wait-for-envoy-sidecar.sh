@@ -15,6 +15,7 @@
 #
 KAFKA_HOME=${KAFKA_HOME:-/opt/kafka}
 WAIT_DIR=${WAIT_DIR:-/var/run/wait}
+TERMINATION_LOG=${TERMINATION_LOG:-/dev/termination-log}
 
 if [[ -n "$ENVOY_SIDECAR_STATUS" ]]; then
   COUNT=0
@@ -30,21 +31,53 @@ if [[ -n "$ENVOY_SIDECAR_STATUS" ]]; then
     fi
   done
 fi
+# Refuse to start on a data directory of another broker or cluster, e.g. a persistent volume bound to the wrong claim,
+# the operator leaves the pod failed with exit code 78 and reports the termination message in a status condition.
+# CLUSTER_ID is only set in KRaft mode, the cluster id of a ZooKeeper based cluster is not checked
+if [[ -n "${LOG_DIRS}" && -n "${BROKER_ID}" ]]; then
+  IFS=',' read -ra LOGS <<< "${LOG_DIRS}"
+  for LOG in "${LOGS[@]}"; do
+    META_PROPERTIES="${LOG}/kafka/meta.properties"
+    if [ ! -f "${META_PROPERTIES}" ]; then
+      continue
+    fi
+    FOUND_NODE_ID=$(sed -n -e 's/^node\.id=//p' -e 's/^broker\.id=//p' "${META_PROPERTIES}" | head -n 1)
+    FOUND_CLUSTER_ID=$(sed -n 's/^cluster\.id=//p' "${META_PROPERTIES}" | head -n 1)
+    MISMATCH=""
+    if [[ -n "${FOUND_NODE_ID}" && "${FOUND_NODE_ID}" != "${BROKER_ID}" ]]; then
+      MISMATCH="node id ${FOUND_NODE_ID} instead of ${BROKER_ID}"
+    fi
+    if [[ -n "${CLUSTER_ID}" && -n "${FOUND_CLUSTER_ID}" && "${FOUND_CLUSTER_ID}" != "${CLUSTER_ID}" ]]; then
+      MISMATCH="${MISMATCH:+${MISMATCH}, }cluster id ${FOUND_CLUSTER_ID} instead of ${CLUSTER_ID}"
+    fi
+    if [[ -n "${MISMATCH}" ]]; then
+      echo "foreign data directory ${LOG}: meta.properties has ${MISMATCH}" | tee "${TERMINATION_LOG}"
+      exit 78
+    fi
+  done
+fi
+
 touch ${WAIT_DIR}/do-not-exit-yet
 
 # A few necessary steps if we are in KRaft mode
 if [[ -n "${CLUSTER_ID}" ]]; then
   # If the storage is already formatted (e.g. broker restarts), the kafka-storage.sh will skip formatting for that storage
   # thus we can safely run the storage format command regardless if the storage has been formatted or not
-  echo "Formatting KRaft storage with cluster ID ${CLUSTER_ID}"
-  ${KAFKA_HOME}/bin/kafka-storage.sh format --cluster-id="${CLUSTER_ID}" --ignore-formatted -c /config/broker-config
+  # Controllers of a dynamic quorum are formatted either to form the quorum or to join it as observers
+  FORMAT_ARGS=""
+  if [ -f /config/kraft-storage-format ]; then
+    FORMAT_ARGS=$(cat /config/kraft-storage-format)
+  fi
+  echo "Formatting KRaft storage with cluster ID ${CLUSTER_ID} ${FORMAT_ARGS}"
+  ${KAFKA_HOME}/bin/kafka-storage.sh format --cluster-id="${CLUSTER_ID}" --ignore-formatted -c /config/broker-config ${FORMAT_ARGS}
 
   # Adding or removing controller nodes to the Kafka cluster would trigger cluster rolling upgrade so all the nodes in the cluster are aware of the newly added/removed controllers.
   # When this happens, Kafka's local quorum state file would be outdated since it is static and the Kafka server can't be started with conflicting controllers info (compared to info stored in ConfigMap),
   # so we need to wipe out the local state files before starting the server so the information about the controller nodes is up-to-date with what is stored in ConfigMap
   # (Note: although we don't know if the server start-up is due to scaling up/down of the controller nodes, it is not harmful to remove the quorum state file before the server start-up process
   #  because the server will re-create the quorum state file after it starts up successfully)
-  if [[ -n "${LOG_DIRS}" ]]; then
+  # A dynamic quorum keeps its voters in the metadata log and does not need this.
+  if [[ -n "${LOG_DIRS}" ]] && ! grep -q "^controller.quorum.bootstrap.servers=" /config/broker-config; then
     IFS=',' read -ra LOGS <<< "${LOG_DIRS}"
     for LOG in "${LOGS[@]}"; do
       QUORUM_STATE_FILE="${LOG}/kafka/__cluster_metadata-0/quorum-state"
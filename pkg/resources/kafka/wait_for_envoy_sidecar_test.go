@@ -19,6 +19,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -97,3 +98,97 @@ func TestWaitForEnvoySidecarExitCodes(t *testing.T) {
 		})
 	}
 }
+
+// TestWaitForEnvoySidecarForeignDataDirectory verifies that the script refuses to start the broker on a log dir whose
+// meta.properties belongs to another broker or cluster.
+func TestWaitForEnvoySidecarForeignDataDirectory(t *testing.T) {
+	tests := []struct {
+		name           string
+		metaProperties string
+		clusterID      string
+		expectedExit   int
+		expectedReason string
+	}{
+		{
+			name:           "log dir of the broker",
+			metaProperties: "version=0\nbroker.id=1\ncluster.id=zk-cluster\n",
+			expectedExit:   0,
+		},
+		{
+			name:           "log dir of another broker",
+			metaProperties: "version=1\nnode.id=2\ncluster.id=kraft-cluster\n",
+			clusterID:      "kraft-cluster",
+			expectedExit:   foreignDataDirectoryExitCode,
+			expectedReason: "meta.properties has node id 2 instead of 1",
+		},
+		{
+			name:           "log dir of another cluster",
+			metaProperties: "version=1\nnode.id=1\ncluster.id=other-cluster\n",
+			clusterID:      "kraft-cluster",
+			expectedExit:   foreignDataDirectoryExitCode,
+			expectedReason: "meta.properties has cluster id other-cluster instead of kraft-cluster",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tmp := t.TempDir()
+
+			binDir := filepath.Join(tmp, "bin")
+			if err := os.MkdirAll(binDir, 0o755); err != nil {
+				t.Fatalf("create bin dir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(binDir, "kafka-server-start.sh"), []byte("#!/bin/bash\nexit 0\n"), 0o755); err != nil {
+				t.Fatalf("write stub: %v", err)
+			}
+			waitDir := filepath.Join(tmp, "wait")
+			if err := os.MkdirAll(waitDir, 0o755); err != nil {
+				t.Fatalf("create wait dir: %v", err)
+			}
+			logDir := filepath.Join(tmp, "kafka-logs")
+			if err := os.MkdirAll(filepath.Join(logDir, "kafka"), 0o755); err != nil {
+				t.Fatalf("create log dir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(logDir, "kafka", "meta.properties"), []byte(tc.metaProperties), 0o644); err != nil {
+				t.Fatalf("write meta.properties: %v", err)
+			}
+			terminationLog := filepath.Join(tmp, "termination-log")
+
+			cmd := exec.Command("bash", "-c", envoySidecarScript)
+			cmd.Env = []string{
+				"KAFKA_HOME=" + tmp,
+				"WAIT_DIR=" + waitDir,
+				"TERMINATION_LOG=" + terminationLog,
+				"LOG_DIRS=" + filepath.Join(tmp, "empty") + "," + logDir,
+				"BROKER_ID=1",
+				"PATH=" + os.Getenv("PATH"),
+			}
+			if tc.clusterID != "" {
+				cmd.Env = append(cmd.Env, "CLUSTER_ID="+tc.clusterID)
+			}
+
+			err := cmd.Run()
+
+			got := 0
+			if err != nil {
+				if exitErr, ok := err.(*exec.ExitError); ok {
+					got = exitErr.ExitCode()
+				} else {
+					t.Fatalf("unexpected error running script: %v", err)
+				}
+			}
+			if got != tc.expectedExit {
+				t.Errorf("want script exit %d, got %d", tc.expectedExit, got)
+			}
+			if tc.expectedReason != "" {
+				message, err := os.ReadFile(terminationLog)
+				if err != nil {
+					t.Fatalf("read termination log: %v", err)
+				}
+				if !strings.Contains(string(message), tc.expectedReason) {
+					t.Errorf("termination message %q does not contain %q", message, tc.expectedReason)
+				}
+			}
+		})
+	}
+}
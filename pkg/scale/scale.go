@@ -70,6 +70,10 @@ var (
 	removeDisksSupportedParams = map[string]struct{}{
 		ParamBrokerIDAndLogDirs: {},
 	}
+	fixOfflineReplicasSupportedParams = map[string]struct{}{
+		ParamExcludeDemoted: {},
+		ParamExcludeRemoved: {},
+	}
 )
 
 func ScaleFactoryFn() func(ctx context.Context, kafkaCluster *v1beta1.KafkaCluster) (CruiseControlScaler, error) {
@@ -615,6 +619,58 @@ func (cc *cruiseControlScaler) RebalanceWithParams(ctx context.Context, params m
 	}, nil
 }
 
+// FixOfflineReplicasWithParams requests Cruise Control to move the offline replicas of the cluster to the healthy
+// log directories.
+func (cc *cruiseControlScaler) FixOfflineReplicasWithParams(ctx context.Context, params map[string]string) (*Result, error) {
+	fixReq := &api.FixOfflineReplicasRequest{
+		AllowCapacityEstimation: true,
+		DataFrom:                types.ProposalDataSourceValidWindows,
+		UseReadyDefaultGoals:    true,
+	}
+
+	for param, pvalue := range params {
+		if _, ok := fixOfflineReplicasSupportedParams[param]; ok {
+			switch param {
+			case ParamExcludeDemoted:
+				ret, err := strconv.ParseBool(pvalue)
+				if err != nil {
+					return nil, err
+				}
+				fixReq.ExcludeRecentlyDemotedBrokers = ret
+			case ParamExcludeRemoved:
+				ret, err := strconv.ParseBool(pvalue)
+				if err != nil {
+					return nil, err
+				}
+				fixReq.ExcludeRecentlyRemovedBrokers = ret
+			default:
+				return nil, fmt.Errorf("unsupported %s parameter: %s, supported parameters: %s", v1alpha1.OperationFixOfflineReplicas, param, fixOfflineReplicasSupportedParams)
+			}
+		}
+	}
+
+	fixResp, err := cc.client.FixOfflineReplicas(ctx, fixReq)
+	if err != nil {
+		return &Result{
+			TaskID:             fixResp.TaskID,
+			StartedAt:          fixResp.Date,
+			ResponseStatusCode: fixResp.StatusCode,
+			RequestURL:         fixResp.RequestURL,
+			State:              v1beta1.CruiseControlTaskCompletedWithError,
+			Err:                err,
+		}, err
+	}
+
+	return &Result{
+		TaskID:             fixResp.TaskID,
+		StartedAt:          fixResp.Date,
+		ResponseStatusCode: fixResp.StatusCode,
+		RequestURL:         fixResp.RequestURL,
+		Result:             fixResp.Result,
+		State:              v1beta1.CruiseControlTaskActive,
+	}, nil
+}
+
 func (cc *cruiseControlScaler) RemoveDisksWithParams(ctx context.Context, params map[string]string) (*Result, error) {
 	removeReq := &api.RemoveDisksRequest{}
 
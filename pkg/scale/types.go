@@ -37,6 +37,7 @@ type CruiseControlScaler interface {
 	StopExecution(ctx context.Context) (*Result, error)
 	RemoveBrokers(ctx context.Context, brokerIDs ...string) (*Result, error)
 	RemoveDisksWithParams(ctx context.Context, params map[string]string) (*Result, error)
+	FixOfflineReplicasWithParams(ctx context.Context, params map[string]string) (*Result, error)
 	RebalanceDisks(ctx context.Context, brokerIDs ...string) (*Result, error)
 	BrokersWithState(ctx context.Context, states ...KafkaBrokerState) ([]string, error)
 	KafkaClusterState(ctx context.Context) (*types.KafkaClusterState, error)
@@ -231,6 +231,101 @@ func GenerateJKS(certs []*x509.Certificate, privateKey []byte) (out, passw []byt
 	return outBuf.Bytes(), password, err
 }
 
+// KeystoreEntries returns the secret entries holding the keystore, truststore and password of a keystore format
+func KeystoreEntries(format v1alpha1.KeystoreFormat) []string {
+	switch format {
+	case v1alpha1.KeystoreFormatJKS:
+		return []string{v1alpha1.TLSJKSKeyStore, v1alpha1.TLSJKSTrustStore, v1alpha1.PasswordKey}
+	case v1alpha1.KeystoreFormatPKCS12:
+		return []string{v1alpha1.TLSPKCS12KeyStore, v1alpha1.TLSPKCS12TrustStore, v1alpha1.PKCS12PasswordKey}
+	case v1alpha1.KeystoreFormatPEMBundle:
+		return []string{v1alpha1.PEMBundleKeyStore, v1alpha1.PEMBundleTrustStore, v1alpha1.PEMBundlePasswordKey}
+	default:
+		return nil
+	}
+}
+
+// RenderKeystores renders the requested keystore formats of a certificate chain and its private key into
+// secret data. Keystores which are already present are kept so that their passwords stay stable.
+// It returns whether the secret data has been changed.
+func RenderKeystores(data map[string][]byte, formats []v1alpha1.KeystoreFormat,
+	chain []*x509.Certificate, caCerts []*x509.Certificate, privateKey []byte) (bool, error) {
+	changed := false
+	for _, format := range MissingKeystores(data, formats) {
+		switch format {
+		case v1alpha1.KeystoreFormatJKS:
+			keystore, passw, err := GenerateJKS(chain, privateKey)
+			if err != nil {
+				return false, errors.WrapIf(err, "could not generate JKS keystore")
+			}
+			data[v1alpha1.TLSJKSKeyStore] = keystore
+			// the JKS keystore holds the CA certificates as trusted entries so it is used as truststore as well
+			data[v1alpha1.TLSJKSTrustStore] = keystore
+			data[v1alpha1.PasswordKey] = passw
+		case v1alpha1.KeystoreFormatPKCS12:
+			keystore, passw, err := GeneratePKCS12(chain, privateKey)
+			if err != nil {
+				return false, errors.WrapIf(err, "could not generate PKCS#12 keystore")
+			}
+			truststore, err := GeneratePKCS12TrustStore(caCerts, passw)
+			if err != nil {
+				return false, errors.WrapIf(err, "could not generate PKCS#12 truststore")
+			}
+			data[v1alpha1.TLSPKCS12KeyStore] = keystore
+			data[v1alpha1.TLSPKCS12TrustStore] = truststore
+			data[v1alpha1.PKCS12PasswordKey] = passw
+		case v1alpha1.KeystoreFormatPEMBundle:
+			if len(caCerts) == 0 {
+				return false, errors.WrapIf(errorNoCertificates, "could not generate PEM truststore")
+			}
+			keystore, passw, err := GeneratePEMBundle(chain, privateKey)
+			if err != nil {
+				return false, errors.WrapIf(err, "could not generate PEM keystore")
+			}
+			var truststore []byte
+			for _, caCert := range caCerts {
+				truststore = append(truststore, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})...)
+			}
+			data[v1alpha1.PEMBundleKeyStore] = keystore
+			data[v1alpha1.PEMBundleTrustStore] = truststore
+			data[v1alpha1.PEMBundlePasswordKey] = passw
+		default:
+			return false, errors.NewWithDetails("unsupported keystore format", "format", format)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// DeleteKeystores removes every rendered keystore from secret data
+func DeleteKeystores(data map[string][]byte) {
+	for _, format := range []v1alpha1.KeystoreFormat{
+		v1alpha1.KeystoreFormatJKS, v1alpha1.KeystoreFormatPKCS12, v1alpha1.KeystoreFormatPEMBundle} {
+		for _, entry := range KeystoreEntries(format) {
+			delete(data, entry)
+		}
+	}
+}
+
+// MissingKeystores returns the keystore formats which are not rendered into secret data yet
+func MissingKeystores(data map[string][]byte, formats []v1alpha1.KeystoreFormat) []v1alpha1.KeystoreFormat {
+	var missing []v1alpha1.KeystoreFormat
+	for _, format := range formats {
+		entries := KeystoreEntries(format)
+		rendered := len(entries) > 0
+		for _, entry := range entries {
+			if len(data[entry]) == 0 {
+				rendered = false
+				break
+			}
+		}
+		if !rendered {
+			missing = append(missing, format)
+		}
+	}
+	return missing
+}
+
 // GenerateTestCert is used from unit tests for generating certificates
 func GenerateTestCert() (cert, key []byte, expectedDn string, err error) {
 	priv, serialNumber, err := generatePrivateKey()
@@ -0,0 +1,179 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+
+	"emperror.dev/errors"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// The PKCS#12 keystores are encoded with the Modern2023 profile of go-pkcs12 (the OpenSSL 3 default): PBES2 with
+// PBKDF2-HMAC-SHA256 and AES-256-CBC for the shrouded key and certificates, and HMAC-SHA256 for the integrity MAC.
+// These are supported by Java 12+ and OpenSSL 1.1.1+ based clients. The private key of the PEM bundle is encrypted
+// with the same algorithms.
+const (
+	pbkdf2Iterations   = 2048
+	pbkdf2SaltLen      = 16
+	encryptedKeyPEMTyp = "ENCRYPTED PRIVATE KEY"
+)
+
+var errorNoCertificates = errors.New("no certificates provided")
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHmacWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type encryptedPrivateKeyInfo struct {
+	AlgorithmIdentifier pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int `asn1:"optional"`
+	Prf        pkix.AlgorithmIdentifier
+}
+
+// GeneratePKCS12 creates a PKCS#12 keystore with a random password from a client cert/key combination
+func GeneratePKCS12(certs []*x509.Certificate, privateKey []byte) (out, passw []byte, err error) {
+	if len(certs) == 0 {
+		return nil, nil, errorNoCertificates
+	}
+	pKeyRaw, err := DecodePrivateKeyBytes(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	passw = GeneratePass(16)
+	out, err = pkcs12.Modern2023.Encode(pKeyRaw, certs[0], certs[1:], string(passw))
+	if err != nil {
+		return nil, nil, errors.WrapIf(err, "could not encode PKCS#12 keystore")
+	}
+	return out, passw, nil
+}
+
+// GeneratePKCS12TrustStore creates a PKCS#12 truststore protected with the given password from CA certificates
+func GeneratePKCS12TrustStore(caCerts []*x509.Certificate, password []byte) ([]byte, error) {
+	if len(caCerts) == 0 {
+		return nil, errorNoCertificates
+	}
+	out, err := pkcs12.Modern2023.EncodeTrustStore(caCerts, string(password))
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not encode PKCS#12 truststore")
+	}
+	return out, nil
+}
+
+// GeneratePEMBundle creates a PEM keystore holding the private key encrypted with a random password
+// followed by the certificate chain
+func GeneratePEMBundle(certs []*x509.Certificate, privateKey []byte) (out, passw []byte, err error) {
+	if len(certs) == 0 {
+		return nil, nil, errorNoCertificates
+	}
+	pKeyRaw, err := DecodePrivateKeyBytes(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	pKeyPKCS8, err := x509.MarshalPKCS8PrivateKey(pKeyRaw)
+	if err != nil {
+		return nil, nil, err
+	}
+	passw = GeneratePass(16)
+	encryptedKey, err := encryptPKCS8PrivateKey(pKeyPKCS8, passw)
+	if err != nil {
+		return nil, nil, err
+	}
+	out = pem.EncodeToMemory(&pem.Block{Type: encryptedKeyPEMTyp, Bytes: encryptedKey})
+	for _, cert := range certs {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return out, passw, nil
+}
+
+// encryptPKCS8PrivateKey returns the DER encoded EncryptedPrivateKeyInfo of a PKCS#8 private key
+func encryptPKCS8PrivateKey(pKeyPKCS8, password []byte) ([]byte, error) {
+	algorithm, encrypted, err := pbes2Encrypt(pKeyPKCS8, password)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(encryptedPrivateKeyInfo{AlgorithmIdentifier: algorithm, EncryptedData: encrypted})
+}
+
+// pbes2Encrypt encrypts data with PBES2 (RFC 8018) using PBKDF2-HMAC-SHA256 and AES-256-CBC
+func pbes2Encrypt(data, password []byte) (pkix.AlgorithmIdentifier, []byte, error) {
+	salt := make([]byte, pbkdf2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	key, err := pbkdf2.Key(sha256.New, string(password), salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	encrypted := make([]byte, len(data)+padding)
+	copy(encrypted, data)
+	for i := len(data); i < len(encrypted); i++ {
+		encrypted[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:       salt,
+		Iterations: pbkdf2Iterations,
+		Prf:        pkix.AlgorithmIdentifier{Algorithm: oidHmacWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	return pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}}, encrypted, nil
+}
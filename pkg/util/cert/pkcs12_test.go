@@ -0,0 +1,205 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"testing"
+
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+)
+
+// pbes2Decrypt reverses pbes2Encrypt
+func pbes2Decrypt(t *testing.T, algorithm asn1.ObjectIdentifier, params, encrypted, password []byte) []byte {
+	t.Helper()
+	if !algorithm.Equal(oidPBES2) {
+		t.Fatal("Expected PBES2 encryption, got:", algorithm)
+	}
+	var pbes2 pbes2Params
+	if _, err := asn1.Unmarshal(params, &pbes2); err != nil {
+		t.Fatal("Failed to parse PBES2 parameters:", err)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(pbes2.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		t.Fatal("Failed to parse PBKDF2 parameters:", err)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(pbes2.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		t.Fatal("Failed to parse IV:", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, string(password), kdf.Salt, kdf.Iterations, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, encrypted)
+	return decrypted[:len(decrypted)-int(decrypted[len(decrypted)-1])]
+}
+
+func decryptPrivateKey(t *testing.T, der, password []byte) any {
+	t.Helper()
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		t.Fatal("Failed to parse encrypted private key:", err)
+	}
+	decrypted := pbes2Decrypt(t, info.AlgorithmIdentifier.Algorithm,
+		info.AlgorithmIdentifier.Parameters.FullBytes, info.EncryptedData, password)
+	key, err := x509.ParsePKCS8PrivateKey(decrypted)
+	if err != nil {
+		t.Fatal("Failed to parse decrypted private key:", err)
+	}
+	return key
+}
+
+func generateTestCertBundle(t *testing.T) ([]*x509.Certificate, []byte) {
+	t.Helper()
+	cert, key, _, err := GenerateTestCert()
+	if err != nil {
+		t.Fatal("Failed to generate test certificate")
+	}
+	certs, err := ParseCertificates(cert)
+	if err != nil {
+		t.Fatal("Failed to parse test certificate")
+	}
+	return GetCertBundle(certs), key
+}
+
+func TestGeneratePKCS12(t *testing.T) {
+	certs, key := generateTestCertBundle(t)
+	expectedKey, err := DecodePrivateKeyBytes(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keystore, password, err := GeneratePKCS12(certs, key)
+	if err != nil {
+		t.Fatal("Expected to generate PKCS#12 keystore, got error:", err)
+	}
+	keystoreKey, keystoreCert, caCerts, err := pkcs12.DecodeChain(keystore, string(password))
+	if err != nil {
+		t.Fatal("Expected to decode PKCS#12 keystore, got error:", err)
+	}
+	if !keystoreCert.Equal(certs[0]) || len(caCerts) != 0 {
+		t.Error("Expected the certificate in the keystore")
+	}
+	if !keystoreKey.(*rsa.PrivateKey).Equal(expectedKey) {
+		t.Error("Expected the private key in the keystore")
+	}
+
+	truststore, err := GeneratePKCS12TrustStore(certs, password)
+	if err != nil {
+		t.Fatal("Expected to generate PKCS#12 truststore, got error:", err)
+	}
+	truststoreCerts, err := pkcs12.DecodeTrustStore(truststore, string(password))
+	if err != nil {
+		t.Fatal("Expected to decode PKCS#12 truststore, got error:", err)
+	}
+	if len(truststoreCerts) != 1 || !truststoreCerts[0].Equal(certs[0]) {
+		t.Error("Expected the CA certificate in the truststore")
+	}
+
+	if _, _, err = GeneratePKCS12(nil, key); err == nil {
+		t.Error("Expected to fail without certificates, got nil error")
+	}
+	if _, _, err = GeneratePKCS12(certs, key[:len(key)-10]); err == nil {
+		t.Error("Expected to fail decoding key, got nil error")
+	}
+	if _, err = GeneratePKCS12TrustStore(nil, password); err == nil {
+		t.Error("Expected to fail without CA certificates, got nil error")
+	}
+}
+
+func TestGeneratePEMBundle(t *testing.T) {
+	certs, key := generateTestCertBundle(t)
+	expectedKey, err := DecodePrivateKeyBytes(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, password, err := GeneratePEMBundle(certs, key)
+	if err != nil {
+		t.Fatal("Expected to generate PEM bundle, got error:", err)
+	}
+	keyBlock, rest := pem.Decode(bundle)
+	if keyBlock == nil || keyBlock.Type != encryptedKeyPEMTyp {
+		t.Fatal("Expected the bundle to start with an encrypted private key")
+	}
+	if !decryptPrivateKey(t, keyBlock.Bytes, password).(*rsa.PrivateKey).Equal(expectedKey) {
+		t.Error("Expected the decrypted private key to match the original one")
+	}
+	bundleCerts, err := ParseCertificates(rest)
+	if err != nil || len(bundleCerts) != 1 || !bundleCerts[0].Certificate.Equal(certs[0]) {
+		t.Error("Expected the certificate chain after the private key, got error:", err)
+	}
+}
+
+func TestRenderKeystores(t *testing.T) {
+	certs, key := generateTestCertBundle(t)
+	formats := []v1alpha1.KeystoreFormat{
+		v1alpha1.KeystoreFormatJKS, v1alpha1.KeystoreFormatPKCS12, v1alpha1.KeystoreFormatPEMBundle}
+	data := map[string][]byte{}
+
+	changed, err := RenderKeystores(data, formats, certs, certs, key)
+	if err != nil {
+		t.Fatal("Expected to render keystores, got error:", err)
+	}
+	if !changed {
+		t.Error("Expected secret data to be changed")
+	}
+	if missing := MissingKeystores(data, formats); len(missing) != 0 {
+		t.Error("Expected every keystore to be rendered, missing:", missing)
+	}
+	if bytes.Equal(data[v1alpha1.PasswordKey], data[v1alpha1.PKCS12PasswordKey]) ||
+		bytes.Equal(data[v1alpha1.PKCS12PasswordKey], data[v1alpha1.PEMBundlePasswordKey]) {
+		t.Error("Expected every keystore format to get its own password")
+	}
+	if _, err := pkcs12.DecodeTrustStore(data[v1alpha1.TLSPKCS12TrustStore], string(data[v1alpha1.PKCS12PasswordKey])); err != nil {
+		t.Error("Expected the PKCS#12 truststore to be protected with the PKCS#12 password, got error:", err)
+	}
+	if !bytes.Equal(data[v1alpha1.PEMBundleTrustStore], pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certs[0].Raw})) {
+		t.Error("Expected the CA certificate in the PEM truststore")
+	}
+
+	password := data[v1alpha1.PKCS12PasswordKey]
+	if changed, err = RenderKeystores(data, formats, certs, certs, key); err != nil || changed {
+		t.Error("Expected already rendered keystores to be kept, got changed:", changed, "error:", err)
+	}
+	if !bytes.Equal(password, data[v1alpha1.PKCS12PasswordKey]) {
+		t.Error("Expected the PKCS#12 password to be stable")
+	}
+
+	DeleteKeystores(data)
+	if len(data) != 0 {
+		t.Error("Expected every keystore to be deleted, got:", len(data))
+	}
+
+	if _, err = RenderKeystores(data, []v1alpha1.KeystoreFormat{v1alpha1.KeystoreFormatPEMBundle}, certs, nil, key); err == nil {
+		t.Error("Expected to fail rendering a truststore without CA certificates, got nil error")
+	}
+}
@@ -60,6 +60,23 @@ func getContainerPortForInnerCom(internalListeners []v1beta1.InternalListenerCon
 	return 0
 }
 
+// GetInnerBrokerListener returns the spec of the listener the operator connects to the brokers through
+func GetInnerBrokerListener(cluster *v1beta1.KafkaCluster) *v1beta1.CommonListenerSpec {
+	for i := range cluster.Spec.ListenersConfig.InternalListeners {
+		listener := &cluster.Spec.ListenersConfig.InternalListeners[i].CommonListenerSpec
+		if listener.UsedForKafkaAdminCommunication || listener.UsedForInnerBrokerCommunication {
+			return listener
+		}
+	}
+	for i := range cluster.Spec.ListenersConfig.ExternalListeners {
+		listener := &cluster.Spec.ListenersConfig.ExternalListeners[i].CommonListenerSpec
+		if listener.UsedForKafkaAdminCommunication || listener.UsedForInnerBrokerCommunication {
+			return listener
+		}
+	}
+	return nil
+}
+
 func GenerateKafkaAddressWithoutPort(cluster *v1beta1.KafkaCluster) string {
 	if cluster.Spec.HeadlessServiceEnabled {
 		return fmt.Sprintf("%s.%s.svc.%s",
@@ -0,0 +1,172 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+// directoryConfigProvider resolves the keystore passwords from the files of the mounted user secret so that
+// they are never inlined into the client configuration
+const (
+	directoryConfigProviderName  = "dir"
+	directoryConfigProviderClass = "org.apache.kafka.common.config.provider.DirectoryConfigProvider"
+)
+
+// ClientBundleListener returns the bootstrap servers and the security protocol of a cluster listener
+func ClientBundleListener(cluster *v1beta1.KafkaCluster, listenerName string) (string, v1beta1.SecurityProtocol, error) {
+	var protocol v1beta1.SecurityProtocol
+	var statuses v1beta1.ListenerStatusList
+	for _, listener := range cluster.Spec.ListenersConfig.InternalListeners {
+		if listener.Name == listenerName {
+			protocol = listener.Type
+			statuses = cluster.Status.ListenerStatuses.InternalListeners[listenerName]
+		}
+	}
+	for _, listener := range cluster.Spec.ListenersConfig.ExternalListeners {
+		if listener.Name == listenerName {
+			protocol = listener.Type
+			statuses = cluster.Status.ListenerStatuses.ExternalListeners[listenerName]
+		}
+	}
+	if protocol == "" {
+		return "", "", errors.NewWithDetails("listener not found in the cluster", "listener", listenerName)
+	}
+	if len(statuses) == 0 {
+		return "", protocol, errors.NewWithDetails("listener status is not available yet", "listener", listenerName)
+	}
+
+	// prefer the address which load balances across every broker
+	brokerAddresses := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		if status.Name == "headless" || strings.HasPrefix(status.Name, "any-broker") {
+			return status.Address, protocol, nil
+		}
+		brokerAddresses = append(brokerAddresses, status.Address)
+	}
+	return strings.Join(brokerAddresses, ","), protocol, nil
+}
+
+// RenderClientBundle renders the Java and librdkafka client configurations of a KafkaUser. The credential
+// files are referenced from the user secret mounted at the mount path of the bundle.
+func RenderClientBundle(bundle *v1alpha1.ClientBundleSpec, bootstrapServers string,
+	protocol v1beta1.SecurityProtocol, secretData map[string][]byte) (map[string]string, error) {
+	mountPath := bundle.GetMountPath()
+	secretFile := func(key string) string {
+		return path.Join(mountPath, key)
+	}
+	secretPassword := func(key string) string {
+		return fmt.Sprintf("${%s:%s:%s}", directoryConfigProviderName, mountPath, key)
+	}
+
+	javaConfig := []string{
+		KafkaConfigBoostrapServers, bootstrapServers,
+		KafkaConfigSecurityProtocol, protocol.ToUpperString(),
+	}
+	librdkafkaConfig := []string{
+		KafkaConfigBoostrapServers, bootstrapServers,
+		KafkaConfigSecurityProtocol, strings.ToLower(protocol.ToUpperString()),
+	}
+
+	if protocol.IsSasl() && bundle.SASLMechanism != "" {
+		javaConfig = append(javaConfig, "sasl.mechanism", bundle.SASLMechanism)
+		librdkafkaConfig = append(librdkafkaConfig, "sasl.mechanisms", bundle.SASLMechanism)
+	}
+
+	if protocol.IsSSL() {
+		caKey := v1alpha1.CoreCACertKey
+		if _, ok := secretData[caKey]; !ok {
+			// the k8s-csr backend stores the CA chain under a different key
+			caKey = v1alpha1.CaChainPem
+		}
+		librdkafkaConfig = append(librdkafkaConfig, "ssl.ca.location", secretFile(caKey))
+		if _, ok := secretData[corev1.TLSCertKey]; ok {
+			librdkafkaConfig = append(librdkafkaConfig,
+				"ssl.certificate.location", secretFile(corev1.TLSCertKey),
+				"ssl.key.location", secretFile(corev1.TLSPrivateKeyKey))
+		}
+
+		javaConfig = append(javaConfig,
+			"config.providers", directoryConfigProviderName,
+			fmt.Sprintf("config.providers.%s.class", directoryConfigProviderName), directoryConfigProviderClass)
+		switch {
+		case hasEntries(secretData, certutil.KeystoreEntries(v1alpha1.KeystoreFormatPKCS12)...):
+			javaConfig = append(javaConfig, javaStoreConfig("PKCS12", secretFile(v1alpha1.TLSPKCS12KeyStore),
+				secretFile(v1alpha1.TLSPKCS12TrustStore), secretPassword(v1alpha1.PKCS12PasswordKey))...)
+		case hasEntries(secretData, certutil.KeystoreEntries(v1alpha1.KeystoreFormatJKS)...):
+			javaConfig = append(javaConfig, javaStoreConfig("JKS", secretFile(v1alpha1.TLSJKSKeyStore),
+				secretFile(v1alpha1.TLSJKSTrustStore), secretPassword(v1alpha1.PasswordKey))...)
+		case hasEntries(secretData, certutil.KeystoreEntries(v1alpha1.KeystoreFormatPEMBundle)...):
+			javaConfig = append(javaConfig,
+				KafkaConfigSSLKeystoreType, "PEM",
+				KafkaConfigSSLKeyStoreLocation, secretFile(v1alpha1.PEMBundleKeyStore),
+				KafkaConfigSSLKeyPassword, secretPassword(v1alpha1.PEMBundlePasswordKey),
+				KafkaConfigSSLTrustStoreType, "PEM",
+				KafkaConfigSSLTrustStoreLocation, secretFile(v1alpha1.PEMBundleTrustStore))
+		default:
+			// without a rendered keystore only the server certificate can be verified
+			javaConfig = append(javaConfig,
+				KafkaConfigSSLTrustStoreType, "PEM",
+				KafkaConfigSSLTrustStoreLocation, secretFile(caKey))
+		}
+	}
+
+	bundleData := make(map[string]string, 2)
+	for key, config := range map[string][]string{
+		v1alpha1.ClientPropertiesKey: javaConfig,
+		v1alpha1.LibrdkafkaConfigKey: librdkafkaConfig,
+	} {
+		props := properties.NewProperties()
+		for i := 0; i < len(config); i += 2 {
+			if err := props.Set(config[i], config[i+1]); err != nil {
+				return nil, errors.WrapIfWithDetails(err, "could not set client bundle property", "property", config[i])
+			}
+		}
+		bundleData[key] = props.String()
+	}
+	return bundleData, nil
+}
+
+// javaStoreConfig returns the Java client properties of a keystore and truststore protected with the same password
+func javaStoreConfig(storeType, keystore, truststore, password string) []string {
+	return []string{
+		KafkaConfigSSLKeystoreType, storeType,
+		KafkaConfigSSLKeyStoreLocation, keystore,
+		KafkaConfigSSLKeyStorePassword, password,
+		KafkaConfigSSLKeyPassword, password,
+		KafkaConfigSSLTrustStoreType, storeType,
+		KafkaConfigSSLTrustStoreLocation, truststore,
+		KafkaConfigSSLTrustStorePassword, password,
+	}
+}
+
+func hasEntries(data map[string][]byte, keys ...string) bool {
+	for _, key := range keys {
+		if len(data[key]) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func TestClientBundleListener(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		Spec: v1beta1.KafkaClusterSpec{
+			ListenersConfig: v1beta1.ListenersConfig{
+				InternalListeners: []v1beta1.InternalListenerConfig{
+					{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "internal", Type: v1beta1.SecurityProtocolSSL}},
+					{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "pending", Type: v1beta1.SecurityProtocolPlaintext}},
+				},
+				ExternalListeners: []v1beta1.ExternalListenerConfig{
+					{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "external", Type: v1beta1.SecurityProtocolSaslSSL}},
+				},
+			},
+		},
+		Status: v1beta1.KafkaClusterStatus{
+			ListenerStatuses: v1beta1.ListenerStatuses{
+				InternalListeners: map[string]v1beta1.ListenerStatusList{
+					"internal": {
+						{Name: "any-broker", Address: "kafka-all-broker.kafka.svc.cluster.local:29092"},
+						{Name: "broker-0", Address: "kafka-0.kafka.svc.cluster.local:29092"},
+					},
+				},
+				ExternalListeners: map[string]v1beta1.ListenerStatusList{
+					"external": {
+						{Name: "broker-0", Address: "kafka-0.example.com:9094"},
+						{Name: "broker-1", Address: "kafka-1.example.com:9094"},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		listener         string
+		bootstrapServers string
+		protocol         v1beta1.SecurityProtocol
+		expectError      bool
+	}{
+		{
+			listener:         "internal",
+			bootstrapServers: "kafka-all-broker.kafka.svc.cluster.local:29092",
+			protocol:         v1beta1.SecurityProtocolSSL,
+		},
+		{
+			listener:         "external",
+			bootstrapServers: "kafka-0.example.com:9094,kafka-1.example.com:9094",
+			protocol:         v1beta1.SecurityProtocolSaslSSL,
+		},
+		{
+			listener:    "pending",
+			protocol:    v1beta1.SecurityProtocolPlaintext,
+			expectError: true,
+		},
+		{
+			listener:    "missing",
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		bootstrapServers, protocol, err := ClientBundleListener(cluster, test.listener)
+		if test.expectError != (err != nil) {
+			t.Errorf("listener %s: expected error %t, got: %v", test.listener, test.expectError, err)
+		}
+		if bootstrapServers != test.bootstrapServers {
+			t.Errorf("listener %s: expected bootstrap servers %q, got %q", test.listener, test.bootstrapServers, bootstrapServers)
+		}
+		if protocol != test.protocol {
+			t.Errorf("listener %s: expected protocol %q, got %q", test.listener, test.protocol, protocol)
+		}
+	}
+}
+
+func TestRenderClientBundle(t *testing.T) {
+	testCases := []struct {
+		testName           string
+		bundle             v1alpha1.ClientBundleSpec
+		protocol           v1beta1.SecurityProtocol
+		secretData         map[string][]byte
+		expectedProperties string
+		expectedLibrdkafka string
+	}{
+		{
+			testName:   "plaintext",
+			bundle:     v1alpha1.ClientBundleSpec{Listener: "plaintext"},
+			protocol:   v1beta1.SecurityProtocolPlaintext,
+			secretData: map[string][]byte{},
+			expectedProperties: `bootstrap.servers=kafka:9092
+security.protocol=PLAINTEXT
+`,
+			expectedLibrdkafka: `bootstrap.servers=kafka:9092
+security.protocol=plaintext
+`,
+		},
+		{
+			testName: "ssl with pkcs12 keystore from the k8s-csr backend",
+			bundle:   v1alpha1.ClientBundleSpec{Listener: "ssl", MountPath: "/var/run/kafka/"},
+			protocol: v1beta1.SecurityProtocolSSL,
+			secretData: map[string][]byte{
+				corev1.TLSCertKey:            []byte("cert"),
+				corev1.TLSPrivateKeyKey:      []byte("key"),
+				v1alpha1.CaChainPem:          []byte("ca"),
+				v1alpha1.TLSPKCS12KeyStore:   []byte("keystore"),
+				v1alpha1.TLSPKCS12TrustStore: []byte("truststore"),
+				v1alpha1.PKCS12PasswordKey:   []byte("password"),
+			},
+			expectedProperties: `bootstrap.servers=kafka:9092
+security.protocol=SSL
+config.providers=dir
+config.providers.dir.class=org.apache.kafka.common.config.provider.DirectoryConfigProvider
+ssl.keystore.type=PKCS12
+ssl.keystore.location=/var/run/kafka/keystore.p12
+ssl.keystore.password=${dir:/var/run/kafka:pkcs12.password}
+ssl.key.password=${dir:/var/run/kafka:pkcs12.password}
+ssl.truststore.type=PKCS12
+ssl.truststore.location=/var/run/kafka/truststore.p12
+ssl.truststore.password=${dir:/var/run/kafka:pkcs12.password}
+`,
+			expectedLibrdkafka: `bootstrap.servers=kafka:9092
+security.protocol=ssl
+ssl.ca.location=/var/run/kafka/chain.pem
+ssl.certificate.location=/var/run/kafka/tls.crt
+ssl.key.location=/var/run/kafka/tls.key
+`,
+		},
+		{
+			testName: "sasl_ssl without client certificate",
+			bundle:   v1alpha1.ClientBundleSpec{Listener: "sasl", SASLMechanism: "SCRAM-SHA-512"},
+			protocol: v1beta1.SecurityProtocolSaslSSL,
+			secretData: map[string][]byte{
+				v1alpha1.CoreCACertKey: []byte("ca"),
+			},
+			expectedProperties: `bootstrap.servers=kafka:9092
+security.protocol=SASL_SSL
+sasl.mechanism=SCRAM-SHA-512
+config.providers=dir
+config.providers.dir.class=org.apache.kafka.common.config.provider.DirectoryConfigProvider
+ssl.truststore.type=PEM
+ssl.truststore.location=/etc/kafka/secrets/ca.crt
+`,
+			expectedLibrdkafka: `bootstrap.servers=kafka:9092
+security.protocol=sasl_ssl
+sasl.mechanisms=SCRAM-SHA-512
+ssl.ca.location=/etc/kafka/secrets/ca.crt
+`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			bundleData, err := RenderClientBundle(&test.bundle, "kafka:9092", test.protocol, test.secretData)
+			if err != nil {
+				t.Fatal("Expected no error, got:", err)
+			}
+			if bundleData[v1alpha1.ClientPropertiesKey] != test.expectedProperties {
+				t.Errorf("Expected client.properties:\n%s\ngot:\n%s", test.expectedProperties, bundleData[v1alpha1.ClientPropertiesKey])
+			}
+			if bundleData[v1alpha1.LibrdkafkaConfigKey] != test.expectedLibrdkafka {
+				t.Errorf("Expected librdkafka.config:\n%s\ngot:\n%s", test.expectedLibrdkafka, bundleData[v1alpha1.LibrdkafkaConfigKey])
+			}
+		})
+	}
+}
@@ -18,6 +18,9 @@ package kafka
 // ConfigPropertyName name in the ConfigMap's Data field for the broker configuration
 const ConfigPropertyName = "broker-config"
 
+// StorageFormatPropertyName name in the ConfigMap's Data field for the arguments the KRaft storage is formatted with
+const StorageFormatPropertyName = "kraft-storage-format"
+
 // used for Kafka configurations
 const (
 	KafkaConfigSuperUsers = "super.users"
@@ -32,7 +35,9 @@ const (
 	KafkaConfigNodeID                 = "node.id"
 	KafkaConfigProcessRoles           = "process.roles"
 	KafkaConfigControllerQuorumVoters = "controller.quorum.voters"
-	KafkaConfigControllerListenerName = "controller.listener.names"
+	// KafkaConfigControllerQuorumBootstrapServers replaces the voters when the quorum is dynamic
+	KafkaConfigControllerQuorumBootstrapServers = "controller.quorum.bootstrap.servers"
+	KafkaConfigControllerListenerName           = "controller.listener.names"
 
 	KafkaConfigListeners                   = "listeners"
 	KafkaConfigListenerName                = "listener.name"
@@ -50,12 +55,40 @@ const (
 	KafkaConfigSSLKeystoreType       = "ssl.keystore.type"
 	KafkaConfigSSLKeyStoreLocation   = "ssl.keystore.location"
 	KafkaConfigSSLKeyStorePassword   = "ssl.keystore.password"
+	KafkaConfigSSLKeyPassword        = "ssl.key.password"
+	KafkaConfigSSLEnabledProtocols   = "ssl.enabled.protocols"
+	KafkaConfigSSLCipherSuites       = "ssl.cipher.suites"
+
+	KafkaConfigSSLPrincipalMappingRules = "ssl.principal.mapping.rules"
+
+	// KafkaConfigInterBrokerProtocolVersion is the protocol version the brokers of a ZooKeeper based cluster talk
+	KafkaConfigInterBrokerProtocolVersion = "inter.broker.protocol.version"
+	// KafkaConfigLogFlushIntervalMessages is the number of messages appended to a partition before its log is flushed
+	KafkaConfigLogFlushIntervalMessages = "log.flush.interval.messages"
+
+	KafkaConfigConfigProviders = "config.providers"
+)
+
+// used for tiered storage configurations
+const (
+	KafkaConfigRemoteLogStorageSystemEnable         = "remote.log.storage.system.enable"
+	KafkaConfigRemoteLogStorageManagerClassName     = "remote.log.storage.manager.class.name"
+	KafkaConfigRemoteLogStorageManagerClassPath     = "remote.log.storage.manager.class.path"
+	KafkaConfigRemoteLogMetadataManagerClassName    = "remote.log.metadata.manager.class.name"
+	KafkaConfigRemoteLogMetadataManagerListenerName = "remote.log.metadata.manager.listener.name"
+	// KafkaConfigRemoteStorageManagerConfigPrefix is the default prefix of the configs passed to the remote storage
+	// manager plugin
+	KafkaConfigRemoteStorageManagerConfigPrefix = "rsm.config."
+	KafkaConfigLogLocalRetentionMs              = "log.local.retention.ms"
+	KafkaConfigLogLocalRetentionBytes           = "log.local.retention.bytes"
 )
 
 // used for zk to kraft migration
 const (
 	MigrationBrokerControllerQuorumConfigEnabled = "migration.broker.controllerQuorumConfigEnabled"
 	MigrationBrokerKRaftMode                     = "migration.broker.kRaftMode"
+
+	KafkaConfigZooKeeperMigrationEnable = "zookeeper.metadata.migration.enable"
 )
 
 // used for Cruise Control configurations
@@ -0,0 +1,139 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+const defaultPrincipalMappingRule = "DEFAULT"
+
+var (
+	// principalMappingRuleParser is the rule syntax accepted by the SslPrincipalMapper of the brokers
+	principalMappingRuleParser = regexp.MustCompile(`^RULE:((?:\\.|[^\\/])*)/((?:\\.|[^\\/])*)/([LU]?)$`)
+)
+
+type principalMappingRule struct {
+	isDefault bool
+	pattern   *regexp.Regexp
+	// fullMatch is the pattern anchored to the whole name, like Java's Matcher.matches
+	fullMatch   *regexp.Regexp
+	replacement string
+	toLowerCase bool
+	toUpperCase bool
+}
+
+// parsePrincipalMappingRules parses rules in the format of the ssl.principal.mapping.rules broker config
+func parsePrincipalMappingRules(rules []string) ([]principalMappingRule, error) {
+	parsed := make([]principalMappingRule, 0, len(rules))
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == defaultPrincipalMappingRule {
+			parsed = append(parsed, principalMappingRule{isDefault: true})
+			continue
+		}
+		match := principalMappingRuleParser.FindStringSubmatch(rule)
+		if match == nil {
+			return nil, errors.NewWithDetails("invalid principal mapping rule", "rule", rule)
+		}
+		pattern, err := regexp.Compile(match[1])
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "invalid principal mapping rule pattern", "rule", rule)
+		}
+		parsed = append(parsed, principalMappingRule{
+			pattern:     pattern,
+			fullMatch:   regexp.MustCompile(`^(?:` + match[1] + `)$`),
+			replacement: convertJavaReplacement(match[2], pattern.NumSubexp()),
+			toLowerCase: match[3] == "L",
+			toUpperCase: match[3] == "U",
+		})
+	}
+	return parsed, nil
+}
+
+// ValidatePrincipalMappingRules checks whether the rules are valid ssl.principal.mapping.rules entries
+func ValidatePrincipalMappingRules(rules []string) error {
+	_, err := parsePrincipalMappingRules(rules)
+	return err
+}
+
+// MapPrincipal maps the Distinguished Name of a client certificate to a Kafka principal name the same way the
+// brokers do with the given ssl.principal.mapping.rules: the first rule matching the whole name is applied.
+// Without rules the Distinguished Name is returned as is.
+func MapPrincipal(rules []string, distinguishedName string) (string, error) {
+	if len(rules) == 0 {
+		return distinguishedName, nil
+	}
+	parsed, err := parsePrincipalMappingRules(rules)
+	if err != nil {
+		return "", err
+	}
+	for _, rule := range parsed {
+		if rule.isDefault {
+			return distinguishedName, nil
+		}
+		if !rule.fullMatch.MatchString(distinguishedName) {
+			continue
+		}
+		principal := rule.pattern.ReplaceAllString(distinguishedName, rule.replacement)
+		switch {
+		case rule.toLowerCase:
+			principal = strings.ToLower(principal)
+		case rule.toUpperCase:
+			principal = strings.ToUpper(principal)
+		}
+		return principal, nil
+	}
+	return "", errors.NewWithDetails("no principal mapping rule applies to the distinguished name", "distinguishedName", distinguishedName)
+}
+
+// convertJavaReplacement converts a replacement string of Java's Matcher.replaceAll to the template syntax of
+// regexp.Expand. Java reads the digits of a group reference only while they form an existing group number and
+// backslashes escape the next character.
+func convertJavaReplacement(replacement string, groupCount int) string {
+	var converted strings.Builder
+	for i := 0; i < len(replacement); i++ {
+		switch c := replacement[i]; {
+		case c == '\\' && i+1 < len(replacement):
+			i++
+			if replacement[i] == '$' {
+				converted.WriteString("$$")
+			} else {
+				converted.WriteByte(replacement[i])
+			}
+		case c == '$' && i+1 < len(replacement) && isDigit(replacement[i+1]):
+			i++
+			group := int(replacement[i] - '0')
+			for i+1 < len(replacement) && isDigit(replacement[i+1]) && group*10+int(replacement[i+1]-'0') <= groupCount {
+				i++
+				group = group*10 + int(replacement[i]-'0')
+			}
+			converted.WriteString("${" + strconv.Itoa(group) + "}")
+		case c == '$':
+			converted.WriteString("$$")
+		default:
+			converted.WriteByte(c)
+		}
+	}
+	return converted.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
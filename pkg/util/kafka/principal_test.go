@@ -0,0 +1,89 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+)
+
+func TestMapPrincipal(t *testing.T) {
+	testCases := []struct {
+		testName          string
+		rules             []string
+		distinguishedName string
+		expected          string
+		expectError       bool
+	}{
+		{
+			testName:          "no rules",
+			distinguishedName: "CN=kafka-user,O=koperator",
+			expected:          "CN=kafka-user,O=koperator",
+		},
+		{
+			testName:          "common name extracted",
+			rules:             []string{`RULE:^CN=([^,]*),.*$/$1/`, "DEFAULT"},
+			distinguishedName: "CN=kafka-user,O=koperator",
+			expected:          "kafka-user",
+		},
+		{
+			testName:          "first matching rule wins",
+			rules:             []string{`RULE:^CN=(.*?),OU=ServiceUsers.*$/$1/`, `RULE:^CN=(.*?),O=(.*)$/$1@$2/U`},
+			distinguishedName: "CN=kafka-user,O=koperator",
+			expected:          "KAFKA-USER@KOPERATOR",
+		},
+		{
+			testName:          "lower case with escaped slash",
+			rules:             []string{`RULE:^CN=([^,]*),O=(.*)$/$2\/$1/L`},
+			distinguishedName: "CN=Kafka-User,O=Koperator",
+			expected:          "koperator/kafka-user",
+		},
+		{
+			testName:          "back reference followed by a digit",
+			rules:             []string{`RULE:^CN=(.*)$/$10/`},
+			distinguishedName: "CN=kafka-user",
+			expected:          "kafka-user0",
+		},
+		{
+			testName:          "default rule",
+			rules:             []string{`RULE:^CN=(.*?),OU=ServiceUsers.*$/$1/`, "DEFAULT"},
+			distinguishedName: "CN=kafka-user,O=koperator",
+			expected:          "CN=kafka-user,O=koperator",
+		},
+		{
+			testName:          "no matching rule",
+			rules:             []string{`RULE:^CN=(.*?),OU=ServiceUsers.*$/$1/`},
+			distinguishedName: "CN=kafka-user,O=koperator",
+			expectError:       true,
+		},
+		{
+			testName:          "invalid rule",
+			rules:             []string{"RULE:^CN=(.*)$/$1"},
+			distinguishedName: "CN=kafka-user",
+			expectError:       true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			principal, err := MapPrincipal(test.rules, test.distinguishedName)
+			if test.expectError != (err != nil) {
+				t.Fatalf("Expected error %t, got: %v", test.expectError, err)
+			}
+			if principal != test.expected {
+				t.Errorf("Expected principal %q, got %q", test.expected, principal)
+			}
+		})
+	}
+}
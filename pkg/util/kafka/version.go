@@ -0,0 +1,126 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+var (
+	releaseParser = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.\d+)?$`)
+	// imageTagReleaseParser matches the Kafka version of image tags like 3.9.2 or 2.13-3.9.2-jdk21, where 2.13 is the
+	// Scala version
+	imageTagReleaseParser = regexp.MustCompile(`^(?:\d+\.\d+-)?(\d+)\.(\d+)\.\d+`)
+
+	// metadataVersionLevels are the highest production metadata.version levels of the Kafka releases
+	metadataVersionLevels = []struct {
+		release Release
+		level   int16
+	}{
+		{Release{3, 3}, 7},
+		{Release{3, 4}, 8},
+		{Release{3, 5}, 11},
+		{Release{3, 6}, 14},
+		{Release{3, 7}, 19},
+		{Release{3, 8}, 20},
+		{Release{3, 9}, 21},
+		{Release{4, 0}, 25},
+		{Release{4, 1}, 27},
+	}
+)
+
+// Release is the major and minor version of a Kafka release, the version inter.broker.protocol.version is set to
+type Release struct {
+	Major int
+	Minor int
+}
+
+// ParseRelease parses the release of a Kafka version like 3.9.2 or 3.9
+func ParseRelease(version string) (Release, error) {
+	match := releaseParser.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return Release{}, errors.NewWithDetails("invalid Kafka version", "version", version)
+	}
+	return newRelease(match[1], match[2])
+}
+
+// ReleaseFromImage returns the Kafka release named by the tag of the image, false when the tag does not name one
+func ReleaseFromImage(image string) (Release, bool) {
+	image, _, _ = strings.Cut(image, "@")
+	image = image[strings.LastIndex(image, "/")+1:]
+	_, tag, found := strings.Cut(image, ":")
+	if !found {
+		return Release{}, false
+	}
+	match := imageTagReleaseParser.FindStringSubmatch(tag)
+	if match == nil {
+		return Release{}, false
+	}
+	release, err := newRelease(match[1], match[2])
+	return release, err == nil
+}
+
+func newRelease(major, minor string) (Release, error) {
+	majorVersion, err := strconv.Atoi(major)
+	if err != nil {
+		return Release{}, err
+	}
+	minorVersion, err := strconv.Atoi(minor)
+	if err != nil {
+		return Release{}, err
+	}
+	return Release{Major: majorVersion, Minor: minorVersion}, nil
+}
+
+// String returns the release in the format of inter.broker.protocol.version
+func (r Release) String() string {
+	return fmt.Sprintf("%d.%d", r.Major, r.Minor)
+}
+
+// Compare returns -1, 0 or +1 depending on whether the release is older, the same or newer than the other one
+func (r Release) Compare(other Release) int {
+	if c := cmp.Compare(r.Major, other.Major); c != 0 {
+		return c
+	}
+	return cmp.Compare(r.Minor, other.Minor)
+}
+
+// MetadataVersionLevel returns the highest production metadata.version level of the release, false when the release
+// is not known
+func MetadataVersionLevel(release Release) (int16, bool) {
+	for _, v := range metadataVersionLevels {
+		if v.release == release {
+			return v.level, true
+		}
+	}
+	return 0, false
+}
+
+// ReleaseOfMetadataVersion returns the first release which supports the metadata.version level, false when the level
+// is newer than the known releases
+func ReleaseOfMetadataVersion(level int16) (Release, bool) {
+	for _, v := range metadataVersionLevels {
+		if v.level >= level {
+			return v.release, true
+		}
+	}
+	return Release{}, false
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+)
+
+func TestParseRelease(t *testing.T) {
+	testCases := []struct {
+		version     string
+		expected    Release
+		expectError bool
+	}{
+		{version: "3.9.2", expected: Release{3, 9}},
+		{version: "4.0", expected: Release{4, 0}},
+		{version: "3.10.1", expected: Release{3, 10}},
+		{version: "3", expectError: true},
+		{version: "latest", expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		release, err := ParseRelease(testCase.version)
+		if testCase.expectError {
+			if err == nil {
+				t.Errorf("expected an error for version %q", testCase.version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for version %q: %v", testCase.version, err)
+		}
+		if release != testCase.expected {
+			t.Errorf("expected release %s for version %q, got: %s", testCase.expected, testCase.version, release)
+		}
+	}
+}
+
+func TestReleaseFromImage(t *testing.T) {
+	testCases := []struct {
+		image    string
+		expected Release
+		found    bool
+	}{
+		{image: "ghcr.io/adobe/koperator/kafka:2.13-3.9.2-jdk21.0.11", expected: Release{3, 9}, found: true},
+		{image: "apache/kafka:4.0.0", expected: Release{4, 0}, found: true},
+		{image: "registry:5000/kafka:3.8.1@sha256:0123", expected: Release{3, 8}, found: true},
+		{image: "registry:5000/kafka"},
+		{image: "apache/kafka:latest"},
+	}
+
+	for _, testCase := range testCases {
+		release, found := ReleaseFromImage(testCase.image)
+		if found != testCase.found || release != testCase.expected {
+			t.Errorf("expected release %s (found: %t) for image %q, got: %s (found: %t)",
+				testCase.expected, testCase.found, testCase.image, release, found)
+		}
+	}
+}
+
+func TestMetadataVersionLevels(t *testing.T) {
+	if level, ok := MetadataVersionLevel(Release{3, 9}); !ok || level != 21 {
+		t.Errorf("expected metadata.version 21 for 3.9, got: %d (known: %t)", level, ok)
+	}
+	if _, ok := MetadataVersionLevel(Release{2, 8}); ok {
+		t.Error("expected 2.8 not to have a metadata.version")
+	}
+	if release, ok := ReleaseOfMetadataVersion(21); !ok || release != (Release{3, 9}) {
+		t.Errorf("expected metadata.version 21 to be introduced by 3.9, got: %s (known: %t)", release, ok)
+	}
+	if release, ok := ReleaseOfMetadataVersion(22); !ok || release != (Release{4, 0}) {
+		t.Errorf("expected metadata.version 22 to be introduced by 4.0, got: %s (known: %t)", release, ok)
+	}
+	if _, ok := ReleaseOfMetadataVersion(1000); ok {
+		t.Error("expected metadata.version 1000 not to be known")
+	}
+	if (Release{3, 10}).Compare(Release{3, 9}) != 1 || (Release{3, 9}).Compare(Release{4, 0}) != -1 {
+		t.Error("unexpected release order")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/robfig/cron/v3"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+// Validate checks that the schedule and the time zone of the maintenance window can be parsed
+func Validate(window v1beta1.MaintenanceWindow) error {
+	_, _, err := parse(window)
+	return err
+}
+
+// Open tells whether a maintenance window is open at the given time and when the next window opens. The time is
+// inside a window when no window is given.
+func Open(windows []v1beta1.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	var open bool
+	var next time.Time
+	for _, window := range windows {
+		schedule, location, err := parse(window)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		now := now.In(location)
+		// the window which opened last is still open when it opened less than its duration ago
+		if start := schedule.Next(now.Add(-window.Duration.Duration)); !start.After(now) {
+			open = true
+		}
+		if start := schedule.Next(now); next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return open, next, nil
+}
+
+func parse(window v1beta1.MaintenanceWindow) (cron.Schedule, *time.Location, error) {
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return nil, nil, errors.WrapIfWithDetails(err, "could not parse the schedule of the maintenance window", "schedule", window.Schedule)
+	}
+	location := time.UTC
+	if window.TimeZone != "" {
+		location, err = time.LoadLocation(window.TimeZone)
+		if err != nil {
+			return nil, nil, errors.WrapIfWithDetails(err, "could not load the time zone of the maintenance window", "timeZone", window.TimeZone)
+		}
+	}
+	if window.Duration.Duration <= 0 {
+		return nil, nil, errors.NewWithDetails("the duration of the maintenance window has to be positive", "duration", window.Duration.Duration)
+	}
+	return schedule, location, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Adobe. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func TestOpen(t *testing.T) {
+	saturdayNight := v1beta1.MaintenanceWindow{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 3 * time.Hour}}
+	berlinDaily := v1beta1.MaintenanceWindow{
+		Schedule: "0 22 * * *",
+		Duration: metav1.Duration{Duration: time.Hour},
+		TimeZone: "Europe/Berlin",
+	}
+	// Saturday
+	saturday := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		testName     string
+		windows      []v1beta1.MaintenanceWindow
+		now          time.Time
+		expectedOpen bool
+		expectedNext time.Time
+		expectedErr  bool
+	}{
+		{
+			testName:     "no window",
+			now:          saturday,
+			expectedOpen: true,
+		},
+		{
+			testName:     "before the window",
+			windows:      []v1beta1.MaintenanceWindow{saturdayNight},
+			now:          saturday.Add(time.Hour),
+			expectedNext: saturday.Add(2 * time.Hour),
+		},
+		{
+			testName:     "window opens",
+			windows:      []v1beta1.MaintenanceWindow{saturdayNight},
+			now:          saturday.Add(2 * time.Hour),
+			expectedOpen: true,
+			expectedNext: saturday.Add(7*24*time.Hour + 2*time.Hour),
+		},
+		{
+			testName:     "inside the window",
+			windows:      []v1beta1.MaintenanceWindow{saturdayNight},
+			now:          saturday.Add(4*time.Hour + 59*time.Minute),
+			expectedOpen: true,
+			expectedNext: saturday.Add(7*24*time.Hour + 2*time.Hour),
+		},
+		{
+			testName:     "window closed",
+			windows:      []v1beta1.MaintenanceWindow{saturdayNight},
+			now:          saturday.Add(5 * time.Hour),
+			expectedNext: saturday.Add(7*24*time.Hour + 2*time.Hour),
+		},
+		{
+			testName:     "window in time zone",
+			windows:      []v1beta1.MaintenanceWindow{berlinDaily},
+			now:          saturday.Add(20*time.Hour + 30*time.Minute),
+			expectedOpen: true,
+			expectedNext: saturday.Add(44 * time.Hour),
+		},
+		{
+			testName:     "earliest of the windows is next",
+			windows:      []v1beta1.MaintenanceWindow{saturdayNight, berlinDaily},
+			now:          saturday.Add(-12 * time.Hour),
+			expectedNext: saturday.Add(-4 * time.Hour),
+		},
+		{
+			testName:    "invalid schedule",
+			windows:     []v1beta1.MaintenanceWindow{{Schedule: "every day", Duration: metav1.Duration{Duration: time.Hour}}},
+			now:         saturday,
+			expectedErr: true,
+		},
+		{
+			testName: "invalid time zone",
+			windows: []v1beta1.MaintenanceWindow{{
+				Schedule: "0 2 * * *",
+				Duration: metav1.Duration{Duration: time.Hour},
+				TimeZone: "Mars/Olympus",
+			}},
+			now:         saturday,
+			expectedErr: true,
+		},
+		{
+			testName:    "missing duration",
+			windows:     []v1beta1.MaintenanceWindow{{Schedule: "0 2 * * *"}},
+			now:         saturday,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			open, next, err := Open(test.windows, test.now)
+			if test.expectedErr {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if open != test.expectedOpen {
+				t.Errorf("expected open: %v, got: %v", test.expectedOpen, open)
+			}
+			if !next.Equal(test.expectedNext) {
+				t.Errorf("expected next window: %v, got: %v", test.expectedNext, next)
+			}
+		})
+	}
+}
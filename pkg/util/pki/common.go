@@ -87,6 +87,10 @@ type Manager interface {
 	// FinalizeUserCertificate removes/revokes a user certificate
 	FinalizeUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser) error
 
+	// RenewUserCertificate triggers the re-issuance of a user certificate, backends which renew the certificates
+	// by themselves leave it to their own schedule
+	RenewUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser) error
+
 	// GetControllerTLSConfig retrieves a TLS configuration for a controller kafka client
 	GetControllerTLSConfig() (*tls.Config, error)
 }
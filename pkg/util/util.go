@@ -29,6 +29,7 @@ import (
 	"io"
 	"math/rand"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -229,6 +230,35 @@ func FilterControllerOnlyNodes(brokerIDs []string, spec v1beta1.KafkaClusterSpec
 	return filteredIDs, nil
 }
 
+// RequestedRestartBrokers returns the ids of the brokers of the spec whose restart is requested with the
+// restart-brokers annotation of the cluster
+func RequestedRestartBrokers(cluster *v1beta1.KafkaCluster) ([]string, error) {
+	value := strings.TrimSpace(cluster.GetAnnotations()[v1beta1.RestartBrokersAnnotationKey])
+	if value == "" {
+		return nil, nil
+	}
+
+	var requested []string
+	if value != v1beta1.RestartAllBrokers {
+		for _, field := range strings.Split(value, ",") {
+			brokerID, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "invalid broker id in the restart-brokers annotation", "value", value)
+			}
+			requested = append(requested, strconv.Itoa(brokerID))
+		}
+	}
+
+	var brokerIDs []string
+	for _, broker := range cluster.Spec.Brokers {
+		brokerID := strconv.Itoa(int(broker.Id))
+		if value == v1beta1.RestartAllBrokers || slices.Contains(requested, brokerID) {
+			brokerIDs = append(brokerIDs, brokerID)
+		}
+	}
+	return brokerIDs, nil
+}
+
 // IsIngressConfigInUse returns true if the provided ingressConfigName is bound to the given broker
 func IsIngressConfigInUse(iConfigName, defaultConfigName string, cluster *v1beta1.KafkaCluster, log logr.Logger) bool {
 	// Check if the global default is in use
@@ -504,6 +534,48 @@ func CreateTLSConfigFromSecret(tlsKeys *corev1.Secret) (*tls.Config, error) {
 	return config, nil
 }
 
+// ApplyTLSSettings restricts a TLS configuration to the given protocol versions and cipher suites.
+// TLS 1.3 cipher suites are accepted but not configurable in Go so they are skipped.
+func ApplyTLSSettings(config *tls.Config, protocols []v1beta1.TLSProtocol, cipherSuites []v1beta1.CipherSuite) error {
+	for _, protocol := range protocols {
+		var version uint16
+		switch protocol {
+		case v1beta1.TLSProtocolV12:
+			version = tls.VersionTLS12
+		case v1beta1.TLSProtocolV13:
+			version = tls.VersionTLS13
+		default:
+			return errors.NewWithDetails("unsupported TLS protocol", "protocol", protocol)
+		}
+		if config.MinVersion == 0 || version < config.MinVersion {
+			config.MinVersion = version
+		}
+		if version > config.MaxVersion {
+			config.MaxVersion = version
+		}
+	}
+
+	if len(cipherSuites) == 0 {
+		return nil
+	}
+	supported := make(map[string]*tls.CipherSuite)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		supported[suite.Name] = suite
+	}
+	config.CipherSuites = make([]uint16, 0, len(cipherSuites))
+	for _, name := range cipherSuites {
+		suite, ok := supported[string(name)]
+		if !ok {
+			return errors.NewWithDetails("unsupported cipher suite", "cipherSuite", name)
+		}
+		if slices.Contains(suite.SupportedVersions, tls.VersionTLS13) {
+			continue
+		}
+		config.CipherSuites = append(config.CipherSuites, suite.ID)
+	}
+	return nil
+}
+
 func ObjectManagedByClusterRegistry(object metav1.Object) bool {
 	annotations := object.GetAnnotations()
 	_, ok := annotations[clusterregv1alpha1.OwnershipAnnotation]
@@ -527,6 +599,28 @@ func GzipAndBase64BrokerConfiguration(broker *v1beta1.Broker) (string, error) {
 	return base64.StdEncoding.EncodeToString(buff.Bytes()), nil
 }
 
+// GzipAndBase64ResolvedBrokerConfiguration compresses the broker with its broker config group merged into its broker
+// config, so the backup also restores the configuration of the group
+func GzipAndBase64ResolvedBrokerConfiguration(broker *v1beta1.Broker, kafkaClusterSpec v1beta1.KafkaClusterSpec) (string, error) {
+	if broker == nil {
+		return "", nil
+	}
+	brokerConfig, err := broker.GetBrokerConfig(kafkaClusterSpec)
+	if err != nil {
+		return "", err
+	}
+	return GzipAndBase64BrokerConfiguration(&v1beta1.Broker{
+		Id:             broker.Id,
+		ReadOnlyConfig: broker.ReadOnlyConfig,
+		BrokerConfig:   brokerConfig,
+	})
+}
+
+// BrokerConfigurationRevision returns the revision of a broker configuration backup
+func BrokerConfigurationRevision(configurationBackup string) string {
+	return GetMD5Hash(configurationBackup)[:10]
+}
+
 func GetBrokerFromBrokerConfigurationBackup(config string) (v1beta1.Broker, error) {
 	if config == "" {
 		return v1beta1.Broker{}, errors.New("broker configurationBackup is empty")
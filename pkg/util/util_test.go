@@ -16,6 +16,7 @@
 package util
 
 import (
+	"crypto/tls"
 	"reflect"
 	"strconv"
 	"testing"
@@ -584,6 +585,59 @@ cruise.control.metrics.reporter.kubernetes.mode=true`,
 	}
 }
 
+func TestResolvedConfigurationBackup(t *testing.T) {
+	spec := v1beta1.KafkaClusterSpec{
+		BrokerConfigGroups: map[string]v1beta1.BrokerConfig{
+			"default": {Image: "apache/kafka:3.9.1", Config: "num.io.threads=8"},
+		},
+	}
+	broker := v1beta1.Broker{
+		Id:                1,
+		BrokerConfigGroup: "default",
+		ReadOnlyConfig:    "auto.create.topics.enable=false",
+		BrokerConfig:      &v1beta1.BrokerConfig{Image: "apache/kafka:4.0.0"},
+	}
+
+	config, err := GzipAndBase64ResolvedBrokerConfiguration(&broker, spec)
+	if err != nil {
+		t.Errorf("error should be nil, got: %v", err)
+	}
+	restored, err := GetBrokerFromBrokerConfigurationBackup(config)
+	if err != nil {
+		t.Errorf("error should be nil, got: %v", err)
+	}
+	expected := v1beta1.Broker{
+		Id:             1,
+		ReadOnlyConfig: "auto.create.topics.enable=false",
+		BrokerConfig:   &v1beta1.BrokerConfig{Image: "apache/kafka:4.0.0", Config: "num.io.threads=8"},
+	}
+	if !reflect.DeepEqual(expected, restored) {
+		t.Errorf("Expected: %v  Got: %v", expected, restored)
+	}
+
+	// the resolved backup renders the same broker config as the broker of the spec
+	restoredConfig, err := restored.GetBrokerConfig(spec)
+	if err != nil {
+		t.Errorf("error should be nil, got: %v", err)
+	}
+	brokerConfig, err := broker.GetBrokerConfig(spec)
+	if err != nil {
+		t.Errorf("error should be nil, got: %v", err)
+	}
+	if !reflect.DeepEqual(brokerConfig, restoredConfig) {
+		t.Errorf("Expected: %v  Got: %v", brokerConfig, restoredConfig)
+	}
+
+	spec.BrokerConfigGroups["default"] = v1beta1.BrokerConfig{Image: "apache/kafka:3.9.1", Config: "num.io.threads=16"}
+	changed, err := GzipAndBase64ResolvedBrokerConfiguration(&broker, spec)
+	if err != nil {
+		t.Errorf("error should be nil, got: %v", err)
+	}
+	if BrokerConfigurationRevision(config) == BrokerConfigurationRevision(changed) {
+		t.Errorf("revision should change with the config of the broker config group")
+	}
+}
+
 func TestFilterControllerOnlyNodes(t *testing.T) {
 	testCases := []struct {
 		testName                  string
@@ -899,3 +953,63 @@ func TestShouldIncludeBroker(t *testing.T) {
 		}
 	})
 }
+
+func TestApplyTLSSettings(t *testing.T) {
+	config := &tls.Config{}
+	err := ApplyTLSSettings(config, []v1beta1.TLSProtocol{v1beta1.TLSProtocolV13, v1beta1.TLSProtocolV12},
+		[]v1beta1.CipherSuite{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", "TLS_AES_128_GCM_SHA256", "TLS_RSA_WITH_AES_128_CBC_SHA"})
+	require.NoError(t, err)
+	require.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	require.Equal(t, uint16(tls.VersionTLS13), config.MaxVersion)
+	require.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, tls.TLS_RSA_WITH_AES_128_CBC_SHA}, config.CipherSuites)
+
+	config = &tls.Config{}
+	require.NoError(t, ApplyTLSSettings(config, nil, nil))
+	require.Equal(t, &tls.Config{}, config)
+
+	require.Error(t, ApplyTLSSettings(&tls.Config{}, []v1beta1.TLSProtocol{"TLSv1.1"}, nil))
+	require.Error(t, ApplyTLSSettings(&tls.Config{}, nil, []v1beta1.CipherSuite{"TLS_UNKNOWN"}))
+}
+
+func TestRequestedRestartBrokers(t *testing.T) {
+	spec := v1beta1.KafkaClusterSpec{Brokers: []v1beta1.Broker{{Id: 0}, {Id: 1}, {Id: 2}}}
+	testCases := []struct {
+		testName          string
+		annotation        string
+		expectedBrokerIDs []string
+		expectedErr       bool
+	}{
+		{
+			testName: "no restart requested",
+		},
+		{
+			testName:          "all brokers",
+			annotation:        "all",
+			expectedBrokerIDs: []string{"0", "1", "2"},
+		},
+		{
+			testName:          "brokers of the spec",
+			annotation:        "2, 0,5",
+			expectedBrokerIDs: []string{"0", "2"},
+		},
+		{
+			testName:    "invalid broker id",
+			annotation:  "0,one",
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			cluster := &v1beta1.KafkaCluster{Spec: spec}
+			cluster.SetAnnotations(map[string]string{v1beta1.RestartBrokersAnnotationKey: testCase.annotation})
+			brokerIDs, err := RequestedRestartBrokers(cluster)
+			if testCase.expectedErr != (err != nil) {
+				t.Errorf("Expected error: %v  Got: %v", testCase.expectedErr, err)
+			}
+			if !reflect.DeepEqual(testCase.expectedBrokerIDs, brokerIDs) {
+				t.Errorf("Expected: %v  Got: %v", testCase.expectedBrokerIDs, brokerIDs)
+			}
+		})
+	}
+}
@@ -29,6 +29,7 @@ const (
 	unsupportedRemovingStorageMsg                  = "removing storage from a broker is not supported"
 	invalidExternalListenerStartingPortErrMsg      = "invalid external listener starting port number"
 	invalidContainerPortForIngressControllerErrMsg = "invalid trarget port number for ingress controller deployment"
+	tieredStorageNotEnabledErrMsg                  = "tiered storage is not enabled on the kafka cluster"
 
 	// errorDuringValidationMsg is added to infrastructure errors (e.g. failed to connect), but not to field validation errors
 	errorDuringValidationMsg = "error during validation"
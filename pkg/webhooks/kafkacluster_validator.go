@@ -18,6 +18,7 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -30,13 +31,15 @@ import (
 
 	banzaicloudv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/util"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+	"github.com/banzaicloud/koperator/pkg/util/maintenance"
 )
 
 type KafkaClusterValidator struct {
 	Log logr.Logger
 }
 
-func (s KafkaClusterValidator) ValidateUpdate(ctx context.Context, _, kafkaClusterNew *banzaicloudv1beta1.KafkaCluster) (warnings admission.Warnings, err error) {
+func (s KafkaClusterValidator) ValidateUpdate(ctx context.Context, kafkaClusterOld, kafkaClusterNew *banzaicloudv1beta1.KafkaCluster) (warnings admission.Warnings, err error) {
 	var allErrs field.ErrorList
 	log := s.Log.WithValues("name", kafkaClusterNew.GetName(), "namespace", kafkaClusterNew.GetNamespace())
 
@@ -45,6 +48,21 @@ func (s KafkaClusterValidator) ValidateUpdate(ctx context.Context, _, kafkaClust
 		allErrs = append(allErrs, listenerErrs...)
 	}
 
+	allErrs = append(allErrs, checkMigration(&kafkaClusterNew.Spec)...)
+
+	allErrs = append(allErrs, checkMaintenanceWindows(&kafkaClusterNew.Spec)...)
+
+	allErrs = append(allErrs, checkStorageMigrations(&kafkaClusterNew.Spec)...)
+
+	allErrs = append(allErrs, checkRestartBrokersAnnotation(kafkaClusterNew)...)
+
+	allErrs = append(allErrs, checkBrokerTemplate(&kafkaClusterNew.Spec)...)
+
+	if kafkaClusterOld != nil {
+		allErrs = append(allErrs, checkKRaftQuorum(&kafkaClusterOld.Spec, &kafkaClusterNew.Spec)...)
+		allErrs = append(allErrs, checkVersionDowngrade(kafkaClusterOld, kafkaClusterNew)...)
+	}
+
 	if len(allErrs) == 0 {
 		return nil, nil
 	}
@@ -64,6 +82,18 @@ func (s KafkaClusterValidator) ValidateCreate(ctx context.Context, kafkaCluster
 		allErrs = append(allErrs, listenerErrs...)
 	}
 
+	allErrs = append(allErrs, checkMigration(&kafkaCluster.Spec)...)
+
+	allErrs = append(allErrs, checkMaintenanceWindows(&kafkaCluster.Spec)...)
+
+	allErrs = append(allErrs, checkStorageMigrations(&kafkaCluster.Spec)...)
+
+	allErrs = append(allErrs, checkRestartBrokersAnnotation(kafkaCluster)...)
+
+	allErrs = append(allErrs, checkBrokerTemplate(&kafkaCluster.Spec)...)
+
+	allErrs = append(allErrs, checkKRaftQuorum(nil, &kafkaCluster.Spec)...)
+
 	if len(allErrs) == 0 {
 		return nil, nil
 	}
@@ -86,9 +116,270 @@ func checkInternalAndExternalListeners(kafkaClusterSpec *banzaicloudv1beta1.Kafk
 
 	allErrs = append(allErrs, checkExternalListeners(kafkaClusterSpec)...)
 
+	allErrs = append(allErrs, checkPrincipalMappingRules(kafkaClusterSpec)...)
+
+	return allErrs
+}
+
+// checkPrincipalMappingRules checks that the principal mapping rules can be parsed both by the operator and the brokers
+func checkPrincipalMappingRules(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, rule := range kafkaClusterSpec.ListenersConfig.PrincipalMappingRules {
+		if err := kafkautils.ValidatePrincipalMappingRules([]string{rule}); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("listenersConfig").Child("principalMappingRules").Index(i), rule, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// checkMaintenanceWindows checks that the schedules, durations and time zones of the maintenance windows are valid
+func checkMaintenanceWindows(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, window := range kafkaClusterSpec.MaintenanceWindows {
+		if err := maintenance.Validate(window); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("maintenanceWindows").Index(i), window, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// checkStorageMigrations checks that the storage configs migrated to replace another storage config of the broker
+func checkStorageMigrations(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	groupNames := make([]string, 0, len(kafkaClusterSpec.BrokerConfigGroups))
+	for name := range kafkaClusterSpec.BrokerConfigGroups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		allErrs = append(allErrs, checkStorageConfigMigrations(kafkaClusterSpec.BrokerConfigGroups[name].StorageConfigs,
+			field.NewPath("spec").Child("brokerConfigGroups").Key(name).Child("storageConfigs"))...)
+	}
+	for i, broker := range kafkaClusterSpec.Brokers {
+		if broker.BrokerConfig != nil {
+			allErrs = append(allErrs, checkStorageConfigMigrations(broker.BrokerConfig.StorageConfigs,
+				field.NewPath("spec").Child("brokers").Index(i).Child("brokerConfig").Child("storageConfigs"))...)
+		}
+	}
+	return allErrs
+}
+
+func checkStorageConfigMigrations(storageConfigs []banzaicloudv1beta1.StorageConfig, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, storage := range storageConfigs {
+		if storage.MigrateFrom == "" {
+			continue
+		}
+		migrateFromPath := path.Index(i).Child("migrateFrom")
+		if storage.PvcSpec == nil {
+			allErrs = append(allErrs, field.Invalid(migrateFromPath, storage.MigrateFrom, "only the volume of a pvcSpec can be migrated to"))
+		}
+		for j := range storageConfigs {
+			if storageConfigs[j].MountPath == storage.MigrateFrom {
+				allErrs = append(allErrs, field.Invalid(migrateFromPath, storage.MigrateFrom,
+					"the mount path migrated from has to be replaced by the storage config, it cannot be kept"))
+				break
+			}
+		}
+	}
+	return allErrs
+}
+
+// checkRestartBrokersAnnotation checks that the restart-brokers annotation lists broker ids or requests every broker
+func checkRestartBrokersAnnotation(kafkaCluster *banzaicloudv1beta1.KafkaCluster) field.ErrorList {
+	if _, err := util.RequestedRestartBrokers(kafkaCluster); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("metadata").Child("annotations").Key(banzaicloudv1beta1.RestartBrokersAnnotationKey),
+			kafkaCluster.GetAnnotations()[banzaicloudv1beta1.RestartBrokersAnnotationKey], err.Error())}
+	}
+	return nil
+}
+
+// checkBrokerTemplate checks that the brokers derived from the broker template use an existing broker config group
+// and are not KRaft controllers, the controllers cannot be removed gracefully when the template is scaled down
+func checkBrokerTemplate(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	template := kafkaClusterSpec.BrokerTemplate
+	if template == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("brokerTemplate").Child("brokerConfigGroup")
+	group, ok := kafkaClusterSpec.BrokerConfigGroups[template.BrokerConfigGroup]
+	if !ok {
+		return field.ErrorList{field.NotFound(path, template.BrokerConfigGroup)}
+	}
+	if kafkaClusterSpec.KRaftMode && group.IsControllerNode() {
+		return field.ErrorList{field.Invalid(path, template.BrokerConfigGroup,
+			"the brokers derived from the broker template cannot be controllers")}
+	}
+	return nil
+}
+
+// checkMigration validates that the cluster can be migrated from ZooKeeper to KRaft when spec.migration is enabled
+func checkMigration(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	migration := kafkaClusterSpec.Migration
+	if migration == nil || !migration.Enabled {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if !kafkaClusterSpec.KRaftMode {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("kRaft"), kafkaClusterSpec.KRaftMode,
+			"kRaft has to be enabled to migrate the cluster from ZooKeeper"))
+	}
+	if len(kafkaClusterSpec.ZKAddresses) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec").Child("zkAddresses"),
+			"zkAddresses have to be kept until the migration is finalized"))
+	}
+	if migration.Finalize && migration.Rollback {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("migration").Child("rollback"), migration.Rollback,
+			"the migration cannot be finalized and rolled back at the same time"))
+	}
+
+	var controllers int
+	for i, broker := range kafkaClusterSpec.Brokers {
+		brokerPath := field.NewPath("spec").Child("brokers").Index(i)
+		bConfig, err := broker.GetBrokerConfig(*kafkaClusterSpec)
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(brokerPath, err))
+			continue
+		}
+		if bConfig.IsCombinedNode() {
+			allErrs = append(allErrs, field.Invalid(brokerPath, bConfig.Roles,
+				"combined nodes are not supported by the migration from ZooKeeper"))
+		}
+		if bConfig.IsControllerOnlyNode() {
+			controllers++
+		}
+	}
+	if controllers == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec").Child("brokers"),
+			"at least one controller-only node is needed to migrate the cluster from ZooKeeper"))
+	}
+	return allErrs
+}
+
+// checkKRaftQuorum validates that the controller changes keep a majority of the KRaft quorum, so the quorum
+// can still elect a leader while the controllers are replaced, and that the quorum type is not changed later on
+// kRaftDynamicQuorumRelease is the first Kafka release supporting dynamic KRaft quorums (KIP-853)
+var kRaftDynamicQuorumRelease = kafkautils.Release{Major: 3, Minor: 9}
+
+func checkKRaftQuorum(kafkaClusterSpecOld, kafkaClusterSpecNew *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	if kafkaClusterSpecNew.KRaftDynamicQuorum && !kafkaClusterSpecNew.KRaftMode {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("kRaftDynamicQuorum"), kafkaClusterSpecNew.KRaftDynamicQuorum,
+			"dynamic quorum requires kRaft to be enabled"))
+	}
+	if kafkaClusterSpecNew.KRaftDynamicQuorum {
+		// images whose tag does not name a Kafka version are not checked
+		if release, ok := kafkautils.ReleaseFromImage(kafkaClusterSpecNew.GetClusterImage()); ok && release.Compare(kRaftDynamicQuorumRelease) < 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("kRaftDynamicQuorum"), kafkaClusterSpecNew.KRaftDynamicQuorum,
+				fmt.Sprintf("dynamic quorum requires Kafka %s or later, the cluster image runs Kafka %s", kRaftDynamicQuorumRelease, release)))
+		}
+	}
+	if kafkaClusterSpecOld == nil || !kafkaClusterSpecOld.KRaftMode || !kafkaClusterSpecNew.KRaftMode {
+		return allErrs
+	}
+
+	if kafkaClusterSpecOld.KRaftDynamicQuorum != kafkaClusterSpecNew.KRaftDynamicQuorum {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("kRaftDynamicQuorum"),
+			"the quorum of a KRaft cluster cannot be changed between static and dynamic"))
+	}
+
+	oldControllers := getControllerIDs(kafkaClusterSpecOld)
+	newControllers := getControllerIDs(kafkaClusterSpecNew)
+	var kept int
+	for id := range oldControllers {
+		if newControllers[id] {
+			kept++
+		}
+	}
+	if len(oldControllers) > 0 && kept*2 <= len(oldControllers) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("brokers"), len(newControllers),
+			fmt.Sprintf("only %d of the %d controllers are kept, a majority of the KRaft quorum has to be kept in each change",
+				kept, len(oldControllers))))
+	}
 	return allErrs
 }
 
+// checkVersionDowngrade refuses the broker images whose Kafka release is older than the release the cluster is
+// finalized at: the brokers of an older release cannot read the finalized metadata.version or talk the finalized
+// inter.broker.protocol.version. Images whose tag does not name a Kafka version are not checked.
+func checkVersionDowngrade(kafkaClusterOld, kafkaClusterNew *banzaicloudv1beta1.KafkaCluster) field.ErrorList {
+	finalized, ok := finalizedRelease(&kafkaClusterOld.Status)
+	if !ok {
+		return nil
+	}
+
+	oldImages := getBrokerImages(&kafkaClusterOld.Spec)
+	checked := make(map[string]bool)
+	var allErrs field.ErrorList
+	for i, broker := range kafkaClusterNew.Spec.Brokers {
+		bConfig, err := broker.GetBrokerConfig(kafkaClusterNew.Spec)
+		if err != nil {
+			continue
+		}
+		image := util.GetBrokerImage(bConfig, kafkaClusterNew.Spec.GetClusterImage())
+		if image == oldImages[broker.Id] || checked[image] {
+			continue
+		}
+		checked[image] = true
+		release, ok := kafkautils.ReleaseFromImage(image)
+		if !ok || release.Compare(finalized) >= 0 {
+			continue
+		}
+		path := field.NewPath("spec").Child("brokers").Index(i)
+		if image == kafkaClusterNew.Spec.GetClusterImage() {
+			path = field.NewPath("spec").Child("clusterImage")
+		}
+		allErrs = append(allErrs, field.Forbidden(path,
+			fmt.Sprintf("image %s runs Kafka %s, the cluster is finalized at Kafka %s and cannot be downgraded", image, release, finalized)))
+	}
+	return allErrs
+}
+
+// finalizedRelease returns the Kafka release the cluster is finalized at, either by the operator or by the
+// metadata.version finalized in the cluster
+func finalizedRelease(status *banzaicloudv1beta1.KafkaClusterStatus) (kafkautils.Release, bool) {
+	var finalized kafkautils.Release
+	var ok bool
+	if status.VersionUpgrade != nil {
+		if release, err := kafkautils.ParseRelease(status.VersionUpgrade.FinalizedRelease); err == nil {
+			finalized, ok = release, true
+		}
+	}
+	if status.Features != nil && status.Features.MetadataVersion > 0 {
+		if release, found := kafkautils.ReleaseOfMetadataVersion(status.Features.MetadataVersion); found && (!ok || release.Compare(finalized) > 0) {
+			finalized, ok = release, true
+		}
+	}
+	return finalized, ok
+}
+
+func getBrokerImages(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) map[int32]string {
+	images := make(map[int32]string)
+	for _, broker := range kafkaClusterSpec.Brokers {
+		bConfig, err := broker.GetBrokerConfig(*kafkaClusterSpec)
+		if err != nil {
+			continue
+		}
+		images[broker.Id] = util.GetBrokerImage(bConfig, kafkaClusterSpec.GetClusterImage())
+	}
+	return images
+}
+
+func getControllerIDs(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) map[int32]bool {
+	controllers := make(map[int32]bool)
+	for _, broker := range kafkaClusterSpec.Brokers {
+		bConfig, err := broker.GetBrokerConfig(*kafkaClusterSpec)
+		if err != nil {
+			continue
+		}
+		if bConfig.IsControllerNode() {
+			controllers[broker.Id] = true
+		}
+	}
+	return controllers
+}
+
 func checkInternalListeners(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
 	return checkUniqueListenerContainerPort(kafkaClusterSpec.ListenersConfig)
 }
@@ -18,9 +18,11 @@ package webhooks
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/banzaicloud/koperator/pkg/util"
@@ -385,3 +387,383 @@ func TestCheckTargetPortsCollisionForEnvoy(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckPrincipalMappingRules(t *testing.T) {
+	testCases := []struct {
+		testName string
+		rules    []string
+		expected int
+	}{
+		{
+			testName: "no rules",
+		},
+		{
+			testName: "valid rules",
+			rules:    []string{`RULE:^CN=([^,]*),.*$/$1/L`, "DEFAULT"},
+		},
+		{
+			testName: "invalid rules",
+			rules:    []string{`RULE:^CN=(.*$/$1/`, "DEFAULT", "CN=kafka"},
+			expected: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			kafkaClusterSpec := v1beta1.KafkaClusterSpec{
+				ListenersConfig: v1beta1.ListenersConfig{PrincipalMappingRules: testCase.rules},
+			}
+			require.Len(t, checkPrincipalMappingRules(&kafkaClusterSpec), testCase.expected)
+		})
+	}
+}
+
+func TestCheckMaintenanceWindows(t *testing.T) {
+	hour := metav1.Duration{Duration: time.Hour}
+	testCases := []struct {
+		testName string
+		windows  []v1beta1.MaintenanceWindow
+		expected int
+	}{
+		{
+			testName: "no windows",
+		},
+		{
+			testName: "valid windows",
+			windows: []v1beta1.MaintenanceWindow{
+				{Schedule: "0 2 * * SAT", Duration: hour},
+				{Schedule: "@daily", Duration: hour, TimeZone: "America/New_York"},
+			},
+		},
+		{
+			testName: "invalid windows",
+			windows: []v1beta1.MaintenanceWindow{
+				{Schedule: "0 2 * *", Duration: hour},
+				{Schedule: "0 2 * * *", Duration: hour, TimeZone: "Nowhere"},
+				{Schedule: "0 2 * * *"},
+				{Schedule: "0 3 * * *", Duration: hour},
+			},
+			expected: 3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			kafkaClusterSpec := v1beta1.KafkaClusterSpec{MaintenanceWindows: testCase.windows}
+			require.Len(t, checkMaintenanceWindows(&kafkaClusterSpec), testCase.expected)
+		})
+	}
+}
+
+func TestCheckStorageMigrations(t *testing.T) {
+	pvcSpec := &corev1.PersistentVolumeClaimSpec{StorageClassName: util.StringPointer("gp3")}
+	testCases := []struct {
+		testName       string
+		storageConfigs []v1beta1.StorageConfig
+		expected       int
+	}{
+		{
+			testName:       "no migration",
+			storageConfigs: []v1beta1.StorageConfig{{MountPath: "/kafka-logs", PvcSpec: pvcSpec}},
+		},
+		{
+			testName:       "valid migration",
+			storageConfigs: []v1beta1.StorageConfig{{MountPath: "/kafka-logs-gp3", MigrateFrom: "/kafka-logs", PvcSpec: pvcSpec}},
+		},
+		{
+			testName: "mount path migrated from is kept",
+			storageConfigs: []v1beta1.StorageConfig{
+				{MountPath: "/kafka-logs"},
+				{MountPath: "/kafka-logs-gp3", MigrateFrom: "/kafka-logs", PvcSpec: pvcSpec},
+				{MountPath: "/kafka-logs-2", MigrateFrom: "/kafka-logs-2", PvcSpec: pvcSpec},
+			},
+			expected: 2,
+		},
+		{
+			testName:       "migration to an empty dir",
+			storageConfigs: []v1beta1.StorageConfig{{MountPath: "/kafka-logs-tmp", MigrateFrom: "/kafka-logs", EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			expected:       1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			kafkaClusterSpec := v1beta1.KafkaClusterSpec{
+				BrokerConfigGroups: map[string]v1beta1.BrokerConfig{"default": {StorageConfigs: testCase.storageConfigs}},
+				Brokers:            []v1beta1.Broker{{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{StorageConfigs: testCase.storageConfigs}}},
+			}
+			require.Len(t, checkStorageMigrations(&kafkaClusterSpec), 2*testCase.expected)
+		})
+	}
+}
+
+func TestCheckRestartBrokersAnnotation(t *testing.T) {
+	testCases := []struct {
+		testName string
+		value    string
+		expected int
+	}{
+		{
+			testName: "no annotation",
+		},
+		{
+			testName: "all brokers",
+			value:    "all",
+		},
+		{
+			testName: "broker ids",
+			value:    "0, 2,3",
+		},
+		{
+			testName: "invalid broker id",
+			value:    "0,broker-1",
+			expected: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			kafkaCluster := v1beta1.KafkaCluster{}
+			if testCase.value != "" {
+				kafkaCluster.SetAnnotations(map[string]string{v1beta1.RestartBrokersAnnotationKey: testCase.value})
+			}
+			require.Len(t, checkRestartBrokersAnnotation(&kafkaCluster), testCase.expected)
+		})
+	}
+}
+
+func TestCheckBrokerTemplate(t *testing.T) {
+	testCases := []struct {
+		testName  string
+		kRaftMode bool
+		group     string
+		roles     []string
+		expected  int
+	}{
+		{
+			testName: "broker config group",
+			group:    "default",
+		},
+		{
+			testName: "missing broker config group",
+			group:    "missing",
+			expected: 1,
+		},
+		{
+			testName:  "broker-only broker config group in KRaft mode",
+			kRaftMode: true,
+			group:     "default",
+			roles:     []string{"broker"},
+		},
+		{
+			testName:  "controller broker config group in KRaft mode",
+			kRaftMode: true,
+			group:     "default",
+			roles:     []string{"broker", "controller"},
+			expected:  1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			kafkaClusterSpec := v1beta1.KafkaClusterSpec{
+				KRaftMode:          testCase.kRaftMode,
+				BrokerConfigGroups: map[string]v1beta1.BrokerConfig{"default": {Roles: testCase.roles}},
+				BrokerTemplate:     &v1beta1.BrokerTemplate{BrokerConfigGroup: testCase.group},
+			}
+			require.Len(t, checkBrokerTemplate(&kafkaClusterSpec), testCase.expected)
+		})
+	}
+}
+
+func TestCheckMigration(t *testing.T) {
+	controller := v1beta1.Broker{Id: 100, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"controller"}}}
+	broker := v1beta1.Broker{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"broker"}}}
+	combined := v1beta1.Broker{Id: 1, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"broker", "controller"}}}
+
+	testCases := []struct {
+		testName string
+		spec     v1beta1.KafkaClusterSpec
+		expected int
+	}{
+		{
+			testName: "no migration",
+			spec:     v1beta1.KafkaClusterSpec{Brokers: []v1beta1.Broker{broker}},
+		},
+		{
+			testName: "valid migration",
+			spec: v1beta1.KafkaClusterSpec{
+				KRaftMode:   true,
+				ZKAddresses: []string{"zk:2181"},
+				Brokers:     []v1beta1.Broker{broker, controller},
+				Migration:   &v1beta1.MigrationConfig{Enabled: true},
+			},
+		},
+		{
+			testName: "migration without kRaft and ZooKeeper",
+			spec: v1beta1.KafkaClusterSpec{
+				Brokers:   []v1beta1.Broker{broker, controller},
+				Migration: &v1beta1.MigrationConfig{Enabled: true},
+			},
+			expected: 2,
+		},
+		{
+			testName: "migration with combined node and without controllers",
+			spec: v1beta1.KafkaClusterSpec{
+				KRaftMode:   true,
+				ZKAddresses: []string{"zk:2181"},
+				Brokers:     []v1beta1.Broker{broker, combined},
+				Migration:   &v1beta1.MigrationConfig{Enabled: true},
+			},
+			expected: 2,
+		},
+		{
+			testName: "migration finalized and rolled back",
+			spec: v1beta1.KafkaClusterSpec{
+				KRaftMode:   true,
+				ZKAddresses: []string{"zk:2181"},
+				Brokers:     []v1beta1.Broker{broker, controller},
+				Migration:   &v1beta1.MigrationConfig{Enabled: true, Finalize: true, Rollback: true},
+			},
+			expected: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			require.Len(t, checkMigration(&testCase.spec), testCase.expected)
+		})
+	}
+}
+
+func TestCheckKRaftQuorum(t *testing.T) {
+	brokers := func(controllerIDs ...int32) []v1beta1.Broker {
+		result := []v1beta1.Broker{{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"broker"}}}}
+		for _, id := range controllerIDs {
+			result = append(result, v1beta1.Broker{Id: id, BrokerConfig: &v1beta1.BrokerConfig{Roles: []string{"controller"}}})
+		}
+		return result
+	}
+
+	testCases := []struct {
+		testName string
+		oldSpec  *v1beta1.KafkaClusterSpec
+		newSpec  v1beta1.KafkaClusterSpec
+		expected int
+	}{
+		{
+			testName: "new dynamic quorum",
+			newSpec:  v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, Brokers: brokers(100)},
+		},
+		{
+			testName: "dynamic quorum without kRaft",
+			newSpec:  v1beta1.KafkaClusterSpec{KRaftDynamicQuorum: true, Brokers: brokers()},
+			expected: 1,
+		},
+		{
+			testName: "dynamic quorum with an older Kafka image",
+			newSpec:  v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, ClusterImage: "apache/kafka:3.8.1", Brokers: brokers(100)},
+			expected: 1,
+		},
+		{
+			testName: "dynamic quorum with an unversioned image",
+			newSpec:  v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, ClusterImage: "apache/kafka:latest", Brokers: brokers(100)},
+		},
+		{
+			testName: "controller added",
+			oldSpec:  &v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, Brokers: brokers(100, 101, 102)},
+			newSpec:  v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, Brokers: brokers(100, 101, 102, 103)},
+		},
+		{
+			testName: "controller replaced",
+			oldSpec:  &v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, Brokers: brokers(100, 101, 102)},
+			newSpec:  v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, Brokers: brokers(100, 101, 103)},
+		},
+		{
+			testName: "majority of the controllers replaced",
+			oldSpec:  &v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, Brokers: brokers(100, 101, 102)},
+			newSpec:  v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, Brokers: brokers(100, 103, 104)},
+			expected: 1,
+		},
+		{
+			testName: "static quorum changed to dynamic",
+			oldSpec:  &v1beta1.KafkaClusterSpec{KRaftMode: true, Brokers: brokers(100, 101, 102)},
+			newSpec:  v1beta1.KafkaClusterSpec{KRaftMode: true, KRaftDynamicQuorum: true, Brokers: brokers(100, 101, 102)},
+			expected: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			require.Len(t, checkKRaftQuorum(testCase.oldSpec, &testCase.newSpec), testCase.expected)
+		})
+	}
+}
+
+func TestCheckVersionDowngrade(t *testing.T) {
+	cluster := func(clusterImage string, status v1beta1.KafkaClusterStatus, brokers ...v1beta1.Broker) *v1beta1.KafkaCluster {
+		if len(brokers) == 0 {
+			brokers = []v1beta1.Broker{{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{}}, {Id: 1, BrokerConfig: &v1beta1.BrokerConfig{}}}
+		}
+		return &v1beta1.KafkaCluster{
+			Spec:   v1beta1.KafkaClusterSpec{ClusterImage: clusterImage, Brokers: brokers},
+			Status: status,
+		}
+	}
+	zkFinalized := v1beta1.KafkaClusterStatus{VersionUpgrade: &v1beta1.VersionUpgradeStatus{FinalizedRelease: "3.9"}}
+	kRaftFinalized := v1beta1.KafkaClusterStatus{Features: &v1beta1.FeaturesStatus{MetadataVersion: 21}}
+
+	testCases := []struct {
+		testName   string
+		oldCluster *v1beta1.KafkaCluster
+		newCluster *v1beta1.KafkaCluster
+		expected   int
+	}{
+		{
+			testName:   "upgrade",
+			oldCluster: cluster("apache/kafka:3.9.1", zkFinalized),
+			newCluster: cluster("apache/kafka:4.0.0", zkFinalized),
+		},
+		{
+			testName:   "patch downgrade",
+			oldCluster: cluster("apache/kafka:3.9.1", zkFinalized),
+			newCluster: cluster("apache/kafka:3.9.0", zkFinalized),
+		},
+		{
+			testName:   "downgrade below the finalized inter.broker.protocol.version",
+			oldCluster: cluster("apache/kafka:3.9.1", zkFinalized),
+			newCluster: cluster("apache/kafka:3.8.1", zkFinalized),
+			expected:   1,
+		},
+		{
+			testName:   "downgrade below the finalized metadata.version",
+			oldCluster: cluster("ghcr.io/adobe/koperator/kafka:2.13-3.9.2-jdk21.0.11", kRaftFinalized),
+			newCluster: cluster("ghcr.io/adobe/koperator/kafka:2.13-3.8.1-jdk21.0.11", kRaftFinalized),
+			expected:   1,
+		},
+		{
+			testName:   "downgrade of a broker image",
+			oldCluster: cluster("apache/kafka:3.9.1", kRaftFinalized),
+			newCluster: cluster("apache/kafka:3.9.1", kRaftFinalized,
+				v1beta1.Broker{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{}},
+				v1beta1.Broker{Id: 1, BrokerConfig: &v1beta1.BrokerConfig{Image: "apache/kafka:3.7.2"}}),
+			expected: 1,
+		},
+		{
+			testName:   "downgrade of a cluster which is not finalized",
+			oldCluster: cluster("apache/kafka:3.9.1", v1beta1.KafkaClusterStatus{}),
+			newCluster: cluster("apache/kafka:3.8.1", v1beta1.KafkaClusterStatus{}),
+		},
+		{
+			testName:   "image without a Kafka version",
+			oldCluster: cluster("apache/kafka:3.9.1", zkFinalized),
+			newCluster: cluster("apache/kafka:latest", zkFinalized),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			require.Len(t, checkVersionDowngrade(testCase.oldCluster, testCase.newCluster), testCase.expected)
+		})
+	}
+}
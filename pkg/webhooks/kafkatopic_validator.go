@@ -128,6 +128,8 @@ func (s *KafkaTopicValidator) validateKafkaTopic(ctx context.Context, log logr.L
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("clusterRef").Child("name"), clusterName, logMsg))
 	}
 
+	allErrs = append(allErrs, checkRemoteStorage(topic, cluster)...)
+
 	fieldErr, err := s.checkExistingKafkaTopicCRs(ctx, clusterNamespace, topic)
 	if err != nil {
 		return nil, err
@@ -187,7 +189,7 @@ func (s *KafkaTopicValidator) checkKafka(ctx context.Context, topic *banzaicloud
 						fmt.Sprintf(`When creating KafkaTopic CR for existing topic, initially its replication factor must be the same as what the existing kafka topic has (given: %v present: %v)`, topic.Spec.ReplicationFactor, existing.ReplicationFactor)))
 				}
 
-				if diff := cmp.Diff(existing.ConfigEntries, util.MapStringStringPointer(topic.Spec.Config), cmpopts.EquateEmpty()); diff != "" {
+				if diff := cmp.Diff(existing.ConfigEntries, util.MapStringStringPointer(topic.Spec.GetConfig()), cmpopts.EquateEmpty()); diff != "" {
 					allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("config"), topic.Spec.Partitions,
 						fmt.Sprintf(`When creating KafkaTopic CR for existing topic, initially its configuration must be the same as the existing kafka topic configuration.
 						Difference: %s`, diff)))
@@ -221,6 +223,27 @@ func (s *KafkaTopicValidator) checkKafka(ctx context.Context, topic *banzaicloud
 	return allErrs, nil
 }
 
+// checkRemoteStorage checks whether the remote storage of the topic can be enabled on the referred KafkaCluster
+func checkRemoteStorage(topic *banzaicloudv1alpha1.KafkaTopic, cluster *banzaicloudv1beta1.KafkaCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	configPath := field.NewPath("spec").Child("config").Key(banzaicloudv1alpha1.RemoteStorageEnableConfig)
+	remoteStorageEnableConfig, configured := topic.Spec.Config[banzaicloudv1alpha1.RemoteStorageEnableConfig]
+	if topic.Spec.RemoteStorageEnable && configured && !strings.EqualFold(remoteStorageEnableConfig, "true") {
+		allErrs = append(allErrs, field.Invalid(configPath, remoteStorageEnableConfig,
+			"remote storage is enabled by remoteStorageEnable but disabled by the topic config"))
+	}
+	if cluster.Spec.IsTieredStorageEnabled() {
+		return allErrs
+	}
+	if topic.Spec.RemoteStorageEnable {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("remoteStorageEnable"), topic.Spec.RemoteStorageEnable,
+			tieredStorageNotEnabledErrMsg))
+	} else if strings.EqualFold(remoteStorageEnableConfig, "true") {
+		allErrs = append(allErrs, field.Invalid(configPath, remoteStorageEnableConfig, tieredStorageNotEnabledErrMsg))
+	}
+	return allErrs
+}
+
 // checkExistingKafkaTopicCRs checks whether there's any other duplicate KafkaTopic CR exists
 // that refers to the same KafkaCluster's same topic
 func (s *KafkaTopicValidator) checkExistingKafkaTopicCRs(ctx context.Context,
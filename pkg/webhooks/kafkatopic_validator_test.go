@@ -360,3 +360,58 @@ func TestValidateTopic(t *testing.T) {
 		t.Error("Expected not allowed for reason: kafka does not support changing the replication factor")
 	}
 }
+
+func TestCheckRemoteStorage(t *testing.T) {
+	tieredCluster := newMockCluster()
+	tieredCluster.Spec.TieredStorage = &v1beta1.TieredStorageConfig{RemoteStorageManagerClassName: "org.example.RemoteStorageManager"}
+
+	testCases := []struct {
+		name                string
+		cluster             *v1beta1.KafkaCluster
+		remoteStorageEnable bool
+		config              map[string]string
+		expectedErrors      int
+	}{
+		{
+			name:    "remote storage disabled",
+			cluster: newMockCluster(),
+		},
+		{
+			name:                "remote storage enabled on tiered storage cluster",
+			cluster:             tieredCluster,
+			remoteStorageEnable: true,
+		},
+		{
+			name:                "remote storage enabled without tiered storage",
+			cluster:             newMockCluster(),
+			remoteStorageEnable: true,
+			expectedErrors:      1,
+		},
+		{
+			name:           "remote storage enabled by config without tiered storage",
+			cluster:        newMockCluster(),
+			config:         map[string]string{v1alpha1.RemoteStorageEnableConfig: "true"},
+			expectedErrors: 1,
+		},
+		{
+			name:                "remote storage disabled by config",
+			cluster:             tieredCluster,
+			remoteStorageEnable: true,
+			config:              map[string]string{v1alpha1.RemoteStorageEnableConfig: "false"},
+			expectedErrors:      1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			topic := newMockTopic()
+			topic.Spec.RemoteStorageEnable = testCase.remoteStorageEnable
+			topic.Spec.Config = testCase.config
+
+			fieldErrorList := checkRemoteStorage(topic, testCase.cluster)
+			if len(fieldErrorList) != testCase.expectedErrors {
+				t.Errorf("expected %d invalid fields, got: %v", testCase.expectedErrors, fieldErrorList)
+			}
+		})
+	}
+}
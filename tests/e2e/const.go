@@ -141,6 +141,7 @@ func koperatorCRDs() []string {
 		kafkaKind,
 		"kafkausers.kafka.banzaicloud.io",
 		"cruisecontroloperations.kafka.banzaicloud.io",
+		"kafkaclusterbackups.kafka.banzaicloud.io",
 	}
 }
 
@@ -151,6 +152,7 @@ func koperatorRelatedResourceKinds() []string {
 		kafkaKind,
 		"kafkausers.kafka.banzaicloud.io",
 		"cruisecontroloperations.kafka.banzaicloud.io",
+		"kafkaclusterbackups.kafka.banzaicloud.io",
 		"clusterissuers.cert-manager.io",
 		"servicemonitors.monitoring.coreos.com",
 	}
@@ -67,6 +67,7 @@ var (
 			LocalCRDSubpaths: []string{
 				"crds/cruisecontroloperations.yaml",
 				"crds/kafkaclusters.yaml",
+				"crds/kafkaclusterbackups.yaml",
 				"crds/kafkatopics.yaml",
 				"crds/kafkausers.yaml",
 			},
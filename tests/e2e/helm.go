@@ -74,6 +74,7 @@ func (helmDescriptor *helmDescriptor) crdPath() (string, error) { //nolint:unuse
 		[]string{
 			"crds/cruisecontroloperations.yaml",
 			"crds/kafkaclusters.yaml",
+			"crds/kafkaclusterbackups.yaml",
 			"crds/kafkatopics.yaml",
 			"crds/kafkausers.yaml",
 		},